@@ -4,10 +4,29 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/joshibbotson/gym-tracker-backend/internal/db"
 	m "github.com/joshibbotson/gym-tracker-backend/internal/middleware"
 	"github.com/joshibbotson/gym-tracker-backend/internal/modules/auth"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/calendar"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/export"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/importer"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/intake"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/interaction"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/notification"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/nutrition"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/phase"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/program"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/progress"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/record"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/recovery"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/report"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/share"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/social"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/stats"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/template"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/user"
 	"github.com/joshibbotson/gym-tracker-backend/internal/modules/workout"
 )
 
@@ -28,20 +47,172 @@ func main() {
 	authService := auth.NewAuthService(authRepository)
 	authHandler := &auth.AuthHandler{Service: authService}
 
+	userRepository := user.NewUserRepository()
+	userService := user.NewUserService(userRepository)
+	userHandler := &user.UserHandler{Service: userService}
+
+	recordRepository := record.NewRecordRepository()
+	recordService := record.NewRecordService(recordRepository)
+	recordHandler := &record.RecordHandler{Service: recordService}
+
 	workoutRepository := workout.NewWorkoutRepository()
-	workoutService := workout.NewWorkoutService(workoutRepository)
+	interactionRepository := interaction.NewInteractionRepository()
+	workoutService := workout.NewWorkoutService(workoutRepository, recordService, userService, interactionRepository)
 	workoutHandler := &workout.WorkoutHandler{Service: workoutService}
 
+	templateRepository := template.NewTemplateRepository()
+	templateService := template.NewTemplateService(templateRepository, workoutService)
+	templateHandler := &template.TemplateHandler{Service: templateService}
+
+	programRepository := program.NewProgramRepository()
+	programService := program.NewProgramService(programRepository, workoutService)
+	programHandler := &program.ProgramHandler{Service: programService}
+
+	recoveryService := recovery.NewRecoveryService(workoutService, templateService)
+	recoveryHandler := &recovery.RecoveryHandler{Service: recoveryService}
+
+	statsService := stats.NewStatsService(workoutService, userService)
+	statsHandler := &stats.StatsHandler{Service: statsService}
+
+	phaseRepository := phase.NewPhaseRepository()
+	phaseService := phase.NewPhaseService(phaseRepository, workoutService)
+	phaseHandler := &phase.PhaseHandler{Service: phaseService}
+
+	intakeRepository := intake.NewIntakeRepository()
+	intakeService := intake.NewIntakeService(intakeRepository, userService)
+	intakeHandler := &intake.IntakeHandler{Service: intakeService}
+
+	nutritionRepository := nutrition.NewNutritionRepository()
+	nutritionService := nutrition.NewNutritionService(nutritionRepository, phaseService, intakeService, userService)
+	nutritionHandler := &nutrition.NutritionHandler{Service: nutritionService}
+
+	progressService := progress.NewProgressService(workoutService, userService, phaseService, intakeService)
+	progressHandler := &progress.ProgressHandler{Service: progressService}
+
+	importerRepository := importer.NewImporterRepository()
+	importerService := importer.NewImporterService(importerRepository, workoutService, userService)
+	importerHandler := &importer.ImporterHandler{Service: importerService}
+
+	exportService := export.NewExportService(workoutService)
+	exportHandler := &export.ExportHandler{Service: exportService}
+
+	reportRepository := report.NewReportRepository()
+	reportService := report.NewReportService(reportRepository, workoutService, progressService, recordService, userService)
+	reportHandler := &report.ReportHandler{Service: reportService}
+
+	calendarRepository := calendar.NewCalendarRepository()
+	calendarService := calendar.NewCalendarService(calendarRepository, workoutService, templateService, userService)
+	calendarHandler := &calendar.CalendarHandler{Service: calendarService}
+
+	shareRepository := share.NewShareRepository()
+	shareService := share.NewShareService(shareRepository, workoutService, userService, statsService, recordService, progressService)
+	shareHandler := &share.ShareHandler{Service: shareService}
+
+	socialRepository := social.NewSocialRepository()
+	socialService := social.NewSocialService(socialRepository, userService, workoutService)
+	socialHandler := &social.SocialHandler{Service: socialService}
+
+	notificationRepository := notification.NewNotificationRepository()
+	notificationService := notification.NewNotificationService(notificationRepository, userService)
+	notificationHandler := &notification.NotificationHandler{Service: notificationService}
+
+	interactionService := interaction.NewInteractionService(interactionRepository, workoutService, socialService, userService, notificationService)
+	interactionHandler := &interaction.InteractionHandler{Service: interactionService}
+
+	// hard delete workouts that have been in the trash past the retention window
+	go func() {
+		for {
+			if purged, err := workoutService.PurgeExpiredTrash(); err != nil {
+				log.Println("error purging workout trash:", err)
+			} else if purged > 0 {
+				log.Println("purged workouts from trash:", purged)
+			}
+			time.Sleep(time.Hour)
+		}
+	}()
+
 	// http.HandleFunc("/auth", authHandler.UserHandler)
 	// http.HandleFunc("/auth/login", m.HeaderMiddleware(authHandler.LoginHandler))
 	// http.HandleFunc("/user/user-details", middlewareChain((authHandler.HandleUserDetails)))
+	http.HandleFunc("/user/settings", middlewareChain(userHandler.SettingsHandler))
 	http.HandleFunc("/auth/google/login", m.HeaderMiddleware((authHandler.HandleGoogleLogin)))
 	http.HandleFunc("/auth/google/callback", m.HeaderMiddleware((authHandler.HandleOAuth2Callback)))
 	http.HandleFunc("/auth/logout", middlewareChain((authHandler.Logout)))
 	http.HandleFunc("/workout", middlewareChain(workoutHandler.Handler))
 	http.HandleFunc("/workout/count", middlewareChain(workoutHandler.Handler))
+	http.HandleFunc("/workout/stats", middlewareChain(statsHandler.Handler))
+	http.HandleFunc("/workout/years", middlewareChain(workoutHandler.CalendarHandler))
+	http.HandleFunc("/workout/years/{year}", middlewareChain(workoutHandler.CalendarHandler))
 	http.HandleFunc("/workout/delete/{id}", middlewareChain(workoutHandler.Handler))
+	http.HandleFunc("/workout/revisions/{id}", middlewareChain(workoutHandler.RevisionsHandler))
+	http.HandleFunc("/workout/revisions/{id}/{revision}", middlewareChain(workoutHandler.RevisionHandler))
+	http.HandleFunc("/workout/records", middlewareChain(recordHandler.Handler))
+	http.HandleFunc("/workout/records/{exercise}", middlewareChain(recordHandler.Handler))
+	http.HandleFunc("/workout/analytics/strength", middlewareChain(workoutHandler.AnalyticsHandler))
+	http.HandleFunc("/workout/analytics/volume", middlewareChain(workoutHandler.AnalyticsHandler))
+	http.HandleFunc("/workout/analytics/cardio", middlewareChain(workoutHandler.AnalyticsHandler))
+	http.HandleFunc("/workout/import", middlewareChain(importerHandler.CSVHandler))
+	http.HandleFunc("/workout/import/activity", middlewareChain(importerHandler.ActivityHandler))
+	http.HandleFunc("/workout/track/{id}", middlewareChain(importerHandler.ActivityHandler))
+	http.HandleFunc("/workout/import/scale", middlewareChain(importerHandler.ScaleHandler))
+	http.HandleFunc("/workout/import/health", middlewareChain(importerHandler.HealthHandler))
+	http.HandleFunc("/workout/import/health/{id}", middlewareChain(importerHandler.HealthHandler))
+	http.HandleFunc("/workout/export", middlewareChain(exportHandler.Handler))
+	http.HandleFunc("/workout/trash", middlewareChain(workoutHandler.TrashHandler))
+	http.HandleFunc("/workout/trash/{id}", middlewareChain(workoutHandler.TrashHandler))
 	http.HandleFunc("/workout/{date}", middlewareChain(workoutHandler.Handler))
+	http.HandleFunc("/template", middlewareChain(templateHandler.Handler))
+	http.HandleFunc("/template/{id}", middlewareChain(templateHandler.Handler))
+	http.HandleFunc("/template/apply/{id}", middlewareChain(templateHandler.Handler))
+	http.HandleFunc("/template/save/{workoutId}", middlewareChain(templateHandler.Handler))
+	http.HandleFunc("/routine", middlewareChain(templateHandler.RoutineHandler))
+	http.HandleFunc("/routine/today", middlewareChain(templateHandler.RoutineHandler))
+	http.HandleFunc("/routine/{id}", middlewareChain(templateHandler.RoutineHandler))
+	http.HandleFunc("/program", middlewareChain(programHandler.Handler))
+	http.HandleFunc("/program/next", middlewareChain(programHandler.Handler))
+	http.HandleFunc("/program/{id}", middlewareChain(programHandler.Handler))
+	http.HandleFunc("/program/schedule/{id}", middlewareChain(programHandler.Handler))
+	http.HandleFunc("/intake", middlewareChain(intakeHandler.Handler))
+	http.HandleFunc("/intake/{date}", middlewareChain(intakeHandler.Handler))
+	http.HandleFunc("/nutrition/foods", middlewareChain(nutritionHandler.FoodHandler))
+	http.HandleFunc("/nutrition/foods/{id}", middlewareChain(nutritionHandler.FoodHandler))
+	http.HandleFunc("/nutrition/meals", middlewareChain(nutritionHandler.MealHandler))
+	http.HandleFunc("/nutrition/meals/copy", middlewareChain(nutritionHandler.MealHandler))
+	http.HandleFunc("/nutrition/meals/{id}", middlewareChain(nutritionHandler.MealHandler))
+	http.HandleFunc("/nutrition/day/{date}", middlewareChain(nutritionHandler.MealHandler))
+	http.HandleFunc("/phase", middlewareChain(phaseHandler.Handler))
+	http.HandleFunc("/phase/current", middlewareChain(phaseHandler.Handler))
+	http.HandleFunc("/phase/derive", middlewareChain(phaseHandler.Handler))
+	http.HandleFunc("/phase/{id}", middlewareChain(phaseHandler.Handler))
+	http.HandleFunc("/phase/summary", middlewareChain(phaseHandler.SummaryHandler))
+	http.HandleFunc("/phase/summary/{id}", middlewareChain(phaseHandler.SummaryHandler))
+	http.HandleFunc("/progress/weight", middlewareChain(progressHandler.Handler))
+	http.HandleFunc("/progress/tdee", middlewareChain(progressHandler.Handler))
+	http.HandleFunc("/progress/report", middlewareChain(reportHandler.Handler))
+	http.HandleFunc("/chart/share", middlewareChain(reportHandler.ChartShareHandler))
+	http.HandleFunc("/chart/share/{token}/{chart}", m.HeaderMiddleware(reportHandler.SharedChartHandler))
+	http.HandleFunc("/chart/{chart}", middlewareChain(reportHandler.ChartHandler))
+	http.HandleFunc("/calendar/feed", middlewareChain(calendarHandler.FeedHandler))
+	http.HandleFunc("/calendar/feed/{token}", m.HeaderMiddleware(calendarHandler.ICSHandler))
+	http.HandleFunc("/share", middlewareChain(shareHandler.Handler))
+	http.HandleFunc("/share/{id}", middlewareChain(shareHandler.Handler))
+	http.HandleFunc("/shared/{token}", m.HeaderMiddleware(shareHandler.SharedHandler))
+	http.HandleFunc("/social/follow/{userId}", middlewareChain(socialHandler.FollowHandler))
+	http.HandleFunc("/social/followers", middlewareChain(socialHandler.FollowersHandler))
+	http.HandleFunc("/social/followers/{userId}", middlewareChain(socialHandler.FollowersHandler))
+	http.HandleFunc("/social/following", middlewareChain(socialHandler.FollowingHandler))
+	http.HandleFunc("/social/blocks", middlewareChain(socialHandler.BlockHandler))
+	http.HandleFunc("/social/blocks/{userId}", middlewareChain(socialHandler.BlockHandler))
+	http.HandleFunc("/social/feed", middlewareChain(socialHandler.FeedHandler))
+	http.HandleFunc("/social/users/{userId}", middlewareChain(socialHandler.UserHandler))
+	http.HandleFunc("/social/users/{userId}/workouts", middlewareChain(socialHandler.UserHandler))
+	http.HandleFunc("/reaction/workout/{workoutId}", middlewareChain(interactionHandler.ReactionHandler))
+	http.HandleFunc("/comment/workout/{workoutId}", middlewareChain(interactionHandler.CommentHandler))
+	http.HandleFunc("/comment/{id}", middlewareChain(interactionHandler.CommentHandler))
+	http.HandleFunc("/notification", middlewareChain(notificationHandler.Handler))
+	http.HandleFunc("/notification/{id}", middlewareChain(notificationHandler.Handler))
+	http.HandleFunc("/recovery", middlewareChain(recoveryHandler.Handler))
+	http.HandleFunc("/recovery/today", middlewareChain(recoveryHandler.Handler))
 	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("OK"))
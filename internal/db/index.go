@@ -0,0 +1,16 @@
+package db
+
+import (
+	"context"
+	"log"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// EnsureIndexes creates the indexes a collection relies on. Creating an index
+// that already exists does nothing, so repositories call it on every start.
+func EnsureIndexes(collection *mongo.Collection, models ...mongo.IndexModel) {
+	if _, err := collection.Indexes().CreateMany(context.TODO(), models); err != nil {
+		log.Println("error creating indexes on", collection.Name()+":", err)
+	}
+}
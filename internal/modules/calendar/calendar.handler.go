@@ -0,0 +1,109 @@
+package calendar
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	m "github.com/joshibbotson/gym-tracker-backend/internal/middleware"
+	util "github.com/joshibbotson/gym-tracker-backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CalendarHandler struct {
+	Service CalendarService
+}
+
+func NewCalendarHandler(service CalendarService) *CalendarHandler {
+	return &CalendarHandler{
+		Service: service,
+	}
+}
+
+// FeedHandler serves /calendar/feed
+func (h *CalendarHandler) FeedHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		m.PermissionMiddleware(h.handleGetFeed)(w, r)
+	case http.MethodPost:
+		m.PermissionMiddleware(h.handleCreateFeed)(w, r)
+	case http.MethodDelete:
+		m.PermissionMiddleware(h.handleDeleteFeed)(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// ICSHandler serves /calendar/feed/{token}. Calendar apps subscribe without
+// the session cookie so the token in the path is all that identifies the user.
+func (h *CalendarHandler) ICSHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	h.handleGetCalendar(w, r)
+}
+
+func (h *CalendarHandler) handleGetFeed(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	feed, err := h.Service.GetFeed(userID)
+	if err != nil {
+		if errors.Is(err, ErrFeedNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Error fetching calendar feed", http.StatusInternalServerError)
+		return
+	}
+	util.WriteJSON(w, http.StatusOK, feed)
+}
+
+// handleCreateFeed creates the feed or regenerates its token, which stops the
+// previous URL working.
+func (h *CalendarHandler) handleCreateFeed(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	feed, err := h.Service.CreateFeed(userID)
+	if err != nil {
+		http.Error(w, "Error creating calendar feed", http.StatusInternalServerError)
+		return
+	}
+	feed.URL = util.BaseURL(r) + "/calendar/feed/" + feed.Token + ".ics"
+	util.WriteJSON(w, http.StatusCreated, feed)
+}
+
+func (h *CalendarHandler) handleDeleteFeed(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	deleted, err := h.Service.DeleteFeed(userID)
+	if err != nil {
+		http.Error(w, "Error deleting calendar feed", http.StatusInternalServerError)
+		return
+	}
+	if !deleted {
+		http.Error(w, "Calendar feed not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"message": "Calendar feed deleted successfully"}`))
+}
+
+func (h *CalendarHandler) handleGetCalendar(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimSuffix(r.PathValue("token"), ".ics")
+
+	calendar, err := h.Service.GetFeedCalendar(token)
+	if err != nil {
+		if errors.Is(err, ErrFeedNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Error fetching calendar", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="workouts.ics"`)
+	w.Header().Set("Cache-Control", "private, max-age=900")
+	w.Write([]byte(calendar))
+}
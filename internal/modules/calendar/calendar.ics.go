@@ -0,0 +1,179 @@
+package calendar
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/calendar/constants"
+	tt "github.com/joshibbotson/gym-tracker-backend/internal/modules/template/types"
+	uc "github.com/joshibbotson/gym-tracker-backend/internal/modules/user/constants"
+	wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+	wt "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+)
+
+// icsWriter builds an RFC 5545 calendar, lines end in CRLF and are folded
+// at 75 octets.
+type icsWriter struct {
+	builder strings.Builder
+}
+
+const (
+	icsDate      = "20060102"
+	icsTimestamp = "20060102T150405Z"
+	icsLineLimit = 75
+)
+
+func (w *icsWriter) line(name string, value string) {
+	content := name + ":" + value
+	for len(content) > icsLineLimit {
+		cut := icsLineLimit
+		// folding mustn't split a multi-byte character
+		for cut > 0 && content[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		w.builder.WriteString(content[:cut] + "\r\n")
+		// continuation lines start with a space, which counts to their length
+		content = " " + content[cut:]
+	}
+	w.builder.WriteString(content + "\r\n")
+}
+
+func (w *icsWriter) text(name string, value string) {
+	w.line(name, escapeText(value))
+}
+
+func escapeText(value string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(value)
+}
+
+func (w *icsWriter) begin() {
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", c.ProductID)
+	w.line("CALSCALE", "GREGORIAN")
+	w.line("METHOD", "PUBLISH")
+	w.text("X-WR-CALNAME", c.CalendarName)
+	w.line("REFRESH-INTERVAL;VALUE=DURATION", c.FeedRefreshInterval)
+	w.line("X-PUBLISHED-TTL", c.FeedRefreshInterval)
+}
+
+func (w *icsWriter) end() string {
+	w.line("END", "VCALENDAR")
+	return w.builder.String()
+}
+
+// allDayEvent writes an event for a whole day, workouts are logged against a
+// day rather than a time.
+func (w *icsWriter) allDayEvent(uid string, stamp time.Time, date time.Time, summary string, description string, status string) {
+	w.line("BEGIN", "VEVENT")
+	w.line("UID", uid+"@"+c.UIDDomain)
+	w.line("DTSTAMP", stamp.UTC().Format(icsTimestamp))
+	w.line("DTSTART;VALUE=DATE", date.Format(icsDate))
+	w.line("DTEND;VALUE=DATE", date.AddDate(0, 0, 1).Format(icsDate))
+	w.text("SUMMARY", summary)
+	if description != "" {
+		w.text("DESCRIPTION", description)
+	}
+	w.line("STATUS", status)
+	w.line("TRANSP", "TRANSPARENT")
+	w.line("END", "VEVENT")
+}
+
+// workoutTitle names a workout after its target muscles, falling back to
+// its cardio or first exercise.
+func workoutTitle(config *wt.WorkoutConfig) string {
+	if len(config.TargetMuscles) > 0 {
+		return muscleNames(config.TargetMuscles)
+	}
+	if config.Cardio != nil {
+		return titleCase(string(config.Cardio.Modality))
+	}
+	if len(config.Exercises) > 0 {
+		return strings.TrimSpace(config.Exercises[0].Name)
+	}
+	return "Workout"
+}
+
+func muscleNames(muscles []wc.TargetMuscles) string {
+	names := make([]string, len(muscles))
+	for i, muscle := range muscles {
+		names[i] = titleCase(strings.ReplaceAll(string(muscle), "_", " "))
+	}
+	return strings.Join(names, ", ")
+}
+
+func titleCase(name string) string {
+	words := strings.Fields(name)
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// workoutDescription lists the sets of each exercise and the cardio done.
+func workoutDescription(config *wt.WorkoutConfig, unit uc.WeightUnit) string {
+	lines := []string{}
+	for _, exercise := range config.Exercises {
+		sets := []string{}
+		for _, set := range exercise.Sets {
+			if set.Warmup {
+				continue
+			}
+			if set.Weight != nil {
+				sets = append(sets, fmt.Sprintf("%d x %s %s", set.Reps, formatNumber(*set.Weight), unit))
+			} else {
+				sets = append(sets, strconv.Itoa(set.Reps))
+			}
+		}
+		line := strings.TrimSpace(exercise.Name)
+		if len(sets) > 0 {
+			line += ": " + strings.Join(sets, ", ")
+		}
+		lines = append(lines, line)
+	}
+
+	if cardio := config.Cardio; cardio != nil {
+		line := fmt.Sprintf("%s %s", titleCase(string(cardio.Modality)), formatDuration(cardio.Duration))
+		if cardio.Distance != nil {
+			line += fmt.Sprintf(", %s km", formatNumber(*cardio.Distance))
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// sessionDescription lists what a scheduled template prescribes.
+func sessionDescription(template *tt.Template, unit uc.WeightUnit) string {
+	lines := []string{}
+	if len(template.TargetMuscles) > 0 {
+		lines = append(lines, muscleNames(template.TargetMuscles))
+	}
+	for _, exercise := range template.Exercises {
+		line := fmt.Sprintf("%s: %d x %d", exercise.Name, exercise.TargetSets, exercise.TargetReps)
+		if exercise.TargetWeight != nil {
+			line += fmt.Sprintf(" @ %s %s", formatNumber(*exercise.TargetWeight), unit)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// formatDuration writes seconds as h:mm:ss, or m:ss under an hour.
+func formatDuration(seconds int) string {
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds%3600/60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
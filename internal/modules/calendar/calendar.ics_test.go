@@ -0,0 +1,76 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestEscapeText(tt *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"plain", "Push day", "Push day"},
+		{"separators", "Squat; 3x5, heavy", `Squat\; 3x5\, heavy`},
+		{"backslash first", `a\b;`, `a\\b\;`},
+		{"line breaks", "one\r\ntwo\nthree", `one\ntwo\nthree`},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			if got := escapeText(test.value); got != test.want {
+				tt.Fatalf("escapeText(%q) = %q, want %q", test.value, got, test.want)
+			}
+		})
+	}
+}
+
+func TestLineFolding(tt *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		lines int
+	}{
+		{"short line is left alone", "Legs", 1},
+		{"exactly the limit", strings.Repeat("a", icsLineLimit-len("SUMMARY:")), 1},
+		{"one over the limit", strings.Repeat("a", icsLineLimit-len("SUMMARY:")+1), 2},
+		{"long line", strings.Repeat("a", 200), 3},
+		{"multi-byte characters", strings.Repeat("é", 100), 3},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			w := &icsWriter{}
+			w.line("SUMMARY", test.value)
+			out := w.builder.String()
+			if !strings.HasSuffix(out, "\r\n") {
+				tt.Fatalf("%q doesn't end in CRLF", out)
+			}
+
+			lines := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+			if len(lines) != test.lines {
+				tt.Fatalf("got %d lines, want %d: %q", len(lines), test.lines, lines)
+			}
+			unfolded := lines[0]
+			for i, line := range lines {
+				if len(line) > icsLineLimit {
+					tt.Fatalf("line %d is %d octets, over the limit", i, len(line))
+				}
+				if !utf8.ValidString(line) {
+					tt.Fatalf("line %d splits a character: %q", i, line)
+				}
+				if i > 0 {
+					if !strings.HasPrefix(line, " ") {
+						tt.Fatalf("continuation line %q doesn't start with a space", line)
+					}
+					unfolded += line[1:]
+				}
+			}
+			if unfolded != "SUMMARY:"+test.value {
+				tt.Fatalf("unfolded to %q", unfolded)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package calendar
+
+import (
+	"context"
+	"time"
+
+	db "github.com/joshibbotson/gym-tracker-backend/internal/db"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/calendar/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CalendarRepository interface {
+	ReplaceFeed(ctx context.Context, feed t.CalendarFeed) (*t.CalendarFeed, error)
+	FetchFeed(ctx context.Context, userID primitive.ObjectID) (*t.CalendarFeed, error)
+	FetchFeedByTokenHash(ctx context.Context, tokenHash string) (*t.CalendarFeed, error)
+	TouchFeed(ctx context.Context, feedID primitive.ObjectID, fetchedAt time.Time) error
+	RemoveFeed(ctx context.Context, userID primitive.ObjectID) (bool, error)
+}
+
+type calendarRepository struct {
+	feedCollection *mongo.Collection
+}
+
+func NewCalendarRepository() CalendarRepository {
+	return &calendarRepository{
+		feedCollection: db.Client.Database(db.DB_NAME).Collection("calendarFeed"),
+	}
+}
+
+// ReplaceFeed saves the user's feed in place of any they had, so the old
+// token stops working.
+func (r *calendarRepository) ReplaceFeed(ctx context.Context, feed t.CalendarFeed) (*t.CalendarFeed, error) {
+	opts := options.Replace().SetUpsert(true)
+	if _, err := r.feedCollection.ReplaceOne(ctx, bson.M{"userId": feed.UserId}, feed, opts); err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+func (r *calendarRepository) FetchFeed(ctx context.Context, userID primitive.ObjectID) (*t.CalendarFeed, error) {
+	return r.fetchFeed(ctx, bson.M{"userId": userID})
+}
+
+func (r *calendarRepository) FetchFeedByTokenHash(ctx context.Context, tokenHash string) (*t.CalendarFeed, error) {
+	return r.fetchFeed(ctx, bson.M{"tokenHash": tokenHash})
+}
+
+func (r *calendarRepository) fetchFeed(ctx context.Context, filter bson.M) (*t.CalendarFeed, error) {
+	var feed t.CalendarFeed
+	err := r.feedCollection.FindOne(ctx, filter).Decode(&feed)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &feed, nil
+}
+
+func (r *calendarRepository) TouchFeed(ctx context.Context, feedID primitive.ObjectID, fetchedAt time.Time) error {
+	_, err := r.feedCollection.UpdateByID(ctx, feedID, bson.M{"$set": bson.M{"lastFetchedAt": fetchedAt}})
+	return err
+}
+
+func (r *calendarRepository) RemoveFeed(ctx context.Context, userID primitive.ObjectID) (bool, error) {
+	result, err := r.feedCollection.DeleteOne(ctx, bson.M{"userId": userID})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
@@ -0,0 +1,141 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/calendar/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/calendar/types"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/template"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/user"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/workout"
+	util "github.com/joshibbotson/gym-tracker-backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CalendarService interface {
+	CreateFeed(userID primitive.ObjectID) (*t.CalendarFeed, error)
+	GetFeed(userID primitive.ObjectID) (*t.CalendarFeed, error)
+	DeleteFeed(userID primitive.ObjectID) (bool, error)
+	GetFeedCalendar(token string) (string, error)
+}
+
+var (
+	ErrFeedNotFound = errors.New("calendar feed not found")
+)
+
+type calendarService struct {
+	repo            CalendarRepository
+	workoutService  workout.WorkoutService
+	templateService template.TemplateService
+	userService     user.UserService
+}
+
+func NewCalendarService(repo CalendarRepository, workoutService workout.WorkoutService, templateService template.TemplateService, userService user.UserService) CalendarService {
+	return &calendarService{repo: repo, workoutService: workoutService, templateService: templateService, userService: userService}
+}
+
+// CreateFeed gives the user a new feed token, replacing the one they had.
+// The token is only returned here, it is stored hashed.
+func (s *calendarService) CreateFeed(userID primitive.ObjectID) (*t.CalendarFeed, error) {
+	token, err := util.NewToken(c.TokenBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.FetchFeed(context.TODO(), userID)
+	if err != nil {
+		return nil, err
+	}
+	feedID := primitive.NewObjectID()
+	if existing != nil {
+		feedID = existing.ID
+	}
+
+	feed, err := s.repo.ReplaceFeed(context.TODO(), t.CalendarFeed{
+		ID:        feedID,
+		UserId:    userID,
+		TokenHash: util.HashToken(token),
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	feed.Token = token
+	return feed, nil
+}
+
+func (s *calendarService) GetFeed(userID primitive.ObjectID) (*t.CalendarFeed, error) {
+	feed, err := s.repo.FetchFeed(context.TODO(), userID)
+	if err != nil {
+		return nil, err
+	}
+	if feed == nil {
+		return nil, ErrFeedNotFound
+	}
+	return feed, nil
+}
+
+func (s *calendarService) DeleteFeed(userID primitive.ObjectID) (bool, error) {
+	return s.repo.RemoveFeed(context.TODO(), userID)
+}
+
+// GetFeedCalendar builds the calendar for a feed token: the workouts logged
+// over the last FeedPastDays and the sessions the active routine has planned
+// for the next FeedUpcomingDays, skipping days already logged.
+func (s *calendarService) GetFeedCalendar(token string) (string, error) {
+	feed, err := s.repo.FetchFeedByTokenHash(context.TODO(), util.HashToken(token))
+	if err != nil {
+		return "", err
+	}
+	if feed == nil {
+		return "", ErrFeedNotFound
+	}
+
+	now := time.Now()
+	if feed.LastFetchedAt == nil || now.Sub(*feed.LastFetchedAt) > c.FeedTouchInterval {
+		if err := s.repo.TouchFeed(context.TODO(), feed.ID, now); err != nil {
+			return "", err
+		}
+	}
+
+	settings, err := s.userService.GetSettings(feed.UserId)
+	if err != nil {
+		return "", err
+	}
+	location, err := s.userService.GetLocation(feed.UserId)
+	if err != nil {
+		return "", err
+	}
+	today := util.CalendarDay(now, location)
+
+	workouts, err := s.workoutService.GetWorkoutsInRange(feed.UserId, today.AddDate(0, 0, -c.FeedPastDays), today.AddDate(0, 0, c.FeedUpcomingDays))
+	if err != nil {
+		return "", err
+	}
+	sessions, err := s.templateService.GetSchedule(feed.UserId, today, c.FeedUpcomingDays)
+	if err != nil {
+		return "", err
+	}
+
+	calendar := &icsWriter{}
+	calendar.begin()
+	logged := map[time.Time]bool{}
+	for _, entry := range workouts {
+		// days that only hold body measurements don't belong in a training calendar
+		if !workout.IsTraining(entry.Workout) {
+			continue
+		}
+		date := util.CalendarDay(entry.Date, location)
+		logged[date] = true
+		calendar.allDayEvent(entry.ID.Hex(), entry.UpdatedAt, date, workoutTitle(entry.Workout), workoutDescription(entry.Workout, settings.WeightUnit), "CONFIRMED")
+	}
+	for _, session := range sessions {
+		if session.Rest || logged[session.Date] {
+			continue
+		}
+		calendar.allDayEvent("scheduled-"+session.Date.Format(icsDate), now, session.Date, session.Template.Name, sessionDescription(session.Template, settings.WeightUnit), "TENTATIVE")
+	}
+	return calendar.end(), nil
+}
@@ -0,0 +1,27 @@
+package constants
+
+import "time"
+
+// TokenBytes is how much randomness goes into a feed token.
+const TokenBytes = 32
+
+// FeedPastDays and FeedUpcomingDays bound the events in a feed, calendar apps
+// fetch the whole feed on every refresh so it's kept to a sliding window.
+const (
+	FeedPastDays     = 365
+	FeedUpcomingDays = 28
+)
+
+// FeedRefreshInterval is how often calendar apps are asked to refetch, as
+// an RFC 5545 duration.
+const FeedRefreshInterval = "PT1H"
+
+// FeedTouchInterval is how stale a feed's LastFetchedAt can get before a
+// fetch updates it, so busy feeds don't write on every request.
+const FeedTouchInterval = time.Hour
+
+const (
+	ProductID    = "-//gym-tracker//workouts//EN"
+	CalendarName = "Workouts"
+	UIDDomain    = "gym-tracker"
+)
@@ -0,0 +1,20 @@
+package types
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CalendarFeed is a user's iCalendar subscription. Only a hash of the token
+// is kept, the token itself is handed out once when the feed is created and
+// a lost one is replaced by creating the feed again.
+type CalendarFeed struct {
+	ID            primitive.ObjectID `bson:"_id" json:"_id"`
+	UserId        primitive.ObjectID `bson:"userId" json:"-"`
+	TokenHash     string             `bson:"tokenHash" json:"-"`
+	Token         string             `bson:"-" json:"token,omitempty"`
+	URL           string             `bson:"-" json:"url,omitempty"`
+	CreatedAt     time.Time          `bson:"createdAt" json:"createdAt"`
+	LastFetchedAt *time.Time         `bson:"lastFetchedAt,omitempty" json:"lastFetchedAt,omitempty"`
+}
@@ -0,0 +1,26 @@
+package constants
+
+type ExportFormat string
+
+const (
+	FormatCSV   ExportFormat = "csv"
+	FormatJSONL ExportFormat = "jsonl"
+	FormatXLSX  ExportFormat = "xlsx"
+)
+
+func (f ExportFormat) Valid() bool {
+	_, ok := ContentTypes[f]
+	return ok
+}
+
+var ContentTypes = map[ExportFormat]string{
+	FormatCSV:   "text/csv; charset=utf-8",
+	FormatJSONL: "application/x-ndjson",
+	FormatXLSX:  "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// ListSeparator joins list fields such as target muscles into one column.
+const ListSeparator = ";"
+
+// SheetName is the name of the one sheet in an xlsx export.
+const SheetName = "Workouts"
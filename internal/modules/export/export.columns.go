@@ -0,0 +1,196 @@
+package export
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/export/constants"
+	wt "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+)
+
+// exportRow is one row of an export, a set of an exercise or the whole
+// workout when it has no exercises. Set is 1-indexed, 0 when there's no set.
+type exportRow struct {
+	workout  wt.Workout
+	exercise *wt.Exercise
+	set      int
+}
+
+// column values are nil when empty, otherwise a string, float64, int64 or bool.
+type column struct {
+	name  string
+	value func(row exportRow) any
+}
+
+// columns are worked out from WorkoutConfig so fields added to it later are
+// exported without touching this, nested structs such as cardio are
+// flattened into "cardio.distance" style names.
+var columns = buildColumns()
+
+func buildColumns() []column {
+	result := []column{
+		{"workoutId", func(row exportRow) any { return row.workout.ID.Hex() }},
+		{"date", func(row exportRow) any { return row.workout.Date.UTC().Format("2006-01-02") }},
+	}
+
+	result = append(result, structColumns("", reflect.TypeOf(wt.WorkoutConfig{}), nil, configSource)...)
+
+	result = append(result,
+		column{"exercise.name", func(row exportRow) any {
+			if row.exercise == nil {
+				return nil
+			}
+			return plainValue(reflect.ValueOf(row.exercise.Name))
+		}},
+		column{"exercise.primaryMuscles", func(row exportRow) any {
+			if row.exercise == nil {
+				return nil
+			}
+			return plainValue(reflect.ValueOf(row.exercise.PrimaryMuscles))
+		}},
+		column{"exercise.secondaryMuscles", func(row exportRow) any {
+			if row.exercise == nil {
+				return nil
+			}
+			return plainValue(reflect.ValueOf(row.exercise.SecondaryMuscles))
+		}},
+		column{"set.number", func(row exportRow) any {
+			if row.set == 0 {
+				return nil
+			}
+			return int64(row.set)
+		}},
+	)
+	result = append(result, structColumns("set.", reflect.TypeOf(wt.ExerciseSet{}), nil, setSource)...)
+
+	result = append(result,
+		column{"createdAt", func(row exportRow) any { return timestamp(row.workout.CreatedAt) }},
+		column{"updatedAt", func(row exportRow) any { return timestamp(row.workout.UpdatedAt) }},
+	)
+	return result
+}
+
+func configSource(row exportRow) (reflect.Value, bool) {
+	if row.workout.Workout == nil {
+		return reflect.Value{}, false
+	}
+	return reflect.ValueOf(*row.workout.Workout), true
+}
+
+// setSource leaves set columns empty on rows without a set.
+func setSource(row exportRow) (reflect.Value, bool) {
+	if row.exercise == nil || row.set == 0 {
+		return reflect.Value{}, false
+	}
+	return reflect.ValueOf(row.exercise.Sets[row.set-1]), true
+}
+
+// structColumns makes a column for each field of the struct source reads
+// from a row, apart from the exercises which get rows of their own.
+func structColumns(prefix string, structType reflect.Type, index []int, source func(exportRow) (reflect.Value, bool)) []column {
+	result := []column{}
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" || name == "exercises" {
+			continue
+		}
+		fieldIndex := append(append([]int{}, index...), i)
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType.Kind() == reflect.Struct && fieldType != reflect.TypeOf(time.Time{}) {
+			result = append(result, structColumns(prefix+name+".", fieldType, fieldIndex, source)...)
+			continue
+		}
+
+		result = append(result, column{prefix + name, func(row exportRow) any {
+			value, ok := source(row)
+			if !ok {
+				return nil
+			}
+			return fieldValue(value, fieldIndex)
+		}})
+	}
+	return result
+}
+
+func fieldValue(value reflect.Value, index []int) any {
+	field, err := value.FieldByIndexErr(index)
+	if err != nil {
+		return nil
+	}
+	return plainValue(field)
+}
+
+// plainValue turns a field into something every format can write, lists
+// are joined with ListSeparator.
+func plainValue(value reflect.Value) any {
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Slice:
+		if value.Len() == 0 {
+			return nil
+		}
+		items := make([]string, value.Len())
+		for i := range items {
+			items[i] = value.Index(i).String()
+		}
+		return strings.Join(items, c.ListSeparator)
+	case reflect.String:
+		if value.String() == "" {
+			return nil
+		}
+		return value.String()
+	case reflect.Float32, reflect.Float64:
+		return value.Float()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int()
+	case reflect.Bool:
+		return value.Bool()
+	case reflect.Struct:
+		if date, ok := value.Interface().(time.Time); ok {
+			return timestamp(date)
+		}
+	}
+	return nil
+}
+
+func timestamp(date time.Time) any {
+	if date.IsZero() {
+		return nil
+	}
+	return date.UTC().Format(time.RFC3339)
+}
+
+// rows calls each with the rows of a workout, one per set when it has
+// exercises and one for the whole workout when it doesn't.
+func rows(workout wt.Workout, each func(exportRow) error) error {
+	if workout.Workout == nil || len(workout.Workout.Exercises) == 0 {
+		return each(exportRow{workout: workout})
+	}
+	for i := range workout.Workout.Exercises {
+		exercise := &workout.Workout.Exercises[i]
+		if len(exercise.Sets) == 0 {
+			if err := each(exportRow{workout: workout, exercise: exercise}); err != nil {
+				return err
+			}
+			continue
+		}
+		for set := range exercise.Sets {
+			if err := each(exportRow{workout: workout, exercise: exercise, set: set + 1}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+	wt "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// collect renders the rows of a workout as "exercise#set", "-" for no exercise.
+func collect(workout wt.Workout) []string {
+	got := []string{}
+	rows(workout, func(row exportRow) error {
+		name := "-"
+		if row.exercise != nil {
+			name = row.exercise.Name
+		}
+		got = append(got, fmt.Sprintf("%s#%d", name, row.set))
+		return nil
+	})
+	return got
+}
+
+func TestRows(tt *testing.T) {
+	weight := 100.0
+	tests := []struct {
+		name    string
+		workout wt.Workout
+		want    string
+	}{
+		{"no config", wt.Workout{}, "-#0"},
+		{"body data only", wt.Workout{Workout: &wt.WorkoutConfig{Weight: &weight}}, "-#0"},
+		{"one row per set", wt.Workout{Workout: &wt.WorkoutConfig{Exercises: []wt.Exercise{
+			{Name: "Squat", Sets: []wt.ExerciseSet{{Reps: 5}, {Reps: 3}}},
+			{Name: "Plank"},
+			{Name: "Curl", Sets: []wt.ExerciseSet{{Reps: 10}}},
+		}}}, "Squat#1 Squat#2 Plank#0 Curl#1"},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			if got := strings.Join(collect(test.workout), " "); got != test.want {
+				tt.Fatalf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRowsStopsOnError(tt *testing.T) {
+	workout := wt.Workout{Workout: &wt.WorkoutConfig{Exercises: []wt.Exercise{{Name: "Squat", Sets: []wt.ExerciseSet{{Reps: 5}, {Reps: 3}}}}}}
+	stop := errors.New("stop")
+	calls := 0
+	err := rows(workout, func(row exportRow) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		tt.Fatalf("got %v after %d rows, want %v after 1", err, calls, stop)
+	}
+}
+
+// values renders a row through every column, keyed by column name.
+func values(row exportRow) map[string]any {
+	got := map[string]any{}
+	for _, column := range columns {
+		got[column.name] = column.value(row)
+	}
+	return got
+}
+
+func TestColumns(tt *testing.T) {
+	names := map[string]bool{}
+	for _, column := range columns {
+		if names[column.name] {
+			tt.Fatalf("column %q appears twice", column.name)
+		}
+		names[column.name] = true
+	}
+	for _, name := range []string{"workoutId", "date", "weight", "targetMuscles", "cardio.distance", "exercise.name", "set.number", "set.weight", "set.warmup", "createdAt"} {
+		if !names[name] {
+			tt.Fatalf("missing column %q", name)
+		}
+	}
+	if names["exercises"] || names["cardio"] {
+		tt.Fatal("exercises and cardio should not be columns of their own")
+	}
+
+	weight := 82.5
+	load := 100.0
+	distance := 5.2
+	workout := wt.Workout{
+		ID:   primitive.NewObjectID(),
+		Date: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		Workout: &wt.WorkoutConfig{
+			Weight:        &weight,
+			TargetMuscles: []wc.TargetMuscles{"legs", "core"},
+			Cardio:        &wt.CardioSession{Distance: &distance},
+			Exercises:     []wt.Exercise{{Name: "Squat", Sets: []wt.ExerciseSet{{Reps: 5, Weight: &load, Warmup: true}}}},
+		},
+	}
+
+	tests := []struct {
+		name string
+		row  exportRow
+		want map[string]any
+	}{
+		{"set row", exportRow{workout: workout, exercise: &workout.Workout.Exercises[0], set: 1}, map[string]any{
+			"workoutId":       workout.ID.Hex(),
+			"date":            "2026-03-01",
+			"weight":          weight,
+			"targetMuscles":   "legs;core",
+			"cardio.distance": distance,
+			"cardio.pace":     nil,
+			"exercise.name":   "Squat",
+			"set.number":      int64(1),
+			"set.reps":        int64(5),
+			"set.weight":      load,
+			"set.warmup":      true,
+			"createdAt":       nil,
+		}},
+		{"workout row", exportRow{workout: wt.Workout{ID: workout.ID, Date: workout.Date}}, map[string]any{
+			"weight":        nil,
+			"exercise.name": nil,
+			"set.number":    nil,
+			"set.reps":      nil,
+		}},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			got := values(test.row)
+			for name, want := range test.want {
+				if got[name] != want {
+					tt.Fatalf("%s = %#v, want %#v", name, got[name], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	m "github.com/joshibbotson/gym-tracker-backend/internal/middleware"
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/export/constants"
+	util "github.com/joshibbotson/gym-tracker-backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ExportHandler struct {
+	Service ExportService
+}
+
+func NewExportHandler(service ExportService) *ExportHandler {
+	return &ExportHandler{
+		Service: service,
+	}
+}
+
+// Handler serves /workout/export
+func (h *ExportHandler) Handler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	m.PermissionMiddleware(h.handleExportWorkouts)(w, r)
+}
+
+// handleExportWorkouts takes format (csv, jsonl or xlsx, csv by default) and
+// an optional from and to, everything is exported when they're left out.
+func (h *ExportHandler) handleExportWorkouts(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	format := c.ExportFormat(r.URL.Query().Get("format"))
+	if format == "" {
+		format = c.FormatCSV
+	}
+	if !format.Valid() {
+		http.Error(w, ErrInvalidFormat.Error(), http.StatusBadRequest)
+		return
+	}
+	from, to, ok := util.ParseDateRangeWithDefaults(w, r, time.Time{}, time.Now().AddDate(100, 0, 0))
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", c.ContentTypes[format])
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="workouts-%s.%s"`, time.Now().Format("2006-01-02"), format))
+	// the rows are streamed, so once they've started an error can only cut
+	// the file short
+	if err := h.Service.ExportWorkouts(userID, format, from, to, w); err != nil {
+		if errors.Is(err, ErrInvalidFormat) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		fmt.Println("error exporting workouts:", err)
+	}
+}
@@ -0,0 +1,62 @@
+package export
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/export/constants"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/workout"
+	wt "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ExportService interface {
+	ExportWorkouts(userID primitive.ObjectID, format c.ExportFormat, from time.Time, to time.Time, out io.Writer) error
+}
+
+var (
+	ErrInvalidFormat = errors.New("format must be csv, jsonl or xlsx")
+)
+
+type exportService struct {
+	workoutService workout.WorkoutService
+}
+
+func NewExportService(workoutService workout.WorkoutService) ExportService {
+	return &exportService{workoutService: workoutService}
+}
+
+// ExportWorkouts writes the workouts in the range to out as flat rows, one
+// per set or one per workout without exercises, as they come off the cursor.
+func (s *exportService) ExportWorkouts(userID primitive.ObjectID, format c.ExportFormat, from time.Time, to time.Time, out io.Writer) error {
+	if !format.Valid() {
+		return ErrInvalidFormat
+	}
+	writer, err := newRowWriter(format, out)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = column.name
+	}
+	if err := writer.WriteHeader(names); err != nil {
+		return err
+	}
+
+	values := make([]any, len(columns))
+	err = s.workoutService.StreamWorkouts(userID, from, to, func(logged wt.Workout) error {
+		return rows(logged, func(row exportRow) error {
+			for i, column := range columns {
+				values[i] = column.value(row)
+			}
+			return writer.WriteRow(values)
+		})
+	})
+	if err != nil {
+		return err
+	}
+	return writer.Close()
+}
@@ -0,0 +1,216 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/export/constants"
+)
+
+// rowWriter writes an export a row at a time, Close must be called to
+// finish the file.
+type rowWriter interface {
+	WriteHeader(names []string) error
+	WriteRow(values []any) error
+	Close() error
+}
+
+func newRowWriter(format c.ExportFormat, out io.Writer) (rowWriter, error) {
+	switch format {
+	case c.FormatCSV:
+		return &csvWriter{writer: csv.NewWriter(out)}, nil
+	case c.FormatJSONL:
+		return &jsonlWriter{writer: bufio.NewWriter(out)}, nil
+	case c.FormatXLSX:
+		return newXLSXWriter(out)
+	}
+	return nil, ErrInvalidFormat
+}
+
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case bool:
+		return strconv.FormatBool(v)
+	case string:
+		return v
+	}
+	return fmt.Sprint(value)
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+}
+
+func (w *csvWriter) WriteHeader(names []string) error {
+	return w.writer.Write(names)
+}
+
+func (w *csvWriter) WriteRow(values []any) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		record[i] = formatValue(value)
+	}
+	return w.writer.Write(record)
+}
+
+func (w *csvWriter) Close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// jsonlWriter writes each row as an object with its keys in column order,
+// leaving out empty columns.
+type jsonlWriter struct {
+	writer *bufio.Writer
+	names  [][]byte
+}
+
+func (w *jsonlWriter) WriteHeader(names []string) error {
+	for _, name := range names {
+		encoded, err := json.Marshal(name)
+		if err != nil {
+			return err
+		}
+		w.names = append(w.names, encoded)
+	}
+	return nil
+}
+
+func (w *jsonlWriter) WriteRow(values []any) error {
+	w.writer.WriteByte('{')
+	first := true
+	for i, value := range values {
+		if value == nil {
+			continue
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		if !first {
+			w.writer.WriteByte(',')
+		}
+		first = false
+		w.writer.Write(w.names[i])
+		w.writer.WriteByte(':')
+		w.writer.Write(encoded)
+	}
+	w.writer.WriteString("}\n")
+	return nil
+}
+
+func (w *jsonlWriter) Close() error {
+	return w.writer.Flush()
+}
+
+// xlsxWriter streams a single sheet workbook. The fixed parts of the package
+// go first so the sheet can be written straight through as the last entry,
+// with numbers as numeric cells and everything else as inline strings.
+type xlsxWriter struct {
+	archive *zip.Writer
+	sheet   *bufio.Writer
+	row     int
+}
+
+var xlsxParts = []struct {
+	name    string
+	content string
+}{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+	{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="` + c.SheetName + `" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`},
+}
+
+func newXLSXWriter(out io.Writer) (*xlsxWriter, error) {
+	archive := zip.NewWriter(out)
+	for _, part := range xlsxParts {
+		entry, err := archive.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(entry, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	entry, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	sheet := bufio.NewWriter(entry)
+	sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	return &xlsxWriter{archive: archive, sheet: sheet}, nil
+}
+
+func (w *xlsxWriter) WriteHeader(names []string) error {
+	values := make([]any, len(names))
+	for i, name := range names {
+		values[i] = name
+	}
+	return w.WriteRow(values)
+}
+
+func (w *xlsxWriter) WriteRow(values []any) error {
+	w.row++
+	fmt.Fprintf(w.sheet, `<row r="%d">`, w.row)
+	for i, value := range values {
+		if value == nil {
+			continue
+		}
+		cell := columnName(i) + strconv.Itoa(w.row)
+		switch v := value.(type) {
+		case float64, int64:
+			fmt.Fprintf(w.sheet, `<c r="%s"><v>%s</v></c>`, cell, formatValue(v))
+		case bool:
+			boolean := 0
+			if v {
+				boolean = 1
+			}
+			fmt.Fprintf(w.sheet, `<c r="%s" t="b"><v>%d</v></c>`, cell, boolean)
+		default:
+			fmt.Fprintf(w.sheet, `<c r="%s" t="inlineStr"><is><t>`, cell)
+			if err := xml.EscapeText(w.sheet, []byte(formatValue(v))); err != nil {
+				return err
+			}
+			w.sheet.WriteString(`</t></is></c>`)
+		}
+	}
+	_, err := w.sheet.WriteString(`</row>`)
+	return err
+}
+
+func (w *xlsxWriter) Close() error {
+	w.sheet.WriteString(`</sheetData></worksheet>`)
+	if err := w.sheet.Flush(); err != nil {
+		return err
+	}
+	return w.archive.Close()
+}
+
+// columnName turns a 0-indexed column into its spreadsheet letters, A to Z
+// then AA onwards.
+func columnName(index int) string {
+	name := ""
+	for index++; index > 0; index = (index - 1) / 26 {
+		name = string(rune('A'+(index-1)%26)) + name
+	}
+	return name
+}
@@ -0,0 +1,116 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/export/constants"
+)
+
+func TestColumnName(tt *testing.T) {
+	tests := []struct {
+		index int
+		want  string
+	}{
+		{0, "A"},
+		{25, "Z"},
+		{26, "AA"},
+		{27, "AB"},
+		{51, "AZ"},
+		{52, "BA"},
+		{701, "ZZ"},
+		{702, "AAA"},
+	}
+
+	for _, test := range tests {
+		if got := columnName(test.index); got != test.want {
+			tt.Fatalf("columnName(%d) = %q, want %q", test.index, got, test.want)
+		}
+	}
+}
+
+type xlsxSheet struct {
+	Rows []struct {
+		Number int `xml:"r,attr"`
+		Cells  []struct {
+			Ref    string `xml:"r,attr"`
+			Type   string `xml:"t,attr"`
+			Value  string `xml:"v"`
+			Inline string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func TestXLSXWriter(tt *testing.T) {
+	var out bytes.Buffer
+	writer, err := newRowWriter(c.FormatXLSX, &out)
+	if err != nil {
+		tt.Fatalf("unexpected error: %v", err)
+	}
+	if err := writer.WriteHeader([]string{"name", "weight", "reps", "warmup", "note"}); err != nil {
+		tt.Fatalf("unexpected error: %v", err)
+	}
+	if err := writer.WriteRow([]any{"Squat <& \"heavy\">", 102.5, int64(5), true, nil}); err != nil {
+		tt.Fatalf("unexpected error: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		tt.Fatalf("unexpected error: %v", err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	if err != nil {
+		tt.Fatalf("not a zip: %v", err)
+	}
+	entries := map[string]*zip.File{}
+	for _, file := range archive.File {
+		entries[file.Name] = file
+	}
+	for _, part := range xlsxParts {
+		if entries[part.name] == nil {
+			tt.Fatalf("missing %s", part.name)
+		}
+	}
+	if archive.File[len(archive.File)-1].Name != "xl/worksheets/sheet1.xml" {
+		tt.Fatal("expected the sheet to be the last entry")
+	}
+
+	entry, err := entries["xl/worksheets/sheet1.xml"].Open()
+	if err != nil {
+		tt.Fatalf("unexpected error: %v", err)
+	}
+	defer entry.Close()
+	content, err := io.ReadAll(entry)
+	if err != nil {
+		tt.Fatalf("unexpected error: %v", err)
+	}
+	var sheet xlsxSheet
+	if err := xml.Unmarshal(content, &sheet); err != nil {
+		tt.Fatalf("sheet isn't valid xml: %v", err)
+	}
+
+	if len(sheet.Rows) != 2 || sheet.Rows[0].Number != 1 || sheet.Rows[1].Number != 2 {
+		tt.Fatalf("rows = %+v, want rows 1 and 2", sheet.Rows)
+	}
+	if header := sheet.Rows[0].Cells; len(header) != 5 || header[4].Ref != "E1" || header[4].Inline != "note" {
+		tt.Fatalf("header = %+v, want 5 inline strings ending in E1 note", header)
+	}
+
+	cells := sheet.Rows[1].Cells
+	if len(cells) != 4 {
+		tt.Fatalf("cells = %+v, want the empty column left out", cells)
+	}
+	want := []struct{ ref, cellType, value, inline string }{
+		{"A2", "inlineStr", "", "Squat <& \"heavy\">"},
+		{"B2", "", "102.5", ""},
+		{"C2", "", "5", ""},
+		{"D2", "b", "1", ""},
+	}
+	for i, cell := range cells {
+		if cell.Ref != want[i].ref || cell.Type != want[i].cellType || cell.Value != want[i].value || cell.Inline != want[i].inline {
+			tt.Fatalf("cell %d = %+v, want %+v", i, cell, want[i])
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package constants
+
+import (
+	"strings"
+
+	wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+)
+
+type ActivityFormat string
+
+const (
+	FormatGPX ActivityFormat = "gpx"
+	FormatTCX ActivityFormat = "tcx"
+	FormatFIT ActivityFormat = "fit"
+	// FormatHealth marks workouts read from an Apple Health export, they
+	// come with totals but no samples.
+	FormatHealth ActivityFormat = "health"
+)
+
+// MaxUploadBytes caps the size of an uploaded file, a multi-hour ride
+// recorded every second is well under this.
+const MaxUploadBytes = 32 << 20
+
+// ElevationNoiseMetres is how far altitude has to climb from the last low
+// point before it counts as gain, so GPS jitter on the flat isn't added up.
+const ElevationNoiseMetres = 2.0
+
+// DefaultModality is used when the file doesn't say what the activity was.
+const DefaultModality = wc.CardioRun
+
+// FITSports maps the FIT sport enum to a cardio modality.
+var FITSports = map[uint64]wc.CardioModality{
+	1:  wc.CardioRun,
+	2:  wc.CardioBike,
+	5:  wc.CardioSwim,
+	11: wc.CardioWalk,
+	15: wc.CardioRow,
+	17: wc.CardioWalk, // hiking
+}
+
+// ModalityFromName maps the free text sport names used by GPX and TCX files,
+// e.g. "Running", "cycling" or "indoor_rowing".
+func ModalityFromName(name string) (wc.CardioModality, bool) {
+	name = strings.ToLower(name)
+	switch {
+	case strings.Contains(name, "run"):
+		return wc.CardioRun, true
+	case strings.Contains(name, "bik"), strings.Contains(name, "cycl"), strings.Contains(name, "ride"):
+		return wc.CardioBike, true
+	case strings.Contains(name, "swim"):
+		return wc.CardioSwim, true
+	case strings.Contains(name, "row"):
+		return wc.CardioRow, true
+	case strings.Contains(name, "walk"), strings.Contains(name, "hik"):
+		return wc.CardioWalk, true
+	}
+	return "", false
+}
@@ -0,0 +1,52 @@
+package constants
+
+type CSVFormat string
+
+const (
+	FormatStrong  CSVFormat = "strong"
+	FormatHevy    CSVFormat = "hevy"
+	FormatGeneric CSVFormat = "generic"
+)
+
+func (f CSVFormat) Valid() bool {
+	return f == FormatStrong || f == FormatHevy || f == FormatGeneric
+}
+
+// Columns of the generic CSV format, header names are matched ignoring case
+// and column order doesn't matter:
+//
+//	date      required, YYYY-MM-DD, "YYYY-MM-DD HH:MM:SS" or RFC3339
+//	exercise  required, the exercise name
+//	reps      required, rows without reps are skipped
+//	weight    optional, in the unit given with the upload (the user's unit by default)
+//	warmup    optional, true/yes/1 marks a warmup set
+//	session   optional, splits rows with the same date into separate workouts
+const (
+	GenericDate     = "date"
+	GenericExercise = "exercise"
+	GenericReps     = "reps"
+	GenericWeight   = "weight"
+	GenericWarmup   = "warmup"
+	GenericSession  = "session"
+)
+
+// CSVDateLayouts are tried in order for dates in Strong, Hevy and generic files.
+var CSVDateLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04:05Z07:00",
+	"2006-01-02",
+	"2 Jan 2006, 15:04",
+	"02 Jan 2006, 15:04",
+	"Jan 2, 2006, 3:04 PM",
+}
+
+const (
+	// MaxRowErrors caps the row errors reported back, a file in the wrong
+	// format would otherwise report every line.
+	MaxRowErrors = 50
+	// MaxSuggestions is how many catalog exercises are offered for a name
+	// that couldn't be matched.
+	MaxSuggestions    = 3
+	PoundsPerKilogram = 2.20462
+)
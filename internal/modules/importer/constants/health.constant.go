@@ -0,0 +1,79 @@
+package constants
+
+import (
+	"time"
+
+	wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+)
+
+type ImportStatus string
+
+const (
+	ImportPending   ImportStatus = "pending"
+	ImportReading   ImportStatus = "reading"
+	ImportSaving    ImportStatus = "saving"
+	ImportCompleted ImportStatus = "completed"
+	ImportFailed    ImportStatus = "failed"
+)
+
+func (s ImportStatus) Finished() bool {
+	return s == ImportCompleted || s == ImportFailed
+}
+
+// MaxHealthUploadBytes caps an Apple Health upload, exports with years of
+// heart rate samples run to a few hundred MB, or a fraction of that zipped.
+const MaxHealthUploadBytes = 2 << 30
+
+// HealthExportFile is the file inside export.zip that holds the data.
+const HealthExportFile = "export.xml"
+
+// HealthDateLayout is the date format used throughout export.xml.
+const HealthDateLayout = "2006-01-02 15:04:05 -0700"
+
+// HealthProgressEvery is how many elements are read between saving an
+// import's progress.
+const HealthProgressEvery = 50000
+
+// HealthSaveBatch is how many days of measurements, or workouts, are saved
+// between saving an import's progress.
+const HealthSaveBatch = 200
+
+// HealthStaleAfter is how long a running import can go without progress
+// before it is reported as failed, e.g. when the server restarted mid import.
+const HealthStaleAfter = 10 * time.Minute
+
+// MaxImportJobs is how many imports are listed, most recent first.
+const MaxImportJobs = 20
+
+// Record types read from an export, everything else is skipped.
+const (
+	HealthBodyMass = "HKQuantityTypeIdentifierBodyMass"
+	HealthBodyFat  = "HKQuantityTypeIdentifierBodyFatPercentage"
+	HealthWaist    = "HKQuantityTypeIdentifierWaistCircumference"
+)
+
+// Workout statistics read from an export, newer exports only carry workout
+// totals this way.
+const (
+	HealthStatHeartRate = "HKQuantityTypeIdentifierHeartRate"
+	HealthStatEnergy    = "HKQuantityTypeIdentifierActiveEnergyBurned"
+)
+
+// HealthWorkoutTypes maps the workout activity types that are cardio onto a
+// modality, other workouts such as strength training are skipped.
+var HealthWorkoutTypes = map[string]wc.CardioModality{
+	"HKWorkoutActivityTypeRunning":  wc.CardioRun,
+	"HKWorkoutActivityTypeWalking":  wc.CardioWalk,
+	"HKWorkoutActivityTypeHiking":   wc.CardioWalk,
+	"HKWorkoutActivityTypeCycling":  wc.CardioBike,
+	"HKWorkoutActivityTypeRowing":   wc.CardioRow,
+	"HKWorkoutActivityTypeSwimming": wc.CardioSwim,
+}
+
+// Unit conversions for the units Health writes lengths, distances and energy in.
+var (
+	CentimetresPer  = map[string]float64{"cm": 1, "mm": 0.1, "m": 100, "in": 2.54, "ft": 30.48}
+	KilometresPer   = map[string]float64{"km": 1, "m": 0.001, "mi": 1.609344, "yd": 0.0009144}
+	KilocaloriesPer = map[string]float64{"kcal": 1, "Cal": 1, "cal": 0.001, "kJ": 0.239006}
+	SecondsPer      = map[string]float64{"s": 1, "min": 60, "hr": 3600}
+)
@@ -0,0 +1,74 @@
+package constants
+
+type ScaleFormat string
+
+const (
+	ScaleGeneric  ScaleFormat = "generic"
+	ScaleWithings ScaleFormat = "withings"
+	ScaleRenpho   ScaleFormat = "renpho"
+)
+
+// ScaleFormats is the order formats are tried in when detecting one, the
+// format matching the most columns wins and ties go to the earliest.
+var ScaleFormats = []ScaleFormat{ScaleGeneric, ScaleWithings, ScaleRenpho}
+
+func (f ScaleFormat) Valid() bool {
+	_, ok := ScaleColumns[f]
+	return ok
+}
+
+type ScaleField string
+
+const (
+	ScaleDate       ScaleField = "date"
+	ScaleWeight     ScaleField = "weight"
+	ScaleBodyFat    ScaleField = "bodyFat"
+	ScaleMuscleMass ScaleField = "muscleMass"
+	ScaleBodyWater  ScaleField = "bodyWater"
+	ScaleBoneMass   ScaleField = "boneMass"
+)
+
+// ScaleColumns maps each format's column headers onto the fields they hold.
+// Headers are matched ignoring case, spaces, underscores and a unit in
+// brackets, so "Body Fat(%)", "body_fat" and "bodyFat" are the same column.
+// Body fat and water are read as a percentage unless the header gives a
+// weight unit, as Withings does, when they are worked out from the weight.
+//
+// The generic format has the columns date, weight, bodyFat, muscleMass,
+// bodyWater and boneMass, only date and one measurement are required.
+var ScaleColumns = map[ScaleFormat]map[ScaleField][]string{
+	ScaleGeneric: {
+		ScaleDate:       {"date"},
+		ScaleWeight:     {"weight"},
+		ScaleBodyFat:    {"bodyfat"},
+		ScaleMuscleMass: {"musclemass"},
+		ScaleBodyWater:  {"bodywater"},
+		ScaleBoneMass:   {"bonemass"},
+	},
+	ScaleWithings: {
+		ScaleDate:       {"date"},
+		ScaleWeight:     {"weight"},
+		ScaleBodyFat:    {"fatmass", "fatratio"},
+		ScaleMuscleMass: {"musclemass"},
+		ScaleBodyWater:  {"hydration"},
+		ScaleBoneMass:   {"bonemass"},
+	},
+	ScaleRenpho: {
+		ScaleDate:       {"timeofmeasurement", "date"},
+		ScaleWeight:     {"weight"},
+		ScaleBodyFat:    {"bodyfat"},
+		ScaleMuscleMass: {"musclemass"},
+		ScaleBodyWater:  {"bodywater"},
+		ScaleBoneMass:   {"bonemass"},
+	},
+}
+
+// ScaleDateLayouts are tried in order for dates in scale files, after the
+// layouts workout CSVs use.
+var ScaleDateLayouts = append(append([]string{}, CSVDateLayouts...),
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04",
+	"2006.01.02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006/01/02",
+)
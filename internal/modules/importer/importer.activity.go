@@ -0,0 +1,235 @@
+package importer
+
+import (
+	"bytes"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/importer/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/importer/types"
+	wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+)
+
+const earthRadiusMetres = 6371000
+
+// detectFormat goes by the file's contents first and only falls back to the
+// extension, watches aren't consistent about naming.
+func detectFormat(fileName string, data []byte) (c.ActivityFormat, bool) {
+	if len(data) >= 12 && string(data[8:12]) == ".FIT" {
+		return c.FormatFIT, true
+	}
+	head := data
+	if len(head) > 1024 {
+		head = head[:1024]
+	}
+	if bytes.Contains(head, []byte("<gpx")) {
+		return c.FormatGPX, true
+	}
+	if bytes.Contains(head, []byte("<TrainingCenterDatabase")) {
+		return c.FormatTCX, true
+	}
+
+	switch c.ActivityFormat(strings.TrimPrefix(strings.ToLower(filepath.Ext(fileName)), ".")) {
+	case c.FormatGPX:
+		return c.FormatGPX, true
+	case c.FormatTCX:
+		return c.FormatTCX, true
+	case c.FormatFIT:
+		return c.FormatFIT, true
+	}
+	return "", false
+}
+
+func parseActivity(fileName string, data []byte) (*t.Activity, error) {
+	format, ok := detectFormat(fileName, data)
+	if !ok {
+		return nil, ErrUnsupportedFormat
+	}
+
+	var activity *t.Activity
+	var err error
+	switch format {
+	case c.FormatGPX:
+		activity, err = parseGPX(data)
+	case c.FormatTCX:
+		activity, err = parseTCX(data)
+	case c.FormatFIT:
+		activity, err = parseFIT(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	summarise(activity)
+	if activity.StartTime.IsZero() || activity.Duration <= 0 {
+		return nil, ErrEmptyActivity
+	}
+	return activity, nil
+}
+
+// summarise fills in the totals the file didn't carry from its laps, and
+// failing that from its points.
+func summarise(activity *t.Activity) {
+	points := activity.Points
+	sort.SliceStable(points, func(i, j int) bool { return points[i].Time.Before(points[j].Time) })
+
+	if activity.Modality == "" {
+		activity.Modality = c.DefaultModality
+	}
+
+	if activity.StartTime.IsZero() {
+		if len(activity.Laps) > 0 && !activity.Laps[0].StartTime.IsZero() {
+			activity.StartTime = activity.Laps[0].StartTime
+		} else if len(points) > 0 {
+			activity.StartTime = points[0].Time
+		}
+	}
+
+	var lapDuration int
+	var lapDistance, lapCalories float64
+	hasLapCalories := false
+	for _, lap := range activity.Laps {
+		lapDuration += lap.Duration
+		lapDistance += lap.Distance
+		if lap.Calories != nil {
+			lapCalories += *lap.Calories
+			hasLapCalories = true
+		}
+	}
+
+	if activity.Duration <= 0 {
+		activity.Duration = lapDuration
+	}
+	if activity.Duration <= 0 && len(points) > 1 {
+		activity.Duration = int(points[len(points)-1].Time.Sub(points[0].Time).Seconds())
+	}
+
+	if activity.Distance <= 0 {
+		activity.Distance = lapDistance
+	}
+	if activity.Distance <= 0 {
+		activity.Distance = lastDistance(points) / 1000
+	}
+	activity.Distance = math.Round(activity.Distance*100) / 100
+
+	if activity.Calories == nil && hasLapCalories {
+		activity.Calories = &lapCalories
+	}
+	if activity.ElevationGain == nil {
+		activity.ElevationGain = elevationGain(points)
+	}
+
+	average, maximum := heartRateStats(points)
+	if activity.AvgHeartRate == nil {
+		activity.AvgHeartRate = average
+	}
+	if activity.MaxHeartRate == nil {
+		activity.MaxHeartRate = maximum
+	}
+}
+
+// withCumulativeDistance sets the distance of each point from the positions
+// before it, for formats that only record positions.
+func withCumulativeDistance(points []t.TrackPoint) {
+	var total float64
+	var previous *t.TrackPoint
+	for i := range points {
+		point := &points[i]
+		if point.Latitude == nil || point.Longitude == nil {
+			continue
+		}
+		if previous != nil {
+			total += haversine(*previous.Latitude, *previous.Longitude, *point.Latitude, *point.Longitude)
+		}
+		distance := total
+		point.Distance = &distance
+		previous = point
+	}
+}
+
+func lapFromPoints(points []t.TrackPoint) t.Lap {
+	lap := t.Lap{StartTime: points[0].Time}
+	lap.Duration = int(points[len(points)-1].Time.Sub(points[0].Time).Seconds())
+
+	var first, last *float64
+	for i := range points {
+		if points[i].Distance == nil {
+			continue
+		}
+		if first == nil {
+			first = points[i].Distance
+		}
+		last = points[i].Distance
+	}
+	if first != nil {
+		lap.Distance = math.Round((*last-*first)/10) / 100
+	}
+	lap.AvgHeartRate, lap.MaxHeartRate = heartRateStats(points)
+	return lap
+}
+
+func lastDistance(points []t.TrackPoint) float64 {
+	for i := len(points) - 1; i >= 0; i-- {
+		if points[i].Distance != nil {
+			return *points[i].Distance
+		}
+	}
+	return 0
+}
+
+// elevationGain adds up climbs, only counting a climb once it has gone
+// ElevationNoiseMetres above the lowest point since the last one counted.
+func elevationGain(points []t.TrackPoint) *float64 {
+	var gain float64
+	var reference *float64
+	for _, point := range points {
+		if point.Elevation == nil {
+			continue
+		}
+		elevation := *point.Elevation
+		if reference == nil || elevation < *reference {
+			reference = &elevation
+			continue
+		}
+		if elevation-*reference >= c.ElevationNoiseMetres {
+			gain += elevation - *reference
+			reference = &elevation
+		}
+	}
+	if reference == nil {
+		return nil
+	}
+	gain = math.Round(gain)
+	return &gain
+}
+
+// heartRateStats averages the heart rate samples, leaving out readings a
+// workout couldn't be saved with since they're sensor dropouts or spikes.
+func heartRateStats(points []t.TrackPoint) (*int, *int) {
+	var sum, count, maximum int
+	for _, point := range points {
+		if point.HeartRate == nil || *point.HeartRate <= 0 || *point.HeartRate > wc.MaxHeartRate {
+			continue
+		}
+		sum += *point.HeartRate
+		count++
+		maximum = max(maximum, *point.HeartRate)
+	}
+	if count == 0 {
+		return nil, nil
+	}
+	average := int(math.Round(float64(sum) / float64(count)))
+	return &average, &maximum
+}
+
+// haversine is the great-circle distance between two positions in metres.
+func haversine(lat1, lon1, lat2, lon2 float64) float64 {
+	toRadians := func(degrees float64) float64 { return degrees * math.Pi / 180 }
+	dLat := toRadians(lat2 - lat1)
+	dLon := toRadians(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusMetres * math.Asin(math.Sqrt(a))
+}
@@ -0,0 +1,198 @@
+package importer
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/importer/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/importer/types"
+	wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+)
+
+const gpxFixture = `<?xml version="1.0" encoding="UTF-8"?>
+<gpx version="1.1" creator="test" xmlns:gpxtpx="http://www.garmin.com/xmlschemas/TrackPointExtension/v1">
+  <trk>
+    <type>running</type>
+    <trkseg>
+      <trkpt lat="51.500" lon="-0.100"><ele>10</ele><time>2026-03-01T08:00:00Z</time>
+        <extensions><gpxtpx:TrackPointExtension><gpxtpx:hr>140</gpxtpx:hr></gpxtpx:TrackPointExtension></extensions></trkpt>
+      <trkpt lat="51.501" lon="-0.100"><ele>15</ele><time>2026-03-01T08:01:00Z</time>
+        <extensions><gpxtpx:TrackPointExtension><gpxtpx:hr>150</gpxtpx:hr></gpxtpx:TrackPointExtension></extensions></trkpt>
+      <trkpt lat="51.502" lon="-0.100"><ele>14</ele><time>2026-03-01T08:02:00Z</time>
+        <extensions><gpxtpx:TrackPointExtension><gpxtpx:hr>300</gpxtpx:hr></gpxtpx:TrackPointExtension></extensions></trkpt>
+    </trkseg>
+  </trk>
+</gpx>`
+
+const tcxFixture = `<?xml version="1.0" encoding="UTF-8"?>
+<TrainingCenterDatabase xmlns="http://www.garmin.com/xmlschemas/TrainingCenterDatabase/v2">
+  <Activities>
+    <Activity Sport="Biking">
+      <Id>2026-03-02T17:30:00Z</Id>
+      <Lap StartTime="2026-03-02T17:30:00Z">
+        <TotalTimeSeconds>600</TotalTimeSeconds>
+        <DistanceMeters>5000</DistanceMeters>
+        <Calories>100</Calories>
+        <Track>
+          <Trackpoint><Time>2026-03-02T17:30:00Z</Time><HeartRateBpm><Value>0</Value></HeartRateBpm></Trackpoint>
+          <Trackpoint><Time>2026-03-02T17:40:00Z</Time><HeartRateBpm><Value>130</Value></HeartRateBpm></Trackpoint>
+        </Track>
+      </Lap>
+    </Activity>
+  </Activities>
+</TrainingCenterDatabase>`
+
+// fitRecordSample is a record message: a timestamp in seconds since the FIT
+// epoch, heart rate and cumulative distance in centimetres.
+type fitRecordSample struct {
+	timestamp uint32
+	heartRate byte
+	distance  uint32
+}
+
+// fitFixture encodes a FIT file with one record definition and a data message
+// per sample.
+func fitFixture(samples ...fitRecordSample) []byte {
+	body := []byte{
+		0x40, 0, 0, fitMessageRecord, 0, 3, // definition of local message 0, little endian
+		fitFieldTimestamp, 4, 0x86,
+		3, 1, 0x02,
+		5, 4, 0x86,
+	}
+	for _, sample := range samples {
+		body = append(body, 0x00)
+		body = binary.LittleEndian.AppendUint32(body, sample.timestamp)
+		body = append(body, sample.heartRate)
+		body = binary.LittleEndian.AppendUint32(body, sample.distance)
+	}
+
+	header := []byte{12, 0x10, 0, 0, 0, 0, 0, 0, '.', 'F', 'I', 'T'}
+	binary.LittleEndian.PutUint32(header[4:8], uint32(len(body)))
+	return append(header, body...)
+}
+
+var fitSamples = []fitRecordSample{
+	{timestamp: 1_100_000_000, heartRate: 120, distance: 0},
+	{timestamp: 1_100_000_300, heartRate: 0xFF, distance: 100_000}, // 0xFF is FIT's "no value"
+	{timestamp: 1_100_000_600, heartRate: 140, distance: 200_000},
+}
+
+func TestParseActivity(tt *testing.T) {
+	tests := []struct {
+		name         string
+		fileName     string
+		data         []byte
+		wantFormat   c.ActivityFormat
+		wantModality wc.CardioModality
+		wantDuration int
+		wantDistance float64
+		wantAvgHR    int
+		wantMaxHR    int
+		wantPoints   int
+	}{
+		{"gpx drops the heart rate spike", "morning.gpx", []byte(gpxFixture), c.FormatGPX, wc.CardioRun, 120, 0.22, 145, 150, 3},
+		{"tcx uses the lap totals", "ride.tcx", []byte(tcxFixture), c.FormatTCX, wc.CardioBike, 600, 5, 130, 130, 2},
+		{"fit is detected without an extension", "upload", fitFixture(fitSamples...), c.FormatFIT, wc.CardioRun, 600, 2, 130, 140, 3},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			activity, err := parseActivity(test.fileName, test.data)
+			if err != nil {
+				tt.Fatalf("unexpected error: %v", err)
+			}
+			if activity.Format != test.wantFormat || activity.Modality != test.wantModality {
+				tt.Fatalf("got %s %s, want %s %s", activity.Format, activity.Modality, test.wantFormat, test.wantModality)
+			}
+			if activity.Duration != test.wantDuration || activity.Distance != test.wantDistance {
+				tt.Fatalf("got %ds %vkm, want %ds %vkm", activity.Duration, activity.Distance, test.wantDuration, test.wantDistance)
+			}
+			if activity.AvgHeartRate == nil || *activity.AvgHeartRate != test.wantAvgHR || *activity.MaxHeartRate != test.wantMaxHR {
+				tt.Fatalf("got heart rate %v/%v, want %d/%d", deref(activity.AvgHeartRate), deref(activity.MaxHeartRate), test.wantAvgHR, test.wantMaxHR)
+			}
+			if len(activity.Points) != test.wantPoints {
+				tt.Fatalf("got %d points, want %d", len(activity.Points), test.wantPoints)
+			}
+		})
+	}
+}
+
+func TestParseActivityRejects(tt *testing.T) {
+	fit := fitFixture(fitSamples...)
+	// claims one more byte of data than the file holds
+	cutMessage := fitFixture(fitSamples...)
+	cutMessage = cutMessage[:len(cutMessage)-1]
+	binary.LittleEndian.PutUint32(cutMessage[4:8], uint32(len(cutMessage)-12))
+	// a data message for a local message that was never defined
+	undefined := append(fitFixture(), 0x01, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(undefined[4:8], uint32(len(undefined)-12))
+
+	tests := []struct {
+		name     string
+		fileName string
+		data     []byte
+		want     error
+	}{
+		{"garbage", "notes.txt", []byte("not an activity"), ErrUnsupportedFormat},
+		{"empty gpx", "run.gpx", nil, ErrInvalidFile},
+		{"garbage gpx", "run.gpx", []byte{0xde, 0xad, 0xbe, 0xef}, ErrInvalidFile},
+		{"truncated gpx", "run.gpx", []byte(gpxFixture[:len(gpxFixture)/2]), ErrInvalidFile},
+		{"gpx without points", "run.gpx", []byte(`<gpx><trk><trkseg></trkseg></trk></gpx>`), ErrEmptyActivity},
+		{"truncated tcx", "ride.tcx", []byte(tcxFixture[:len(tcxFixture)/2]), ErrInvalidFile},
+		{"tcx without activities", "ride.tcx", []byte(`<TrainingCenterDatabase></TrainingCenterDatabase>`), ErrEmptyActivity},
+		{"fit shorter than its header", "ride.fit", fit[:10], ErrInvalidFile},
+		{"fit shorter than its data size", "ride.fit", fit[:len(fit)/2], ErrInvalidFile},
+		{"fit with a cut off message", "ride.fit", cutMessage, ErrInvalidFile},
+		{"fit with an undefined message", "ride.fit", undefined, ErrInvalidFile},
+		{"fit without records", "ride.fit", fitFixture(), ErrEmptyActivity},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			_, err := parseActivity(test.fileName, test.data)
+			if !errors.Is(err, test.want) {
+				tt.Fatalf("got %v, want %v", err, test.want)
+			}
+		})
+	}
+}
+
+func TestHeartRateStats(tt *testing.T) {
+	samples := func(rates ...int) []t.TrackPoint {
+		points := make([]t.TrackPoint, len(rates))
+		for i := range rates {
+			points[i].HeartRate = &rates[i]
+		}
+		return points
+	}
+
+	tests := []struct {
+		name    string
+		points  []t.TrackPoint
+		wantAvg interface{}
+		wantMax interface{}
+	}{
+		{"averages the samples", samples(100, 121), 111, 121},
+		{"dropouts and spikes are left out", samples(0, 100, 120, wc.MaxHeartRate+1), 110, 120},
+		{"the limit itself counts", samples(wc.MaxHeartRate), wc.MaxHeartRate, wc.MaxHeartRate},
+		{"nothing usable", samples(-1, 0, 999), nil, nil},
+		{"no samples", []t.TrackPoint{{}}, nil, nil},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			average, maximum := heartRateStats(test.points)
+			if deref(average) != test.wantAvg || deref(maximum) != test.wantMax {
+				tt.Fatalf("got %v/%v, want %v/%v", deref(average), deref(maximum), test.wantAvg, test.wantMax)
+			}
+		})
+	}
+}
+
+func deref(value *int) interface{} {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
@@ -0,0 +1,230 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/importer/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/importer/types"
+	uc "github.com/joshibbotson/gym-tracker-backend/internal/modules/user/constants"
+)
+
+// csvRow is one set read from a file, Weight is still in Unit.
+type csvRow struct {
+	Line     int
+	Start    time.Time
+	Session  string
+	Exercise string
+	Reps     int
+	Weight   *float64
+	Unit     uc.WeightUnit
+	Warmup   bool
+}
+
+// csvTable is a file split into its header, keyed by lower case column name,
+// and its rows.
+type csvTable struct {
+	columns map[string]int
+	rows    [][]string
+}
+
+func (table csvTable) has(column string) bool {
+	_, ok := table.columns[column]
+	return ok
+}
+
+func (table csvTable) value(row []string, column string) string {
+	i, ok := table.columns[column]
+	if !ok || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+// readCSV accepts comma or semicolon separated files, Strong uses semicolons
+// in locales that write decimals with a comma.
+func readCSV(data []byte) (*csvTable, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	firstLine := data
+	if end := bytes.IndexByte(data, '\n'); end >= 0 {
+		firstLine = data[:end]
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, ErrInvalidFile
+	}
+	table := &csvTable{columns: map[string]int{}, rows: [][]string{}}
+	for i, name := range header {
+		table.columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, ErrInvalidFile
+		}
+		table.rows = append(table.rows, row)
+	}
+	return table, nil
+}
+
+func detectCSVFormat(table *csvTable) (c.CSVFormat, bool) {
+	switch {
+	case table.has("exercise_title") && table.has("start_time"):
+		return c.FormatHevy, true
+	case table.has("exercise name") && table.has("set order"):
+		return c.FormatStrong, true
+	case table.has(c.GenericDate) && table.has(c.GenericExercise) && table.has(c.GenericReps):
+		return c.FormatGeneric, true
+	}
+	return "", false
+}
+
+// readRows turns the table into sets, rows without reps (rest timers, cardio
+// and notes rows) are skipped and rows that can't be read are reported.
+func readRows(table *csvTable, format c.CSVFormat, unit uc.WeightUnit) ([]csvRow, int, []t.RowError) {
+	rows := []csvRow{}
+	skipped := 0
+	rowErrors := []t.RowError{}
+	reject := func(line int, message string) {
+		skipped++
+		if len(rowErrors) < c.MaxRowErrors {
+			rowErrors = append(rowErrors, t.RowError{Line: line, Message: message})
+		}
+	}
+
+	for i, values := range table.rows {
+		line := i + 2 // the header is line 1
+		row, ok, err := readRow(table, format, unit, values)
+		if err != nil {
+			reject(line, err.Error())
+			continue
+		}
+		if !ok {
+			skipped++
+			continue
+		}
+		row.Line = line
+		rows = append(rows, row)
+	}
+	return rows, skipped, rowErrors
+}
+
+func readRow(table *csvTable, format c.CSVFormat, unit uc.WeightUnit, values []string) (csvRow, bool, error) {
+	var row csvRow
+	var date, reps, weight string
+
+	switch format {
+	case c.FormatStrong:
+		date = table.value(values, "date")
+		row.Session = table.value(values, "workout name")
+		row.Exercise = table.value(values, "exercise name")
+		reps = table.value(values, "reps")
+		weight = table.value(values, "weight")
+		row.Warmup = strings.EqualFold(table.value(values, "set order"), "W")
+		row.Unit = unit
+		if strings.HasPrefix(strings.ToLower(table.value(values, "weight unit")), "lb") {
+			row.Unit = uc.WeightUnitLb
+		} else if strings.EqualFold(table.value(values, "weight unit"), "kg") {
+			row.Unit = uc.WeightUnitKg
+		}
+	case c.FormatHevy:
+		date = table.value(values, "start_time")
+		row.Session = table.value(values, "title")
+		row.Exercise = table.value(values, "exercise_title")
+		reps = table.value(values, "reps")
+		row.Warmup = strings.EqualFold(table.value(values, "set_type"), "warmup")
+		row.Unit = uc.WeightUnitKg
+		weight = table.value(values, "weight_kg")
+		if table.has("weight_lbs") {
+			row.Unit = uc.WeightUnitLb
+			weight = table.value(values, "weight_lbs")
+		}
+	default:
+		date = table.value(values, c.GenericDate)
+		row.Session = table.value(values, c.GenericSession)
+		row.Exercise = table.value(values, c.GenericExercise)
+		reps = table.value(values, c.GenericReps)
+		weight = table.value(values, c.GenericWeight)
+		switch strings.ToLower(table.value(values, c.GenericWarmup)) {
+		case "true", "yes", "y", "1":
+			row.Warmup = true
+		}
+		row.Unit = unit
+	}
+
+	if reps == "" || row.Exercise == "" {
+		return row, false, nil
+	}
+	parsedReps, err := parseNumber(reps)
+	if err != nil {
+		return row, false, fmt.Errorf("invalid reps %q", reps)
+	}
+	if parsedReps <= 0 {
+		return row, false, nil
+	}
+	row.Reps = int(math.Round(parsedReps))
+
+	if weight != "" {
+		parsedWeight, err := parseNumber(weight)
+		if err != nil || parsedWeight < 0 {
+			return row, false, fmt.Errorf("invalid weight %q", weight)
+		}
+		if parsedWeight > 0 {
+			row.Weight = &parsedWeight
+		}
+	}
+
+	row.Start, err = parseCSVDate(date, c.CSVDateLayouts)
+	if err != nil {
+		return row, false, fmt.Errorf("invalid date %q", date)
+	}
+	return row, true, nil
+}
+
+func parseCSVDate(value string, layouts []string) (time.Time, error) {
+	for _, layout := range layouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unknown date format")
+}
+
+// parseNumber accepts a decimal comma as well as a decimal point, but not
+// NaN or infinity which ParseFloat would otherwise let through.
+func parseNumber(value string) (float64, error) {
+	number, err := strconv.ParseFloat(strings.ReplaceAll(value, ",", "."), 64)
+	if err == nil && (math.IsNaN(number) || math.IsInf(number, 0)) {
+		return 0, fmt.Errorf("%q is not a number", value)
+	}
+	return number, err
+}
+
+// convertWeight converts between kg and lb, rounded to two places.
+func convertWeight(weight float64, from uc.WeightUnit, to uc.WeightUnit) float64 {
+	switch {
+	case from == uc.WeightUnitKg && to == uc.WeightUnitLb:
+		weight *= c.PoundsPerKilogram
+	case from == uc.WeightUnitLb && to == uc.WeightUnitKg:
+		weight /= c.PoundsPerKilogram
+	}
+	return math.Round(weight*100) / 100
+}
@@ -0,0 +1,138 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/importer/constants"
+	uc "github.com/joshibbotson/gym-tracker-backend/internal/modules/user/constants"
+)
+
+const strongFixture = "\xef\xbb\xbfDate;Workout Name;Exercise Name;Set Order;Weight;Weight Unit;Reps\n" +
+	"2026-03-01 07:30:00;Push;Bench Press;W;40;kg;10\n" +
+	"2026-03-01 07:30:00;Push;Bench Press;1;82,5;kg;5\n" +
+	"2026-03-01 07:30:00;Push;Rest Timer;;;;\n"
+
+const hevyFixture = "title,start_time,exercise_title,set_type,weight_lbs,reps\n" +
+	"Legs,\"2 Mar 2026, 18:00\",Squat,warmup,135,5\n" +
+	"Legs,\"2 Mar 2026, 18:00\",Squat,normal,225,5\n"
+
+const genericFixture = "Exercise,Date,Reps,Weight,Warmup\n" +
+	"Pull Up,2026-03-03,8,,no\n" +
+	"Deadlift,2026-03-03,five,140,\n" +
+	"Deadlift,03/03/2026,5,140,\n" +
+	"Deadlift,2026-03-03,5,-1,\n" +
+	"Deadlift,2026-03-03,NaN,140,\n" +
+	"Deadlift,2026-03-03,5,Inf,\n" +
+	"Plank,2026-03-03,0,,\n"
+
+// describe renders rows as "exercise reps@weight unit" with a w for warm-ups.
+func describe(rows []csvRow) []string {
+	lines := make([]string, len(rows))
+	for i, row := range rows {
+		weight := "bw"
+		if row.Weight != nil {
+			weight = fmt.Sprint(*row.Weight)
+		}
+		lines[i] = fmt.Sprintf("%s %d@%s %s", row.Exercise, row.Reps, weight, row.Unit)
+		if row.Warmup {
+			lines[i] += " w"
+		}
+	}
+	return lines
+}
+
+func TestReadRows(tt *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		wantFormat  c.CSVFormat
+		want        []string
+		wantSkipped int
+		wantErrors  []int
+	}{
+		{
+			name:        "strong with semicolons and decimal commas",
+			data:        strongFixture,
+			wantFormat:  c.FormatStrong,
+			want:        []string{"Bench Press 10@40 kg w", "Bench Press 5@82.5 kg"},
+			wantSkipped: 1,
+		},
+		{
+			name:       "hevy in pounds",
+			data:       hevyFixture,
+			wantFormat: c.FormatHevy,
+			want:       []string{"Squat 5@135 lb w", "Squat 5@225 lb"},
+		},
+		{
+			name:        "generic reports the rows it can't read",
+			data:        genericFixture,
+			wantFormat:  c.FormatGeneric,
+			want:        []string{"Pull Up 8@bw kg"},
+			wantSkipped: 6,
+			wantErrors:  []int{3, 4, 5, 6, 7},
+		},
+		{
+			name:        "truncated mid row",
+			data:        hevyFixture[:len(hevyFixture)-20],
+			wantFormat:  c.FormatHevy,
+			want:        []string{"Squat 5@135 lb w"},
+			wantSkipped: 1,
+		},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			table, err := readCSV([]byte(test.data))
+			if err != nil {
+				tt.Fatalf("unexpected error: %v", err)
+			}
+			format, ok := detectCSVFormat(table)
+			if !ok || format != test.wantFormat {
+				tt.Fatalf("detected %q, want %q", format, test.wantFormat)
+			}
+
+			rows, skipped, rowErrors := readRows(table, format, uc.WeightUnitKg)
+			if fmt.Sprint(describe(rows)) != fmt.Sprint(test.want) {
+				tt.Fatalf("got rows %v, want %v", describe(rows), test.want)
+			}
+			if skipped != test.wantSkipped {
+				tt.Fatalf("skipped %d, want %d", skipped, test.wantSkipped)
+			}
+			lines := []int{}
+			for _, rowError := range rowErrors {
+				lines = append(lines, rowError.Line)
+			}
+			if fmt.Sprint(lines) != fmt.Sprint(append([]int{}, test.wantErrors...)) {
+				tt.Fatalf("errors on lines %v, want %v", lines, test.wantErrors)
+			}
+		})
+	}
+}
+
+func TestReadCSVRejects(tt *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want error
+	}{
+		{"empty", "", ErrInvalidFile},
+		{"binary", "\x00\x01\x02", nil},
+		{"unknown columns", "when,what\n2026-03-01,run\n", nil},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			table, err := readCSV([]byte(test.data))
+			if !errors.Is(err, test.want) {
+				tt.Fatalf("got %v, want %v", err, test.want)
+			}
+			if err == nil {
+				if format, ok := detectCSVFormat(table); ok {
+					tt.Fatalf("detected %q in a file that isn't a workout export", format)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,348 @@
+package importer
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/importer/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/importer/types"
+)
+
+// A minimal FIT decoder: it walks the definition and data messages and keeps
+// the fields of the session, lap and record messages that we store. Anything
+// else, including developer fields, is skipped over.
+
+const (
+	fitMessageSession = 18
+	fitMessageLap     = 19
+	fitMessageRecord  = 20
+
+	fitFieldTimestamp = 253
+)
+
+// fitEpoch is the zero of FIT timestamps.
+var fitEpoch = time.Date(1989, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+type fitFieldDefinition struct {
+	number   byte
+	size     int
+	baseType byte
+}
+
+type fitDefinition struct {
+	global    uint16
+	bigEndian bool
+	fields    []fitFieldDefinition
+	devBytes  int
+}
+
+// fitIntegerSizes are the byte sizes of the integer base types by base type number.
+var fitIntegerSizes = map[byte]int{
+	0x00: 1, 0x01: 1, 0x02: 1, 0x0A: 1, 0x0D: 1,
+	0x03: 2, 0x04: 2, 0x0B: 2,
+	0x05: 4, 0x06: 4, 0x0C: 4,
+	0x0E: 8, 0x0F: 8, 0x10: 8,
+}
+
+// fitMessage holds the valid values of a decoded message by field number.
+type fitMessage map[byte]uint64
+
+func parseFIT(data []byte) (*t.Activity, error) {
+	if len(data) < 12 || string(data[8:12]) != ".FIT" {
+		return nil, ErrInvalidFile
+	}
+	headerSize := int(data[0])
+	dataSize := int(binary.LittleEndian.Uint32(data[4:8]))
+	if headerSize < 12 || headerSize+dataSize > len(data) {
+		return nil, ErrInvalidFile
+	}
+
+	records, err := decodeFITMessages(data[headerSize : headerSize+dataSize])
+	if err != nil {
+		return nil, err
+	}
+
+	activity := &t.Activity{Format: c.FormatFIT, Points: []t.TrackPoint{}, Laps: []t.Lap{}}
+	for _, record := range records {
+		switch record.global {
+		case fitMessageRecord:
+			if point, ok := fitTrackPoint(record.values); ok {
+				activity.Points = append(activity.Points, point)
+			}
+		case fitMessageLap:
+			activity.Laps = append(activity.Laps, fitLap(record.values))
+		case fitMessageSession:
+			applyFITSession(activity, record.values)
+		}
+	}
+	return activity, nil
+}
+
+type fitRecord struct {
+	global uint16
+	values fitMessage
+}
+
+func decodeFITMessages(data []byte) ([]fitRecord, error) {
+	definitions := map[byte]*fitDefinition{}
+	records := []fitRecord{}
+	var lastTimestamp uint64
+
+	for offset := 0; offset < len(data); {
+		header := data[offset]
+		offset++
+
+		// compressed timestamp header, a data message whose timestamp is an
+		// offset from the last full one
+		if header&0x80 != 0 {
+			definition := definitions[(header>>5)&0x03]
+			if definition == nil {
+				return nil, ErrInvalidFile
+			}
+			values, next, err := readFITData(data, offset, definition)
+			if err != nil {
+				return nil, err
+			}
+			offset = next
+
+			timeOffset := uint64(header & 0x1F)
+			timestamp := lastTimestamp&^0x1F + timeOffset
+			if timeOffset < lastTimestamp&0x1F {
+				timestamp += 0x20
+			}
+			values[fitFieldTimestamp] = timestamp
+			lastTimestamp = timestamp
+			records = append(records, fitRecord{global: definition.global, values: values})
+			continue
+		}
+
+		local := header & 0x0F
+		if header&0x40 != 0 {
+			definition, next, err := readFITDefinition(data, offset, header&0x20 != 0)
+			if err != nil {
+				return nil, err
+			}
+			definitions[local] = definition
+			offset = next
+			continue
+		}
+
+		definition := definitions[local]
+		if definition == nil {
+			return nil, ErrInvalidFile
+		}
+		values, next, err := readFITData(data, offset, definition)
+		if err != nil {
+			return nil, err
+		}
+		offset = next
+		if timestamp, ok := values[fitFieldTimestamp]; ok {
+			lastTimestamp = timestamp
+		}
+		records = append(records, fitRecord{global: definition.global, values: values})
+	}
+	return records, nil
+}
+
+func readFITDefinition(data []byte, offset int, developer bool) (*fitDefinition, int, error) {
+	if offset+5 > len(data) {
+		return nil, 0, ErrInvalidFile
+	}
+	definition := &fitDefinition{bigEndian: data[offset+1] == 1}
+	if definition.bigEndian {
+		definition.global = binary.BigEndian.Uint16(data[offset+2 : offset+4])
+	} else {
+		definition.global = binary.LittleEndian.Uint16(data[offset+2 : offset+4])
+	}
+	count := int(data[offset+4])
+	offset += 5
+
+	if offset+count*3 > len(data) {
+		return nil, 0, ErrInvalidFile
+	}
+	for i := 0; i < count; i++ {
+		definition.fields = append(definition.fields, fitFieldDefinition{
+			number:   data[offset],
+			size:     int(data[offset+1]),
+			baseType: data[offset+2],
+		})
+		offset += 3
+	}
+
+	if developer {
+		if offset >= len(data) {
+			return nil, 0, ErrInvalidFile
+		}
+		devCount := int(data[offset])
+		offset++
+		if offset+devCount*3 > len(data) {
+			return nil, 0, ErrInvalidFile
+		}
+		for i := 0; i < devCount; i++ {
+			definition.devBytes += int(data[offset+1])
+			offset += 3
+		}
+	}
+	return definition, offset, nil
+}
+
+func readFITData(data []byte, offset int, definition *fitDefinition) (fitMessage, int, error) {
+	values := fitMessage{}
+	for _, field := range definition.fields {
+		if offset+field.size > len(data) {
+			return nil, 0, ErrInvalidFile
+		}
+		if value, ok := fitValue(data[offset:offset+field.size], field.baseType, definition.bigEndian); ok {
+			values[field.number] = value
+		}
+		offset += field.size
+	}
+	if offset+definition.devBytes > len(data) {
+		return nil, 0, ErrInvalidFile
+	}
+	return values, offset + definition.devBytes, nil
+}
+
+// fitValue reads an integer field, signed values are returned as their two's
+// complement bits. Strings, floats, arrays and invalid values are skipped.
+func fitValue(raw []byte, baseType byte, bigEndian bool) (uint64, bool) {
+	size, ok := fitIntegerSizes[baseType&0x1F]
+	if !ok || len(raw) != size {
+		return 0, false
+	}
+
+	var value uint64
+	for i := 0; i < size; i++ {
+		b := raw[i]
+		if bigEndian {
+			value = value<<8 | uint64(b)
+			continue
+		}
+		value |= uint64(b) << (8 * i)
+	}
+
+	bits := uint(size * 8)
+	all := uint64(math.MaxUint64) >> (64 - bits)
+	switch baseType & 0x1F {
+	case 0x01, 0x03, 0x05, 0x0E: // signed, invalid is the largest positive value
+		if value == all>>1 {
+			return 0, false
+		}
+	case 0x0A, 0x0B, 0x0C, 0x10: // z types, invalid is zero
+		if value == 0 {
+			return 0, false
+		}
+	default:
+		if value == all {
+			return 0, false
+		}
+	}
+	return value, true
+}
+
+func fitTime(value uint64) time.Time {
+	return fitEpoch.Add(time.Duration(value) * time.Second)
+}
+
+// fitSigned turns the bits of a sint32 back into a signed number.
+func fitSigned(value uint64) int64 {
+	return int64(int32(uint32(value)))
+}
+
+func fitTrackPoint(values fitMessage) (t.TrackPoint, bool) {
+	timestamp, ok := values[fitFieldTimestamp]
+	if !ok {
+		return t.TrackPoint{}, false
+	}
+	point := t.TrackPoint{Time: fitTime(timestamp)}
+
+	latitude, hasLatitude := values[0]
+	longitude, hasLongitude := values[1]
+	if hasLatitude && hasLongitude {
+		lat := semicirclesToDegrees(fitSigned(latitude))
+		lon := semicirclesToDegrees(fitSigned(longitude))
+		point.Latitude, point.Longitude = &lat, &lon
+	}
+	// enhanced_altitude (78) has more range than altitude (2), both are
+	// scaled by 5 with a 500m offset
+	if altitude, ok := values[78]; ok {
+		elevation := float64(altitude)/5 - 500
+		point.Elevation = &elevation
+	} else if altitude, ok := values[2]; ok {
+		elevation := float64(altitude)/5 - 500
+		point.Elevation = &elevation
+	}
+	if heartRate, ok := values[3]; ok {
+		hr := int(heartRate)
+		point.HeartRate = &hr
+	}
+	if distance, ok := values[5]; ok {
+		metres := float64(distance) / 100
+		point.Distance = &metres
+	}
+	return point, true
+}
+
+func fitLap(values fitMessage) t.Lap {
+	lap := t.Lap{
+		StartTime: fitTime(values[2]),
+		Duration:  int(math.Round(float64(values[7]) / 1000)),
+		Distance:  float64(values[9]) / 100 / 1000,
+	}
+	if calories, ok := values[11]; ok {
+		kcal := float64(calories)
+		lap.Calories = &kcal
+	}
+	if heartRate, ok := values[15]; ok {
+		hr := int(heartRate)
+		lap.AvgHeartRate = &hr
+	}
+	if heartRate, ok := values[16]; ok {
+		hr := int(heartRate)
+		lap.MaxHeartRate = &hr
+	}
+	return lap
+}
+
+// applyFITSession takes the totals the device worked out for the session.
+func applyFITSession(activity *t.Activity, values fitMessage) {
+	if start, ok := values[2]; ok && activity.StartTime.IsZero() {
+		activity.StartTime = fitTime(start)
+	}
+	if sport, ok := values[5]; ok && activity.Modality == "" {
+		activity.Modality = c.FITSports[sport]
+	}
+	if elapsed, ok := values[7]; ok {
+		activity.Duration += int(math.Round(float64(elapsed) / 1000))
+	}
+	if distance, ok := values[9]; ok {
+		activity.Distance += float64(distance) / 100 / 1000
+	}
+	if calories, ok := values[11]; ok {
+		kcal := float64(calories)
+		if activity.Calories != nil {
+			kcal += *activity.Calories
+		}
+		activity.Calories = &kcal
+	}
+	if heartRate, ok := values[16]; ok && activity.AvgHeartRate == nil {
+		hr := int(heartRate)
+		activity.AvgHeartRate = &hr
+	}
+	if heartRate, ok := values[17]; ok && (activity.MaxHeartRate == nil || int(heartRate) > *activity.MaxHeartRate) {
+		hr := int(heartRate)
+		activity.MaxHeartRate = &hr
+	}
+	if ascent, ok := values[22]; ok {
+		gain := float64(ascent)
+		if activity.ElevationGain != nil {
+			gain += *activity.ElevationGain
+		}
+		activity.ElevationGain = &gain
+	}
+}
+
+func semicirclesToDegrees(semicircles int64) float64 {
+	return float64(semicircles) * (180 / math.Pow(2, 31))
+}
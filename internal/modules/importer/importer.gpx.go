@@ -0,0 +1,72 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/xml"
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/importer/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/importer/types"
+)
+
+// gpx only maps what we read, heart rate comes from the Garmin
+// TrackPointExtension which most devices write.
+type gpx struct {
+	Tracks []struct {
+		Type     string `xml:"type"`
+		Segments []struct {
+			Points []struct {
+				Latitude  float64   `xml:"lat,attr"`
+				Longitude float64   `xml:"lon,attr"`
+				Elevation *float64  `xml:"ele"`
+				Time      time.Time `xml:"time"`
+				HeartRate *int      `xml:"extensions>TrackPointExtension>hr"`
+			} `xml:"trkpt"`
+		} `xml:"trkseg"`
+	} `xml:"trk"`
+}
+
+// parseGPX reads every track point, GPX has no laps of its own so each track
+// segment becomes a lap when there is more than one.
+func parseGPX(data []byte) (*t.Activity, error) {
+	var file gpx
+	if err := xml.NewDecoder(bytes.NewReader(data)).Decode(&file); err != nil {
+		return nil, ErrInvalidFile
+	}
+
+	activity := &t.Activity{Format: c.FormatGPX, Points: []t.TrackPoint{}, Laps: []t.Lap{}}
+	segments := [][]t.TrackPoint{}
+	for _, track := range file.Tracks {
+		if modality, ok := c.ModalityFromName(track.Type); ok && activity.Modality == "" {
+			activity.Modality = modality
+		}
+		for _, segment := range track.Segments {
+			points := []t.TrackPoint{}
+			for _, point := range segment.Points {
+				if point.Time.IsZero() {
+					continue
+				}
+				latitude, longitude := point.Latitude, point.Longitude
+				points = append(points, t.TrackPoint{
+					Time:      point.Time.UTC(),
+					Latitude:  &latitude,
+					Longitude: &longitude,
+					Elevation: point.Elevation,
+					HeartRate: point.HeartRate,
+				})
+			}
+			if len(points) > 0 {
+				segments = append(segments, points)
+				activity.Points = append(activity.Points, points...)
+			}
+		}
+	}
+
+	withCumulativeDistance(activity.Points)
+	if len(segments) > 1 {
+		for _, points := range segments {
+			activity.Laps = append(activity.Laps, lapFromPoints(points))
+		}
+	}
+	return activity, nil
+}
@@ -0,0 +1,313 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	m "github.com/joshibbotson/gym-tracker-backend/internal/middleware"
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/importer/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/importer/types"
+	uc "github.com/joshibbotson/gym-tracker-backend/internal/modules/user/constants"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/workout"
+	wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+	util "github.com/joshibbotson/gym-tracker-backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ImporterHandler struct {
+	Service ImporterService
+}
+
+func NewImporterHandler(service ImporterService) *ImporterHandler {
+	return &ImporterHandler{
+		Service: service,
+	}
+}
+
+// ActivityHandler serves /workout/import/activity and /workout/track/{id}
+func (h *ImporterHandler) ActivityHandler(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodPost && r.PathValue("id") == "":
+		m.PermissionMiddleware(h.handleImportActivity)(w, r)
+	case r.Method == http.MethodGet && r.PathValue("id") != "":
+		m.PermissionMiddleware(h.handleReadTrack)(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleImportActivity takes the file as a multipart "file" field or as the
+// raw body, and an optional modality query param to override the file's sport.
+func (h *ImporterHandler) handleImportActivity(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	fileName, data, ok := readUpload(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.Service.ImportActivity(userID, fileName, data, wc.CardioModality(r.URL.Query().Get("modality")))
+	if errors.Is(err, ErrUnsupportedFormat) || errors.Is(err, ErrInvalidFile) || errors.Is(err, ErrEmptyActivity) || errors.Is(err, ErrInvalidModality) || errors.Is(err, workout.ErrInvalidCardio) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error importing activity", http.StatusInternalServerError)
+		return
+	}
+
+	status := http.StatusCreated
+	if result.Duplicate {
+		status = http.StatusOK
+	}
+	util.WriteJSON(w, status, result)
+}
+
+func (h *ImporterHandler) handleReadTrack(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+	workoutID, ok := util.ParseObjectID(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+
+	track, err := h.Service.GetTrack(userID, workoutID)
+	if errors.Is(err, ErrTrackNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error fetching track", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, track)
+}
+
+// CSVHandler serves /workout/import
+func (h *ImporterHandler) CSVHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	m.PermissionMiddleware(h.handleImportWorkouts)(w, r)
+}
+
+// handleImportWorkouts takes the file as a multipart "file" field or as the raw
+// body. Options are form fields or query params: format (strong, hevy or
+// generic, detected when empty), unit (kg or lb, the weight unit of Strong and
+// generic files), mappings (a JSON object of file name to catalog exercise) and
+// commit=true to save the workouts, otherwise only the summary is returned.
+func (h *ImporterHandler) handleImportWorkouts(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	_, data, ok := readUpload(w, r)
+	if !ok {
+		return
+	}
+
+	options := t.CSVImportOptions{
+		Format: c.CSVFormat(r.FormValue("format")),
+		Commit: r.FormValue("commit") == "true",
+		Unit:   uc.WeightUnit(r.FormValue("unit")),
+	}
+	if mappings := r.FormValue("mappings"); mappings != "" {
+		if err := json.Unmarshal([]byte(mappings), &options.Mappings); err != nil {
+			http.Error(w, "Invalid mappings", http.StatusBadRequest)
+			return
+		}
+	}
+
+	summary, err := h.Service.ImportWorkouts(userID, data, options)
+	if errors.Is(err, ErrUnmatchedExercises) {
+		util.WriteJSON(w, http.StatusConflict, summary)
+		return
+	}
+	if errors.Is(err, ErrUnsupportedCSV) || errors.Is(err, ErrInvalidFile) || errors.Is(err, ErrInvalidUnit) || errors.Is(err, ErrInvalidMapping) || errors.Is(err, ErrEmptyImport) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error importing workouts", http.StatusInternalServerError)
+		return
+	}
+
+	status := http.StatusOK
+	if summary.Committed {
+		status = http.StatusCreated
+	}
+	util.WriteJSON(w, status, summary)
+}
+
+// readUpload reads an uploaded file up to MaxUploadBytes, writing a 400 or
+// 413 and returning false if it can't.
+func readUpload(w http.ResponseWriter, r *http.Request) (string, []byte, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, c.MaxUploadBytes)
+
+	var fileName string
+	var reader io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			uploadError(w, err)
+			return "", nil, false
+		}
+		defer file.Close()
+		fileName, reader = header.Filename, file
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		uploadError(w, err)
+		return "", nil, false
+	}
+	if len(data) == 0 {
+		http.Error(w, "File is required", http.StatusBadRequest)
+		return "", nil, false
+	}
+	return fileName, data, true
+}
+
+func uploadError(w http.ResponseWriter, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		http.Error(w, "File is too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	http.Error(w, "File is required", http.StatusBadRequest)
+}
+
+// HealthHandler serves /workout/import/health and /workout/import/health/{id}
+func (h *ImporterHandler) HealthHandler(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodPost && r.PathValue("id") == "":
+		m.PermissionMiddleware(h.handleImportHealth)(w, r)
+	case r.Method == http.MethodGet && r.PathValue("id") != "":
+		m.PermissionMiddleware(h.handleReadImportJob)(w, r)
+	case r.Method == http.MethodGet:
+		m.PermissionMiddleware(h.handleReadImportJobs)(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleImportHealth takes export.zip, or the export.xml inside it, as a
+// multipart "file" field or as the raw body. The upload is streamed to disk
+// rather than parsed as a form, and the import carries on after the response.
+func (h *ImporterHandler) handleImportHealth(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+	r.Body = http.MaxBytesReader(w, r.Body, c.MaxHealthUploadBytes)
+
+	var fileName string
+	var reader io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		multipart, err := r.MultipartReader()
+		if err != nil {
+			http.Error(w, "File is required", http.StatusBadRequest)
+			return
+		}
+		for {
+			part, err := multipart.NextPart()
+			if err != nil {
+				uploadError(w, err)
+				return
+			}
+			if part.FormName() == "file" {
+				fileName, reader = part.FileName(), part
+				break
+			}
+		}
+	}
+
+	job, err := h.Service.StartHealthImport(userID, fileName, reader)
+	if errors.Is(err, ErrImportInProgress) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if errors.Is(err, ErrInvalidHealthExport) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		uploadError(w, err)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error importing export", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusAccepted, job)
+}
+
+func (h *ImporterHandler) handleReadImportJobs(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	jobs, err := h.Service.GetImportJobs(userID)
+	if err != nil {
+		http.Error(w, "Error fetching imports", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, jobs)
+}
+
+func (h *ImporterHandler) handleReadImportJob(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+	jobID, ok := util.ParseObjectID(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+
+	job, err := h.Service.GetImportJobById(userID, jobID)
+	if errors.Is(err, ErrImportJobNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error fetching import", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, job)
+}
+
+// ScaleHandler serves /workout/import/scale
+func (h *ImporterHandler) ScaleHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	m.PermissionMiddleware(h.handleImportScale)(w, r)
+}
+
+// handleImportScale takes the file as a multipart "file" field or as the raw
+// body. Options are form fields or query params: format (withings, renpho or
+// generic, detected when empty) and unit (kg or lb, for weight columns whose
+// header has no unit, the user's unit by default).
+func (h *ImporterHandler) handleImportScale(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	_, data, ok := readUpload(w, r)
+	if !ok {
+		return
+	}
+
+	summary, err := h.Service.ImportScale(userID, data, t.ScaleImportOptions{
+		Format: c.ScaleFormat(r.FormValue("format")),
+		Unit:   uc.WeightUnit(r.FormValue("unit")),
+	})
+	if errors.Is(err, ErrUnsupportedScaleCSV) || errors.Is(err, ErrInvalidFile) || errors.Is(err, ErrInvalidUnit) || errors.Is(err, ErrNoReadings) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error importing weigh-ins", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, summary)
+}
@@ -0,0 +1,423 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"math"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/importer/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/importer/types"
+	uc "github.com/joshibbotson/gym-tracker-backend/internal/modules/user/constants"
+	wt "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+)
+
+// An Apple Health export is one export.xml, zipped with workout routes and
+// clinical records when shared from the phone. The xml is streamed a token at
+// a time and only the records imported are held on to, since years of heart
+// rate samples make for hundreds of MB.
+
+type healthSample struct {
+	Time  time.Time
+	Value float64
+}
+
+// healthExport is what's kept from an export, Weights are in the user's
+// weight unit, BodyFat in percent and Waist in cm.
+type healthExport struct {
+	Weights    []healthSample
+	BodyFat    []healthSample
+	Waist      []healthSample
+	Workouts   []t.Activity
+	Records    int
+	Duplicates int
+	Skipped    int
+}
+
+type healthWorkout struct {
+	ActivityType          string            `xml:"workoutActivityType,attr"`
+	Duration              float64           `xml:"duration,attr"`
+	DurationUnit          string            `xml:"durationUnit,attr"`
+	TotalDistance         float64           `xml:"totalDistance,attr"`
+	TotalDistanceUnit     string            `xml:"totalDistanceUnit,attr"`
+	TotalEnergyBurned     float64           `xml:"totalEnergyBurned,attr"`
+	TotalEnergyBurnedUnit string            `xml:"totalEnergyBurnedUnit,attr"`
+	StartDate             string            `xml:"startDate,attr"`
+	EndDate               string            `xml:"endDate,attr"`
+	Statistics            []healthStatistic `xml:"WorkoutStatistics"`
+}
+
+type healthStatistic struct {
+	Type    string  `xml:"type,attr"`
+	Sum     float64 `xml:"sum,attr"`
+	Average float64 `xml:"average,attr"`
+	Maximum float64 `xml:"maximum,attr"`
+	Unit    string  `xml:"unit,attr"`
+}
+
+// countingReader keeps count of the bytes read through it, for progress.
+type countingReader struct {
+	reader io.Reader
+	read   int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.read += int64(n)
+	return n, err
+}
+
+type zipEntryReader struct {
+	io.ReadCloser
+	archive *zip.ReadCloser
+}
+
+func (r *zipEntryReader) Close() error {
+	r.ReadCloser.Close()
+	return r.archive.Close()
+}
+
+// openHealthExport opens an uploaded export.xml, or the export.xml inside an
+// uploaded export.zip, returning its uncompressed size.
+func openHealthExport(filePath string) (io.ReadCloser, int64, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, 0, err
+	}
+	magic := make([]byte, 4)
+	if _, err := io.ReadFull(file, magic); err != nil {
+		file.Close()
+		return nil, 0, ErrInvalidHealthExport
+	}
+
+	if !bytes.Equal(magic, []byte("PK\x03\x04")) {
+		info, err := file.Stat()
+		if err == nil {
+			_, err = file.Seek(0, io.SeekStart)
+		}
+		if err != nil {
+			file.Close()
+			return nil, 0, err
+		}
+		return file, info.Size(), nil
+	}
+
+	file.Close()
+	archive, err := zip.OpenReader(filePath)
+	if err != nil {
+		return nil, 0, ErrInvalidHealthExport
+	}
+	for _, entry := range archive.File {
+		if path.Base(entry.Name) != c.HealthExportFile {
+			continue
+		}
+		reader, err := entry.Open()
+		if err != nil {
+			archive.Close()
+			return nil, 0, ErrInvalidHealthExport
+		}
+		return &zipEntryReader{ReadCloser: reader, archive: archive}, int64(entry.UncompressedSize64), nil
+	}
+	archive.Close()
+	return nil, 0, ErrInvalidHealthExport
+}
+
+// checkHealthExport makes sure an upload is a Health export before it is
+// imported in the background, returning the size of the xml.
+func checkHealthExport(filePath string) (int64, error) {
+	reader, size, err := openHealthExport(filePath)
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+
+	decoder := newHealthDecoder(reader)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return 0, ErrInvalidHealthExport
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			if start.Name.Local != "HealthData" {
+				return 0, ErrInvalidHealthExport
+			}
+			return size, nil
+		}
+	}
+}
+
+func newHealthDecoder(reader io.Reader) *xml.Decoder {
+	decoder := xml.NewDecoder(reader)
+	// exports have been seen with stray characters in device names, which
+	// shouldn't sink the whole import
+	decoder.Strict = false
+	return decoder
+}
+
+// parseHealth reads the body measurements and cardio workouts out of an
+// export.xml, converting body mass to unit. Samples of the same type at the
+// same time are only kept once, as the phone and a paired app often both save
+// a weigh-in. progress is called every HealthProgressEvery elements and an
+// error from it stops the parse.
+func parseHealth(reader io.Reader, unit uc.WeightUnit, progress func(records int) error) (*healthExport, error) {
+	decoder := newHealthDecoder(reader)
+	export := &healthExport{}
+	seen := map[string]bool{}
+	root := false
+	elements := 0
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, ErrInvalidHealthExport
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if !root {
+			if start.Name.Local != "HealthData" {
+				return nil, ErrInvalidHealthExport
+			}
+			root = true
+			continue
+		}
+
+		switch start.Name.Local {
+		case "Record":
+			export.Records++
+			export.addRecord(start, unit, seen)
+		case "Workout":
+			export.Records++
+			var workout healthWorkout
+			if err := decoder.DecodeElement(&workout, &start); err != nil {
+				return nil, ErrInvalidHealthExport
+			}
+			export.addWorkout(workout, seen)
+		}
+
+		elements++
+		if progress != nil && elements%c.HealthProgressEvery == 0 {
+			if err := progress(export.Records); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if !root {
+		return nil, ErrInvalidHealthExport
+	}
+	return export, nil
+}
+
+func (e *healthExport) addRecord(start xml.StartElement, unit uc.WeightUnit, seen map[string]bool) {
+	recordType := attribute(start, "type")
+	if recordType != c.HealthBodyMass && recordType != c.HealthBodyFat && recordType != c.HealthWaist {
+		return
+	}
+
+	date, err := time.Parse(c.HealthDateLayout, attribute(start, "startDate"))
+	if err != nil {
+		e.Skipped++
+		return
+	}
+	value, err := strconv.ParseFloat(attribute(start, "value"), 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) || value <= 0 {
+		e.Skipped++
+		return
+	}
+	recordUnit := attribute(start, "unit")
+
+	key := recordType + date.UTC().Format(time.RFC3339)
+	if seen[key] {
+		e.Duplicates++
+		return
+	}
+	seen[key] = true
+
+	switch recordType {
+	case c.HealthBodyMass:
+		from := uc.WeightUnit(recordUnit)
+		if from != uc.WeightUnitKg && from != uc.WeightUnitLb {
+			e.Skipped++
+			return
+		}
+		e.Weights = append(e.Weights, healthSample{Time: date, Value: convertWeight(value, from, unit)})
+	case c.HealthBodyFat:
+		// Health keeps percentages as a fraction, 0.18 for 18%
+		if recordUnit == "%" {
+			value *= 100
+		}
+		if value > 100 {
+			e.Skipped++
+			return
+		}
+		e.BodyFat = append(e.BodyFat, healthSample{Time: date, Value: math.Round(value*10) / 10})
+	case c.HealthWaist:
+		perUnit, ok := c.CentimetresPer[recordUnit]
+		if !ok {
+			e.Skipped++
+			return
+		}
+		e.Waist = append(e.Waist, healthSample{Time: date, Value: math.Round(value*perUnit*10) / 10})
+	}
+}
+
+// addWorkout keeps the cardio workouts. Older exports put totals on the
+// workout itself, newer ones in WorkoutStatistics, both are read.
+func (e *healthExport) addWorkout(workout healthWorkout, seen map[string]bool) {
+	modality, ok := c.HealthWorkoutTypes[workout.ActivityType]
+	if !ok {
+		e.Skipped++
+		return
+	}
+	start, err := time.Parse(c.HealthDateLayout, workout.StartDate)
+	if err != nil {
+		e.Skipped++
+		return
+	}
+
+	key := "Workout" + start.UTC().Format(time.RFC3339)
+	if seen[key] {
+		e.Duplicates++
+		return
+	}
+	seen[key] = true
+
+	duration := 0.0
+	if perUnit, ok := c.SecondsPer[workout.DurationUnit]; ok {
+		duration = workout.Duration * perUnit
+	}
+	if duration <= 0 {
+		if end, err := time.Parse(c.HealthDateLayout, workout.EndDate); err == nil {
+			duration = end.Sub(start).Seconds()
+		}
+	}
+	if duration <= 0 {
+		e.Skipped++
+		return
+	}
+
+	activity := t.Activity{
+		Format:    c.FormatHealth,
+		Modality:  modality,
+		StartTime: start.UTC(),
+		Duration:  int(math.Round(duration)),
+		Points:    []t.TrackPoint{},
+		Laps:      []t.Lap{},
+	}
+	if perUnit, ok := c.KilometresPer[workout.TotalDistanceUnit]; ok && workout.TotalDistance > 0 {
+		activity.Distance = workout.TotalDistance * perUnit
+	}
+	if perUnit, ok := c.KilocaloriesPer[workout.TotalEnergyBurnedUnit]; ok && workout.TotalEnergyBurned > 0 {
+		calories := math.Round(workout.TotalEnergyBurned * perUnit)
+		activity.Calories = &calories
+	}
+
+	for _, statistic := range workout.Statistics {
+		switch {
+		case strings.HasPrefix(statistic.Type, "HKQuantityTypeIdentifierDistance"):
+			if perUnit, ok := c.KilometresPer[statistic.Unit]; ok && activity.Distance == 0 {
+				activity.Distance = statistic.Sum * perUnit
+			}
+		case statistic.Type == c.HealthStatEnergy:
+			if perUnit, ok := c.KilocaloriesPer[statistic.Unit]; ok && activity.Calories == nil && statistic.Sum > 0 {
+				calories := math.Round(statistic.Sum * perUnit)
+				activity.Calories = &calories
+			}
+		case statistic.Type == c.HealthStatHeartRate:
+			if statistic.Average > 0 {
+				average := int(math.Round(statistic.Average))
+				activity.AvgHeartRate = &average
+			}
+			if statistic.Maximum > 0 {
+				maximum := int(math.Round(statistic.Maximum))
+				activity.MaxHeartRate = &maximum
+			}
+		}
+	}
+	activity.Distance = math.Round(activity.Distance*100) / 100
+
+	e.Workouts = append(e.Workouts, activity)
+}
+
+func attribute(start xml.StartElement, name string) string {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// dailyMeasurements keeps the first sample of each day in the user's
+// timezone, a morning weigh-in being the one worth comparing day to day.
+func dailyMeasurements(export *healthExport, location *time.Location) []wt.DailyMeasurement {
+	readings := []measurementReading{}
+	add := func(samples []healthSample, field func(*wt.DailyMeasurement) **float64) {
+		for _, sample := range samples {
+			local := sample.Time.In(location)
+			reading := measurementReading{Time: sample.Time}
+			reading.Measurement.Date = time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+			value := sample.Value
+			*field(&reading.Measurement) = &value
+			readings = append(readings, reading)
+		}
+	}
+	add(export.Weights, func(day *wt.DailyMeasurement) **float64 { return &day.Weight })
+	add(export.BodyFat, func(day *wt.DailyMeasurement) **float64 { return &day.BodyFat })
+	add(export.Waist, func(day *wt.DailyMeasurement) **float64 { return &day.WaistSize })
+	return earliestByDay(readings)
+}
+
+// measurementReading is what was measured at one time, Measurement.Date is
+// the calendar day it counts towards.
+type measurementReading struct {
+	Time        time.Time
+	Measurement wt.DailyMeasurement
+}
+
+// earliestByDay keeps the earliest value of each measurement on each day.
+func earliestByDay(readings []measurementReading) []wt.DailyMeasurement {
+	sort.SliceStable(readings, func(i, j int) bool { return readings[i].Time.Before(readings[j].Time) })
+
+	days := map[time.Time]*wt.DailyMeasurement{}
+	for _, reading := range readings {
+		day, ok := days[reading.Measurement.Date]
+		if !ok {
+			day = &wt.DailyMeasurement{Date: reading.Measurement.Date}
+			days[day.Date] = day
+		}
+		for _, field := range []struct {
+			target **float64
+			value  *float64
+		}{
+			{&day.Weight, reading.Measurement.Weight},
+			{&day.BodyFat, reading.Measurement.BodyFat},
+			{&day.MuscleMass, reading.Measurement.MuscleMass},
+			{&day.BodyWater, reading.Measurement.BodyWater},
+			{&day.BoneMass, reading.Measurement.BoneMass},
+			{&day.WaistSize, reading.Measurement.WaistSize},
+		} {
+			if *field.target == nil && field.value != nil {
+				*field.target = field.value
+			}
+		}
+	}
+
+	measurements := make([]wt.DailyMeasurement, 0, len(days))
+	for _, day := range days {
+		measurements = append(measurements, *day)
+	}
+	sort.Slice(measurements, func(i, j int) bool { return measurements[i].Date.Before(measurements[j].Date) })
+	return measurements
+}
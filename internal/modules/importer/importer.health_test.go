@@ -0,0 +1,106 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	uc "github.com/joshibbotson/gym-tracker-backend/internal/modules/user/constants"
+	wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+)
+
+const healthFixture = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE HealthData [
+<!ELEMENT HealthData (ExportDate,Record*,Workout*)>
+]>
+<HealthData locale="en_GB">
+ <ExportDate value="2026-03-05 09:00:00 +0000"/>
+ <Record type="HKQuantityTypeIdentifierBodyMass" sourceName="Scale" unit="kg" startDate="2026-03-01 07:00:00 +0000" value="80.4"/>
+ <Record type="HKQuantityTypeIdentifierBodyMass" sourceName="Phone" unit="kg" startDate="2026-03-01 07:00:00 +0000" value="80.4"/>
+ <Record type="HKQuantityTypeIdentifierBodyMass" sourceName="Scale" unit="lb" startDate="2026-03-02 07:00:00 +0000" value="176.37"/>
+ <Record type="HKQuantityTypeIdentifierBodyMass" sourceName="Scale" unit="st" startDate="2026-03-03 07:00:00 +0000" value="12.6"/>
+ <Record type="HKQuantityTypeIdentifierBodyMass" sourceName="Scale" unit="kg" startDate="2026-03-04 07:00:00 +0000" value="NaN"/>
+ <Record type="HKQuantityTypeIdentifierBodyMass" sourceName="Scale" unit="kg" startDate="yesterday" value="80"/>
+ <Record type="HKQuantityTypeIdentifierBodyFatPercentage" sourceName="Scale" unit="%" startDate="2026-03-01 07:00:00 +0000" value="0.184"/>
+ <Record type="HKQuantityTypeIdentifierWaistCircumference" sourceName="Tape" unit="in" startDate="2026-03-01 07:00:00 +0000" value="32"/>
+ <Record type="HKQuantityTypeIdentifierHeartRate" sourceName="Watch" unit="count/min" startDate="2026-03-01 07:00:00 +0000" value="60"/>
+ <Workout workoutActivityType="HKWorkoutActivityTypeRunning" duration="30" durationUnit="min" startDate="2026-03-01 08:00:00 +0000" endDate="2026-03-01 08:30:00 +0000">
+  <WorkoutStatistics type="HKQuantityTypeIdentifierDistanceWalkingRunning" sum="5.2" unit="km"/>
+  <WorkoutStatistics type="HKQuantityTypeIdentifierActiveEnergyBurned" sum="350" unit="kcal"/>
+  <WorkoutStatistics type="HKQuantityTypeIdentifierHeartRate" average="152.4" maximum="178" unit="count/min"/>
+ </Workout>
+ <Workout workoutActivityType="HKWorkoutActivityTypeCycling" duration="0" durationUnit="min" totalDistance="10" totalDistanceUnit="mi" totalEnergyBurned="1000" totalEnergyBurnedUnit="kJ" startDate="2026-03-02 18:00:00 +0000" endDate="2026-03-02 18:45:00 +0000"/>
+ <Workout workoutActivityType="HKWorkoutActivityTypeTraditionalStrengthTraining" duration="60" durationUnit="min" startDate="2026-03-03 18:00:00 +0000" endDate="2026-03-03 19:00:00 +0000"/>
+ <Workout workoutActivityType="HKWorkoutActivityTypeRunning" duration="30" durationUnit="min" startDate="2026-03-01 08:00:00 +0000" endDate="2026-03-01 08:30:00 +0000"/>
+</HealthData>`
+
+func TestParseHealth(tt *testing.T) {
+	export, err := parseHealth(strings.NewReader(healthFixture), uc.WeightUnitKg, nil)
+	if err != nil {
+		tt.Fatalf("unexpected error: %v", err)
+	}
+
+	if export.Records != 13 || export.Duplicates != 2 || export.Skipped != 4 {
+		tt.Fatalf("got %d records, %d duplicates and %d skipped, want 13, 2 and 4", export.Records, export.Duplicates, export.Skipped)
+	}
+
+	weights := []string{}
+	for _, sample := range export.Weights {
+		weights = append(weights, fmt.Sprint(sample.Value))
+	}
+	if fmt.Sprint(weights) != "[80.4 80]" {
+		tt.Fatalf("got weights %v, want [80.4 80]", weights)
+	}
+	if len(export.BodyFat) != 1 || export.BodyFat[0].Value != 18.4 {
+		tt.Fatalf("got body fat %v, want 18.4%%", export.BodyFat)
+	}
+	if len(export.Waist) != 1 || export.Waist[0].Value != 81.3 {
+		tt.Fatalf("got waist %v, want 81.3cm", export.Waist)
+	}
+
+	if len(export.Workouts) != 2 {
+		tt.Fatalf("got %d workouts, want 2", len(export.Workouts))
+	}
+	run, ride := export.Workouts[0], export.Workouts[1]
+	if run.Modality != wc.CardioRun || run.Duration != 1800 || run.Distance != 5.2 || *run.Calories != 350 || *run.AvgHeartRate != 152 || *run.MaxHeartRate != 178 {
+		tt.Fatalf("got run %+v", run)
+	}
+	if ride.Modality != wc.CardioBike || ride.Duration != 2700 || ride.Distance != 16.09 || *ride.Calories != 239 {
+		tt.Fatalf("got ride %+v", ride)
+	}
+	if !ride.StartTime.Equal(time.Date(2026, 3, 2, 18, 0, 0, 0, time.UTC)) {
+		tt.Fatalf("got ride start %v", ride.StartTime)
+	}
+}
+
+func TestParseHealthRejects(tt *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"empty", ""},
+		{"garbage", "\x00\x01 not xml"},
+		{"another xml document", `<gpx><trk></trk></gpx>`},
+		{"truncated in a workout", healthFixture[:strings.Index(healthFixture, "<WorkoutStatistics")+20]},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			_, err := parseHealth(strings.NewReader(test.data), uc.WeightUnitKg, nil)
+			if !errors.Is(err, ErrInvalidHealthExport) {
+				tt.Fatalf("got %v, want ErrInvalidHealthExport", err)
+			}
+		})
+	}
+}
+
+func TestParseHealthStopsOnProgressError(tt *testing.T) {
+	stop := errors.New("stop")
+	records := strings.Repeat(`<Record type="HKQuantityTypeIdentifierHeartRate"/>`, 50000)
+	_, err := parseHealth(strings.NewReader("<HealthData>"+records+"</HealthData>"), uc.WeightUnitKg, func(int) error { return stop })
+	if !errors.Is(err, stop) {
+		tt.Fatalf("got %v, want the progress error", err)
+	}
+}
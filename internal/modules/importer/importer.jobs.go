@@ -0,0 +1,231 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/importer/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/importer/types"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/workout"
+	wt "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// StartHealthImport saves an Apple Health export to a temporary file and
+// imports it in the background, the job returned can be polled for progress.
+// Only one import runs per user at a time.
+// Measurements only fill in days that don't have them yet and workouts are
+// matched on start time, so importing a newer export again only adds what's new.
+func (s *importerService) StartHealthImport(userID primitive.ObjectID, fileName string, reader io.Reader) (*t.ImportJob, error) {
+	jobs, err := s.GetImportJobs(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) > 0 && !jobs[0].Status.Finished() {
+		return nil, ErrImportInProgress
+	}
+
+	file, err := os.CreateTemp("", "health-export-*")
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.Copy(file, reader)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return nil, err
+	}
+
+	size, err := checkHealthExport(file.Name())
+	if err != nil {
+		os.Remove(file.Name())
+		return nil, err
+	}
+
+	now := time.Now()
+	job, err := s.repo.InsertImportJob(context.TODO(), t.ImportJob{
+		ID:         primitive.NewObjectID(),
+		UserId:     userID,
+		Status:     c.ImportPending,
+		FileName:   fileName,
+		BytesTotal: size,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	})
+	if err != nil {
+		os.Remove(file.Name())
+		// another upload got its job in since the check above
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrImportInProgress
+		}
+		return nil, err
+	}
+
+	go s.runHealthImport(*job, file.Name())
+	return job, nil
+}
+
+func (s *importerService) GetImportJobs(userID primitive.ObjectID) ([]t.ImportJob, error) {
+	jobs, err := s.repo.FetchImportJobs(context.TODO(), userID, c.MaxImportJobs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range jobs {
+		if err := s.failStaleJob(&jobs[i]); err != nil {
+			return nil, err
+		}
+	}
+	return jobs, nil
+}
+
+func (s *importerService) GetImportJobById(userID primitive.ObjectID, jobID primitive.ObjectID) (*t.ImportJob, error) {
+	job, err := s.repo.FetchImportJobById(context.TODO(), userID, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, ErrImportJobNotFound
+	}
+	if err := s.failStaleJob(job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// failStaleJob marks an import that stopped making progress as failed, which
+// is what happens to one running when the server restarts.
+func (s *importerService) failStaleJob(job *t.ImportJob) error {
+	if job.Status.Finished() || time.Since(job.UpdatedAt) < c.HealthStaleAfter {
+		return nil
+	}
+	now := time.Now()
+	job.Status = c.ImportFailed
+	job.Error = "import was interrupted, upload the export again"
+	job.UpdatedAt = now
+	job.CompletedAt = &now
+	return s.repo.UpdateImportJob(context.TODO(), *job)
+}
+
+// runHealthImport imports the export at filePath and removes it afterwards,
+// recording how it went on the job.
+func (s *importerService) runHealthImport(job t.ImportJob, filePath string) {
+	defer os.Remove(filePath)
+
+	err := func() (err error) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				err = fmt.Errorf("health import panicked: %v", recovered)
+			}
+		}()
+		return s.importHealth(&job, filePath)
+	}()
+
+	now := time.Now()
+	job.Status = c.ImportCompleted
+	if err != nil {
+		fmt.Println("error importing health export:", err)
+		job.Status = c.ImportFailed
+		job.Error = "Error importing export"
+		if errors.Is(err, ErrInvalidHealthExport) {
+			job.Error = err.Error()
+		}
+	}
+	job.UpdatedAt = now
+	job.CompletedAt = &now
+	if err := s.repo.UpdateImportJob(context.TODO(), job); err != nil {
+		fmt.Println("error saving health import:", err)
+	}
+}
+
+func (s *importerService) importHealth(job *t.ImportJob, filePath string) error {
+	settings, err := s.userService.GetSettings(job.UserId)
+	if err != nil {
+		return err
+	}
+	location, err := s.userService.GetLocation(job.UserId)
+	if err != nil {
+		return err
+	}
+
+	file, _, err := openHealthExport(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	reader := &countingReader{reader: file}
+
+	job.Status = c.ImportReading
+	if err := s.saveProgress(job, reader.read); err != nil {
+		return err
+	}
+	export, err := parseHealth(reader, settings.WeightUnit, func(records int) error {
+		job.Records = records
+		return s.saveProgress(job, reader.read)
+	})
+	if err != nil {
+		return err
+	}
+
+	job.Status = c.ImportSaving
+	job.Records = export.Records
+	job.Measurements = len(export.Weights) + len(export.BodyFat) + len(export.Waist)
+	job.Duplicates = export.Duplicates
+	job.Skipped = export.Skipped
+	job.Days = &wt.MeasurementMergeResult{}
+	if err := s.saveProgress(job, job.BytesTotal); err != nil {
+		return err
+	}
+
+	days := dailyMeasurements(export, location)
+	for start := 0; start < len(days); start += c.HealthSaveBatch {
+		end := min(start+c.HealthSaveBatch, len(days))
+		merged, err := s.workoutService.MergeMeasurements(job.UserId, days[start:end])
+		if err != nil {
+			return err
+		}
+		job.Days.Created += merged.Created
+		job.Days.Updated += merged.Updated
+		job.Days.Unchanged += merged.Unchanged
+		if err := s.saveProgress(job, job.BytesTotal); err != nil {
+			return err
+		}
+	}
+
+	for i, activity := range export.Workouts {
+		result, err := s.saveActivity(job.UserId, activity, job.FileName)
+		switch {
+		case errors.Is(err, workout.ErrInvalidCardio):
+			// the measurements are saved already, so one bad workout is skipped
+			// rather than failing the import
+			job.Skipped++
+		case err != nil:
+			return err
+		case result.Duplicate:
+			job.Duplicates++
+		default:
+			job.Workouts++
+		}
+		if (i+1)%c.HealthSaveBatch == 0 {
+			if err := s.saveProgress(job, job.BytesTotal); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *importerService) saveProgress(job *t.ImportJob, bytesRead int64) error {
+	job.BytesRead = bytesRead
+	if job.BytesTotal > 0 {
+		job.Progress = min(100, float64(bytesRead*1000/job.BytesTotal)/10)
+	}
+	job.UpdatedAt = time.Now()
+	return s.repo.UpdateImportJob(context.TODO(), *job)
+}
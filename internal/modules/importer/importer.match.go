@@ -0,0 +1,98 @@
+package importer
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/importer/constants"
+	wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+)
+
+var equipmentPattern = regexp.MustCompile(`\s*\(([^)]*)\)\s*`)
+
+// catalogName resolves a name or alias to its exercise catalog key.
+func catalogName(name string) (string, bool) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if alias, ok := wc.ExerciseAliases[key]; ok {
+		key = alias
+	}
+	_, ok := wc.ExerciseCatalog[key]
+	return key, ok
+}
+
+// matchCatalog finds the catalog exercise for a name as other apps write it,
+// e.g. "Bench Press (Dumbbell)" or "Pull-Ups", by trying the name as is, with
+// the equipment moved to the front, without the equipment, and without
+// hyphens or a plural s.
+func matchCatalog(name string) (string, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	base := strings.TrimSpace(equipmentPattern.ReplaceAllString(name, " "))
+
+	candidates := []string{name}
+	if match := equipmentPattern.FindStringSubmatch(name); match != nil {
+		candidates = append(candidates, strings.TrimSpace(match[1])+" "+base)
+	}
+	candidates = append(candidates, base)
+
+	for _, candidate := range candidates {
+		for _, variant := range []string{
+			candidate,
+			strings.ReplaceAll(candidate, "-", " "),
+			strings.TrimSuffix(candidate, "s"),
+			strings.TrimSuffix(strings.ReplaceAll(candidate, "-", " "), "s"),
+		} {
+			if key, ok := catalogName(variant); ok {
+				return key, true
+			}
+		}
+	}
+	return "", false
+}
+
+// suggestExercises ranks catalog exercises by how many words they share with
+// name, for the names that couldn't be matched.
+func suggestExercises(name string) []string {
+	words := map[string]bool{}
+	for _, word := range strings.Fields(strings.ToLower(equipmentPattern.ReplaceAllString(name, " "))) {
+		words[strings.TrimSuffix(strings.Trim(word, "-"), "s")] = true
+	}
+
+	type scored struct {
+		name  string
+		score int
+	}
+	candidates := []scored{}
+	for key := range wc.ExerciseCatalog {
+		score := 0
+		for _, word := range strings.Fields(key) {
+			if words[strings.TrimSuffix(word, "s")] {
+				score++
+			}
+		}
+		if score > 0 {
+			candidates = append(candidates, scored{name: key, score: score})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		return candidates[i].name < candidates[j].name
+	})
+
+	suggestions := []string{}
+	for i := 0; i < len(candidates) && i < c.MaxSuggestions; i++ {
+		suggestions = append(suggestions, titleCase(candidates[i].name))
+	}
+	return suggestions
+}
+
+// titleCase writes catalog keys the way exercises are usually logged.
+func titleCase(name string) string {
+	words := strings.Fields(name)
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
+}
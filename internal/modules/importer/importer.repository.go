@@ -0,0 +1,128 @@
+package importer
+
+import (
+	"context"
+	"time"
+
+	db "github.com/joshibbotson/gym-tracker-backend/internal/db"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/importer/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ImporterRepository interface {
+	InsertTrack(ctx context.Context, track t.Track) (*t.Track, error)
+	FetchTrackByStartTime(ctx context.Context, userID primitive.ObjectID, startTime time.Time) (*t.Track, error)
+	FetchTrackByWorkoutId(ctx context.Context, userID primitive.ObjectID, workoutID primitive.ObjectID) (*t.Track, error)
+	RemoveTrack(ctx context.Context, userID primitive.ObjectID, trackID primitive.ObjectID) (bool, error)
+	InsertImportJob(ctx context.Context, job t.ImportJob) (*t.ImportJob, error)
+	UpdateImportJob(ctx context.Context, job t.ImportJob) error
+	FetchImportJobById(ctx context.Context, userID primitive.ObjectID, jobID primitive.ObjectID) (*t.ImportJob, error)
+	FetchImportJobs(ctx context.Context, userID primitive.ObjectID, limit int64) ([]t.ImportJob, error)
+}
+
+type importerRepository struct {
+	trackCollection *mongo.Collection
+	jobCollection   *mongo.Collection
+}
+
+func NewImporterRepository() ImporterRepository {
+	trackCollection := db.Client.Database(db.DB_NAME).Collection("track")
+	// activities are matched on start time, so the same file uploaded twice at
+	// once can only store one track
+	db.EnsureIndexes(trackCollection, mongo.IndexModel{
+		Keys:    bson.D{{Key: "userId", Value: 1}, {Key: "startTime", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+
+	jobCollection := db.Client.Database(db.DB_NAME).Collection("importJob")
+	db.EnsureIndexes(jobCollection, mongo.IndexModel{
+		Keys:    bson.D{{Key: "userId", Value: 1}},
+		Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"active": true}),
+	})
+
+	return &importerRepository{
+		trackCollection: trackCollection,
+		jobCollection:   jobCollection,
+	}
+}
+
+func (r *importerRepository) InsertTrack(ctx context.Context, track t.Track) (*t.Track, error) {
+	if _, err := r.trackCollection.InsertOne(ctx, track); err != nil {
+		return nil, err
+	}
+	return &track, nil
+}
+
+func (r *importerRepository) FetchTrackByStartTime(ctx context.Context, userID primitive.ObjectID, startTime time.Time) (*t.Track, error) {
+	return r.fetchTrack(ctx, bson.M{"userId": userID, "startTime": startTime})
+}
+
+func (r *importerRepository) FetchTrackByWorkoutId(ctx context.Context, userID primitive.ObjectID, workoutID primitive.ObjectID) (*t.Track, error) {
+	return r.fetchTrack(ctx, bson.M{"userId": userID, "workoutId": workoutID})
+}
+
+func (r *importerRepository) fetchTrack(ctx context.Context, filter bson.M) (*t.Track, error) {
+	var track t.Track
+	err := r.trackCollection.FindOne(ctx, filter).Decode(&track)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &track, nil
+}
+
+func (r *importerRepository) RemoveTrack(ctx context.Context, userID primitive.ObjectID, trackID primitive.ObjectID) (bool, error) {
+	result, err := r.trackCollection.DeleteOne(ctx, bson.M{"_id": trackID, "userId": userID})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// InsertImportJob fails with a duplicate key error when the user already has
+// an unfinished import.
+func (r *importerRepository) InsertImportJob(ctx context.Context, job t.ImportJob) (*t.ImportJob, error) {
+	job.Active = !job.Status.Finished()
+	if _, err := r.jobCollection.InsertOne(ctx, job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *importerRepository) UpdateImportJob(ctx context.Context, job t.ImportJob) error {
+	job.Active = !job.Status.Finished()
+	_, err := r.jobCollection.ReplaceOne(ctx, bson.M{"_id": job.ID, "userId": job.UserId}, job)
+	return err
+}
+
+func (r *importerRepository) FetchImportJobById(ctx context.Context, userID primitive.ObjectID, jobID primitive.ObjectID) (*t.ImportJob, error) {
+	var job t.ImportJob
+	err := r.jobCollection.FindOne(ctx, bson.M{"_id": jobID, "userId": userID}).Decode(&job)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *importerRepository) FetchImportJobs(ctx context.Context, userID primitive.ObjectID, limit int64) ([]t.ImportJob, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}).SetLimit(limit)
+	cursor, err := r.jobCollection.Find(ctx, bson.M{"userId": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	jobs := []t.ImportJob{}
+	if err := cursor.All(ctx, &jobs); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
@@ -0,0 +1,259 @@
+package importer
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/importer/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/importer/types"
+	uc "github.com/joshibbotson/gym-tracker-backend/internal/modules/user/constants"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// scaleColumn is a column of a scale file, unit is what its header says the
+// values are in, e.g. "kg" or "%", and empty when it doesn't say.
+type scaleColumn struct {
+	key  string
+	unit string
+}
+
+// ImportScale reads the weigh-ins from a smart scale export and merges the
+// first of each day into the workout logged that day, filling in only what
+// the day doesn't have yet so importing the file again changes nothing.
+func (s *importerService) ImportScale(userID primitive.ObjectID, data []byte, options t.ScaleImportOptions) (*t.ScaleImportSummary, error) {
+	if options.Format != "" && !options.Format.Valid() {
+		return nil, ErrUnsupportedScaleCSV
+	}
+	if options.Unit != "" && !options.Unit.Valid() {
+		return nil, ErrInvalidUnit
+	}
+
+	table, err := readCSV(data)
+	if err != nil {
+		return nil, err
+	}
+	format := options.Format
+	if format == "" {
+		detected, ok := detectScaleFormat(table)
+		if !ok {
+			return nil, ErrUnsupportedScaleCSV
+		}
+		format = detected
+	}
+	columns := scaleColumns(table, format)
+	if _, ok := columns[c.ScaleDate]; !ok || len(columns) < 2 {
+		return nil, ErrUnsupportedScaleCSV
+	}
+
+	settings, err := s.userService.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	fileUnit := options.Unit
+	if fileUnit == "" {
+		fileUnit = settings.WeightUnit
+	}
+
+	summary := &t.ScaleImportSummary{Format: format, Units: map[c.ScaleField]string{}, Errors: []t.RowError{}}
+	for field, column := range columns {
+		if field == c.ScaleDate {
+			continue
+		}
+		column.unit = scaleUnit(field, column.unit, fileUnit)
+		columns[field] = column
+		summary.Units[field] = column.unit
+	}
+
+	readings := []measurementReading{}
+	for i, values := range table.rows {
+		line := i + 2 // the header is line 1
+		reading, ok, err := readScaleRow(table, columns, settings.WeightUnit, values)
+		if err != nil {
+			summary.SkippedRows++
+			if len(summary.Errors) < c.MaxRowErrors {
+				summary.Errors = append(summary.Errors, t.RowError{Line: line, Message: err.Error()})
+			}
+			continue
+		}
+		if !ok {
+			summary.SkippedRows++
+			continue
+		}
+		readings = append(readings, reading)
+	}
+	if len(readings) == 0 {
+		return nil, ErrNoReadings
+	}
+	summary.Readings = len(readings)
+
+	days := earliestByDay(readings)
+	summary.From, summary.To = &days[0].Date, &days[len(days)-1].Date
+
+	merged, err := s.workoutService.MergeMeasurements(userID, days)
+	if err != nil {
+		return nil, err
+	}
+	summary.Days = *merged
+	return summary, nil
+}
+
+// detectScaleFormat picks the format whose columns the file has the most of.
+func detectScaleFormat(table *csvTable) (c.ScaleFormat, bool) {
+	var best c.ScaleFormat
+	bestColumns := 1 // the date and at least one measurement are needed
+	for _, format := range c.ScaleFormats {
+		columns := scaleColumns(table, format)
+		if _, ok := columns[c.ScaleDate]; !ok {
+			continue
+		}
+		if len(columns) > bestColumns {
+			best, bestColumns = format, len(columns)
+		}
+	}
+	return best, best != ""
+}
+
+// scaleColumns finds the columns of each field of format in the file.
+func scaleColumns(table *csvTable, format c.ScaleFormat) map[c.ScaleField]scaleColumn {
+	byName := map[string]scaleColumn{}
+	for key := range table.columns {
+		name, unit := scaleHeader(key)
+		byName[name] = scaleColumn{key: key, unit: unit}
+	}
+
+	columns := map[c.ScaleField]scaleColumn{}
+	for field, names := range c.ScaleColumns[format] {
+		for _, name := range names {
+			if column, ok := byName[name]; ok {
+				columns[field] = column
+				break
+			}
+		}
+	}
+	return columns
+}
+
+// scaleHeader splits a header such as "Body Fat(%)" into "bodyfat" and "%".
+func scaleHeader(header string) (string, string) {
+	name, unit := header, ""
+	if open := strings.IndexAny(header, "(["); open >= 0 {
+		name = header[:open]
+		unit = strings.ToLower(strings.Trim(header[open+1:], " )]"))
+	}
+	name = strings.Map(func(r rune) rune {
+		if r == ' ' || r == '_' || r == '-' {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, name)
+	return name, unit
+}
+
+// scaleUnit settles what a column is in. Weights and masses are in kg or lb,
+// falling back to fileUnit, or "%" when a mass is given as a share of the
+// weight. Body fat and water are "%" unless the header gives a weight unit.
+func scaleUnit(field c.ScaleField, headerUnit string, fileUnit uc.WeightUnit) string {
+	if unit, ok := weightUnitOf(headerUnit); ok {
+		return string(unit)
+	}
+	if field == c.ScaleBodyFat || field == c.ScaleBodyWater || headerUnit == "%" {
+		return "%"
+	}
+	return string(fileUnit)
+}
+
+func weightUnitOf(unit string) (uc.WeightUnit, bool) {
+	switch unit {
+	case "kg", "kgs":
+		return uc.WeightUnitKg, true
+	case "lb", "lbs", "pounds":
+		return uc.WeightUnitLb, true
+	}
+	return "", false
+}
+
+// readScaleRow reads a weigh-in with masses converted to unit, rows without
+// any measurement are skipped. The day is the date as written in the file.
+func readScaleRow(table *csvTable, columns map[c.ScaleField]scaleColumn, unit uc.WeightUnit, values []string) (measurementReading, bool, error) {
+	var reading measurementReading
+
+	date := table.value(values, columns[c.ScaleDate].key)
+	start, err := parseCSVDate(date, c.ScaleDateLayouts)
+	if err != nil {
+		return reading, false, fmt.Errorf("invalid date %q", date)
+	}
+	reading.Time = start
+	reading.Measurement.Date = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+
+	numbers := map[c.ScaleField]float64{}
+	for field, column := range columns {
+		if field == c.ScaleDate {
+			continue
+		}
+		// some scales write the unit into every cell as well, e.g. "21.4%"
+		value := strings.TrimRightFunc(table.value(values, column.key), func(r rune) bool {
+			return unicode.IsLetter(r) || r == '%' || r == ' '
+		})
+		if value == "" || value == "-" || value == "--" {
+			continue
+		}
+		number, err := parseNumber(value)
+		if err != nil || number < 0 {
+			return reading, false, fmt.Errorf("invalid %s %q", field, value)
+		}
+		// scales write 0 for anything they didn't manage to measure
+		if number > 0 {
+			numbers[field] = number
+		}
+	}
+	if len(numbers) == 0 {
+		return reading, false, nil
+	}
+
+	weight, hasWeight := numbers[c.ScaleWeight]
+	if hasWeight {
+		weight = convertWeight(weight, uc.WeightUnit(columns[c.ScaleWeight].unit), unit)
+		reading.Measurement.Weight = &weight
+	}
+
+	for _, field := range []struct {
+		name    c.ScaleField
+		target  **float64
+		percent bool
+	}{
+		{c.ScaleBodyFat, &reading.Measurement.BodyFat, true},
+		{c.ScaleBodyWater, &reading.Measurement.BodyWater, true},
+		{c.ScaleMuscleMass, &reading.Measurement.MuscleMass, false},
+		{c.ScaleBoneMass, &reading.Measurement.BoneMass, false},
+	} {
+		number, ok := numbers[field.name]
+		if !ok {
+			continue
+		}
+		columnUnit := columns[field.name].unit
+		isPercent := columnUnit == "%"
+		if isPercent && number > 100 {
+			return reading, false, fmt.Errorf("invalid %s %v%%", field.name, number)
+		}
+		if isPercent != field.percent && !hasWeight {
+			return reading, false, fmt.Errorf("%s needs the weight to convert from %s", field.name, columnUnit)
+		}
+
+		var value float64
+		switch {
+		case isPercent && field.percent:
+			value = math.Round(number*10) / 10
+		case field.percent:
+			value = math.Round(convertWeight(number, uc.WeightUnit(columnUnit), unit)/weight*1000) / 10
+		case isPercent:
+			value = math.Round(weight*number) / 100
+		default:
+			value = convertWeight(number, uc.WeightUnit(columnUnit), unit)
+		}
+		*field.target = &value
+	}
+	return reading, true, nil
+}
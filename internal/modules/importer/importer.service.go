@@ -0,0 +1,179 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/importer/types"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/user"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/workout"
+	wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+	wt "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+	util "github.com/joshibbotson/gym-tracker-backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type ImporterService interface {
+	ImportActivity(userID primitive.ObjectID, fileName string, data []byte, modality wc.CardioModality) (*t.ActivityImportResult, error)
+	GetTrack(userID primitive.ObjectID, workoutID primitive.ObjectID) (*t.Track, error)
+	ImportWorkouts(userID primitive.ObjectID, data []byte, options t.CSVImportOptions) (*t.CSVImportSummary, error)
+	StartHealthImport(userID primitive.ObjectID, fileName string, reader io.Reader) (*t.ImportJob, error)
+	GetImportJobs(userID primitive.ObjectID) ([]t.ImportJob, error)
+	GetImportJobById(userID primitive.ObjectID, jobID primitive.ObjectID) (*t.ImportJob, error)
+	ImportScale(userID primitive.ObjectID, data []byte, options t.ScaleImportOptions) (*t.ScaleImportSummary, error)
+}
+
+var (
+	ErrUnsupportedFormat   = errors.New("unsupported file, expected GPX, TCX or FIT")
+	ErrInvalidFile         = errors.New("file could not be read")
+	ErrEmptyActivity       = errors.New("file has no timed activity in it")
+	ErrInvalidModality     = errors.New("modality must be run, row, bike, swim or walk")
+	ErrTrackNotFound       = errors.New("track not found")
+	ErrUnsupportedCSV      = errors.New("unsupported CSV, expected a Strong or Hevy export or the generic format")
+	ErrInvalidUnit         = errors.New("unit must be kg or lb")
+	ErrInvalidMapping      = errors.New("mappings must map onto exercises in the catalog, or be empty to keep the name")
+	ErrEmptyImport         = errors.New("file has no sets to import")
+	ErrUnmatchedExercises  = errors.New("some exercises aren't matched, map them or keep their names before committing")
+	ErrInvalidHealthExport = errors.New("file isn't an Apple Health export, upload export.zip or the export.xml inside it")
+	ErrImportInProgress    = errors.New("an import is already running")
+	ErrImportJobNotFound   = errors.New("import not found")
+	ErrUnsupportedScaleCSV = errors.New("unsupported CSV, expected a Withings or Renpho export or the generic scale format")
+	ErrNoReadings          = errors.New("file has no weigh-ins to import")
+)
+
+type importerService struct {
+	repo           ImporterRepository
+	workoutService workout.WorkoutService
+	userService    user.UserService
+}
+
+func NewImporterService(repo ImporterRepository, workoutService workout.WorkoutService, userService user.UserService) ImporterService {
+	return &importerService{repo: repo, workoutService: workoutService, userService: userService}
+}
+
+// ImportActivity stores a cardio workout and its track from an activity file.
+// Files are matched on start time, so uploading one again returns the workout
+// from the first upload. modality overrides the sport in the file when set.
+func (s *importerService) ImportActivity(userID primitive.ObjectID, fileName string, data []byte, modality wc.CardioModality) (*t.ActivityImportResult, error) {
+	if modality != "" && !modality.Valid() {
+		return nil, ErrInvalidModality
+	}
+
+	activity, err := parseActivity(fileName, data)
+	if err != nil {
+		return nil, err
+	}
+	if modality != "" {
+		activity.Modality = modality
+	}
+	return s.saveActivity(userID, *activity, fileName)
+}
+
+// saveActivity creates the workout and track for an activity unless one with
+// the same start time was imported before.
+func (s *importerService) saveActivity(userID primitive.ObjectID, activity t.Activity, fileName string) (*t.ActivityImportResult, error) {
+	activity.StartTime = activity.StartTime.UTC().Truncate(time.Second)
+
+	existing, err := s.repo.FetchTrackByStartTime(context.TODO(), userID, activity.StartTime)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		duplicate, err := s.duplicateActivity(userID, *existing)
+		if err != nil || duplicate != nil {
+			return duplicate, err
+		}
+		// the workout was deleted since, so the old track goes with it
+		if _, err := s.repo.RemoveTrack(context.TODO(), userID, existing.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	location, err := s.userService.GetLocation(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	distance := activity.Distance
+	cardio := &wt.CardioSession{
+		Modality:      activity.Modality,
+		Duration:      activity.Duration,
+		AvgHeartRate:  activity.AvgHeartRate,
+		MaxHeartRate:  activity.MaxHeartRate,
+		ElevationGain: activity.ElevationGain,
+		Calories:      activity.Calories,
+	}
+	if distance > 0 {
+		cardio.Distance = &distance
+	}
+
+	created, err := s.workoutService.CreateWorkout(userID, wt.CreateWorkoutRequest{
+		Date:   util.CalendarDay(activity.StartTime, location),
+		Cardio: cardio,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	track, err := s.repo.InsertTrack(context.TODO(), t.Track{
+		ID:        primitive.NewObjectID(),
+		UserId:    userID,
+		WorkoutID: created.ID,
+		Format:    activity.Format,
+		FileName:  fileName,
+		StartTime: activity.StartTime,
+		Points:    activity.Points,
+		Laps:      activity.Laps,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		// don't leave a workout without its track
+		if discardErr := s.discardWorkout(userID, created.ID); discardErr != nil {
+			return nil, discardErr
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			// the same file was imported at the same time, hand back the copy that won
+			if existing, fetchErr := s.repo.FetchTrackByStartTime(context.TODO(), userID, activity.StartTime); fetchErr == nil && existing != nil {
+				if duplicate, fetchErr := s.duplicateActivity(userID, *existing); fetchErr == nil && duplicate != nil {
+					return duplicate, nil
+				}
+			}
+		}
+		return nil, err
+	}
+
+	return &t.ActivityImportResult{Workout: created, TrackID: track.ID, Laps: track.Laps, Points: len(track.Points)}, nil
+}
+
+// discardWorkout removes a workout for good, trash and revisions included.
+func (s *importerService) discardWorkout(userID primitive.ObjectID, workoutID primitive.ObjectID) error {
+	if _, err := s.workoutService.DeleteWorkout(userID, workoutID); err != nil {
+		return err
+	}
+	_, err := s.workoutService.PurgeWorkout(userID, workoutID)
+	return err
+}
+
+// duplicateActivity returns the earlier import of an activity, or nil if its
+// workout has been deleted.
+func (s *importerService) duplicateActivity(userID primitive.ObjectID, existing t.Track) (*t.ActivityImportResult, error) {
+	imported, err := s.workoutService.GetWorkoutById(userID, existing.WorkoutID)
+	if err != nil || imported == nil {
+		return nil, err
+	}
+	return &t.ActivityImportResult{Duplicate: true, Workout: imported, TrackID: existing.ID, Laps: existing.Laps, Points: len(existing.Points)}, nil
+}
+
+func (s *importerService) GetTrack(userID primitive.ObjectID, workoutID primitive.ObjectID) (*t.Track, error) {
+	track, err := s.repo.FetchTrackByWorkoutId(context.TODO(), userID, workoutID)
+	if err != nil {
+		return nil, err
+	}
+	if track == nil {
+		return nil, ErrTrackNotFound
+	}
+	return track, nil
+}
@@ -0,0 +1,89 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/xml"
+	"math"
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/importer/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/importer/types"
+)
+
+type tcx struct {
+	Activities []struct {
+		Sport string    `xml:"Sport,attr"`
+		ID    time.Time `xml:"Id"`
+		Laps  []struct {
+			StartTime        time.Time `xml:"StartTime,attr"`
+			TotalTimeSeconds float64   `xml:"TotalTimeSeconds"`
+			DistanceMeters   float64   `xml:"DistanceMeters"`
+			Calories         *float64  `xml:"Calories"`
+			AvgHeartRate     *int      `xml:"AverageHeartRateBpm>Value"`
+			MaxHeartRate     *int      `xml:"MaximumHeartRateBpm>Value"`
+			Points           []struct {
+				Time           time.Time `xml:"Time"`
+				Latitude       *float64  `xml:"Position>LatitudeDegrees"`
+				Longitude      *float64  `xml:"Position>LongitudeDegrees"`
+				AltitudeMeters *float64  `xml:"AltitudeMeters"`
+				DistanceMeters *float64  `xml:"DistanceMeters"`
+				HeartRate      *int      `xml:"HeartRateBpm>Value"`
+			} `xml:"Track>Trackpoint"`
+		} `xml:"Lap"`
+	} `xml:"Activities>Activity"`
+}
+
+// parseTCX reads the first activity in the file, its laps carry the totals the
+// device worked out so those are used over anything derived from the points.
+func parseTCX(data []byte) (*t.Activity, error) {
+	var file tcx
+	if err := xml.NewDecoder(bytes.NewReader(data)).Decode(&file); err != nil {
+		return nil, ErrInvalidFile
+	}
+	if len(file.Activities) == 0 {
+		return nil, ErrEmptyActivity
+	}
+	source := file.Activities[0]
+
+	activity := &t.Activity{Format: c.FormatTCX, StartTime: source.ID.UTC(), Points: []t.TrackPoint{}, Laps: []t.Lap{}}
+	if modality, ok := c.ModalityFromName(source.Sport); ok {
+		activity.Modality = modality
+	}
+
+	var calories float64
+	hasCalories := false
+	for _, lap := range source.Laps {
+		activity.Laps = append(activity.Laps, t.Lap{
+			StartTime:    lap.StartTime.UTC(),
+			Duration:     int(math.Round(lap.TotalTimeSeconds)),
+			Distance:     lap.DistanceMeters / 1000,
+			AvgHeartRate: lap.AvgHeartRate,
+			MaxHeartRate: lap.MaxHeartRate,
+			Calories:     lap.Calories,
+		})
+		activity.Duration += int(math.Round(lap.TotalTimeSeconds))
+		activity.Distance += lap.DistanceMeters / 1000
+		if lap.Calories != nil {
+			calories += *lap.Calories
+			hasCalories = true
+		}
+
+		for _, point := range lap.Points {
+			if point.Time.IsZero() {
+				continue
+			}
+			activity.Points = append(activity.Points, t.TrackPoint{
+				Time:      point.Time.UTC(),
+				Latitude:  point.Latitude,
+				Longitude: point.Longitude,
+				Elevation: point.AltitudeMeters,
+				HeartRate: point.HeartRate,
+				Distance:  point.DistanceMeters,
+			})
+		}
+	}
+	if hasCalories {
+		activity.Calories = &calories
+	}
+	return activity, nil
+}
@@ -0,0 +1,272 @@
+package importer
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/importer/types"
+	uc "github.com/joshibbotson/gym-tracker-backend/internal/modules/user/constants"
+	wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+	wt "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// csvSession is the rows of one workout in the file.
+type csvSession struct {
+	Date      time.Time
+	Name      string
+	Exercises []wt.Exercise
+	Duplicate bool
+}
+
+// resolvedExercise is what an exercise name in the file will be saved as.
+type resolvedExercise struct {
+	Name     string
+	Exercise string
+	Rows     int
+	Mapped   bool
+	Matched  bool
+}
+
+// ImportWorkouts reads a Strong, Hevy or generic CSV export. Without Commit it
+// only reports what would be imported; committing is refused while any
+// exercise name is neither matched to the catalog nor given a mapping.
+func (s *importerService) ImportWorkouts(userID primitive.ObjectID, data []byte, options t.CSVImportOptions) (*t.CSVImportSummary, error) {
+	if options.Format != "" && !options.Format.Valid() {
+		return nil, ErrUnsupportedCSV
+	}
+	if options.Unit != "" && !options.Unit.Valid() {
+		return nil, ErrInvalidUnit
+	}
+	mappings := map[string]string{}
+	for name, exercise := range options.Mappings {
+		if exercise != "" {
+			key, ok := catalogName(exercise)
+			if !ok {
+				return nil, ErrInvalidMapping
+			}
+			exercise = titleCase(key)
+		}
+		mappings[strings.ToLower(strings.TrimSpace(name))] = exercise
+	}
+
+	table, err := readCSV(data)
+	if err != nil {
+		return nil, err
+	}
+	format := options.Format
+	if format == "" {
+		detected, ok := detectCSVFormat(table)
+		if !ok {
+			return nil, ErrUnsupportedCSV
+		}
+		format = detected
+	}
+
+	settings, err := s.userService.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	sourceUnit := options.Unit
+	if sourceUnit == "" {
+		sourceUnit = settings.WeightUnit
+	}
+
+	rows, skipped, rowErrors := readRows(table, format, sourceUnit)
+	if len(rows) == 0 {
+		return nil, ErrEmptyImport
+	}
+
+	exercises := resolveExercises(rows, mappings)
+	sessions := groupSessions(rows, exercises, settings.WeightUnit)
+
+	from := sessions[0].Date
+	to := sessions[len(sessions)-1].Date
+	existing, err := s.workoutService.GetWorkoutsInRange(userID, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	markDuplicates(sessions, existing)
+
+	summary := &t.CSVImportSummary{
+		Format:      format,
+		Unit:        settings.WeightUnit,
+		SkippedRows: skipped,
+		From:        &from,
+		To:          &to,
+		Matched:     []t.ExerciseMatch{},
+		Unmatched:   []t.UnmatchedExercise{},
+		Errors:      rowErrors,
+		Workouts:    []t.SessionPreview{},
+	}
+	for _, exercise := range exercises {
+		if !exercise.Matched {
+			summary.Unmatched = append(summary.Unmatched, t.UnmatchedExercise{
+				Name:        exercise.Name,
+				Rows:        exercise.Rows,
+				Suggestions: suggestExercises(exercise.Name),
+			})
+			continue
+		}
+		summary.Matched = append(summary.Matched, t.ExerciseMatch{Name: exercise.Name, Exercise: exercise.Exercise, Rows: exercise.Rows, Mapped: exercise.Mapped})
+	}
+
+	requests := []wt.CreateWorkoutRequest{}
+	for _, session := range sessions {
+		sets := 0
+		for _, exercise := range session.Exercises {
+			sets += len(exercise.Sets)
+		}
+		summary.Workouts = append(summary.Workouts, t.SessionPreview{
+			Date:      session.Date,
+			Name:      session.Name,
+			Exercises: len(session.Exercises),
+			Sets:      sets,
+			Duplicate: session.Duplicate,
+		})
+		if session.Duplicate {
+			summary.Duplicates++
+			continue
+		}
+		summary.Sessions++
+		summary.Sets += sets
+		requests = append(requests, wt.CreateWorkoutRequest{
+			Date:          session.Date,
+			TargetMuscles: primaryMuscles(session.Exercises),
+			Exercises:     session.Exercises,
+		})
+	}
+
+	if !options.Commit {
+		return summary, nil
+	}
+	if len(summary.Unmatched) > 0 {
+		return summary, ErrUnmatchedExercises
+	}
+
+	created, err := s.workoutService.CreateWorkouts(userID, requests)
+	if err != nil {
+		return nil, err
+	}
+	summary.Created = len(created)
+	summary.Committed = true
+	return summary, nil
+}
+
+// resolveExercises works out what each exercise name in the file is saved as,
+// a mapping from the user wins over the catalog match.
+func resolveExercises(rows []csvRow, mappings map[string]string) []*resolvedExercise {
+	byName := map[string]*resolvedExercise{}
+	ordered := []*resolvedExercise{}
+	for _, row := range rows {
+		key := strings.ToLower(strings.TrimSpace(row.Exercise))
+		if exercise, ok := byName[key]; ok {
+			exercise.Rows++
+			continue
+		}
+
+		exercise := &resolvedExercise{Name: strings.TrimSpace(row.Exercise), Rows: 1}
+		if mapped, ok := mappings[key]; ok {
+			exercise.Exercise, exercise.Mapped, exercise.Matched = mapped, true, true
+			if mapped == "" {
+				exercise.Exercise = exercise.Name
+			}
+		} else if catalogKey, ok := matchCatalog(row.Exercise); ok {
+			exercise.Exercise, exercise.Matched = titleCase(catalogKey), true
+		} else {
+			exercise.Exercise = exercise.Name
+		}
+		byName[key] = exercise
+		ordered = append(ordered, exercise)
+	}
+	return ordered
+}
+
+// groupSessions puts rows into a workout per start time and session name,
+// sets of the same exercise are collected under it even when another
+// exercise was done in between, as in a superset.
+func groupSessions(rows []csvRow, exercises []*resolvedExercise, unit uc.WeightUnit) []*csvSession {
+	savedAs := map[string]string{}
+	for _, exercise := range exercises {
+		savedAs[strings.ToLower(exercise.Name)] = exercise.Exercise
+	}
+
+	byKey := map[string]*csvSession{}
+	exerciseIndex := map[*csvSession]map[string]int{}
+	sessions := []*csvSession{}
+	for _, row := range rows {
+		key := row.Start.Format(time.RFC3339) + "|" + row.Session
+		session, ok := byKey[key]
+		if !ok {
+			// dates are kept on the day they were written with, whatever their offset
+			session = &csvSession{
+				Date:      time.Date(row.Start.Year(), row.Start.Month(), row.Start.Day(), 0, 0, 0, 0, time.UTC),
+				Name:      row.Session,
+				Exercises: []wt.Exercise{},
+			}
+			byKey[key] = session
+			exerciseIndex[session] = map[string]int{}
+			sessions = append(sessions, session)
+		}
+
+		name := savedAs[strings.ToLower(strings.TrimSpace(row.Exercise))]
+		i, ok := exerciseIndex[session][strings.ToLower(name)]
+		if !ok {
+			i = len(session.Exercises)
+			exerciseIndex[session][strings.ToLower(name)] = i
+			session.Exercises = append(session.Exercises, wt.Exercise{Name: name, Sets: []wt.ExerciseSet{}})
+		}
+
+		set := wt.ExerciseSet{Reps: row.Reps, Warmup: row.Warmup}
+		if row.Weight != nil {
+			weight := convertWeight(*row.Weight, row.Unit, unit)
+			set.Weight = &weight
+		}
+		session.Exercises[i].Sets = append(session.Exercises[i].Sets, set)
+	}
+
+	sort.SliceStable(sessions, func(i, j int) bool { return sessions[i].Date.Before(sessions[j].Date) })
+	return sessions
+}
+
+// markDuplicates flags sessions that have a workout on the same day with the
+// same exercises, which is what importing the same file twice produces.
+func markDuplicates(sessions []*csvSession, existing []wt.Workout) {
+	signatures := map[string]bool{}
+	for _, workout := range existing {
+		if workout.Workout != nil {
+			signatures[sessionSignature(workout.Date, workout.Workout.Exercises)] = true
+		}
+	}
+	for _, session := range sessions {
+		session.Duplicate = signatures[sessionSignature(session.Date, session.Exercises)]
+	}
+}
+
+func sessionSignature(date time.Time, exercises []wt.Exercise) string {
+	names := make([]string, 0, len(exercises))
+	for _, exercise := range exercises {
+		names = append(names, strings.ToLower(strings.TrimSpace(exercise.Name)))
+	}
+	sort.Strings(names)
+	return date.UTC().Format("2006-01-02") + "|" + strings.Join(names, "|")
+}
+
+func primaryMuscles(exercises []wt.Exercise) []wc.TargetMuscles {
+	seen := map[wc.TargetMuscles]bool{}
+	muscles := []wc.TargetMuscles{}
+	for _, exercise := range exercises {
+		catalogExercise, ok := wc.FindCatalogExercise(exercise.Name)
+		if !ok {
+			continue
+		}
+		for _, muscle := range catalogExercise.Primary {
+			if !seen[muscle] {
+				seen[muscle] = true
+				muscles = append(muscles, muscle)
+			}
+		}
+	}
+	return muscles
+}
@@ -0,0 +1,71 @@
+package types
+
+import (
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/importer/constants"
+	wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+	wt "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TrackPoint is one sample from a recorded activity. Distance is the
+// cumulative distance in metres when the device recorded it.
+type TrackPoint struct {
+	Time      time.Time `bson:"time" json:"time"`
+	Latitude  *float64  `bson:"lat,omitempty" json:"lat,omitempty"`
+	Longitude *float64  `bson:"lon,omitempty" json:"lon,omitempty"`
+	Elevation *float64  `bson:"ele,omitempty" json:"ele,omitempty"`
+	HeartRate *int      `bson:"hr,omitempty" json:"hr,omitempty"`
+	Distance  *float64  `bson:"distance,omitempty" json:"distance,omitempty"`
+}
+
+// Lap is a lap as split by the device, Distance is in km.
+type Lap struct {
+	StartTime    time.Time `bson:"startTime" json:"startTime"`
+	Duration     int       `bson:"duration" json:"duration"` // seconds
+	Distance     float64   `bson:"distance" json:"distance"`
+	AvgHeartRate *int      `bson:"avgHeartRate,omitempty" json:"avgHeartRate,omitempty"`
+	MaxHeartRate *int      `bson:"maxHeartRate,omitempty" json:"maxHeartRate,omitempty"`
+	Calories     *float64  `bson:"calories,omitempty" json:"calories,omitempty"`
+}
+
+// Activity is what was read from a file. Totals the file doesn't carry are
+// worked out from the points.
+type Activity struct {
+	Format        c.ActivityFormat
+	Modality      wc.CardioModality
+	StartTime     time.Time
+	Duration      int     // seconds
+	Distance      float64 // km
+	ElevationGain *float64
+	AvgHeartRate  *int
+	MaxHeartRate  *int
+	Calories      *float64
+	Points        []TrackPoint
+	Laps          []Lap
+}
+
+// Track keeps the samples of an imported activity alongside the workout
+// created from it. StartTime is what re-uploads are matched on.
+type Track struct {
+	ID        primitive.ObjectID `bson:"_id" json:"_id"`
+	UserId    primitive.ObjectID `bson:"userId" json:"-"`
+	WorkoutID primitive.ObjectID `bson:"workoutId" json:"workoutId"`
+	Format    c.ActivityFormat   `bson:"format" json:"format"`
+	FileName  string             `bson:"fileName,omitempty" json:"fileName,omitempty"`
+	StartTime time.Time          `bson:"startTime" json:"startTime"`
+	Points    []TrackPoint       `bson:"points" json:"points"`
+	Laps      []Lap              `bson:"laps" json:"laps"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+type ActivityImportResult struct {
+	// Duplicate is set when the activity had already been imported, Workout
+	// is then the one created the first time.
+	Duplicate bool               `json:"duplicate"`
+	Workout   *wt.Workout        `json:"workout"`
+	TrackID   primitive.ObjectID `json:"trackId"`
+	Laps      []Lap              `json:"laps"`
+	Points    int                `json:"points"`
+}
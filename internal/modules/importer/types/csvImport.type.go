@@ -0,0 +1,64 @@
+package types
+
+import (
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/importer/constants"
+	uc "github.com/joshibbotson/gym-tracker-backend/internal/modules/user/constants"
+)
+
+// CSVImportOptions come with the upload. Mappings are keyed by the exercise
+// name in the file, a catalog exercise maps it onto that exercise and an empty
+// string keeps the name as it is.
+type CSVImportOptions struct {
+	Format   c.CSVFormat
+	Commit   bool
+	Unit     uc.WeightUnit
+	Mappings map[string]string
+}
+
+type ExerciseMatch struct {
+	Name     string `json:"name"`
+	Exercise string `json:"exercise"`
+	Rows     int    `json:"rows"`
+	Mapped   bool   `json:"mapped"`
+}
+
+type UnmatchedExercise struct {
+	Name        string   `json:"name"`
+	Rows        int      `json:"rows"`
+	Suggestions []string `json:"suggestions"`
+}
+
+type RowError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+type SessionPreview struct {
+	Date      time.Time `json:"date"`
+	Name      string    `json:"name,omitempty"`
+	Exercises int       `json:"exercises"`
+	Sets      int       `json:"sets"`
+	Duplicate bool      `json:"duplicate"`
+}
+
+// CSVImportSummary describes what an import will do, or did when Committed.
+// Sessions that match a workout already logged on the same day with the same
+// exercises are Duplicates and aren't imported again.
+type CSVImportSummary struct {
+	Format      c.CSVFormat         `json:"format"`
+	Committed   bool                `json:"committed"`
+	Unit        uc.WeightUnit       `json:"unit"`
+	Sessions    int                 `json:"sessions"`
+	Sets        int                 `json:"sets"`
+	SkippedRows int                 `json:"skippedRows"`
+	Duplicates  int                 `json:"duplicates"`
+	Created     int                 `json:"created"`
+	From        *time.Time          `json:"from,omitempty"`
+	To          *time.Time          `json:"to,omitempty"`
+	Matched     []ExerciseMatch     `json:"matched"`
+	Unmatched   []UnmatchedExercise `json:"unmatched"`
+	Errors      []RowError          `json:"errors"`
+	Workouts    []SessionPreview    `json:"workouts"`
+}
@@ -0,0 +1,39 @@
+package types
+
+import (
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/importer/constants"
+	wt "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ImportJob tracks an Apple Health import running in the background. Progress
+// is the percentage of the export read so far.
+type ImportJob struct {
+	ID         primitive.ObjectID `bson:"_id" json:"_id"`
+	UserId     primitive.ObjectID `bson:"userId" json:"-"`
+	Status     c.ImportStatus     `bson:"status" json:"status"`
+	FileName   string             `bson:"fileName,omitempty" json:"fileName,omitempty"`
+	BytesTotal int64              `bson:"bytesTotal" json:"bytesTotal"`
+	BytesRead  int64              `bson:"bytesRead" json:"bytesRead"`
+	Progress   float64            `bson:"progress" json:"progress"`
+	// Records counts every record and workout read, Measurements the
+	// weight, body fat and waist samples kept from them.
+	Records      int `bson:"records" json:"records"`
+	Measurements int `bson:"measurements" json:"measurements"`
+	// Days is how the measurements landed once saved, one workout per day.
+	Days *wt.MeasurementMergeResult `bson:"days,omitempty" json:"days,omitempty"`
+	// Workouts are the cardio workouts created, Duplicates the samples and
+	// workouts already seen at the same time, Skipped what couldn't be used.
+	Workouts    int        `bson:"workouts" json:"workouts"`
+	Duplicates  int        `bson:"duplicates" json:"duplicates"`
+	Skipped     int        `bson:"skipped" json:"skipped"`
+	Error       string     `bson:"error,omitempty" json:"error,omitempty"`
+	CreatedAt   time.Time  `bson:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time  `bson:"updatedAt" json:"updatedAt"`
+	CompletedAt *time.Time `bson:"completedAt,omitempty" json:"completedAt,omitempty"`
+	// Active is set while the job is unfinished, a unique index on it lets
+	// only one import run per user.
+	Active bool `bson:"active,omitempty" json:"-"`
+}
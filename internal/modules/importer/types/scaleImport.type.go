@@ -0,0 +1,29 @@
+package types
+
+import (
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/importer/constants"
+	uc "github.com/joshibbotson/gym-tracker-backend/internal/modules/user/constants"
+	wt "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+)
+
+// ScaleImportOptions come with the upload, Unit is used for weight columns
+// whose header doesn't say which unit they're in.
+type ScaleImportOptions struct {
+	Format c.ScaleFormat
+	Unit   uc.WeightUnit
+}
+
+// ScaleImportSummary describes an import, Days is how the first reading of
+// each day was merged into the workout logged that day.
+type ScaleImportSummary struct {
+	Format      c.ScaleFormat             `json:"format"`
+	Units       map[c.ScaleField]string   `json:"units"`
+	Readings    int                       `json:"readings"`
+	SkippedRows int                       `json:"skippedRows"`
+	Days        wt.MeasurementMergeResult `json:"days"`
+	From        *time.Time                `json:"from,omitempty"`
+	To          *time.Time                `json:"to,omitempty"`
+	Errors      []RowError                `json:"errors"`
+}
@@ -0,0 +1,11 @@
+package constants
+
+// IntakeSource records where a day's intake came from. Entries saved before
+// sources were tracked have none and are treated as manual.
+type IntakeSource string
+
+const (
+	IntakeSourceManual IntakeSource = "manual"
+	// IntakeSourceMeals is kept in step with the meals logged that day.
+	IntakeSourceMeals IntakeSource = "meals"
+)
@@ -0,0 +1,123 @@
+package intake
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	m "github.com/joshibbotson/gym-tracker-backend/internal/middleware"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/intake/types"
+	util "github.com/joshibbotson/gym-tracker-backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type IntakeHandler struct {
+	Service IntakeService
+}
+
+func NewIntakeHandler(service IntakeService) *IntakeHandler {
+	return &IntakeHandler{
+		Service: service,
+	}
+}
+
+// Handler serves /intake and /intake/{date}
+func (h *IntakeHandler) Handler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		m.PermissionMiddleware(h.handleLogIntake)(w, r)
+	case http.MethodGet:
+		if r.PathValue("date") != "" {
+			m.PermissionMiddleware(h.handleReadIntakeByDate)(w, r)
+			break
+		}
+		m.PermissionMiddleware(h.handleReadIntake)(w, r)
+	case http.MethodDelete:
+		m.PermissionMiddleware(h.handleDeleteIntake)(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *IntakeHandler) handleLogIntake(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	var request t.IntakeRequest
+	if !util.DecodeBody(w, r, &request) {
+		return
+	}
+
+	entry, err := h.Service.LogIntake(userID, request)
+	if errors.Is(err, ErrInvalidIntake) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error logging intake", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, entry)
+}
+
+// handleReadIntake lists the intake logged between the from and to query
+// params, defaulting to the last 30 days.
+func (h *IntakeHandler) handleReadIntake(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	from, to, ok := util.ParseDateRange(w, r, time.Now().AddDate(0, 0, -30))
+	if !ok {
+		return
+	}
+
+	entries, err := h.Service.GetIntakeInRange(userID, from, to)
+	if err != nil {
+		http.Error(w, "Error fetching intake", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, entries)
+}
+
+func (h *IntakeHandler) handleReadIntakeByDate(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+	date, err := util.ParseDate(r.PathValue("date"))
+	if err != nil {
+		http.Error(w, "Error parsing date", http.StatusBadRequest)
+		return
+	}
+
+	entry, err := h.Service.GetIntakeByDate(userID, date)
+	if errors.Is(err, ErrIntakeNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error fetching intake", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, entry)
+}
+
+func (h *IntakeHandler) handleDeleteIntake(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+	date, err := util.ParseDate(r.PathValue("date"))
+	if err != nil {
+		http.Error(w, "Error parsing date", http.StatusBadRequest)
+		return
+	}
+
+	success, err := h.Service.DeleteIntake(userID, date)
+	if err != nil {
+		http.Error(w, "Error deleting intake", http.StatusInternalServerError)
+		return
+	}
+	if !success {
+		http.Error(w, "Intake not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"message": "Intake deleted successfully"}`))
+}
@@ -0,0 +1,133 @@
+package intake
+
+import (
+	"context"
+	"time"
+
+	db "github.com/joshibbotson/gym-tracker-backend/internal/db"
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/intake/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/intake/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IntakeRepository interface {
+	UpsertIntake(ctx context.Context, entry t.IntakeEntry) (*t.IntakeEntry, error)
+	UpsertMealIntake(ctx context.Context, entry t.IntakeEntry) (*t.IntakeEntry, error)
+	FetchIntakeByDate(ctx context.Context, userID primitive.ObjectID, date time.Time) (*t.IntakeEntry, error)
+	FetchIntakeInRange(ctx context.Context, userID primitive.ObjectID, from time.Time, to time.Time) ([]t.IntakeEntry, error)
+	RemoveIntake(ctx context.Context, userID primitive.ObjectID, date time.Time) (bool, error)
+	RemoveMealIntake(ctx context.Context, userID primitive.ObjectID, date time.Time) (bool, error)
+}
+
+type intakeRepository struct {
+	intakeCollection *mongo.Collection
+}
+
+func NewIntakeRepository() IntakeRepository {
+	intakeCollection := db.Client.Database(db.DB_NAME).Collection("intake")
+	// one entry a day, so a meal sync can't insert beside a manual entry
+	db.EnsureIndexes(intakeCollection, mongo.IndexModel{
+		Keys:    bson.D{{Key: "userId", Value: 1}, {Key: "date", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+
+	return &intakeRepository{
+		intakeCollection: intakeCollection,
+	}
+}
+
+// UpsertIntake replaces the entry for the user and day, keeping the id and
+// createdAt of an existing one.
+func (r *intakeRepository) UpsertIntake(ctx context.Context, entry t.IntakeEntry) (*t.IntakeEntry, error) {
+	return r.upsertIntake(ctx, bson.M{"userId": entry.UserId, "date": entry.Date}, entry)
+}
+
+// UpsertMealIntake saves the entry only if the day has no entry or one synced
+// from meals, it returns nil when a manual entry is already there.
+func (r *intakeRepository) UpsertMealIntake(ctx context.Context, entry t.IntakeEntry) (*t.IntakeEntry, error) {
+	filter := bson.M{"userId": entry.UserId, "date": entry.Date, "source": c.IntakeSourceMeals}
+	saved, err := r.upsertIntake(ctx, filter, entry)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, nil
+	}
+	return saved, err
+}
+
+func (r *intakeRepository) upsertIntake(ctx context.Context, filter bson.M, entry t.IntakeEntry) (*t.IntakeEntry, error) {
+	update := bson.M{
+		"$set": bson.M{
+			"calories":  entry.Calories,
+			"protein":   entry.Protein,
+			"carbs":     entry.Carbs,
+			"fat":       entry.Fat,
+			"fibre":     entry.Fibre,
+			"notes":     entry.Notes,
+			"source":    entry.Source,
+			"updatedAt": entry.UpdatedAt,
+		},
+		"$setOnInsert": bson.M{
+			"_id":       entry.ID,
+			"createdAt": entry.CreatedAt,
+		},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var saved t.IntakeEntry
+	if err := r.intakeCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&saved); err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+func (r *intakeRepository) FetchIntakeByDate(ctx context.Context, userID primitive.ObjectID, date time.Time) (*t.IntakeEntry, error) {
+	var entry t.IntakeEntry
+	err := r.intakeCollection.FindOne(ctx, bson.M{"userId": userID, "date": date}).Decode(&entry)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// FetchIntakeInRange returns entries with from <= date < to, oldest first.
+func (r *intakeRepository) FetchIntakeInRange(ctx context.Context, userID primitive.ObjectID, from time.Time, to time.Time) ([]t.IntakeEntry, error) {
+	filter := bson.M{
+		"userId": userID,
+		"date":   bson.M{"$gte": from, "$lt": to},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}})
+
+	cursor, err := r.intakeCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	entries := []t.IntakeEntry{}
+	if err = cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *intakeRepository) RemoveIntake(ctx context.Context, userID primitive.ObjectID, date time.Time) (bool, error) {
+	result, err := r.intakeCollection.DeleteOne(ctx, bson.M{"userId": userID, "date": date})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// RemoveMealIntake deletes the day's entry only if it was synced from meals.
+func (r *intakeRepository) RemoveMealIntake(ctx context.Context, userID primitive.ObjectID, date time.Time) (bool, error) {
+	result, err := r.intakeCollection.DeleteOne(ctx, bson.M{"userId": userID, "date": date, "source": c.IntakeSourceMeals})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
@@ -0,0 +1,131 @@
+package intake
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/intake/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/intake/types"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/user"
+	util "github.com/joshibbotson/gym-tracker-backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type IntakeService interface {
+	LogIntake(userID primitive.ObjectID, intake t.IntakeRequest) (*t.IntakeEntry, error)
+	GetIntakeByDate(userID primitive.ObjectID, date time.Time) (*t.IntakeEntry, error)
+	GetIntakeInRange(userID primitive.ObjectID, from time.Time, to time.Time) ([]t.IntakeEntry, error)
+	DeleteIntake(userID primitive.ObjectID, date time.Time) (bool, error)
+	SyncMealIntake(userID primitive.ObjectID, intake t.IntakeRequest) (*t.IntakeEntry, error)
+	ClearMealIntake(userID primitive.ObjectID, date time.Time) (bool, error)
+}
+
+var (
+	ErrIntakeNotFound = errors.New("intake not found")
+	ErrInvalidIntake  = errors.New("intake needs a date and calories, macros can't be negative")
+)
+
+type intakeService struct {
+	repo        IntakeRepository
+	userService user.UserService
+}
+
+func NewIntakeService(repo IntakeRepository, userService user.UserService) IntakeService {
+	return &intakeService{repo: repo, userService: userService}
+}
+
+// LogIntake saves the intake for a day, replacing anything already logged for
+// it. Meals logged that day won't overwrite it afterwards.
+func (s *intakeService) LogIntake(userID primitive.ObjectID, intake t.IntakeRequest) (*t.IntakeEntry, error) {
+	entry, err := s.newEntry(userID, intake, c.IntakeSourceManual)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.UpsertIntake(context.TODO(), *entry)
+}
+
+// SyncMealIntake saves the totals of a day's meals as its intake, unless the
+// user logged that day by hand. It returns nil when the manual entry is kept.
+func (s *intakeService) SyncMealIntake(userID primitive.ObjectID, intake t.IntakeRequest) (*t.IntakeEntry, error) {
+	entry, err := s.newEntry(userID, intake, c.IntakeSourceMeals)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.UpsertMealIntake(context.TODO(), *entry)
+}
+
+func (s *intakeService) newEntry(userID primitive.ObjectID, intake t.IntakeRequest, source c.IntakeSource) (*t.IntakeEntry, error) {
+	if !validIntake(intake) {
+		return nil, ErrInvalidIntake
+	}
+	location, err := s.userService.GetLocation(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &t.IntakeEntry{
+		ID:        primitive.NewObjectID(),
+		UserId:    userID,
+		Date:      util.CalendarDay(intake.Date, location),
+		Calories:  intake.Calories,
+		Protein:   intake.Protein,
+		Carbs:     intake.Carbs,
+		Fat:       intake.Fat,
+		Fibre:     intake.Fibre,
+		Notes:     strings.TrimSpace(intake.Notes),
+		Source:    source,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+func (s *intakeService) GetIntakeByDate(userID primitive.ObjectID, date time.Time) (*t.IntakeEntry, error) {
+	location, err := s.userService.GetLocation(userID)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := s.repo.FetchIntakeByDate(context.TODO(), userID, util.CalendarDay(date, location))
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, ErrIntakeNotFound
+	}
+	return entry, nil
+}
+
+func (s *intakeService) GetIntakeInRange(userID primitive.ObjectID, from time.Time, to time.Time) ([]t.IntakeEntry, error) {
+	return s.repo.FetchIntakeInRange(context.TODO(), userID, from, to)
+}
+
+func (s *intakeService) DeleteIntake(userID primitive.ObjectID, date time.Time) (bool, error) {
+	location, err := s.userService.GetLocation(userID)
+	if err != nil {
+		return false, err
+	}
+	return s.repo.RemoveIntake(context.TODO(), userID, util.CalendarDay(date, location))
+}
+
+// ClearMealIntake removes a day's intake once it has no meals, leaving an
+// entry the user logged by hand alone.
+func (s *intakeService) ClearMealIntake(userID primitive.ObjectID, date time.Time) (bool, error) {
+	location, err := s.userService.GetLocation(userID)
+	if err != nil {
+		return false, err
+	}
+	return s.repo.RemoveMealIntake(context.TODO(), userID, util.CalendarDay(date, location))
+}
+
+func validIntake(intake t.IntakeRequest) bool {
+	if intake.Date.IsZero() || intake.Calories <= 0 {
+		return false
+	}
+	for _, macro := range []*float64{intake.Protein, intake.Carbs, intake.Fat, intake.Fibre} {
+		if macro != nil && *macro < 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,35 @@
+package types
+
+import (
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/intake/constants"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// IntakeEntry is what a user ate on one day, there is at most one per day.
+// Macros are in grams.
+type IntakeEntry struct {
+	ID        primitive.ObjectID `bson:"_id" json:"_id"`
+	UserId    primitive.ObjectID `bson:"userId" json:"-"`
+	Date      time.Time          `bson:"date" json:"date"`
+	Calories  float64            `bson:"calories" json:"calories"`
+	Protein   *float64           `bson:"protein,omitempty" json:"protein,omitempty"`
+	Carbs     *float64           `bson:"carbs,omitempty" json:"carbs,omitempty"`
+	Fat       *float64           `bson:"fat,omitempty" json:"fat,omitempty"`
+	Fibre     *float64           `bson:"fibre,omitempty" json:"fibre,omitempty"`
+	Notes     string             `bson:"notes,omitempty" json:"notes,omitempty"`
+	Source    c.IntakeSource     `bson:"source,omitempty" json:"source,omitempty"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+type IntakeRequest struct {
+	Date     time.Time `json:"date"`
+	Calories float64   `json:"calories"`
+	Protein  *float64  `json:"protein,omitempty"`
+	Carbs    *float64  `json:"carbs,omitempty"`
+	Fat      *float64  `json:"fat,omitempty"`
+	Fibre    *float64  `json:"fibre,omitempty"`
+	Notes    string    `json:"notes,omitempty"`
+}
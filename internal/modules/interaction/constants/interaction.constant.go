@@ -0,0 +1,21 @@
+package constants
+
+type ReactionKind string
+
+const (
+	ReactionLike   ReactionKind = "like"
+	ReactionFire   ReactionKind = "fire"
+	ReactionStrong ReactionKind = "strong"
+	ReactionClap   ReactionKind = "clap"
+)
+
+func (k ReactionKind) Valid() bool {
+	return k == ReactionLike || k == ReactionFire || k == ReactionStrong || k == ReactionClap
+}
+
+// MaxCommentLength is in characters, after surrounding whitespace is trimmed.
+const MaxCommentLength = 1000
+
+// MaxCommentDepth is how many replies deep a thread can go, top level
+// comments are depth 0.
+const MaxCommentDepth = 3
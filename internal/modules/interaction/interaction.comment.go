@@ -0,0 +1,205 @@
+package interaction
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/interaction/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/interaction/types"
+	nc "github.com/joshibbotson/gym-tracker-backend/internal/modules/notification/constants"
+	nt "github.com/joshibbotson/gym-tracker-backend/internal/modules/notification/types"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/workout"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CreateComment comments on the workout, or replies to a comment on it when
+// ParentId is set, and notifies the workout's owner.
+func (s *interactionService) CreateComment(userID primitive.ObjectID, workoutID primitive.ObjectID, request t.CreateCommentRequest) (*t.Comment, error) {
+	body, err := commentBody(request.Body)
+	if err != nil {
+		return nil, err
+	}
+	ownerID, err := s.visibleOwner(userID, workoutID)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := t.Comment{
+		ID:        primitive.NewObjectID(),
+		WorkoutId: workoutID,
+		UserId:    userID,
+		Body:      body,
+		CreatedAt: time.Now(),
+	}
+	if request.ParentId != nil {
+		parent, err := s.repo.FetchComment(context.TODO(), *request.ParentId)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil || parent.WorkoutId != workoutID || parent.DeletedAt != nil {
+			return nil, ErrCommentNotFound
+		}
+		if parent.Depth >= c.MaxCommentDepth {
+			return nil, ErrThreadTooDeep
+		}
+		comment.ParentId = &parent.ID
+		comment.Depth = parent.Depth + 1
+	}
+
+	saved, err := s.repo.InsertComment(context.TODO(), comment)
+	if err != nil {
+		return nil, err
+	}
+	err = s.notificationService.Notify(nt.Notification{
+		UserId:    ownerID,
+		ActorId:   userID,
+		Kind:      nc.NotificationComment,
+		WorkoutId: workoutID,
+		CommentId: &saved.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	saved.Replies = []t.Comment{}
+	return saved, nil
+}
+
+// commentBody trims the body and checks it isn't empty or too long.
+func commentBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" || utf8.RuneCountInString(body) > c.MaxCommentLength {
+		return "", ErrInvalidComment
+	}
+	return body, nil
+}
+
+// GetComments returns the comments on the workout as threads, oldest first at
+// every level. A deleted comment is only kept while it has replies that
+// aren't.
+func (s *interactionService) GetComments(userID primitive.ObjectID, workoutID primitive.ObjectID) ([]t.Comment, error) {
+	if _, err := s.visibleOwner(userID, workoutID); err != nil {
+		return nil, err
+	}
+	comments, err := s.repo.FetchComments(context.TODO(), workoutID)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]primitive.ObjectID, 0, len(comments))
+	for _, comment := range comments {
+		if comment.DeletedAt == nil {
+			userIDs = append(userIDs, comment.UserId)
+		}
+	}
+	profiles, err := s.userService.GetProfiles(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range comments {
+		if profile, ok := profiles[comments[i].UserId]; ok && comments[i].DeletedAt == nil {
+			comments[i].Author = &profile
+		}
+	}
+	return threadComments(comments), nil
+}
+
+// threadComments nests replies under their parents. comments are oldest first
+// so every level stays in that order.
+func threadComments(comments []t.Comment) []t.Comment {
+	children := map[primitive.ObjectID][]t.Comment{}
+	var roots []t.Comment
+	for _, comment := range comments {
+		if comment.ParentId == nil {
+			roots = append(roots, comment)
+			continue
+		}
+		children[*comment.ParentId] = append(children[*comment.ParentId], comment)
+	}
+
+	var build func(level []t.Comment) []t.Comment
+	build = func(level []t.Comment) []t.Comment {
+		thread := []t.Comment{}
+		for _, comment := range level {
+			comment.Replies = build(children[comment.ID])
+			if comment.DeletedAt != nil && len(comment.Replies) == 0 {
+				continue
+			}
+			thread = append(thread, comment)
+		}
+		return thread
+	}
+	return build(roots)
+}
+
+// UpdateComment edits the body of a comment, only its author can.
+func (s *interactionService) UpdateComment(userID primitive.ObjectID, commentID primitive.ObjectID, request t.UpdateCommentRequest) (*t.Comment, error) {
+	body, err := commentBody(request.Body)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.repo.FetchComment(context.TODO(), commentID)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil || existing.DeletedAt != nil {
+		return nil, ErrCommentNotFound
+	}
+	if existing.UserId != userID {
+		return nil, ErrNotCommentAuthor
+	}
+	if _, err := s.visibleOwner(userID, existing.WorkoutId); err != nil {
+		if errors.Is(err, workout.ErrWorkoutNotFound) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, err
+	}
+
+	comment, err := s.repo.UpdateCommentBody(context.TODO(), userID, commentID, body, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if comment == nil {
+		return nil, ErrCommentNotFound
+	}
+	comment.Replies = []t.Comment{}
+	return comment, nil
+}
+
+// DeleteComment deletes a comment for its author, or moderates it away for
+// the owner of the workout it's on.
+func (s *interactionService) DeleteComment(userID primitive.ObjectID, commentID primitive.ObjectID) error {
+	comment, err := s.repo.FetchComment(context.TODO(), commentID)
+	if err != nil {
+		return err
+	}
+	if comment == nil || comment.DeletedAt != nil {
+		return ErrCommentNotFound
+	}
+
+	moderated := false
+	if comment.UserId != userID {
+		ownerID, err := s.workoutService.GetWorkoutOwner(comment.WorkoutId)
+		if err != nil {
+			if errors.Is(err, workout.ErrWorkoutNotFound) {
+				return ErrCommentNotFound
+			}
+			return err
+		}
+		if ownerID != userID {
+			return ErrCannotDeleteComment
+		}
+		moderated = true
+	}
+
+	deleted, err := s.repo.SoftDeleteComment(context.TODO(), commentID, time.Now(), moderated)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrCommentNotFound
+	}
+	return s.notificationService.RetractComment(commentID)
+}
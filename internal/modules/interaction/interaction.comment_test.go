@@ -0,0 +1,94 @@
+package interaction
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/interaction/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/interaction/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// comments builds a thread from "body" or "body<parent" specs, oldest first. A
+// body starting with "-" is a deleted comment.
+func comments(specs ...string) []t.Comment {
+	ids := map[string]primitive.ObjectID{}
+	built := []t.Comment{}
+	for _, spec := range specs {
+		body, parent, _ := strings.Cut(spec, "<")
+		comment := t.Comment{ID: primitive.NewObjectID(), Body: strings.TrimPrefix(body, "-")}
+		if parent != "" {
+			parentID := ids[parent]
+			comment.ParentId = &parentID
+		}
+		if strings.HasPrefix(body, "-") {
+			deletedAt := time.Now()
+			comment.DeletedAt = &deletedAt
+		}
+		ids[comment.Body] = comment.ID
+		built = append(built, comment)
+	}
+	return built
+}
+
+// render writes a thread out as "a(b c(d)) e".
+func render(thread []t.Comment) string {
+	parts := make([]string, len(thread))
+	for i, comment := range thread {
+		parts[i] = comment.Body
+		if len(comment.Replies) > 0 {
+			parts[i] += "(" + render(comment.Replies) + ")"
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestThreadComments(tt *testing.T) {
+	tests := []struct {
+		name     string
+		comments []t.Comment
+		want     string
+	}{
+		{"no comments", nil, ""},
+		{"top level stays oldest first", comments("a", "b", "c"), "a b c"},
+		{"replies nest under their parent in order", comments("a", "b", "c<a", "d<c", "e<a"), "a(c(d) e) b"},
+		{"deleted without replies is dropped", comments("a", "-b", "c<a", "-d<c"), "a(c)"},
+		{"deleted with a reply is kept", comments("-a", "b<a", "c"), "a(b) c"},
+		{"deleted with only deleted replies is dropped", comments("-a", "-b<a", "-c<b", "d"), "d"},
+		{"a live reply deep down keeps its deleted parents", comments("-a", "-b<a", "c<b"), "a(b(c))"},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			if got := render(threadComments(test.comments)); got != test.want {
+				tt.Fatalf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCommentBody(tt *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    string
+		wantErr error
+	}{
+		{"trimmed", "  nice lift \n", "nice lift", nil},
+		{"empty", "", "", ErrInvalidComment},
+		{"only whitespace", " \t\n", "", ErrInvalidComment},
+		{"at the limit in characters", strings.Repeat("💪", c.MaxCommentLength), strings.Repeat("💪", c.MaxCommentLength), nil},
+		{"over the limit", strings.Repeat("a", c.MaxCommentLength+1), "", ErrInvalidComment},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			got, err := commentBody(test.body)
+			if !errors.Is(err, test.wantErr) || got != test.want {
+				tt.Fatalf("got %q, %v, want %q, %v", got, err, test.want, test.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package interaction
+
+import (
+	"errors"
+	"net/http"
+
+	m "github.com/joshibbotson/gym-tracker-backend/internal/middleware"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/interaction/types"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/workout"
+	util "github.com/joshibbotson/gym-tracker-backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type InteractionHandler struct {
+	Service InteractionService
+}
+
+func NewInteractionHandler(service InteractionService) *InteractionHandler {
+	return &InteractionHandler{
+		Service: service,
+	}
+}
+
+// ReactionHandler serves /reaction/workout/{workoutId}
+func (h *InteractionHandler) ReactionHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		m.PermissionMiddleware(h.handleReadReactions)(w, r)
+	case http.MethodPut:
+		m.PermissionMiddleware(h.handleReact)(w, r)
+	case http.MethodDelete:
+		m.PermissionMiddleware(h.handleUnreact)(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// CommentHandler serves /comment/workout/{workoutId} and /comment/{id}
+func (h *InteractionHandler) CommentHandler(w http.ResponseWriter, r *http.Request) {
+	onWorkout := r.PathValue("workoutId") != ""
+	switch {
+	case onWorkout && r.Method == http.MethodGet:
+		m.PermissionMiddleware(h.handleReadComments)(w, r)
+	case onWorkout && r.Method == http.MethodPost:
+		m.PermissionMiddleware(h.handleCreateComment)(w, r)
+	case !onWorkout && r.Method == http.MethodPatch:
+		m.PermissionMiddleware(h.handleUpdateComment)(w, r)
+	case !onWorkout && r.Method == http.MethodDelete:
+		m.PermissionMiddleware(h.handleDeleteComment)(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleReact reacts to the workout, or changes the user's reaction to it.
+func (h *InteractionHandler) handleReact(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	workoutID, ok := util.ParseObjectID(w, r.PathValue("workoutId"))
+	if !ok {
+		return
+	}
+	var request t.ReactionRequest
+	if !util.DecodeBody(w, r, &request) {
+		return
+	}
+
+	reaction, err := h.Service.React(userID, workoutID, request)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidReaction):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, workout.ErrWorkoutNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		default:
+			http.Error(w, "Error saving reaction", http.StatusInternalServerError)
+		}
+		return
+	}
+	util.WriteJSON(w, http.StatusOK, reaction)
+}
+
+func (h *InteractionHandler) handleUnreact(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	workoutID, ok := util.ParseObjectID(w, r.PathValue("workoutId"))
+	if !ok {
+		return
+	}
+
+	deleted, err := h.Service.Unreact(userID, workoutID)
+	if err != nil {
+		http.Error(w, "Error deleting reaction", http.StatusInternalServerError)
+		return
+	}
+	if !deleted {
+		http.Error(w, "Reaction not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"message": "Reaction deleted successfully"}`))
+}
+
+func (h *InteractionHandler) handleReadReactions(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	workoutID, ok := util.ParseObjectID(w, r.PathValue("workoutId"))
+	if !ok {
+		return
+	}
+
+	reactions, err := h.Service.GetReactions(userID, workoutID)
+	if err != nil {
+		if errors.Is(err, workout.ErrWorkoutNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Error fetching reactions", http.StatusInternalServerError)
+		return
+	}
+	util.WriteJSON(w, http.StatusOK, reactions)
+}
+
+// handleReadComments returns the comments on a workout as threads.
+func (h *InteractionHandler) handleReadComments(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	workoutID, ok := util.ParseObjectID(w, r.PathValue("workoutId"))
+	if !ok {
+		return
+	}
+
+	comments, err := h.Service.GetComments(userID, workoutID)
+	if err != nil {
+		if errors.Is(err, workout.ErrWorkoutNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Error fetching comments", http.StatusInternalServerError)
+		return
+	}
+	util.WriteJSON(w, http.StatusOK, comments)
+}
+
+// handleCreateComment takes a parentId in the body to reply to a comment.
+func (h *InteractionHandler) handleCreateComment(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	workoutID, ok := util.ParseObjectID(w, r.PathValue("workoutId"))
+	if !ok {
+		return
+	}
+	var request t.CreateCommentRequest
+	if !util.DecodeBody(w, r, &request) {
+		return
+	}
+
+	comment, err := h.Service.CreateComment(userID, workoutID, request)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidComment), errors.Is(err, ErrThreadTooDeep):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, workout.ErrWorkoutNotFound), errors.Is(err, ErrCommentNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		default:
+			http.Error(w, "Error creating comment", http.StatusInternalServerError)
+		}
+		return
+	}
+	util.WriteJSON(w, http.StatusCreated, comment)
+}
+
+func (h *InteractionHandler) handleUpdateComment(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	commentID, ok := util.ParseObjectID(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+	var request t.UpdateCommentRequest
+	if !util.DecodeBody(w, r, &request) {
+		return
+	}
+
+	comment, err := h.Service.UpdateComment(userID, commentID, request)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidComment):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, ErrCommentNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, ErrNotCommentAuthor):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		default:
+			http.Error(w, "Error updating comment", http.StatusInternalServerError)
+		}
+		return
+	}
+	util.WriteJSON(w, http.StatusOK, comment)
+}
+
+// handleDeleteComment lets the author delete their comment and the workout's
+// owner remove any comment on it.
+func (h *InteractionHandler) handleDeleteComment(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	commentID, ok := util.ParseObjectID(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+
+	if err := h.Service.DeleteComment(userID, commentID); err != nil {
+		switch {
+		case errors.Is(err, ErrCommentNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, ErrCannotDeleteComment):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		default:
+			http.Error(w, "Error deleting comment", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"message": "Comment deleted successfully"}`))
+}
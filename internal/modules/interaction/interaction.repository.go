@@ -0,0 +1,219 @@
+package interaction
+
+import (
+	"context"
+	"time"
+
+	db "github.com/joshibbotson/gym-tracker-backend/internal/db"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/interaction/types"
+	wt "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type InteractionRepository interface {
+	UpsertReaction(ctx context.Context, reaction t.Reaction) (*t.Reaction, error)
+	RemoveReaction(ctx context.Context, workoutID primitive.ObjectID, userID primitive.ObjectID) (bool, error)
+	FetchReactions(ctx context.Context, workoutID primitive.ObjectID) ([]t.Reaction, error)
+	InsertComment(ctx context.Context, comment t.Comment) (*t.Comment, error)
+	FetchComment(ctx context.Context, commentID primitive.ObjectID) (*t.Comment, error)
+	FetchComments(ctx context.Context, workoutID primitive.ObjectID) ([]t.Comment, error)
+	UpdateCommentBody(ctx context.Context, userID primitive.ObjectID, commentID primitive.ObjectID, body string, editedAt time.Time) (*t.Comment, error)
+	SoftDeleteComment(ctx context.Context, commentID primitive.ObjectID, deletedAt time.Time, moderated bool) (bool, error)
+	FetchInteractionCounts(ctx context.Context, workoutIDs []primitive.ObjectID) (map[primitive.ObjectID]wt.InteractionCounts, error)
+}
+
+type interactionRepository struct {
+	reactionCollection *mongo.Collection
+	commentCollection  *mongo.Collection
+}
+
+func NewInteractionRepository() InteractionRepository {
+	return &interactionRepository{
+		reactionCollection: db.Client.Database(db.DB_NAME).Collection("reaction"),
+		commentCollection:  db.Client.Database(db.DB_NAME).Collection("comment"),
+	}
+}
+
+// UpsertReaction saves the user's reaction to the workout, replacing the kind
+// of one they've already made. The returned reaction keeps the ID it was first
+// saved with, so a different ID to the one passed in means it already existed.
+func (r *interactionRepository) UpsertReaction(ctx context.Context, reaction t.Reaction) (*t.Reaction, error) {
+	filter := bson.M{"workoutId": reaction.WorkoutId, "userId": reaction.UserId}
+	update := bson.M{
+		"$set": bson.M{"kind": reaction.Kind, "updatedAt": reaction.UpdatedAt},
+		"$setOnInsert": bson.M{
+			"_id":       reaction.ID,
+			"workoutId": reaction.WorkoutId,
+			"userId":    reaction.UserId,
+			"createdAt": reaction.CreatedAt,
+		},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var saved t.Reaction
+	if err := r.reactionCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&saved); err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+func (r *interactionRepository) RemoveReaction(ctx context.Context, workoutID primitive.ObjectID, userID primitive.ObjectID) (bool, error) {
+	result, err := r.reactionCollection.DeleteOne(ctx, bson.M{"workoutId": workoutID, "userId": userID})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// FetchReactions returns the reactions to a workout, newest first.
+func (r *interactionRepository) FetchReactions(ctx context.Context, workoutID primitive.ObjectID) ([]t.Reaction, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := r.reactionCollection.Find(ctx, bson.M{"workoutId": workoutID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	reactions := []t.Reaction{}
+	if err = cursor.All(ctx, &reactions); err != nil {
+		return nil, err
+	}
+	return reactions, nil
+}
+
+func (r *interactionRepository) InsertComment(ctx context.Context, comment t.Comment) (*t.Comment, error) {
+	_, err := r.commentCollection.InsertOne(ctx, comment)
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (r *interactionRepository) FetchComment(ctx context.Context, commentID primitive.ObjectID) (*t.Comment, error) {
+	var comment t.Comment
+	err := r.commentCollection.FindOne(ctx, bson.M{"_id": commentID}).Decode(&comment)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// FetchComments returns every comment on a workout, deleted ones included,
+// oldest first.
+func (r *interactionRepository) FetchComments(ctx context.Context, workoutID primitive.ObjectID) ([]t.Comment, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.commentCollection.Find(ctx, bson.M{"workoutId": workoutID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	comments := []t.Comment{}
+	if err = cursor.All(ctx, &comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// UpdateCommentBody edits a comment userID wrote, returning nil if there's no
+// such comment or it has been deleted.
+func (r *interactionRepository) UpdateCommentBody(ctx context.Context, userID primitive.ObjectID, commentID primitive.ObjectID, body string, editedAt time.Time) (*t.Comment, error) {
+	filter := bson.M{"_id": commentID, "userId": userID, "deletedAt": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"body": body, "editedAt": editedAt}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var comment t.Comment
+	err := r.commentCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&comment)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// SoftDeleteComment blanks the comment's body rather than removing it so its
+// replies stay threaded.
+func (r *interactionRepository) SoftDeleteComment(ctx context.Context, commentID primitive.ObjectID, deletedAt time.Time, moderated bool) (bool, error) {
+	result, err := r.commentCollection.UpdateOne(ctx,
+		bson.M{"_id": commentID, "deletedAt": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"body": "", "deletedAt": deletedAt, "moderated": moderated}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// FetchInteractionCounts counts reactions by kind and comments that haven't
+// been deleted for all of workoutIDs, one aggregation per collection.
+func (r *interactionRepository) FetchInteractionCounts(ctx context.Context, workoutIDs []primitive.ObjectID) (map[primitive.ObjectID]wt.InteractionCounts, error) {
+	counts := map[primitive.ObjectID]wt.InteractionCounts{}
+	countFor := func(workoutID primitive.ObjectID) wt.InteractionCounts {
+		count, ok := counts[workoutID]
+		if !ok {
+			count = wt.InteractionCounts{Reactions: map[string]int64{}}
+		}
+		return count
+	}
+
+	reactionPipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"workoutId": bson.M{"$in": workoutIDs}}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{{Key: "workoutId", Value: "$workoutId"}, {Key: "kind", Value: "$kind"}}},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+	}
+	var reactionCounts []struct {
+		ID struct {
+			WorkoutId primitive.ObjectID `bson:"workoutId"`
+			Kind      string             `bson:"kind"`
+		} `bson:"_id"`
+		Count int64 `bson:"count"`
+	}
+	if err := r.aggregate(ctx, r.reactionCollection, reactionPipeline, &reactionCounts); err != nil {
+		return nil, err
+	}
+	for _, reaction := range reactionCounts {
+		count := countFor(reaction.ID.WorkoutId)
+		count.Reactions[reaction.ID.Kind] = reaction.Count
+		counts[reaction.ID.WorkoutId] = count
+	}
+
+	commentPipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"workoutId": bson.M{"$in": workoutIDs}, "deletedAt": bson.M{"$exists": false}}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$workoutId"},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+	}
+	var commentCounts []struct {
+		WorkoutId primitive.ObjectID `bson:"_id"`
+		Count     int64              `bson:"count"`
+	}
+	if err := r.aggregate(ctx, r.commentCollection, commentPipeline, &commentCounts); err != nil {
+		return nil, err
+	}
+	for _, comment := range commentCounts {
+		count := countFor(comment.WorkoutId)
+		count.Comments = comment.Count
+		counts[comment.WorkoutId] = count
+	}
+	return counts, nil
+}
+
+func (r *interactionRepository) aggregate(ctx context.Context, collection *mongo.Collection, pipeline mongo.Pipeline, results interface{}) error {
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+	return cursor.All(ctx, results)
+}
@@ -0,0 +1,149 @@
+package interaction
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/interaction/types"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/notification"
+	nc "github.com/joshibbotson/gym-tracker-backend/internal/modules/notification/constants"
+	nt "github.com/joshibbotson/gym-tracker-backend/internal/modules/notification/types"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/social"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/user"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/workout"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type InteractionService interface {
+	React(userID primitive.ObjectID, workoutID primitive.ObjectID, request t.ReactionRequest) (*t.Reaction, error)
+	Unreact(userID primitive.ObjectID, workoutID primitive.ObjectID) (bool, error)
+	GetReactions(userID primitive.ObjectID, workoutID primitive.ObjectID) ([]t.Reaction, error)
+	CreateComment(userID primitive.ObjectID, workoutID primitive.ObjectID, request t.CreateCommentRequest) (*t.Comment, error)
+	GetComments(userID primitive.ObjectID, workoutID primitive.ObjectID) ([]t.Comment, error)
+	UpdateComment(userID primitive.ObjectID, commentID primitive.ObjectID, request t.UpdateCommentRequest) (*t.Comment, error)
+	DeleteComment(userID primitive.ObjectID, commentID primitive.ObjectID) error
+}
+
+var (
+	ErrInvalidReaction     = errors.New("kind must be like, fire, strong or clap")
+	ErrInvalidComment      = errors.New("comment can't be empty or longer than 1000 characters")
+	ErrCommentNotFound     = errors.New("comment not found")
+	ErrThreadTooDeep       = errors.New("replies can't be nested any deeper")
+	ErrNotCommentAuthor    = errors.New("only the author can edit a comment")
+	ErrCannotDeleteComment = errors.New("only the author or the workout's owner can delete a comment")
+)
+
+type interactionService struct {
+	repo                InteractionRepository
+	workoutService      workout.WorkoutService
+	socialService       social.SocialService
+	userService         user.UserService
+	notificationService notification.NotificationService
+}
+
+func NewInteractionService(repo InteractionRepository, workoutService workout.WorkoutService, socialService social.SocialService, userService user.UserService, notificationService notification.NotificationService) InteractionService {
+	return &interactionService{
+		repo:                repo,
+		workoutService:      workoutService,
+		socialService:       socialService,
+		userService:         userService,
+		notificationService: notificationService,
+	}
+}
+
+// visibleOwner returns the owner of a workout userID is allowed to see. A
+// workout they can't see is reported as not found rather than forbidden so its
+// existence isn't given away.
+func (s *interactionService) visibleOwner(userID primitive.ObjectID, workoutID primitive.ObjectID) (primitive.ObjectID, error) {
+	ownerID, err := s.workoutService.GetWorkoutOwner(workoutID)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	audience, err := s.socialService.GetAudience(userID, ownerID)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) || errors.Is(err, social.ErrProfilePrivate) {
+			return primitive.NilObjectID, workout.ErrWorkoutNotFound
+		}
+		return primitive.NilObjectID, err
+	}
+	if _, err := s.workoutService.GetVisibleWorkout(ownerID, audience, workoutID); err != nil {
+		return primitive.NilObjectID, err
+	}
+	return ownerID, nil
+}
+
+// React reacts to the workout, or changes the kind of the user's reaction. The
+// owner is only notified the first time.
+func (s *interactionService) React(userID primitive.ObjectID, workoutID primitive.ObjectID, request t.ReactionRequest) (*t.Reaction, error) {
+	if !request.Kind.Valid() {
+		return nil, ErrInvalidReaction
+	}
+	ownerID, err := s.visibleOwner(userID, workoutID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	reactionID := primitive.NewObjectID()
+	reaction, err := s.repo.UpsertReaction(context.TODO(), t.Reaction{
+		ID:        reactionID,
+		WorkoutId: workoutID,
+		UserId:    userID,
+		Kind:      request.Kind,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if reaction.ID == reactionID {
+		err = s.notificationService.Notify(nt.Notification{
+			UserId:    ownerID,
+			ActorId:   userID,
+			Kind:      nc.NotificationReaction,
+			WorkoutId: workoutID,
+			Reaction:  string(reaction.Kind),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return reaction, nil
+}
+
+// Unreact works even if the user can no longer see the workout, so they can
+// always take back a reaction.
+func (s *interactionService) Unreact(userID primitive.ObjectID, workoutID primitive.ObjectID) (bool, error) {
+	deleted, err := s.repo.RemoveReaction(context.TODO(), workoutID, userID)
+	if err != nil || !deleted {
+		return deleted, err
+	}
+	return true, s.notificationService.RetractReaction(userID, workoutID)
+}
+
+// GetReactions returns who reacted to the workout and how, newest first.
+func (s *interactionService) GetReactions(userID primitive.ObjectID, workoutID primitive.ObjectID) ([]t.Reaction, error) {
+	if _, err := s.visibleOwner(userID, workoutID); err != nil {
+		return nil, err
+	}
+	reactions, err := s.repo.FetchReactions(context.TODO(), workoutID)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]primitive.ObjectID, len(reactions))
+	for i, reaction := range reactions {
+		userIDs[i] = reaction.UserId
+	}
+	profiles, err := s.userService.GetProfiles(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range reactions {
+		if profile, ok := profiles[reactions[i].UserId]; ok {
+			reactions[i].User = &profile
+		}
+	}
+	return reactions, nil
+}
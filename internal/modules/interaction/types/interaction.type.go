@@ -0,0 +1,52 @@
+package types
+
+import (
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/interaction/constants"
+	ut "github.com/joshibbotson/gym-tracker-backend/internal/modules/user/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Reaction is UserId's reaction to a workout, each user has at most one per
+// workout.
+type Reaction struct {
+	ID        primitive.ObjectID `bson:"_id" json:"_id"`
+	WorkoutId primitive.ObjectID `bson:"workoutId" json:"workoutId"`
+	UserId    primitive.ObjectID `bson:"userId" json:"userId"`
+	Kind      c.ReactionKind     `bson:"kind" json:"kind"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt"`
+	User      *ut.UserProfile    `bson:"-" json:"user,omitempty"`
+}
+
+type ReactionRequest struct {
+	Kind c.ReactionKind `json:"kind"`
+}
+
+// Comment is a comment on a workout, or a reply to one when ParentId is set.
+// A deleted comment keeps its place in the thread without its body while it
+// has replies, Moderated when the workout's owner removed it.
+type Comment struct {
+	ID        primitive.ObjectID  `bson:"_id" json:"_id"`
+	WorkoutId primitive.ObjectID  `bson:"workoutId" json:"workoutId"`
+	UserId    primitive.ObjectID  `bson:"userId" json:"userId"`
+	ParentId  *primitive.ObjectID `bson:"parentId,omitempty" json:"parentId,omitempty"`
+	Depth     int                 `bson:"depth" json:"depth"`
+	Body      string              `bson:"body" json:"body"`
+	CreatedAt time.Time           `bson:"createdAt" json:"createdAt"`
+	EditedAt  *time.Time          `bson:"editedAt,omitempty" json:"editedAt,omitempty"`
+	DeletedAt *time.Time          `bson:"deletedAt,omitempty" json:"deletedAt,omitempty"`
+	Moderated bool                `bson:"moderated,omitempty" json:"moderated,omitempty"`
+	Author    *ut.UserProfile     `bson:"-" json:"author,omitempty"`
+	Replies   []Comment           `bson:"-" json:"replies"`
+}
+
+type CreateCommentRequest struct {
+	Body     string              `json:"body"`
+	ParentId *primitive.ObjectID `json:"parentId"`
+}
+
+type UpdateCommentRequest struct {
+	Body string `json:"body"`
+}
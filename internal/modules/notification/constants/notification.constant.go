@@ -0,0 +1,11 @@
+package constants
+
+type NotificationKind string
+
+const (
+	NotificationReaction NotificationKind = "reaction"
+	NotificationComment  NotificationKind = "comment"
+)
+
+// MaxNotifications is how many of the newest notifications are returned.
+const MaxNotifications = 100
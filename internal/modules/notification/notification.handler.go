@@ -0,0 +1,105 @@
+package notification
+
+import (
+	"net/http"
+
+	m "github.com/joshibbotson/gym-tracker-backend/internal/middleware"
+	util "github.com/joshibbotson/gym-tracker-backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type NotificationHandler struct {
+	Service NotificationService
+}
+
+func NewNotificationHandler(service NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		Service: service,
+	}
+}
+
+// Handler serves /notification and /notification/{id}
+func (h *NotificationHandler) Handler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		m.PermissionMiddleware(h.handleReadNotifications)(w, r)
+	case http.MethodPatch:
+		if r.PathValue("id") != "" {
+			m.PermissionMiddleware(h.handleMarkRead)(w, r)
+			break
+		}
+		m.PermissionMiddleware(h.handleMarkAllRead)(w, r)
+	case http.MethodDelete:
+		m.PermissionMiddleware(h.handleDeleteNotification)(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleReadNotifications takes unread=true to leave out ones already read.
+func (h *NotificationHandler) handleReadNotifications(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	notifications, err := h.Service.GetNotifications(userID, r.URL.Query().Get("unread") == "true")
+	if err != nil {
+		http.Error(w, "Error fetching notifications", http.StatusInternalServerError)
+		return
+	}
+	util.WriteJSON(w, http.StatusOK, notifications)
+}
+
+func (h *NotificationHandler) handleMarkRead(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	notificationID, ok := util.ParseObjectID(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+
+	found, err := h.Service.MarkRead(userID, notificationID)
+	if err != nil {
+		http.Error(w, "Error updating notification", http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		http.Error(w, "Notification not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"message": "Notification marked as read"}`))
+}
+
+func (h *NotificationHandler) handleMarkAllRead(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	if err := h.Service.MarkAllRead(userID); err != nil {
+		http.Error(w, "Error updating notifications", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"message": "Notifications marked as read"}`))
+}
+
+func (h *NotificationHandler) handleDeleteNotification(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	notificationID, ok := util.ParseObjectID(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+
+	deleted, err := h.Service.DeleteNotification(userID, notificationID)
+	if err != nil {
+		http.Error(w, "Error deleting notification", http.StatusInternalServerError)
+		return
+	}
+	if !deleted {
+		http.Error(w, "Notification not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"message": "Notification deleted successfully"}`))
+}
@@ -0,0 +1,115 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	db "github.com/joshibbotson/gym-tracker-backend/internal/db"
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/notification/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/notification/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type NotificationRepository interface {
+	InsertNotification(ctx context.Context, notification t.Notification) (*t.Notification, error)
+	FetchNotifications(ctx context.Context, userID primitive.ObjectID, unreadOnly bool, limit int) ([]t.Notification, error)
+	CountUnread(ctx context.Context, userID primitive.ObjectID) (int64, error)
+	MarkRead(ctx context.Context, userID primitive.ObjectID, notificationID primitive.ObjectID, readAt time.Time) (bool, error)
+	MarkAllRead(ctx context.Context, userID primitive.ObjectID, readAt time.Time) error
+	RemoveNotification(ctx context.Context, userID primitive.ObjectID, notificationID primitive.ObjectID) (bool, error)
+	RemoveReactionNotifications(ctx context.Context, actorID primitive.ObjectID, workoutID primitive.ObjectID) error
+	RemoveCommentNotifications(ctx context.Context, commentID primitive.ObjectID) error
+}
+
+type notificationRepository struct {
+	notificationCollection *mongo.Collection
+}
+
+func NewNotificationRepository() NotificationRepository {
+	return &notificationRepository{
+		notificationCollection: db.Client.Database(db.DB_NAME).Collection("notification"),
+	}
+}
+
+func (r *notificationRepository) InsertNotification(ctx context.Context, notification t.Notification) (*t.Notification, error) {
+	_, err := r.notificationCollection.InsertOne(ctx, notification)
+	if err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// FetchNotifications returns the newest limit notifications of userID.
+func (r *notificationRepository) FetchNotifications(ctx context.Context, userID primitive.ObjectID, unreadOnly bool, limit int) ([]t.Notification, error) {
+	filter := bson.M{"userId": userID}
+	if unreadOnly {
+		filter["readAt"] = bson.M{"$exists": false}
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.notificationCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	notifications := []t.Notification{}
+	if err = cursor.All(ctx, &notifications); err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+func (r *notificationRepository) CountUnread(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	return r.notificationCollection.CountDocuments(ctx, bson.M{"userId": userID, "readAt": bson.M{"$exists": false}})
+}
+
+// MarkRead returns false if the notification doesn't exist, one already read
+// is left as it was.
+func (r *notificationRepository) MarkRead(ctx context.Context, userID primitive.ObjectID, notificationID primitive.ObjectID, readAt time.Time) (bool, error) {
+	result, err := r.notificationCollection.UpdateOne(ctx,
+		bson.M{"_id": notificationID, "userId": userID},
+		bson.A{bson.M{"$set": bson.M{"readAt": bson.M{"$ifNull": bson.A{"$readAt", readAt}}}}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+func (r *notificationRepository) MarkAllRead(ctx context.Context, userID primitive.ObjectID, readAt time.Time) error {
+	_, err := r.notificationCollection.UpdateMany(ctx,
+		bson.M{"userId": userID, "readAt": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"readAt": readAt}},
+	)
+	return err
+}
+
+func (r *notificationRepository) RemoveNotification(ctx context.Context, userID primitive.ObjectID, notificationID primitive.ObjectID) (bool, error) {
+	result, err := r.notificationCollection.DeleteOne(ctx, bson.M{"_id": notificationID, "userId": userID})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// RemoveReactionNotifications removes what actorID reacting to the workout
+// notified its owner of.
+func (r *notificationRepository) RemoveReactionNotifications(ctx context.Context, actorID primitive.ObjectID, workoutID primitive.ObjectID) error {
+	_, err := r.notificationCollection.DeleteMany(ctx, bson.M{
+		"actorId":   actorID,
+		"workoutId": workoutID,
+		"kind":      c.NotificationReaction,
+	})
+	return err
+}
+
+func (r *notificationRepository) RemoveCommentNotifications(ctx context.Context, commentID primitive.ObjectID) error {
+	_, err := r.notificationCollection.DeleteMany(ctx, bson.M{"commentId": commentID})
+	return err
+}
@@ -0,0 +1,93 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/notification/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/notification/types"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/user"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type NotificationService interface {
+	Notify(notification t.Notification) error
+	GetNotifications(userID primitive.ObjectID, unreadOnly bool) (*t.NotificationList, error)
+	MarkRead(userID primitive.ObjectID, notificationID primitive.ObjectID) (bool, error)
+	MarkAllRead(userID primitive.ObjectID) error
+	DeleteNotification(userID primitive.ObjectID, notificationID primitive.ObjectID) (bool, error)
+	RetractReaction(actorID primitive.ObjectID, workoutID primitive.ObjectID) error
+	RetractComment(commentID primitive.ObjectID) error
+}
+
+type notificationService struct {
+	repo        NotificationRepository
+	userService user.UserService
+}
+
+func NewNotificationService(repo NotificationRepository, userService user.UserService) NotificationService {
+	return &notificationService{repo: repo, userService: userService}
+}
+
+// Notify saves the notification, unless the user would be notifying themselves.
+func (s *notificationService) Notify(notification t.Notification) error {
+	if notification.ActorId == notification.UserId {
+		return nil
+	}
+	notification.ID = primitive.NewObjectID()
+	notification.CreatedAt = time.Now()
+	notification.ReadAt = nil
+	_, err := s.repo.InsertNotification(context.TODO(), notification)
+	return err
+}
+
+// GetNotifications returns the newest MaxNotifications, with who they're from
+// looked up in one go.
+func (s *notificationService) GetNotifications(userID primitive.ObjectID, unreadOnly bool) (*t.NotificationList, error) {
+	notifications, err := s.repo.FetchNotifications(context.TODO(), userID, unreadOnly, c.MaxNotifications)
+	if err != nil {
+		return nil, err
+	}
+	unread, err := s.repo.CountUnread(context.TODO(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	actorIDs := make([]primitive.ObjectID, len(notifications))
+	for i, notification := range notifications {
+		actorIDs[i] = notification.ActorId
+	}
+	profiles, err := s.userService.GetProfiles(actorIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range notifications {
+		if profile, ok := profiles[notifications[i].ActorId]; ok {
+			notifications[i].Actor = &profile
+		}
+	}
+	return &t.NotificationList{Notifications: notifications, Unread: unread}, nil
+}
+
+func (s *notificationService) MarkRead(userID primitive.ObjectID, notificationID primitive.ObjectID) (bool, error) {
+	return s.repo.MarkRead(context.TODO(), userID, notificationID, time.Now())
+}
+
+func (s *notificationService) MarkAllRead(userID primitive.ObjectID) error {
+	return s.repo.MarkAllRead(context.TODO(), userID, time.Now())
+}
+
+func (s *notificationService) DeleteNotification(userID primitive.ObjectID, notificationID primitive.ObjectID) (bool, error) {
+	return s.repo.RemoveNotification(context.TODO(), userID, notificationID)
+}
+
+// RetractReaction removes the notification of a reaction that's been taken
+// back, so the owner isn't told about something that's no longer there.
+func (s *notificationService) RetractReaction(actorID primitive.ObjectID, workoutID primitive.ObjectID) error {
+	return s.repo.RemoveReactionNotifications(context.TODO(), actorID, workoutID)
+}
+
+// RetractComment removes the notification of a deleted comment.
+func (s *notificationService) RetractComment(commentID primitive.ObjectID) error {
+	return s.repo.RemoveCommentNotifications(context.TODO(), commentID)
+}
@@ -0,0 +1,29 @@
+package types
+
+import (
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/notification/constants"
+	ut "github.com/joshibbotson/gym-tracker-backend/internal/modules/user/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Notification tells UserId that ActorId reacted to or commented on one of
+// their workouts.
+type Notification struct {
+	ID        primitive.ObjectID  `bson:"_id" json:"_id"`
+	UserId    primitive.ObjectID  `bson:"userId" json:"-"`
+	ActorId   primitive.ObjectID  `bson:"actorId" json:"actorId"`
+	Kind      c.NotificationKind  `bson:"kind" json:"kind"`
+	WorkoutId primitive.ObjectID  `bson:"workoutId" json:"workoutId"`
+	CommentId *primitive.ObjectID `bson:"commentId,omitempty" json:"commentId,omitempty"`
+	Reaction  string              `bson:"reaction,omitempty" json:"reaction,omitempty"`
+	CreatedAt time.Time           `bson:"createdAt" json:"createdAt"`
+	ReadAt    *time.Time          `bson:"readAt,omitempty" json:"readAt,omitempty"`
+	Actor     *ut.UserProfile     `bson:"-" json:"actor,omitempty"`
+}
+
+type NotificationList struct {
+	Notifications []Notification `json:"notifications"`
+	Unread        int64          `json:"unread"`
+}
@@ -0,0 +1,22 @@
+package constants
+
+type MealType string
+
+const (
+	MealBreakfast MealType = "breakfast"
+	MealLunch     MealType = "lunch"
+	MealDinner    MealType = "dinner"
+	MealSnack     MealType = "snack"
+)
+
+// MealTypes is the order meals are listed in for a day.
+var MealTypes = []MealType{MealBreakfast, MealLunch, MealDinner, MealSnack}
+
+func (m MealType) Valid() bool {
+	for _, meal := range MealTypes {
+		if m == meal {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,287 @@
+package nutrition
+
+import (
+	"errors"
+	"net/http"
+
+	m "github.com/joshibbotson/gym-tracker-backend/internal/middleware"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/nutrition/types"
+	util "github.com/joshibbotson/gym-tracker-backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type NutritionHandler struct {
+	Service NutritionService
+}
+
+func NewNutritionHandler(service NutritionService) *NutritionHandler {
+	return &NutritionHandler{
+		Service: service,
+	}
+}
+
+// FoodHandler serves /nutrition/foods and /nutrition/foods/{id}
+func (h *NutritionHandler) FoodHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		m.PermissionMiddleware(h.handleCreateFood)(w, r)
+	case http.MethodGet:
+		if r.PathValue("id") != "" {
+			m.PermissionMiddleware(h.handleReadFood)(w, r)
+			break
+		}
+		m.PermissionMiddleware(h.handleReadFoods)(w, r)
+	case http.MethodPatch:
+		m.PermissionMiddleware(h.handleUpdateFood)(w, r)
+	case http.MethodDelete:
+		m.PermissionMiddleware(h.handleDeleteFood)(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// MealHandler serves /nutrition/meals, /nutrition/meals/{id}, /nutrition/meals/copy
+// and /nutrition/day/{date}
+func (h *NutritionHandler) MealHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		if r.URL.Path == "/nutrition/meals/copy" {
+			m.PermissionMiddleware(h.handleCopyMeal)(w, r)
+			break
+		}
+		m.PermissionMiddleware(h.handleAddMealEntry)(w, r)
+	case http.MethodGet:
+		if r.PathValue("date") == "" {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			break
+		}
+		m.PermissionMiddleware(h.handleReadDay)(w, r)
+	case http.MethodPatch:
+		m.PermissionMiddleware(h.handleUpdateMealEntry)(w, r)
+	case http.MethodDelete:
+		m.PermissionMiddleware(h.handleDeleteMealEntry)(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *NutritionHandler) handleCreateFood(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	var request t.FoodRequest
+	if !util.DecodeBody(w, r, &request) {
+		return
+	}
+
+	food, err := h.Service.CreateFood(userID, request)
+	if errors.Is(err, ErrInvalidFood) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error creating food", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusCreated, food)
+}
+
+// handleReadFoods takes an optional q query param to search by name or brand
+func (h *NutritionHandler) handleReadFoods(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	foods, err := h.Service.GetFoods(userID, r.URL.Query().Get("q"))
+	if err != nil {
+		http.Error(w, "Error fetching foods", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, foods)
+}
+
+func (h *NutritionHandler) handleReadFood(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+	foodID, ok := util.ParseObjectID(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+
+	food, err := h.Service.GetFoodById(userID, foodID)
+	if errors.Is(err, ErrFoodNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error fetching food", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, food)
+}
+
+func (h *NutritionHandler) handleUpdateFood(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+	foodID, ok := util.ParseObjectID(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+
+	var request t.FoodRequest
+	if !util.DecodeBody(w, r, &request) {
+		return
+	}
+
+	food, err := h.Service.UpdateFood(userID, foodID, request)
+	if errors.Is(err, ErrInvalidFood) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, ErrFoodNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error updating food", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, food)
+}
+
+func (h *NutritionHandler) handleDeleteFood(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+	foodID, ok := util.ParseObjectID(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+
+	success, err := h.Service.DeleteFood(userID, foodID)
+	if err != nil {
+		http.Error(w, "Error deleting food", http.StatusInternalServerError)
+		return
+	}
+	if !success {
+		http.Error(w, "Food not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"message": "Food deleted successfully"}`))
+}
+
+func (h *NutritionHandler) handleAddMealEntry(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	var request t.MealEntryRequest
+	if !util.DecodeBody(w, r, &request) {
+		return
+	}
+
+	entry, err := h.Service.AddMealEntry(userID, request)
+	if errors.Is(err, ErrInvalidMealEntry) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, ErrFoodNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error logging meal", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusCreated, entry)
+}
+
+func (h *NutritionHandler) handleUpdateMealEntry(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+	entryID, ok := util.ParseObjectID(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+
+	var request t.UpdateMealEntryRequest
+	if !util.DecodeBody(w, r, &request) {
+		return
+	}
+
+	entry, err := h.Service.UpdateMealEntry(userID, entryID, request)
+	if errors.Is(err, ErrInvalidMealEntry) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, ErrMealEntryNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error updating meal", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, entry)
+}
+
+func (h *NutritionHandler) handleDeleteMealEntry(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+	entryID, ok := util.ParseObjectID(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+
+	success, err := h.Service.DeleteMealEntry(userID, entryID)
+	if err != nil {
+		http.Error(w, "Error deleting meal entry", http.StatusInternalServerError)
+		return
+	}
+	if !success {
+		http.Error(w, "Meal entry not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"message": "Meal entry deleted successfully"}`))
+}
+
+func (h *NutritionHandler) handleCopyMeal(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	var request t.CopyMealRequest
+	if !util.DecodeBody(w, r, &request) {
+		return
+	}
+
+	entries, err := h.Service.CopyMeal(userID, request)
+	if errors.Is(err, ErrInvalidCopy) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, ErrNothingToCopy) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error copying meal", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusCreated, entries)
+}
+
+func (h *NutritionHandler) handleReadDay(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+	date, err := util.ParseDate(r.PathValue("date"))
+	if err != nil {
+		http.Error(w, "Error parsing date", http.StatusBadRequest)
+		return
+	}
+
+	day, err := h.Service.GetDay(userID, date)
+	if err != nil {
+		http.Error(w, "Error fetching nutrition", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, day)
+}
@@ -0,0 +1,160 @@
+package nutrition
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	db "github.com/joshibbotson/gym-tracker-backend/internal/db"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/nutrition/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type NutritionRepository interface {
+	InsertFood(ctx context.Context, food t.Food) (*t.Food, error)
+	FetchFoods(ctx context.Context, userID primitive.ObjectID, search string) ([]t.Food, error)
+	FetchFoodById(ctx context.Context, userID primitive.ObjectID, foodID primitive.ObjectID) (*t.Food, error)
+	UpdateFood(ctx context.Context, food t.Food) (*t.Food, error)
+	RemoveFood(ctx context.Context, userID primitive.ObjectID, foodID primitive.ObjectID) (bool, error)
+	InsertMealEntries(ctx context.Context, entries []t.MealEntry) error
+	FetchMealEntriesByDate(ctx context.Context, userID primitive.ObjectID, date time.Time) ([]t.MealEntry, error)
+	FetchMealEntryById(ctx context.Context, userID primitive.ObjectID, entryID primitive.ObjectID) (*t.MealEntry, error)
+	UpdateMealEntry(ctx context.Context, entry t.MealEntry) (*t.MealEntry, error)
+	RemoveMealEntry(ctx context.Context, userID primitive.ObjectID, entryID primitive.ObjectID) (bool, error)
+}
+
+type nutritionRepository struct {
+	foodCollection *mongo.Collection
+	mealCollection *mongo.Collection
+}
+
+func NewNutritionRepository() NutritionRepository {
+	return &nutritionRepository{
+		foodCollection: db.Client.Database(db.DB_NAME).Collection("food"),
+		mealCollection: db.Client.Database(db.DB_NAME).Collection("meal"),
+	}
+}
+
+func (r *nutritionRepository) InsertFood(ctx context.Context, food t.Food) (*t.Food, error) {
+	if _, err := r.foodCollection.InsertOne(ctx, food); err != nil {
+		return nil, err
+	}
+	return &food, nil
+}
+
+// FetchFoods returns the user's foods sorted by name, search matches anywhere
+// in the name or brand ignoring case.
+func (r *nutritionRepository) FetchFoods(ctx context.Context, userID primitive.ObjectID, search string) ([]t.Food, error) {
+	filter := bson.M{"userId": userID}
+	if search != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(search), Options: "i"}
+		filter["$or"] = bson.A{bson.M{"name": pattern}, bson.M{"brand": pattern}}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+
+	cursor, err := r.foodCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	foods := []t.Food{}
+	if err = cursor.All(ctx, &foods); err != nil {
+		return nil, err
+	}
+	return foods, nil
+}
+
+func (r *nutritionRepository) FetchFoodById(ctx context.Context, userID primitive.ObjectID, foodID primitive.ObjectID) (*t.Food, error) {
+	var food t.Food
+	err := r.foodCollection.FindOne(ctx, bson.M{"_id": foodID, "userId": userID}).Decode(&food)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &food, nil
+}
+
+func (r *nutritionRepository) UpdateFood(ctx context.Context, food t.Food) (*t.Food, error) {
+	result, err := r.foodCollection.ReplaceOne(ctx, bson.M{"_id": food.ID, "userId": food.UserId}, food)
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, nil
+	}
+	return &food, nil
+}
+
+func (r *nutritionRepository) RemoveFood(ctx context.Context, userID primitive.ObjectID, foodID primitive.ObjectID) (bool, error) {
+	result, err := r.foodCollection.DeleteOne(ctx, bson.M{"_id": foodID, "userId": userID})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+func (r *nutritionRepository) InsertMealEntries(ctx context.Context, entries []t.MealEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	documents := make([]interface{}, len(entries))
+	for i, entry := range entries {
+		documents[i] = entry
+	}
+	_, err := r.mealCollection.InsertMany(ctx, documents)
+	return err
+}
+
+// FetchMealEntriesByDate returns a day's entries in the order they were logged.
+func (r *nutritionRepository) FetchMealEntriesByDate(ctx context.Context, userID primitive.ObjectID, date time.Time) ([]t.MealEntry, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := r.mealCollection.Find(ctx, bson.M{"userId": userID, "date": date}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	entries := []t.MealEntry{}
+	if err = cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *nutritionRepository) FetchMealEntryById(ctx context.Context, userID primitive.ObjectID, entryID primitive.ObjectID) (*t.MealEntry, error) {
+	var entry t.MealEntry
+	err := r.mealCollection.FindOne(ctx, bson.M{"_id": entryID, "userId": userID}).Decode(&entry)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *nutritionRepository) UpdateMealEntry(ctx context.Context, entry t.MealEntry) (*t.MealEntry, error) {
+	result, err := r.mealCollection.ReplaceOne(ctx, bson.M{"_id": entry.ID, "userId": entry.UserId}, entry)
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, nil
+	}
+	return &entry, nil
+}
+
+func (r *nutritionRepository) RemoveMealEntry(ctx context.Context, userID primitive.ObjectID, entryID primitive.ObjectID) (bool, error) {
+	result, err := r.mealCollection.DeleteOne(ctx, bson.M{"_id": entryID, "userId": userID})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
@@ -0,0 +1,396 @@
+package nutrition
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/intake"
+	it "github.com/joshibbotson/gym-tracker-backend/internal/modules/intake/types"
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/nutrition/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/nutrition/types"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/phase"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/user"
+	util "github.com/joshibbotson/gym-tracker-backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type NutritionService interface {
+	CreateFood(userID primitive.ObjectID, food t.FoodRequest) (*t.Food, error)
+	GetFoods(userID primitive.ObjectID, search string) ([]t.Food, error)
+	GetFoodById(userID primitive.ObjectID, foodID primitive.ObjectID) (*t.Food, error)
+	UpdateFood(userID primitive.ObjectID, foodID primitive.ObjectID, food t.FoodRequest) (*t.Food, error)
+	DeleteFood(userID primitive.ObjectID, foodID primitive.ObjectID) (bool, error)
+	AddMealEntry(userID primitive.ObjectID, entry t.MealEntryRequest) (*t.MealEntry, error)
+	UpdateMealEntry(userID primitive.ObjectID, entryID primitive.ObjectID, entry t.UpdateMealEntryRequest) (*t.MealEntry, error)
+	DeleteMealEntry(userID primitive.ObjectID, entryID primitive.ObjectID) (bool, error)
+	CopyMeal(userID primitive.ObjectID, request t.CopyMealRequest) ([]t.MealEntry, error)
+	GetDay(userID primitive.ObjectID, date time.Time) (*t.DailyNutrition, error)
+}
+
+var (
+	ErrFoodNotFound      = errors.New("food not found")
+	ErrMealEntryNotFound = errors.New("meal entry not found")
+	ErrNothingToCopy     = errors.New("no meals logged to copy")
+	ErrInvalidFood       = errors.New("food needs a name, a serving size and unit, and macros can't be negative")
+	ErrInvalidMealEntry  = errors.New("meal entry needs a date, a valid meal, a food and positive servings")
+	ErrInvalidCopy       = errors.New("copy needs a target day different from the source and a valid meal")
+)
+
+type nutritionService struct {
+	repo          NutritionRepository
+	phaseService  phase.PhaseService
+	intakeService intake.IntakeService
+	userService   user.UserService
+}
+
+func NewNutritionService(repo NutritionRepository, phaseService phase.PhaseService, intakeService intake.IntakeService, userService user.UserService) NutritionService {
+	return &nutritionService{repo: repo, phaseService: phaseService, intakeService: intakeService, userService: userService}
+}
+
+func (s *nutritionService) CreateFood(userID primitive.ObjectID, food t.FoodRequest) (*t.Food, error) {
+	if !validFood(food) {
+		return nil, ErrInvalidFood
+	}
+
+	newFood := t.Food{
+		ID:        primitive.NewObjectID(),
+		UserId:    userID,
+		CreatedAt: time.Now(),
+	}
+	applyFoodRequest(&newFood, food)
+	return s.repo.InsertFood(context.TODO(), newFood)
+}
+
+func (s *nutritionService) GetFoods(userID primitive.ObjectID, search string) ([]t.Food, error) {
+	return s.repo.FetchFoods(context.TODO(), userID, strings.TrimSpace(search))
+}
+
+func (s *nutritionService) GetFoodById(userID primitive.ObjectID, foodID primitive.ObjectID) (*t.Food, error) {
+	food, err := s.repo.FetchFoodById(context.TODO(), userID, foodID)
+	if err != nil {
+		return nil, err
+	}
+	if food == nil {
+		return nil, ErrFoodNotFound
+	}
+	return food, nil
+}
+
+// UpdateFood changes the food for meals logged from now on, entries already
+// logged keep the macros they were logged with.
+func (s *nutritionService) UpdateFood(userID primitive.ObjectID, foodID primitive.ObjectID, food t.FoodRequest) (*t.Food, error) {
+	if !validFood(food) {
+		return nil, ErrInvalidFood
+	}
+
+	existing, err := s.GetFoodById(userID, foodID)
+	if err != nil {
+		return nil, err
+	}
+	applyFoodRequest(existing, food)
+
+	updated, err := s.repo.UpdateFood(context.TODO(), *existing)
+	if err != nil {
+		return nil, err
+	}
+	if updated == nil {
+		return nil, ErrFoodNotFound
+	}
+	return updated, nil
+}
+
+func (s *nutritionService) DeleteFood(userID primitive.ObjectID, foodID primitive.ObjectID) (bool, error) {
+	return s.repo.RemoveFood(context.TODO(), userID, foodID)
+}
+
+func (s *nutritionService) AddMealEntry(userID primitive.ObjectID, entry t.MealEntryRequest) (*t.MealEntry, error) {
+	if entry.Date.IsZero() || !entry.Meal.Valid() || entry.FoodID.IsZero() || entry.Servings <= 0 {
+		return nil, ErrInvalidMealEntry
+	}
+
+	food, err := s.GetFoodById(userID, entry.FoodID)
+	if err != nil {
+		return nil, err
+	}
+	location, err := s.userService.GetLocation(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	newEntry := t.MealEntry{
+		ID:         primitive.NewObjectID(),
+		UserId:     userID,
+		Date:       util.CalendarDay(entry.Date, location),
+		Meal:       entry.Meal,
+		FoodID:     food.ID,
+		FoodName:   food.Name,
+		Servings:   entry.Servings,
+		Macros:     scaleMacros(food.Macros, entry.Servings),
+		PerServing: food.Macros,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+	if err := s.repo.InsertMealEntries(context.TODO(), []t.MealEntry{newEntry}); err != nil {
+		return nil, err
+	}
+	if err := s.syncIntake(userID, newEntry.Date); err != nil {
+		return nil, err
+	}
+	return &newEntry, nil
+}
+
+// UpdateMealEntry moves an entry to another meal or changes its servings,
+// macros are rescaled from the food's macros when it was logged.
+func (s *nutritionService) UpdateMealEntry(userID primitive.ObjectID, entryID primitive.ObjectID, entry t.UpdateMealEntryRequest) (*t.MealEntry, error) {
+	if entry.Servings <= 0 || (entry.Meal != "" && !entry.Meal.Valid()) {
+		return nil, ErrInvalidMealEntry
+	}
+
+	existing, err := s.repo.FetchMealEntryById(context.TODO(), userID, entryID)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, ErrMealEntryNotFound
+	}
+
+	existing.Macros = scaleMacros(existing.PerServing, entry.Servings)
+	existing.Servings = entry.Servings
+	if entry.Meal != "" {
+		existing.Meal = entry.Meal
+	}
+	existing.UpdatedAt = time.Now()
+
+	updated, err := s.repo.UpdateMealEntry(context.TODO(), *existing)
+	if err != nil {
+		return nil, err
+	}
+	if updated == nil {
+		return nil, ErrMealEntryNotFound
+	}
+	if err := s.syncIntake(userID, updated.Date); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (s *nutritionService) DeleteMealEntry(userID primitive.ObjectID, entryID primitive.ObjectID) (bool, error) {
+	existing, err := s.repo.FetchMealEntryById(context.TODO(), userID, entryID)
+	if err != nil || existing == nil {
+		return false, err
+	}
+
+	success, err := s.repo.RemoveMealEntry(context.TODO(), userID, entryID)
+	if err != nil || !success {
+		return success, err
+	}
+	return true, s.syncIntake(userID, existing.Date)
+}
+
+// CopyMeal logs the entries of a meal, or of the whole day, again on another
+// day. By default it copies from the day before the target.
+func (s *nutritionService) CopyMeal(userID primitive.ObjectID, request t.CopyMealRequest) ([]t.MealEntry, error) {
+	if request.To.IsZero() || (request.Meal != "" && !request.Meal.Valid()) {
+		return nil, ErrInvalidCopy
+	}
+	location, err := s.userService.GetLocation(userID)
+	if err != nil {
+		return nil, err
+	}
+	to := util.CalendarDay(request.To, location)
+	from := to.AddDate(0, 0, -1)
+	if request.From != nil {
+		from = util.CalendarDay(*request.From, location)
+	}
+	if from.Equal(to) {
+		return nil, ErrInvalidCopy
+	}
+
+	source, err := s.repo.FetchMealEntriesByDate(context.TODO(), userID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	copied := []t.MealEntry{}
+	for _, entry := range source {
+		if request.Meal != "" && entry.Meal != request.Meal {
+			continue
+		}
+		entry.ID = primitive.NewObjectID()
+		entry.Date = to
+		entry.CreatedAt = time.Now()
+		entry.UpdatedAt = time.Now()
+		copied = append(copied, entry)
+	}
+	if len(copied) == 0 {
+		return nil, ErrNothingToCopy
+	}
+
+	if err := s.repo.InsertMealEntries(context.TODO(), copied); err != nil {
+		return nil, err
+	}
+	if err := s.syncIntake(userID, to); err != nil {
+		return nil, err
+	}
+	return copied, nil
+}
+
+// GetDay totals a day's meals and compares them with the macro targets of the
+// calorie phase running on that day.
+func (s *nutritionService) GetDay(userID primitive.ObjectID, date time.Time) (*t.DailyNutrition, error) {
+	location, err := s.userService.GetLocation(userID)
+	if err != nil {
+		return nil, err
+	}
+	date = util.CalendarDay(date, location)
+	entries, err := s.repo.FetchMealEntriesByDate(context.TODO(), userID, date)
+	if err != nil {
+		return nil, err
+	}
+	activePhase, err := s.phaseService.GetPhaseOn(userID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	day := &t.DailyNutrition{
+		Date:  date,
+		Meals: summariseMeals(entries),
+		Phase: activePhase,
+	}
+	for _, meal := range day.Meals {
+		day.Totals = addMacros(day.Totals, meal.Totals)
+	}
+
+	if activePhase != nil {
+		targets := t.MacroTargets{
+			Protein: activePhase.ProteinTarget,
+			Carbs:   activePhase.CarbsTarget,
+			Fat:     activePhase.FatTarget,
+		}
+		if activePhase.CalorieTarget != nil {
+			calories := float64(*activePhase.CalorieTarget)
+			targets.Calories = &calories
+		}
+		if targets.Calories != nil || targets.Protein != nil || targets.Carbs != nil || targets.Fat != nil {
+			day.Targets = &targets
+			day.Remaining = remaining(targets, day.Totals)
+		}
+	}
+
+	return day, nil
+}
+
+// syncIntake writes the day's meal totals to the intake log so the TDEE
+// estimate sees them, the day is cleared from the log once it has no meals.
+// Intake the user logged by hand is never touched.
+func (s *nutritionService) syncIntake(userID primitive.ObjectID, date time.Time) error {
+	entries, err := s.repo.FetchMealEntriesByDate(context.TODO(), userID, date)
+	if err != nil {
+		return err
+	}
+
+	var totals t.Macros
+	for _, entry := range entries {
+		totals = addMacros(totals, entry.Macros)
+	}
+	if totals.Calories <= 0 {
+		_, err := s.intakeService.ClearMealIntake(userID, date)
+		return err
+	}
+
+	_, err = s.intakeService.SyncMealIntake(userID, it.IntakeRequest{
+		Date:     date,
+		Calories: totals.Calories,
+		Protein:  &totals.Protein,
+		Carbs:    &totals.Carbs,
+		Fat:      &totals.Fat,
+		Fibre:    &totals.Fibre,
+		Notes:    "From meals",
+	})
+	return err
+}
+
+// summariseMeals groups entries by meal in the usual meal order, every meal is
+// listed even when nothing was logged for it.
+func summariseMeals(entries []t.MealEntry) []t.MealSummary {
+	summaries := make([]t.MealSummary, len(c.MealTypes))
+	index := map[c.MealType]int{}
+	for i, meal := range c.MealTypes {
+		summaries[i] = t.MealSummary{Meal: meal, Entries: []t.MealEntry{}}
+		index[meal] = i
+	}
+
+	for _, entry := range entries {
+		i, ok := index[entry.Meal]
+		if !ok {
+			continue
+		}
+		summaries[i].Entries = append(summaries[i].Entries, entry)
+		summaries[i].Totals = addMacros(summaries[i].Totals, entry.Macros)
+	}
+	return summaries
+}
+
+func remaining(targets t.MacroTargets, totals t.Macros) *t.MacroTargets {
+	left := func(target *float64, eaten float64) *float64 {
+		if target == nil {
+			return nil
+		}
+		value := round(*target - eaten)
+		return &value
+	}
+	return &t.MacroTargets{
+		Calories: left(targets.Calories, totals.Calories),
+		Protein:  left(targets.Protein, totals.Protein),
+		Carbs:    left(targets.Carbs, totals.Carbs),
+		Fat:      left(targets.Fat, totals.Fat),
+	}
+}
+
+func scaleMacros(macros t.Macros, factor float64) t.Macros {
+	return t.Macros{
+		Calories: round(macros.Calories * factor),
+		Protein:  round(macros.Protein * factor),
+		Carbs:    round(macros.Carbs * factor),
+		Fat:      round(macros.Fat * factor),
+		Fibre:    round(macros.Fibre * factor),
+	}
+}
+
+func addMacros(a t.Macros, b t.Macros) t.Macros {
+	return t.Macros{
+		Calories: round(a.Calories + b.Calories),
+		Protein:  round(a.Protein + b.Protein),
+		Carbs:    round(a.Carbs + b.Carbs),
+		Fat:      round(a.Fat + b.Fat),
+		Fibre:    round(a.Fibre + b.Fibre),
+	}
+}
+
+func applyFoodRequest(food *t.Food, request t.FoodRequest) {
+	food.Name = strings.TrimSpace(request.Name)
+	food.Brand = strings.TrimSpace(request.Brand)
+	food.ServingSize = request.ServingSize
+	food.ServingUnit = strings.TrimSpace(request.ServingUnit)
+	food.Macros = request.Macros
+	food.UpdatedAt = time.Now()
+}
+
+func validFood(food t.FoodRequest) bool {
+	if strings.TrimSpace(food.Name) == "" || food.ServingSize <= 0 || strings.TrimSpace(food.ServingUnit) == "" {
+		return false
+	}
+	for _, macro := range []float64{food.Calories, food.Protein, food.Carbs, food.Fat, food.Fibre} {
+		if macro < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func round(value float64) float64 {
+	return math.Round(value*10) / 10
+}
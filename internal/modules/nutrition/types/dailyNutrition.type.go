@@ -0,0 +1,32 @@
+package types
+
+import (
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/nutrition/constants"
+	pt "github.com/joshibbotson/gym-tracker-backend/internal/modules/phase/types"
+)
+
+type MealSummary struct {
+	Meal    c.MealType  `json:"meal"`
+	Entries []MealEntry `json:"entries"`
+	Totals  Macros      `json:"totals"`
+}
+
+// MacroTargets come from the phase running on the day, only the targets the
+// phase sets are filled in.
+type MacroTargets struct {
+	Calories *float64 `json:"calories,omitempty"`
+	Protein  *float64 `json:"protein,omitempty"`
+	Carbs    *float64 `json:"carbs,omitempty"`
+	Fat      *float64 `json:"fat,omitempty"`
+}
+
+type DailyNutrition struct {
+	Date      time.Time     `json:"date"`
+	Meals     []MealSummary `json:"meals"`
+	Totals    Macros        `json:"totals"`
+	Phase     *pt.Phase     `json:"phase,omitempty"`
+	Targets   *MacroTargets `json:"targets,omitempty"`
+	Remaining *MacroTargets `json:"remaining,omitempty"`
+}
@@ -0,0 +1,37 @@
+package types
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Macros are calories and grams of each macro.
+type Macros struct {
+	Calories float64 `bson:"calories" json:"calories"`
+	Protein  float64 `bson:"protein" json:"protein"`
+	Carbs    float64 `bson:"carbs" json:"carbs"`
+	Fat      float64 `bson:"fat" json:"fat"`
+	Fibre    float64 `bson:"fibre,omitempty" json:"fibre,omitempty"`
+}
+
+// Food is an item in a user's food library, Macros are per serving.
+type Food struct {
+	ID          primitive.ObjectID `bson:"_id" json:"_id"`
+	UserId      primitive.ObjectID `bson:"userId" json:"-"`
+	Name        string             `bson:"name" json:"name"`
+	Brand       string             `bson:"brand,omitempty" json:"brand,omitempty"`
+	ServingSize float64            `bson:"servingSize" json:"servingSize"`
+	ServingUnit string             `bson:"servingUnit" json:"servingUnit"`
+	Macros      `bson:",inline"`
+	CreatedAt   time.Time `bson:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+type FoodRequest struct {
+	Name        string  `json:"name"`
+	Brand       string  `json:"brand,omitempty"`
+	ServingSize float64 `json:"servingSize"`
+	ServingUnit string  `json:"servingUnit"`
+	Macros
+}
@@ -0,0 +1,46 @@
+package types
+
+import (
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/nutrition/constants"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MealEntry is some servings of a food eaten in a meal. The food's name and
+// macros are copied in so editing or deleting the food doesn't rewrite history.
+type MealEntry struct {
+	ID       primitive.ObjectID `bson:"_id" json:"_id"`
+	UserId   primitive.ObjectID `bson:"userId" json:"-"`
+	Date     time.Time          `bson:"date" json:"date"`
+	Meal     c.MealType         `bson:"meal" json:"meal"`
+	FoodID   primitive.ObjectID `bson:"foodId" json:"foodId"`
+	FoodName string             `bson:"foodName" json:"foodName"`
+	Servings float64            `bson:"servings" json:"servings"`
+	// Macros are for all the servings eaten, PerServing is the food's macros
+	// when the entry was logged.
+	Macros     `bson:",inline"`
+	PerServing Macros    `bson:"perServing" json:"perServing"`
+	CreatedAt  time.Time `bson:"createdAt" json:"createdAt"`
+	UpdatedAt  time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+type MealEntryRequest struct {
+	Date     time.Time          `json:"date"`
+	Meal     c.MealType         `json:"meal"`
+	FoodID   primitive.ObjectID `json:"foodId"`
+	Servings float64            `json:"servings"`
+}
+
+type UpdateMealEntryRequest struct {
+	Meal     c.MealType `json:"meal,omitempty"`
+	Servings float64    `json:"servings"`
+}
+
+// CopyMealRequest copies a day's meal, or every meal when Meal is empty, onto
+// another day. From defaults to the day before To.
+type CopyMealRequest struct {
+	From *time.Time `json:"from,omitempty"`
+	To   time.Time  `json:"to"`
+	Meal c.MealType `json:"meal,omitempty"`
+}
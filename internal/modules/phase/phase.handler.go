@@ -0,0 +1,244 @@
+package phase
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	m "github.com/joshibbotson/gym-tracker-backend/internal/middleware"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/phase/types"
+	util "github.com/joshibbotson/gym-tracker-backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type PhaseHandler struct {
+	Service PhaseService
+}
+
+func NewPhaseHandler(service PhaseService) *PhaseHandler {
+	return &PhaseHandler{
+		Service: service,
+	}
+}
+
+// Handler serves /phase, /phase/{id}, /phase/current and /phase/derive
+func (h *PhaseHandler) Handler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		if r.URL.Path == "/phase/derive" {
+			m.PermissionMiddleware(h.handleDerivePhases)(w, r)
+			break
+		}
+		m.PermissionMiddleware(h.handleCreatePhase)(w, r)
+	case http.MethodGet:
+		if r.URL.Path == "/phase/current" {
+			m.PermissionMiddleware(h.handleReadCurrentPhase)(w, r)
+			break
+		}
+		if r.PathValue("id") != "" {
+			m.PermissionMiddleware(h.handleReadPhase)(w, r)
+			break
+		}
+		m.PermissionMiddleware(h.handleReadPhases)(w, r)
+	case http.MethodPatch:
+		m.PermissionMiddleware(h.handleUpdatePhase)(w, r)
+	case http.MethodDelete:
+		m.PermissionMiddleware(h.handleDeletePhase)(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// SummaryHandler serves /phase/summary and /phase/summary/{id}
+func (h *PhaseHandler) SummaryHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if r.PathValue("id") != "" {
+		m.PermissionMiddleware(h.handleReadSummary)(w, r)
+		return
+	}
+	m.PermissionMiddleware(h.handleReadSummaries)(w, r)
+}
+
+func (h *PhaseHandler) handleCreatePhase(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	var request t.PhaseRequest
+	if !util.DecodeBody(w, r, &request) {
+		return
+	}
+
+	phase, err := h.Service.CreatePhase(userID, request)
+	if errors.Is(err, ErrInvalidPhase) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, ErrPhaseOverlap) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error creating phase", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusCreated, phase)
+}
+
+func (h *PhaseHandler) handleReadPhases(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	phases, err := h.Service.GetPhases(userID)
+	if err != nil {
+		http.Error(w, "Error fetching phases", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, phases)
+}
+
+func (h *PhaseHandler) handleReadPhase(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+	phaseID, ok := util.ParseObjectID(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+
+	phase, err := h.Service.GetPhaseById(userID, phaseID)
+	if errors.Is(err, ErrPhaseNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error fetching phase", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, phase)
+}
+
+// handleReadCurrentPhase accepts an optional tz query param (IANA name) for
+// which day counts as today.
+func (h *PhaseHandler) handleReadCurrentPhase(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+	location, ok := util.ParseTimezone(w, r)
+	if !ok {
+		return
+	}
+
+	now := time.Now().In(location)
+	phase, err := h.Service.GetPhaseOn(userID, time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		http.Error(w, "Error fetching phase", http.StatusInternalServerError)
+		return
+	}
+	if phase == nil {
+		http.Error(w, "No current phase", http.StatusNotFound)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, phase)
+}
+
+func (h *PhaseHandler) handleUpdatePhase(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+	phaseID, ok := util.ParseObjectID(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+
+	var request t.PhaseRequest
+	if !util.DecodeBody(w, r, &request) {
+		return
+	}
+
+	phase, err := h.Service.UpdatePhase(userID, phaseID, request)
+	if errors.Is(err, ErrInvalidPhase) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, ErrPhaseNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, ErrPhaseOverlap) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error updating phase", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, phase)
+}
+
+func (h *PhaseHandler) handleDeletePhase(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+	phaseID, ok := util.ParseObjectID(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+
+	success, err := h.Service.DeletePhase(userID, phaseID)
+	if err != nil {
+		http.Error(w, "Error deleting phase", http.StatusInternalServerError)
+		return
+	}
+	if !success {
+		http.Error(w, "Phase not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"message": "Phase deleted successfully"}`))
+}
+
+// handleDerivePhases rebuilds the derived phases from the caloriePhase logged
+// on workouts and returns the ones created.
+func (h *PhaseHandler) handleDerivePhases(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	phases, err := h.Service.DerivePhases(userID)
+	if err != nil {
+		http.Error(w, "Error deriving phases", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, phases)
+}
+
+func (h *PhaseHandler) handleReadSummaries(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	summaries, err := h.Service.GetSummaries(userID)
+	if err != nil {
+		http.Error(w, "Error fetching phase summaries", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, summaries)
+}
+
+func (h *PhaseHandler) handleReadSummary(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+	phaseID, ok := util.ParseObjectID(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+
+	summary, err := h.Service.GetSummary(userID, phaseID)
+	if errors.Is(err, ErrPhaseNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error fetching phase summary", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, summary)
+}
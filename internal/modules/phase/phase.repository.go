@@ -0,0 +1,126 @@
+package phase
+
+import (
+	"context"
+	"time"
+
+	db "github.com/joshibbotson/gym-tracker-backend/internal/db"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/phase/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PhaseRepository interface {
+	InsertPhase(ctx context.Context, phase t.Phase) (*t.Phase, error)
+	InsertPhases(ctx context.Context, phases []t.Phase) error
+	FetchPhases(ctx context.Context, userID primitive.ObjectID) ([]t.Phase, error)
+	FetchPhaseById(ctx context.Context, userID primitive.ObjectID, phaseID primitive.ObjectID) (*t.Phase, error)
+	FetchPhaseOn(ctx context.Context, userID primitive.ObjectID, date time.Time) (*t.Phase, error)
+	UpdatePhase(ctx context.Context, phase t.Phase) (*t.Phase, error)
+	RemovePhase(ctx context.Context, userID primitive.ObjectID, phaseID primitive.ObjectID) (bool, error)
+	RemoveDerivedPhases(ctx context.Context, userID primitive.ObjectID) error
+}
+
+type phaseRepository struct {
+	phaseCollection *mongo.Collection
+}
+
+func NewPhaseRepository() PhaseRepository {
+	return &phaseRepository{
+		phaseCollection: db.Client.Database(db.DB_NAME).Collection("phase"),
+	}
+}
+
+func (r *phaseRepository) InsertPhase(ctx context.Context, phase t.Phase) (*t.Phase, error) {
+	_, err := r.phaseCollection.InsertOne(ctx, phase)
+	if err != nil {
+		return nil, err
+	}
+	return &phase, nil
+}
+
+func (r *phaseRepository) InsertPhases(ctx context.Context, phases []t.Phase) error {
+	if len(phases) == 0 {
+		return nil
+	}
+	documents := make([]interface{}, len(phases))
+	for i, phase := range phases {
+		documents[i] = phase
+	}
+	_, err := r.phaseCollection.InsertMany(ctx, documents)
+	return err
+}
+
+// FetchPhases returns the user's phases oldest first.
+func (r *phaseRepository) FetchPhases(ctx context.Context, userID primitive.ObjectID) ([]t.Phase, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "startDate", Value: 1}})
+	cursor, err := r.phaseCollection.Find(ctx, bson.M{"userId": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	phases := []t.Phase{}
+	if err = cursor.All(ctx, &phases); err != nil {
+		return nil, err
+	}
+	return phases, nil
+}
+
+func (r *phaseRepository) FetchPhaseById(ctx context.Context, userID primitive.ObjectID, phaseID primitive.ObjectID) (*t.Phase, error) {
+	var phase t.Phase
+	err := r.phaseCollection.FindOne(ctx, bson.M{"_id": phaseID, "userId": userID}).Decode(&phase)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &phase, nil
+}
+
+// FetchPhaseOn returns the phase running on date, nil if there isn't one.
+func (r *phaseRepository) FetchPhaseOn(ctx context.Context, userID primitive.ObjectID, date time.Time) (*t.Phase, error) {
+	filter := bson.M{
+		"userId":    userID,
+		"startDate": bson.M{"$lte": date},
+		"$or": bson.A{
+			bson.M{"endDate": bson.M{"$exists": false}},
+			bson.M{"endDate": bson.M{"$gte": date}},
+		},
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "startDate", Value: -1}})
+
+	var phase t.Phase
+	err := r.phaseCollection.FindOne(ctx, filter, opts).Decode(&phase)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &phase, nil
+}
+
+func (r *phaseRepository) UpdatePhase(ctx context.Context, phase t.Phase) (*t.Phase, error) {
+	_, err := r.phaseCollection.ReplaceOne(ctx, bson.M{"_id": phase.ID, "userId": phase.UserId}, phase)
+	if err != nil {
+		return nil, err
+	}
+	return &phase, nil
+}
+
+func (r *phaseRepository) RemovePhase(ctx context.Context, userID primitive.ObjectID, phaseID primitive.ObjectID) (bool, error) {
+	result, err := r.phaseCollection.DeleteOne(ctx, bson.M{"_id": phaseID, "userId": userID})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+func (r *phaseRepository) RemoveDerivedPhases(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.phaseCollection.DeleteMany(ctx, bson.M{"userId": userID, "derived": true})
+	return err
+}
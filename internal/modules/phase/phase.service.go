@@ -0,0 +1,366 @@
+package phase
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/phase/types"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/workout"
+	wt "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type PhaseService interface {
+	CreatePhase(userID primitive.ObjectID, phase t.PhaseRequest) (*t.Phase, error)
+	GetPhases(userID primitive.ObjectID) ([]t.Phase, error)
+	GetPhaseById(userID primitive.ObjectID, phaseID primitive.ObjectID) (*t.Phase, error)
+	GetPhaseOn(userID primitive.ObjectID, date time.Time) (*t.Phase, error)
+	UpdatePhase(userID primitive.ObjectID, phaseID primitive.ObjectID, phase t.PhaseRequest) (*t.Phase, error)
+	DeletePhase(userID primitive.ObjectID, phaseID primitive.ObjectID) (bool, error)
+	DerivePhases(userID primitive.ObjectID) ([]t.Phase, error)
+	GetSummaries(userID primitive.ObjectID) ([]t.PhaseSummary, error)
+	GetSummary(userID primitive.ObjectID, phaseID primitive.ObjectID) (*t.PhaseSummary, error)
+}
+
+var (
+	ErrPhaseNotFound = errors.New("phase not found")
+	ErrInvalidPhase  = errors.New("phase needs a type of cut, bulk or maintain, a start date and an end date after it")
+	ErrPhaseOverlap  = errors.New("phase overlaps another phase")
+)
+
+type phaseService struct {
+	repo           PhaseRepository
+	workoutService workout.WorkoutService
+}
+
+func NewPhaseService(repo PhaseRepository, workoutService workout.WorkoutService) PhaseService {
+	return &phaseService{repo: repo, workoutService: workoutService}
+}
+
+// CreatePhase adds a phase. A phase still running when the new one starts is
+// ended the day before, any other overlap is rejected.
+func (s *phaseService) CreatePhase(userID primitive.ObjectID, phase t.PhaseRequest) (*t.Phase, error) {
+	if !validPhase(phase) {
+		return nil, ErrInvalidPhase
+	}
+
+	newPhase := t.Phase{
+		ID:        primitive.NewObjectID(),
+		UserId:    userID,
+		CreatedAt: time.Now(),
+	}
+	applyRequest(&newPhase, phase)
+
+	phases, err := s.repo.FetchPhases(context.TODO(), userID)
+	if err != nil {
+		return nil, err
+	}
+	var running *t.Phase
+	for i := range phases {
+		if phases[i].EndDate == nil && phases[i].StartDate.Before(newPhase.StartDate) {
+			running = &phases[i]
+			ended := newPhase.StartDate.AddDate(0, 0, -1)
+			running.EndDate = &ended
+		}
+	}
+	if overlapping(newPhase, phases) {
+		return nil, ErrPhaseOverlap
+	}
+
+	if running != nil {
+		running.UpdatedAt = time.Now()
+		if _, err := s.repo.UpdatePhase(context.TODO(), *running); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.InsertPhase(context.TODO(), newPhase)
+}
+
+func (s *phaseService) GetPhases(userID primitive.ObjectID) ([]t.Phase, error) {
+	return s.repo.FetchPhases(context.TODO(), userID)
+}
+
+func (s *phaseService) GetPhaseById(userID primitive.ObjectID, phaseID primitive.ObjectID) (*t.Phase, error) {
+	phase, err := s.repo.FetchPhaseById(context.TODO(), userID, phaseID)
+	if err != nil {
+		return nil, err
+	}
+	if phase == nil {
+		return nil, ErrPhaseNotFound
+	}
+	return phase, nil
+}
+
+// GetPhaseOn returns the phase running on date, nil if there isn't one.
+func (s *phaseService) GetPhaseOn(userID primitive.ObjectID, date time.Time) (*t.Phase, error) {
+	return s.repo.FetchPhaseOn(context.TODO(), userID, calendarDay(date))
+}
+
+func (s *phaseService) UpdatePhase(userID primitive.ObjectID, phaseID primitive.ObjectID, phase t.PhaseRequest) (*t.Phase, error) {
+	if !validPhase(phase) {
+		return nil, ErrInvalidPhase
+	}
+
+	existing, err := s.GetPhaseById(userID, phaseID)
+	if err != nil {
+		return nil, err
+	}
+	applyRequest(existing, phase)
+	// editing a derived phase makes it the user's own, so re-deriving keeps it
+	existing.Derived = false
+
+	phases, err := s.repo.FetchPhases(context.TODO(), userID)
+	if err != nil {
+		return nil, err
+	}
+	if overlapping(*existing, phases) {
+		return nil, ErrPhaseOverlap
+	}
+	return s.repo.UpdatePhase(context.TODO(), *existing)
+}
+
+func (s *phaseService) DeletePhase(userID primitive.ObjectID, phaseID primitive.ObjectID) (bool, error) {
+	return s.repo.RemovePhase(context.TODO(), userID, phaseID)
+}
+
+// DerivePhases rebuilds phases from the caloriePhase logged on workouts. Each
+// run of workouts logging the same phase becomes a phase lasting until the
+// next run starts, the latest run is left running. Phases the user created or
+// edited are kept and derived runs overlapping them are dropped.
+func (s *phaseService) DerivePhases(userID primitive.ObjectID) ([]t.Phase, error) {
+	workouts, err := s.workoutService.GetWorkoutsInRange(userID, time.Time{}, time.Now().AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.RemoveDerivedPhases(context.TODO(), userID); err != nil {
+		return nil, err
+	}
+	existing, err := s.repo.FetchPhases(context.TODO(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	derived := []t.Phase{}
+	for _, workout := range workouts {
+		if workout.Workout == nil || workout.Workout.CaloriePhase == nil || !workout.Workout.CaloriePhase.Valid() {
+			continue
+		}
+		day := calendarDay(workout.Date)
+		if n := len(derived); n > 0 {
+			if derived[n-1].Type == *workout.Workout.CaloriePhase {
+				continue
+			}
+			ended := day.AddDate(0, 0, -1)
+			derived[n-1].EndDate = &ended
+		}
+		derived = append(derived, t.Phase{
+			ID:        primitive.NewObjectID(),
+			UserId:    userID,
+			Type:      *workout.Workout.CaloriePhase,
+			StartDate: day,
+			Derived:   true,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		})
+	}
+
+	kept := []t.Phase{}
+	for _, phase := range derived {
+		if !overlapping(phase, existing) {
+			kept = append(kept, phase)
+		}
+	}
+	if err := s.repo.InsertPhases(context.TODO(), kept); err != nil {
+		return nil, err
+	}
+	return kept, nil
+}
+
+// GetSummaries summarises every phase oldest first from one fetch of the
+// workouts they span.
+func (s *phaseService) GetSummaries(userID primitive.ObjectID) ([]t.PhaseSummary, error) {
+	phases, err := s.repo.FetchPhases(context.TODO(), userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(phases) == 0 {
+		return []t.PhaseSummary{}, nil
+	}
+
+	from := phases[0].StartDate
+	to := time.Time{}
+	for _, phase := range phases {
+		if end := phaseEnd(phase); end.After(to) {
+			to = end
+		}
+	}
+	workouts, err := s.workoutService.GetWorkoutsInRange(userID, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]t.PhaseSummary, 0, len(phases))
+	for _, phase := range phases {
+		summaries = append(summaries, summarise(phase, workouts))
+	}
+	return summaries, nil
+}
+
+func (s *phaseService) GetSummary(userID primitive.ObjectID, phaseID primitive.ObjectID) (*t.PhaseSummary, error) {
+	phase, err := s.GetPhaseById(userID, phaseID)
+	if err != nil {
+		return nil, err
+	}
+	workouts, err := s.workoutService.GetWorkoutsInRange(userID, phase.StartDate, phaseEnd(*phase).AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	summary := summarise(*phase, workouts)
+	return &summary, nil
+}
+
+// summarise compares the first and last weigh-in and measurements within the
+// phase and counts the training sessions. Workouts outside the phase are ignored.
+func summarise(phase t.Phase, workouts []wt.Workout) t.PhaseSummary {
+	end := phaseEnd(phase)
+	summary := t.PhaseSummary{
+		Phase:        phase,
+		DurationDays: int(end.Sub(phase.StartDate).Hours()/24) + 1,
+		Measurements: []t.MeasurementChange{},
+	}
+
+	var firstWeight, lastWeight *wt.Workout
+	first := map[string]t.MeasurementChange{}
+	trainingDays := map[time.Time]bool{}
+	for i := range workouts {
+		logged := &workouts[i]
+		day := calendarDay(logged.Date)
+		if day.Before(phase.StartDate) || day.After(end) || logged.Workout == nil {
+			continue
+		}
+		config := logged.Workout
+
+		if workout.IsTraining(config) {
+			summary.Workouts++
+			trainingDays[day] = true
+		}
+
+		measurements := workout.BodyMeasurements(config)
+		if config.Weight != nil || len(measurements) > 0 {
+			summary.CheckIns++
+		}
+		if config.Weight != nil && *config.Weight > 0 {
+			if firstWeight == nil {
+				firstWeight = logged
+			}
+			lastWeight = logged
+		}
+		for field, value := range measurements {
+			change, ok := first[field]
+			if !ok {
+				change = t.MeasurementChange{Field: field, First: value, FirstDate: logged.Date}
+			}
+			change.Last, change.LastDate = value, logged.Date
+			change.Change = round(change.Last - change.First)
+			first[field] = change
+		}
+	}
+
+	summary.TrainingDays = len(trainingDays)
+	summary.WorkoutsPerWeek = round(float64(summary.Workouts) / (float64(summary.DurationDays) / 7))
+
+	if firstWeight != nil {
+		startWeight, endWeight := *firstWeight.Workout.Weight, *lastWeight.Workout.Weight
+		change := round(endWeight - startWeight)
+		summary.StartWeight, summary.EndWeight, summary.WeightChange = &startWeight, &endWeight, &change
+		if weeks := lastWeight.Date.Sub(firstWeight.Date).Hours() / 24 / 7; weeks >= 1 {
+			rate := round(change / weeks)
+			summary.ActualWeeklyRate = &rate
+		}
+	}
+
+	for _, field := range workout.MeasurementFields() {
+		if change, ok := first[field]; ok {
+			summary.Measurements = append(summary.Measurements, change)
+		}
+	}
+	return summary
+}
+
+func applyRequest(phase *t.Phase, request t.PhaseRequest) {
+	phase.Type = request.Type
+	phase.StartDate = calendarDay(request.StartDate)
+	phase.EndDate = nil
+	if request.EndDate != nil {
+		end := calendarDay(*request.EndDate)
+		phase.EndDate = &end
+	}
+	phase.TargetWeeklyRate = request.TargetWeeklyRate
+	phase.CalorieTarget = request.CalorieTarget
+	phase.ProteinTarget = request.ProteinTarget
+	phase.CarbsTarget = request.CarbsTarget
+	phase.FatTarget = request.FatTarget
+	phase.Notes = strings.TrimSpace(request.Notes)
+	phase.UpdatedAt = time.Now()
+}
+
+func validPhase(phase t.PhaseRequest) bool {
+	if !phase.Type.Valid() || phase.StartDate.IsZero() {
+		return false
+	}
+	if phase.EndDate != nil && calendarDay(*phase.EndDate).Before(calendarDay(phase.StartDate)) {
+		return false
+	}
+	for _, target := range []*float64{phase.ProteinTarget, phase.CarbsTarget, phase.FatTarget} {
+		if target != nil && *target < 0 {
+			return false
+		}
+	}
+	return phase.CalorieTarget == nil || *phase.CalorieTarget > 0
+}
+
+// overlapping checks phase against every other phase in phases, a running
+// phase overlaps anything starting after it.
+func overlapping(phase t.Phase, phases []t.Phase) bool {
+	for _, other := range phases {
+		if other.ID == phase.ID {
+			continue
+		}
+		if !phase.StartDate.After(openEnd(other)) && !other.StartDate.After(openEnd(phase)) {
+			return true
+		}
+	}
+	return false
+}
+
+func openEnd(phase t.Phase) time.Time {
+	if phase.EndDate != nil {
+		return *phase.EndDate
+	}
+	return time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC)
+}
+
+// phaseEnd is the last day of a phase, today while it's still running.
+func phaseEnd(phase t.Phase) time.Time {
+	if phase.EndDate != nil {
+		return *phase.EndDate
+	}
+	today := calendarDay(time.Now())
+	if phase.StartDate.After(today) {
+		return phase.StartDate
+	}
+	return today
+}
+
+func calendarDay(date time.Time) time.Time {
+	date = date.UTC()
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,233 @@
+package phase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/phase/types"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/workout"
+	wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+	wt "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func day(d int) time.Time {
+	return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC)
+}
+
+// phase runs from start to end in March, end 0 leaves it running.
+func phase(phaseType wc.CaloriePhase, start int, end int) t.Phase {
+	built := t.Phase{ID: primitive.NewObjectID(), Type: phaseType, StartDate: day(start)}
+	if end != 0 {
+		ended := day(end)
+		built.EndDate = &ended
+	}
+	return built
+}
+
+// render writes phases out as "cut 1-9 bulk 10-", oldest first.
+func render(phases ...t.Phase) string {
+	parts := make([]string, len(phases))
+	for i, rendered := range phases {
+		parts[i] = fmt.Sprintf("%s %d-", rendered.Type, rendered.StartDate.Day())
+		if rendered.EndDate != nil {
+			parts[i] += fmt.Sprint(rendered.EndDate.Day())
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestOpenEnd(tt *testing.T) {
+	if got := openEnd(phase(wc.CaloriePhaseCut, 1, 9)); !got.Equal(day(9)) {
+		tt.Fatalf("ended phase: got %v, want %v", got, day(9))
+	}
+	if got := openEnd(phase(wc.CaloriePhaseCut, 1, 0)); !got.After(time.Now().AddDate(1000, 0, 0)) {
+		tt.Fatalf("running phase: got %v, want it to run on indefinitely", got)
+	}
+}
+
+func TestOverlapping(tt *testing.T) {
+	cut := phase(wc.CaloriePhaseCut, 10, 20)
+
+	tests := []struct {
+		name   string
+		phase  t.Phase
+		others []t.Phase
+		want   bool
+	}{
+		{"no other phases", cut, nil, false},
+		{"ends the day before", cut, []t.Phase{phase(wc.CaloriePhaseBulk, 1, 9)}, false},
+		{"starts the day after", cut, []t.Phase{phase(wc.CaloriePhaseBulk, 21, 30)}, false},
+		{"shares the first day", cut, []t.Phase{phase(wc.CaloriePhaseBulk, 1, 10)}, true},
+		{"shares the last day", cut, []t.Phase{phase(wc.CaloriePhaseBulk, 20, 30)}, true},
+		{"inside it", cut, []t.Phase{phase(wc.CaloriePhaseBulk, 12, 15)}, true},
+		{"running since before it", cut, []t.Phase{phase(wc.CaloriePhaseBulk, 1, 0)}, true},
+		{"running from after it", cut, []t.Phase{phase(wc.CaloriePhaseBulk, 21, 0)}, false},
+		{"running into a later phase", phase(wc.CaloriePhaseCut, 10, 0), []t.Phase{phase(wc.CaloriePhaseBulk, 25, 30)}, true},
+		{"itself", cut, []t.Phase{cut}, false},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			if got := overlapping(test.phase, test.others); got != test.want {
+				tt.Fatalf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// fakePhases remembers what was written to it.
+type fakePhases struct {
+	PhaseRepository
+	phases   []t.Phase
+	updated  []t.Phase
+	inserted []t.Phase
+	cleared  bool
+}
+
+func (r *fakePhases) FetchPhases(ctx context.Context, userID primitive.ObjectID) ([]t.Phase, error) {
+	return append([]t.Phase{}, r.phases...), nil
+}
+
+func (r *fakePhases) UpdatePhase(ctx context.Context, phase t.Phase) (*t.Phase, error) {
+	r.updated = append(r.updated, phase)
+	return &phase, nil
+}
+
+func (r *fakePhases) InsertPhase(ctx context.Context, phase t.Phase) (*t.Phase, error) {
+	r.inserted = append(r.inserted, phase)
+	return &phase, nil
+}
+
+func (r *fakePhases) InsertPhases(ctx context.Context, phases []t.Phase) error {
+	r.inserted = append(r.inserted, phases...)
+	return nil
+}
+
+func (r *fakePhases) RemoveDerivedPhases(ctx context.Context, userID primitive.ObjectID) error {
+	r.cleared = true
+	return nil
+}
+
+func TestCreatePhase(tt *testing.T) {
+	end := day(20)
+
+	tests := []struct {
+		name         string
+		existing     []t.Phase
+		request      t.PhaseRequest
+		wantErr      error
+		wantUpdated  string
+		wantInserted string
+	}{
+		{"first phase", nil, t.PhaseRequest{Type: wc.CaloriePhaseCut, StartDate: day(10)}, nil, "", "cut 10-"},
+		{"ends the running phase the day before", []t.Phase{phase(wc.CaloriePhaseBulk, 1, 0)}, t.PhaseRequest{Type: wc.CaloriePhaseCut, StartDate: day(10), EndDate: &end}, nil, "bulk 1-9", "cut 10-20"},
+		{"running phase starting the same day", []t.Phase{phase(wc.CaloriePhaseBulk, 10, 0)}, t.PhaseRequest{Type: wc.CaloriePhaseCut, StartDate: day(10)}, ErrPhaseOverlap, "", ""},
+		{"running phase starting later", []t.Phase{phase(wc.CaloriePhaseBulk, 15, 0)}, t.PhaseRequest{Type: wc.CaloriePhaseCut, StartDate: day(10), EndDate: &end}, ErrPhaseOverlap, "", ""},
+		{"overlaps an ended phase", []t.Phase{phase(wc.CaloriePhaseBulk, 1, 12)}, t.PhaseRequest{Type: wc.CaloriePhaseCut, StartDate: day(10)}, ErrPhaseOverlap, "", ""},
+		{"ended phase is left alone", []t.Phase{phase(wc.CaloriePhaseBulk, 1, 5)}, t.PhaseRequest{Type: wc.CaloriePhaseCut, StartDate: day(10)}, nil, "", "cut 10-"},
+		{"invalid", nil, t.PhaseRequest{Type: "recomp", StartDate: day(10)}, ErrInvalidPhase, "", ""},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			repo := &fakePhases{phases: test.existing}
+			service := NewPhaseService(repo, nil)
+
+			_, err := service.CreatePhase(primitive.NewObjectID(), test.request)
+			if !errors.Is(err, test.wantErr) {
+				tt.Fatalf("got %v, want %v", err, test.wantErr)
+			}
+			if got := render(repo.updated...); got != test.wantUpdated {
+				tt.Fatalf("updated %q, want %q", got, test.wantUpdated)
+			}
+			if got := render(repo.inserted...); got != test.wantInserted {
+				tt.Fatalf("inserted %q, want %q", got, test.wantInserted)
+			}
+		})
+	}
+}
+
+type fakeWorkouts struct {
+	workout.WorkoutService
+	workouts []wt.Workout
+}
+
+func (s *fakeWorkouts) GetWorkoutsInRange(userID primitive.ObjectID, from time.Time, to time.Time) ([]wt.Workout, error) {
+	return s.workouts, nil
+}
+
+// logged is a workout on a day in March logging a calorie phase, an empty
+// phase logs none.
+func logged(d int, caloriePhase wc.CaloriePhase) wt.Workout {
+	config := &wt.WorkoutConfig{}
+	if caloriePhase != "" {
+		config.CaloriePhase = &caloriePhase
+	}
+	return wt.Workout{ID: primitive.NewObjectID(), Date: day(d).Add(18 * time.Hour), Workout: config}
+}
+
+func TestDerivePhases(tt *testing.T) {
+	tests := []struct {
+		name     string
+		workouts []wt.Workout
+		existing []t.Phase
+		want     string
+	}{
+		{"nothing logged", nil, nil, ""},
+		{
+			"runs split where the phase changes",
+			[]wt.Workout{logged(1, wc.CaloriePhaseCut), logged(3, wc.CaloriePhaseCut), logged(10, wc.CaloriePhaseBulk), logged(12, wc.CaloriePhaseBulk), logged(20, wc.CaloriePhaseMaintain)},
+			nil,
+			"cut 1-9 bulk 10-19 maintain 20-",
+		},
+		{
+			"workouts without a valid phase don't break a run",
+			[]wt.Workout{logged(1, wc.CaloriePhaseCut), logged(2, ""), logged(3, "recomp"), {Date: day(4)}, logged(5, wc.CaloriePhaseCut)},
+			nil,
+			"cut 1-",
+		},
+		{
+			"the same phase again later is a new run",
+			[]wt.Workout{logged(1, wc.CaloriePhaseCut), logged(10, wc.CaloriePhaseBulk), logged(20, wc.CaloriePhaseCut)},
+			nil,
+			"cut 1-9 bulk 10-19 cut 20-",
+		},
+		{
+			"runs overlapping the user's own phases are dropped",
+			[]wt.Workout{logged(1, wc.CaloriePhaseCut), logged(10, wc.CaloriePhaseBulk), logged(20, wc.CaloriePhaseMaintain)},
+			[]t.Phase{phase(wc.CaloriePhaseBulk, 12, 15)},
+			"cut 1-9 maintain 20-",
+		},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			repo := &fakePhases{phases: test.existing}
+			service := NewPhaseService(repo, &fakeWorkouts{workouts: test.workouts})
+
+			derived, err := service.DerivePhases(primitive.NewObjectID())
+			if err != nil {
+				tt.Fatalf("unexpected error: %v", err)
+			}
+			if !repo.cleared {
+				tt.Fatal("expected the previously derived phases to be removed")
+			}
+			if got := render(derived...); got != test.want {
+				tt.Fatalf("got %q, want %q", got, test.want)
+			}
+			if got := render(repo.inserted...); got != test.want {
+				tt.Fatalf("inserted %q, want %q", got, test.want)
+			}
+			for _, phase := range derived {
+				if !phase.Derived {
+					tt.Fatalf("%s isn't marked derived", render(phase))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package types
+
+import (
+	"time"
+
+	wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Phase is a cut, bulk or maintenance period. EndDate is inclusive and nil
+// while the phase is still running.
+type Phase struct {
+	ID        primitive.ObjectID `bson:"_id" json:"_id"`
+	UserId    primitive.ObjectID `bson:"userId" json:"-"`
+	Type      wc.CaloriePhase    `bson:"type" json:"type"`
+	StartDate time.Time          `bson:"startDate" json:"startDate"`
+	EndDate   *time.Time         `bson:"endDate,omitempty" json:"endDate,omitempty"`
+	// TargetWeeklyRate is the planned weight change per week, negative for a cut.
+	TargetWeeklyRate *float64 `bson:"targetWeeklyRate,omitempty" json:"targetWeeklyRate,omitempty"`
+	CalorieTarget    *int     `bson:"calorieTarget,omitempty" json:"calorieTarget,omitempty"`
+	// Daily macro targets in grams.
+	ProteinTarget *float64 `bson:"proteinTarget,omitempty" json:"proteinTarget,omitempty"`
+	CarbsTarget   *float64 `bson:"carbsTarget,omitempty" json:"carbsTarget,omitempty"`
+	FatTarget     *float64 `bson:"fatTarget,omitempty" json:"fatTarget,omitempty"`
+	Notes         string   `bson:"notes,omitempty" json:"notes,omitempty"`
+	// Derived is set on phases built from the caloriePhase logged on workouts.
+	Derived   bool      `bson:"derived,omitempty" json:"derived,omitempty"`
+	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+type PhaseRequest struct {
+	Type             wc.CaloriePhase `json:"type"`
+	StartDate        time.Time       `json:"startDate"`
+	EndDate          *time.Time      `json:"endDate,omitempty"`
+	TargetWeeklyRate *float64        `json:"targetWeeklyRate,omitempty"`
+	CalorieTarget    *int            `json:"calorieTarget,omitempty"`
+	ProteinTarget    *float64        `json:"proteinTarget,omitempty"`
+	CarbsTarget      *float64        `json:"carbsTarget,omitempty"`
+	FatTarget        *float64        `json:"fatTarget,omitempty"`
+	Notes            string          `json:"notes,omitempty"`
+}
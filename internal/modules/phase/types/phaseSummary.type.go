@@ -0,0 +1,30 @@
+package types
+
+import "time"
+
+type MeasurementChange struct {
+	Field     string    `json:"field"`
+	First     float64   `json:"first"`
+	FirstDate time.Time `json:"firstDate"`
+	Last      float64   `json:"last"`
+	LastDate  time.Time `json:"lastDate"`
+	Change    float64   `json:"change"`
+}
+
+type PhaseSummary struct {
+	Phase        Phase `json:"phase"`
+	DurationDays int   `json:"durationDays"`
+
+	StartWeight  *float64 `json:"startWeight,omitempty"`
+	EndWeight    *float64 `json:"endWeight,omitempty"`
+	WeightChange *float64 `json:"weightChange,omitempty"`
+	// ActualWeeklyRate is WeightChange spread over the weeks between the first and last weigh-in.
+	ActualWeeklyRate *float64 `json:"actualWeeklyRate,omitempty"`
+
+	CheckIns     int                 `json:"checkIns"`
+	Measurements []MeasurementChange `json:"measurements"`
+
+	Workouts        int     `json:"workouts"`
+	TrainingDays    int     `json:"trainingDays"`
+	WorkoutsPerWeek float64 `json:"workoutsPerWeek"`
+}
@@ -0,0 +1,18 @@
+package constants
+
+type WaveSet struct {
+	Percent float64
+	Reps    int
+	AMRAP   bool
+}
+
+// FiveThreeOneWaves holds the working sets for each week of a 5/3/1 cycle,
+// as a fraction of the training max. The fourth week is the deload.
+var FiveThreeOneWaves = [4][]WaveSet{
+	{{Percent: 0.65, Reps: 5}, {Percent: 0.75, Reps: 5}, {Percent: 0.85, Reps: 5, AMRAP: true}},
+	{{Percent: 0.70, Reps: 3}, {Percent: 0.80, Reps: 3}, {Percent: 0.90, Reps: 3, AMRAP: true}},
+	{{Percent: 0.75, Reps: 5}, {Percent: 0.85, Reps: 3}, {Percent: 0.95, Reps: 1, AMRAP: true}},
+	{{Percent: 0.40, Reps: 5}, {Percent: 0.50, Reps: 5}, {Percent: 0.60, Reps: 5}},
+}
+
+const FiveThreeOneCycleWeeks = 4
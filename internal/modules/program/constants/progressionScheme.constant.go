@@ -0,0 +1,39 @@
+package constants
+
+type ProgressionScheme string
+
+const (
+	// Add a fixed increment every session all prescribed reps are completed,
+	// drop the load 10% after three failed sessions in a row.
+	ProgressionLinear ProgressionScheme = "linear"
+	// Wendler 5/3/1, four week cycles run off a training max.
+	ProgressionFiveThreeOne ProgressionScheme = "531"
+	// Add reps within a range until every set hits the top, then add load.
+	ProgressionDouble ProgressionScheme = "double"
+)
+
+func (p ProgressionScheme) Valid() bool {
+	switch p {
+	case ProgressionLinear, ProgressionFiveThreeOne, ProgressionDouble:
+		return true
+	}
+	return false
+}
+
+// Consecutive failed sessions before a linear progression lift is reset.
+const LinearFailureLimit = 3
+
+// Fraction of the working load kept after a linear progression reset.
+const LinearResetFactor = 0.9
+
+// Default fraction of the working load used in deload weeks.
+const DefaultDeloadFactor = 0.6
+
+// Default load rounding, the smallest jump most gyms can load.
+const DefaultRoundTo = 2.5
+
+// Upper bounds on a lift's sets and reps, every set is prescribed one by one.
+const (
+	MaxSets = 50
+	MaxReps = 1000
+)
@@ -0,0 +1,261 @@
+package program
+
+import (
+	"math"
+	"strings"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/program/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/program/types"
+	wt "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+)
+
+// The program engine is kept free of any db access: it turns a program and
+// its state into prescriptions, and folds logged workouts back into the state.
+
+func isDeloadWeek(program t.Program, week int) bool {
+	if program.Scheme == c.ProgressionFiveThreeOne {
+		return week%c.FiveThreeOneCycleWeeks == 0
+	}
+	return program.DeloadEvery > 0 && week%program.DeloadEvery == 0
+}
+
+func roundLoad(weight float64, roundTo float64) float64 {
+	if roundTo <= 0 {
+		return weight
+	}
+	return math.Round(weight/roundTo) * roundTo
+}
+
+// prescribeSession builds the session for a 1-indexed week and day.
+func prescribeSession(program t.Program, week int, day int) t.PrescribedSession {
+	programDay := program.Days[day-1]
+	deload := isDeloadWeek(program, week)
+
+	session := t.PrescribedSession{
+		ProgramID: program.ID,
+		Week:      week,
+		Day:       day,
+		DayName:   programDay.Name,
+		Deload:    deload,
+		Exercises: make([]t.PrescribedExercise, 0, len(programDay.Exercises)),
+	}
+	for _, exercise := range programDay.Exercises {
+		session.Exercises = append(session.Exercises, prescribeExercise(program, exercise, week))
+	}
+	return session
+}
+
+func prescribeExercise(program t.Program, exercise t.ProgramExercise, week int) t.PrescribedExercise {
+	load := program.State.Loads[exercise.Name]
+	prescribed := t.PrescribedExercise{Name: exercise.Name, Sets: []t.PrescribedSet{}}
+
+	if program.Scheme == c.ProgressionFiveThreeOne {
+		wave := c.FiveThreeOneWaves[(week-1)%c.FiveThreeOneCycleWeeks]
+		for _, set := range wave {
+			prescribed.Sets = append(prescribed.Sets, t.PrescribedSet{
+				Reps:    set.Reps,
+				Weight:  roundLoad(load*set.Percent, program.RoundTo),
+				Percent: set.Percent,
+				AMRAP:   set.AMRAP,
+			})
+		}
+		return prescribed
+	}
+
+	reps := exercise.Reps
+	if program.Scheme == c.ProgressionDouble {
+		if target, ok := program.State.RepTargets[exercise.Name]; ok {
+			reps = target
+		}
+	}
+	if isDeloadWeek(program, week) {
+		load = roundLoad(load*program.DeloadFactor, program.RoundTo)
+	}
+	for i := 0; i < exercise.Sets; i++ {
+		prescribed.Sets = append(prescribed.Sets, t.PrescribedSet{Reps: reps, Weight: load})
+	}
+	return prescribed
+}
+
+// advance folds logged workouts, oldest first, into the program state. Each
+// workout containing at least one of the current day's exercises counts as
+// that day's session.
+func advance(program *t.Program, workouts []wt.Workout) {
+	state := &program.State
+	if state.RepTargets == nil {
+		state.RepTargets = map[string]int{}
+	}
+	if state.Failures == nil {
+		state.Failures = map[string]int{}
+	}
+	if state.MissedAMRAP == nil {
+		state.MissedAMRAP = map[string]bool{}
+	}
+
+	for _, workout := range workouts {
+		if state.Completed {
+			return
+		}
+		if workout.Date.Before(state.LastWorkoutAt) || alreadyCounted(*state, workout) || workout.Workout == nil {
+			continue
+		}
+
+		programDay := program.Days[state.Day-1]
+		if !containsAnyExercise(workout.Workout.Exercises, programDay.Exercises) {
+			continue
+		}
+
+		deload := isDeloadWeek(*program, state.Week)
+		for _, exercise := range programDay.Exercises {
+			logged := findExercise(workout.Workout.Exercises, exercise.Name)
+			if logged == nil || deload {
+				continue
+			}
+			prescribed := prescribeExercise(*program, exercise, state.Week)
+
+			switch program.Scheme {
+			case c.ProgressionLinear:
+				progressLinear(program, exercise, *logged, prescribed)
+			case c.ProgressionDouble:
+				progressDouble(program, exercise, *logged, prescribed)
+			case c.ProgressionFiveThreeOne:
+				amrap := prescribed.Sets[len(prescribed.Sets)-1]
+				if !completedSets(*logged, []t.PrescribedSet{amrap}) {
+					state.MissedAMRAP[exercise.Name] = true
+				}
+			}
+		}
+
+		if workout.Date.After(state.LastWorkoutAt) {
+			state.LastWorkoutIDs = nil
+		}
+		state.LastWorkoutAt = workout.Date
+		state.LastWorkoutIDs = append(state.LastWorkoutIDs, workout.ID)
+		nextDay(program)
+	}
+}
+
+func nextDay(program *t.Program) {
+	state := &program.State
+	state.Day++
+	if state.Day <= len(program.Days) {
+		return
+	}
+
+	state.Day = 1
+	state.Week++
+
+	// 5/3/1 only moves the training max once a full cycle is done, and holds
+	// it for any lift whose AMRAP sets fell short during the cycle
+	if program.Scheme == c.ProgressionFiveThreeOne && (state.Week-1)%c.FiveThreeOneCycleWeeks == 0 {
+		for name, increment := range liftIncrements(program) {
+			if !state.MissedAMRAP[name] {
+				state.Loads[name] += increment
+			}
+		}
+		state.MissedAMRAP = map[string]bool{}
+	}
+
+	if program.Weeks > 0 && state.Week > program.Weeks {
+		state.Completed = true
+	}
+}
+
+func progressLinear(program *t.Program, exercise t.ProgramExercise, logged wt.Exercise, prescribed t.PrescribedExercise) {
+	state := &program.State
+	if completedSets(logged, prescribed.Sets) {
+		state.Loads[exercise.Name] += exercise.Increment
+		state.Failures[exercise.Name] = 0
+		return
+	}
+
+	state.Failures[exercise.Name]++
+	if state.Failures[exercise.Name] >= c.LinearFailureLimit {
+		state.Loads[exercise.Name] = roundLoad(state.Loads[exercise.Name]*c.LinearResetFactor, program.RoundTo)
+		state.Failures[exercise.Name] = 0
+	}
+}
+
+func progressDouble(program *t.Program, exercise t.ProgramExercise, logged wt.Exercise, prescribed t.PrescribedExercise) {
+	state := &program.State
+	if !completedSets(logged, prescribed.Sets) {
+		return
+	}
+
+	target := prescribed.Sets[0].Reps
+	if target >= exercise.MaxReps {
+		state.Loads[exercise.Name] += exercise.Increment
+		state.RepTargets[exercise.Name] = exercise.Reps
+		return
+	}
+	state.RepTargets[exercise.Name] = target + 1
+}
+
+// completedSets checks every prescribed set has a distinct logged set at or
+// above its reps and weight.
+func completedSets(logged wt.Exercise, prescribed []t.PrescribedSet) bool {
+	used := make([]bool, len(logged.Sets))
+	for _, target := range prescribed {
+		matched := false
+		for i, set := range logged.Sets {
+			if used[i] || set.Reps < target.Reps {
+				continue
+			}
+			weight := 0.0
+			if set.Weight != nil {
+				weight = *set.Weight
+			}
+			if weight+0.001 < target.Weight {
+				continue
+			}
+			used[i] = true
+			matched = true
+			break
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+func alreadyCounted(state t.ProgramState, workout wt.Workout) bool {
+	for _, id := range state.LastWorkoutIDs {
+		if id == workout.ID {
+			return true
+		}
+	}
+	return false
+}
+
+func findExercise(exercises []wt.Exercise, name string) *wt.Exercise {
+	for i := range exercises {
+		if strings.EqualFold(strings.TrimSpace(exercises[i].Name), strings.TrimSpace(name)) {
+			return &exercises[i]
+		}
+	}
+	return nil
+}
+
+func containsAnyExercise(logged []wt.Exercise, prescribed []t.ProgramExercise) bool {
+	for _, exercise := range prescribed {
+		if findExercise(logged, exercise.Name) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// liftIncrements returns each distinct lift in the program with its increment,
+// a lift can appear on more than one day but only progresses once a cycle.
+func liftIncrements(program *t.Program) map[string]float64 {
+	increments := map[string]float64{}
+	for _, day := range program.Days {
+		for _, exercise := range day.Exercises {
+			if _, ok := increments[exercise.Name]; !ok {
+				increments[exercise.Name] = exercise.Increment
+			}
+		}
+	}
+	return increments
+}
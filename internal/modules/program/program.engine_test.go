@@ -0,0 +1,208 @@
+package program
+
+import (
+	"testing"
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/program/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/program/types"
+	wt "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newProgram(scheme c.ProgressionScheme, exercise t.ProgramExercise, load float64) t.Program {
+	return t.Program{
+		Scheme:       scheme,
+		Days:         []t.ProgramDay{{Name: "A", Exercises: []t.ProgramExercise{exercise}}},
+		DeloadFactor: c.DefaultDeloadFactor,
+		RoundTo:      c.DefaultRoundTo,
+		State:        t.ProgramState{Week: 1, Day: 1, Loads: map[string]float64{exercise.Name: load}},
+	}
+}
+
+// session logs one workout a day after start with the same reps and weight for
+// every set.
+func session(day int, name string, sets int, reps int, weight float64) wt.Workout {
+	logged := wt.Exercise{Name: name}
+	for i := 0; i < sets; i++ {
+		w := weight
+		logged.Sets = append(logged.Sets, wt.ExerciseSet{Reps: reps, Weight: &w})
+	}
+	return wt.Workout{
+		ID:      primitive.NewObjectID(),
+		Date:    time.Date(2026, 1, 1+day, 0, 0, 0, 0, time.UTC),
+		Workout: &wt.WorkoutConfig{Exercises: []wt.Exercise{logged}},
+	}
+}
+
+func TestAdvanceLinear(tt *testing.T) {
+	squat := t.ProgramExercise{Name: "Squat", Sets: 3, Reps: 5, Increment: 2.5}
+
+	tests := []struct {
+		name     string
+		workouts []wt.Workout
+		want     float64
+	}{
+		{"completed adds the increment", []wt.Workout{session(0, "squat", 3, 5, 100)}, 102.5},
+		{"a short set holds the load", []wt.Workout{session(0, "Squat", 3, 4, 100)}, 100},
+		{"a light set holds the load", []wt.Workout{session(0, "Squat", 3, 5, 95)}, 100},
+		{"third failure in a row resets", []wt.Workout{
+			session(0, "Squat", 3, 4, 100),
+			session(1, "Squat", 3, 4, 100),
+			session(2, "Squat", 3, 4, 100),
+		}, 90},
+		{"a success clears failures", []wt.Workout{
+			session(0, "Squat", 3, 4, 100),
+			session(1, "Squat", 3, 4, 100),
+			session(2, "Squat", 3, 5, 100),
+			session(3, "Squat", 3, 4, 102.5),
+		}, 102.5},
+		{"other exercises don't count", []wt.Workout{session(0, "Bench", 3, 5, 100)}, 100},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			program := newProgram(c.ProgressionLinear, squat, 100)
+			advance(&program, test.workouts)
+			if got := program.State.Loads["Squat"]; got != test.want {
+				tt.Fatalf("load = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestAdvanceCountsAWorkoutOnce(tt *testing.T) {
+	squat := t.ProgramExercise{Name: "Squat", Sets: 3, Reps: 5, Increment: 2.5}
+	program := newProgram(c.ProgressionLinear, squat, 100)
+	workout := session(0, "Squat", 3, 5, 100)
+
+	advance(&program, []wt.Workout{workout})
+	advance(&program, []wt.Workout{workout})
+	if got := program.State.Loads["Squat"]; got != 102.5 {
+		tt.Fatalf("load = %v, want 102.5", got)
+	}
+}
+
+func TestAdvanceDouble(tt *testing.T) {
+	curl := t.ProgramExercise{Name: "Curl", Sets: 3, Reps: 8, MaxReps: 10, Increment: 2.5}
+	program := newProgram(c.ProgressionDouble, curl, 20)
+
+	advance(&program, []wt.Workout{session(0, "Curl", 3, 8, 20)})
+	if got := program.State.RepTargets["Curl"]; got != 9 {
+		tt.Fatalf("rep target = %v, want 9", got)
+	}
+	advance(&program, []wt.Workout{session(1, "Curl", 3, 9, 20), session(2, "Curl", 3, 10, 20)})
+	if got := program.State.Loads["Curl"]; got != 22.5 {
+		tt.Fatalf("load = %v, want 22.5", got)
+	}
+	if got := program.State.RepTargets["Curl"]; got != 8 {
+		tt.Fatalf("rep target = %v, want back to 8", got)
+	}
+}
+
+func TestAdvanceFiveThreeOne(tt *testing.T) {
+	press := t.ProgramExercise{Name: "Press", Increment: 2.5}
+
+	tests := []struct {
+		name        string
+		amrapReps   int
+		wantLoad    float64
+		wantWeek    int
+		wantMissed  bool
+		cycleLength int
+	}{
+		{"full cycle adds the increment", 5, 62.5, 5, false, 4},
+		{"a missed AMRAP holds the training max", 0, 60, 5, false, 4},
+		{"mid cycle keeps the training max", 5, 60, 3, false, 2},
+		{"mid cycle remembers a missed AMRAP", 0, 60, 3, true, 2},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			program := newProgram(c.ProgressionFiveThreeOne, press, 60)
+			workouts := []wt.Workout{}
+			for week := 0; week < test.cycleLength; week++ {
+				workouts = append(workouts, session(week, "Press", 3, test.amrapReps, 60))
+			}
+			advance(&program, workouts)
+
+			if got := program.State.Loads["Press"]; got != test.wantLoad {
+				tt.Fatalf("training max = %v, want %v", got, test.wantLoad)
+			}
+			if program.State.Week != test.wantWeek {
+				tt.Fatalf("week = %v, want %v", program.State.Week, test.wantWeek)
+			}
+			if program.State.MissedAMRAP["Press"] != test.wantMissed {
+				tt.Fatalf("missed AMRAP = %v, want %v", program.State.MissedAMRAP["Press"], test.wantMissed)
+			}
+		})
+	}
+}
+
+func TestPrescribeDeload(tt *testing.T) {
+	squat := t.ProgramExercise{Name: "Squat", Sets: 3, Reps: 5, Increment: 2.5}
+	program := newProgram(c.ProgressionLinear, squat, 100)
+	program.DeloadEvery = 4
+
+	if isDeloadWeek(program, 3) || !isDeloadWeek(program, 4) {
+		tt.Fatal("expected only week 4 to be a deload")
+	}
+	prescribed := prescribeExercise(program, squat, 4)
+	if len(prescribed.Sets) != 3 || prescribed.Sets[0].Weight != 60 {
+		tt.Fatalf("deload sets = %+v, want 3 at 60", prescribed.Sets)
+	}
+}
+
+func TestValidProgram(tt *testing.T) {
+	tests := []struct {
+		name     string
+		scheme   c.ProgressionScheme
+		exercise t.ProgramExercise
+		want     bool
+	}{
+		{"valid", c.ProgressionLinear, t.ProgramExercise{Name: "Squat", Sets: 3, Reps: 5}, true},
+		{"at the caps", c.ProgressionLinear, t.ProgramExercise{Name: "Squat", Sets: c.MaxSets, Reps: c.MaxReps}, true},
+		{"too many sets", c.ProgressionLinear, t.ProgramExercise{Name: "Squat", Sets: 1e9, Reps: 5}, false},
+		{"too many reps", c.ProgressionLinear, t.ProgramExercise{Name: "Squat", Sets: 3, Reps: c.MaxReps + 1}, false},
+		{"rep range over the cap", c.ProgressionDouble, t.ProgramExercise{Name: "Curl", Sets: 3, Reps: 8, MaxReps: c.MaxReps + 1}, false},
+		{"5/3/1 sets come from the waves", c.ProgressionFiveThreeOne, t.ProgramExercise{Name: "Squat"}, true},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			program := t.ProgramRequest{
+				Name:          "Strength",
+				Scheme:        test.scheme,
+				Days:          []t.ProgramDay{{Name: "A", Exercises: []t.ProgramExercise{test.exercise}}},
+				TrainingMaxes: map[string]float64{test.exercise.Name: 100},
+			}
+			if got := validProgram(program); got != test.want {
+				tt.Fatalf("validProgram = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCompletedSets(tt *testing.T) {
+	weight := 100.0
+	logged := wt.Exercise{Sets: []wt.ExerciseSet{{Reps: 5, Weight: &weight}, {Reps: 3, Weight: &weight}}}
+
+	tests := []struct {
+		name       string
+		prescribed []t.PrescribedSet
+		want       bool
+	}{
+		{"each set matched", []t.PrescribedSet{{Reps: 5, Weight: 100}, {Reps: 3, Weight: 100}}, true},
+		{"a logged set only counts once", []t.PrescribedSet{{Reps: 5, Weight: 100}, {Reps: 5, Weight: 100}}, false},
+		{"too heavy", []t.PrescribedSet{{Reps: 3, Weight: 102.5}}, false},
+		{"nothing prescribed", nil, true},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			if got := completedSets(logged, test.prescribed); got != test.want {
+				tt.Fatalf("completedSets = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
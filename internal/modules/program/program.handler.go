@@ -0,0 +1,212 @@
+package program
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	m "github.com/joshibbotson/gym-tracker-backend/internal/middleware"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/program/types"
+	util "github.com/joshibbotson/gym-tracker-backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ProgramHandler struct {
+	Service ProgramService
+}
+
+func NewProgramHandler(service ProgramService) *ProgramHandler {
+	return &ProgramHandler{
+		Service: service,
+	}
+}
+
+// Handler serves /program, /program/{id}, /program/next and /program/schedule/{id}
+func (h *ProgramHandler) Handler(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, "/program/schedule/") {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		m.PermissionMiddleware(h.handleReadProgramWeek)(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		m.PermissionMiddleware(h.handleCreateProgram)(w, r)
+	case http.MethodGet:
+		path := r.URL.Path
+
+		if path == "/program/next" {
+			m.PermissionMiddleware(h.handleReadNextSession)(w, r)
+			break
+		}
+		if r.PathValue("id") != "" {
+			m.PermissionMiddleware(h.handleReadProgram)(w, r)
+			break
+		}
+		m.PermissionMiddleware(h.handleReadPrograms)(w, r)
+	case http.MethodPatch:
+		m.PermissionMiddleware(h.handleUpdateProgram)(w, r)
+	case http.MethodDelete:
+		m.PermissionMiddleware(h.handleDeleteProgram)(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *ProgramHandler) handleCreateProgram(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	var request t.ProgramRequest
+	if !util.DecodeBody(w, r, &request) {
+		return
+	}
+
+	program, err := h.Service.CreateProgram(userID, request)
+	if errors.Is(err, ErrInvalidProgram) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error creating program", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusCreated, program)
+}
+
+func (h *ProgramHandler) handleReadPrograms(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	programs, err := h.Service.GetPrograms(userID)
+	if err != nil {
+		http.Error(w, "Error fetching programs", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, programs)
+}
+
+func (h *ProgramHandler) handleReadProgram(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+	programID, ok := util.ParseObjectID(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+
+	program, err := h.Service.GetProgramById(userID, programID)
+	if errors.Is(err, ErrProgramNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error fetching program", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, program)
+}
+
+func (h *ProgramHandler) handleUpdateProgram(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+	programID, ok := util.ParseObjectID(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+
+	var request t.ProgramRequest
+	if !util.DecodeBody(w, r, &request) {
+		return
+	}
+
+	program, err := h.Service.UpdateProgram(userID, programID, request)
+	if errors.Is(err, ErrInvalidProgram) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, ErrProgramNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error updating program", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, program)
+}
+
+func (h *ProgramHandler) handleDeleteProgram(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+	programID, ok := util.ParseObjectID(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+
+	success, err := h.Service.DeleteProgram(userID, programID)
+	if err != nil {
+		http.Error(w, "Error deleting program", http.StatusInternalServerError)
+		return
+	}
+	if !success {
+		http.Error(w, "Program not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"message": "Program deleted successfully"}`))
+}
+
+// handleReadProgramWeek takes the week as a query param and defaults to week 1
+func (h *ProgramHandler) handleReadProgramWeek(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+	programID, ok := util.ParseObjectID(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+
+	week := 1
+	if weekParam := r.URL.Query().Get("week"); weekParam != "" {
+		parsed, err := strconv.Atoi(weekParam)
+		if err != nil {
+			http.Error(w, "Invalid week", http.StatusBadRequest)
+			return
+		}
+		week = parsed
+	}
+
+	sessions, err := h.Service.GetProgramWeek(userID, programID, week)
+	if errors.Is(err, ErrProgramNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, ErrInvalidWeek) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error fetching program week", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, sessions)
+}
+
+func (h *ProgramHandler) handleReadNextSession(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	session, err := h.Service.GetNextSession(userID)
+	if errors.Is(err, ErrNoActiveProgram) || errors.Is(err, ErrProgramCompleted) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error fetching next session", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, session)
+}
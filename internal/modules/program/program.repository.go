@@ -0,0 +1,104 @@
+package program
+
+import (
+	"context"
+
+	db "github.com/joshibbotson/gym-tracker-backend/internal/db"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/program/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ProgramRepository interface {
+	InsertProgram(ctx context.Context, program t.Program) (*t.Program, error)
+	FetchPrograms(ctx context.Context, userID primitive.ObjectID) ([]t.Program, error)
+	FetchProgramById(ctx context.Context, userID primitive.ObjectID, programID primitive.ObjectID) (*t.Program, error)
+	FetchActiveProgram(ctx context.Context, userID primitive.ObjectID) (*t.Program, error)
+	UpdateProgram(ctx context.Context, program t.Program) (*t.Program, error)
+	DeactivatePrograms(ctx context.Context, userID primitive.ObjectID, except primitive.ObjectID) error
+	RemoveProgram(ctx context.Context, userID primitive.ObjectID, programID primitive.ObjectID) (bool, error)
+}
+
+type programRepository struct {
+	programCollection *mongo.Collection
+}
+
+func NewProgramRepository() ProgramRepository {
+	return &programRepository{
+		programCollection: db.Client.Database(db.DB_NAME).Collection("program"),
+	}
+}
+
+func (r *programRepository) InsertProgram(ctx context.Context, program t.Program) (*t.Program, error) {
+	_, err := r.programCollection.InsertOne(ctx, program)
+	if err != nil {
+		return nil, err
+	}
+	return &program, nil
+}
+
+func (r *programRepository) FetchPrograms(ctx context.Context, userID primitive.ObjectID) ([]t.Program, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := r.programCollection.Find(ctx, bson.M{"userId": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	programs := []t.Program{}
+	if err = cursor.All(ctx, &programs); err != nil {
+		return nil, err
+	}
+	return programs, nil
+}
+
+func (r *programRepository) FetchProgramById(ctx context.Context, userID primitive.ObjectID, programID primitive.ObjectID) (*t.Program, error) {
+	var program t.Program
+	err := r.programCollection.FindOne(ctx, bson.M{"_id": programID, "userId": userID}).Decode(&program)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &program, nil
+}
+
+func (r *programRepository) FetchActiveProgram(ctx context.Context, userID primitive.ObjectID) (*t.Program, error) {
+	var program t.Program
+	err := r.programCollection.FindOne(ctx, bson.M{"userId": userID, "active": true}).Decode(&program)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &program, nil
+}
+
+func (r *programRepository) UpdateProgram(ctx context.Context, program t.Program) (*t.Program, error) {
+	_, err := r.programCollection.UpdateOne(ctx, bson.M{"_id": program.ID, "userId": program.UserId}, bson.M{"$set": program})
+	if err != nil {
+		return nil, err
+	}
+	return &program, nil
+}
+
+// DeactivatePrograms makes sure only one program is being followed at a time.
+func (r *programRepository) DeactivatePrograms(ctx context.Context, userID primitive.ObjectID, except primitive.ObjectID) error {
+	_, err := r.programCollection.UpdateMany(ctx,
+		bson.M{"userId": userID, "_id": bson.M{"$ne": except}, "active": true},
+		bson.M{"$set": bson.M{"active": false}},
+	)
+	return err
+}
+
+func (r *programRepository) RemoveProgram(ctx context.Context, userID primitive.ObjectID, programID primitive.ObjectID) (bool, error) {
+	res, err := r.programCollection.DeleteOne(ctx, bson.M{"_id": programID, "userId": userID})
+	if err != nil {
+		return false, err
+	}
+	return res.DeletedCount > 0, nil
+}
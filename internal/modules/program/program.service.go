@@ -0,0 +1,249 @@
+package program
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/program/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/program/types"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/workout"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ProgramService interface {
+	CreateProgram(userID primitive.ObjectID, program t.ProgramRequest) (*t.Program, error)
+	GetPrograms(userID primitive.ObjectID) ([]t.Program, error)
+	GetProgramById(userID primitive.ObjectID, programID primitive.ObjectID) (*t.Program, error)
+	UpdateProgram(userID primitive.ObjectID, programID primitive.ObjectID, program t.ProgramRequest) (*t.Program, error)
+	DeleteProgram(userID primitive.ObjectID, programID primitive.ObjectID) (bool, error)
+	GetProgramWeek(userID primitive.ObjectID, programID primitive.ObjectID, week int) ([]t.PrescribedSession, error)
+	GetNextSession(userID primitive.ObjectID) (*t.PrescribedSession, error)
+}
+
+var (
+	ErrProgramNotFound  = errors.New("program not found")
+	ErrNoActiveProgram  = errors.New("no active program")
+	ErrProgramCompleted = errors.New("program completed")
+	ErrInvalidProgram   = fmt.Errorf("program needs a name, a valid scheme, at least one day and a training max for every exercise, with at most %d sets and %d reps", c.MaxSets, c.MaxReps)
+	ErrInvalidWeek      = errors.New("week is outside the program")
+)
+
+type programService struct {
+	repo           ProgramRepository
+	workoutService workout.WorkoutService
+}
+
+func NewProgramService(repo ProgramRepository, workoutService workout.WorkoutService) ProgramService {
+	return &programService{repo: repo, workoutService: workoutService}
+}
+
+func (s *programService) CreateProgram(userID primitive.ObjectID, program t.ProgramRequest) (*t.Program, error) {
+	if !validProgram(program) {
+		return nil, ErrInvalidProgram
+	}
+
+	startDate := program.StartDate
+	if startDate.IsZero() {
+		startDate = time.Now().UTC().Truncate(24 * time.Hour)
+	}
+
+	newProgram := t.Program{
+		ID:     primitive.NewObjectID(),
+		UserId: userID,
+		State: t.ProgramState{
+			Week:          1,
+			Day:           1,
+			Loads:         map[string]float64{},
+			LastWorkoutAt: startDate,
+		},
+		CreatedAt: time.Now(),
+	}
+	applyRequest(&newProgram, program)
+	newProgram.StartDate = startDate
+
+	created, err := s.repo.InsertProgram(context.TODO(), newProgram)
+	if err != nil {
+		return nil, err
+	}
+	if created.Active {
+		if err := s.repo.DeactivatePrograms(context.TODO(), userID, created.ID); err != nil {
+			return nil, err
+		}
+	}
+	return created, nil
+}
+
+func (s *programService) GetPrograms(userID primitive.ObjectID) ([]t.Program, error) {
+	return s.repo.FetchPrograms(context.TODO(), userID)
+}
+
+func (s *programService) GetProgramById(userID primitive.ObjectID, programID primitive.ObjectID) (*t.Program, error) {
+	program, err := s.repo.FetchProgramById(context.TODO(), userID, programID)
+	if err != nil {
+		return nil, err
+	}
+	if program == nil {
+		return nil, ErrProgramNotFound
+	}
+	return program, nil
+}
+
+// UpdateProgram replaces the program definition. Progress made so far is kept,
+// except for lifts whose training max was changed which restart from the new max.
+func (s *programService) UpdateProgram(userID primitive.ObjectID, programID primitive.ObjectID, program t.ProgramRequest) (*t.Program, error) {
+	if !validProgram(program) {
+		return nil, ErrInvalidProgram
+	}
+
+	existing, err := s.GetProgramById(userID, programID)
+	if err != nil {
+		return nil, err
+	}
+	if !program.StartDate.IsZero() {
+		existing.StartDate = program.StartDate
+	}
+	applyRequest(existing, program)
+	if existing.State.Day > len(existing.Days) {
+		existing.State.Day = 1
+	}
+
+	updated, err := s.repo.UpdateProgram(context.TODO(), *existing)
+	if err != nil {
+		return nil, err
+	}
+	if updated.Active {
+		if err := s.repo.DeactivatePrograms(context.TODO(), userID, updated.ID); err != nil {
+			return nil, err
+		}
+	}
+	return updated, nil
+}
+
+func (s *programService) DeleteProgram(userID primitive.ObjectID, programID primitive.ObjectID) (bool, error) {
+	return s.repo.RemoveProgram(context.TODO(), userID, programID)
+}
+
+// GetProgramWeek prescribes every day of a week using the loads progression has
+// reached so far, so weeks further out are a projection rather than a promise.
+func (s *programService) GetProgramWeek(userID primitive.ObjectID, programID primitive.ObjectID, week int) ([]t.PrescribedSession, error) {
+	program, err := s.GetProgramById(userID, programID)
+	if err != nil {
+		return nil, err
+	}
+	if week < 1 || (program.Weeks > 0 && week > program.Weeks) {
+		return nil, ErrInvalidWeek
+	}
+	if err := s.syncProgress(program); err != nil {
+		return nil, err
+	}
+
+	sessions := make([]t.PrescribedSession, 0, len(program.Days))
+	for day := 1; day <= len(program.Days); day++ {
+		sessions = append(sessions, prescribeSession(*program, week, day))
+	}
+	return sessions, nil
+}
+
+func (s *programService) GetNextSession(userID primitive.ObjectID) (*t.PrescribedSession, error) {
+	program, err := s.repo.FetchActiveProgram(context.TODO(), userID)
+	if err != nil {
+		return nil, err
+	}
+	if program == nil {
+		return nil, ErrNoActiveProgram
+	}
+	if err := s.syncProgress(program); err != nil {
+		return nil, err
+	}
+	if program.State.Completed {
+		return nil, ErrProgramCompleted
+	}
+
+	session := prescribeSession(*program, program.State.Week, program.State.Day)
+	return &session, nil
+}
+
+// syncProgress advances the program with any workouts logged since it was last
+// advanced and saves the new state.
+func (s *programService) syncProgress(program *t.Program) error {
+	if program.State.Completed {
+		return nil
+	}
+
+	workouts, err := s.workoutService.GetWorkoutsInRange(program.UserId, program.State.LastWorkoutAt, time.Now().Add(24*time.Hour))
+	if err != nil {
+		return err
+	}
+	if len(workouts) == 0 {
+		return nil
+	}
+
+	advance(program, workouts)
+	program.UpdatedAt = time.Now()
+	_, err = s.repo.UpdateProgram(context.TODO(), *program)
+	return err
+}
+
+func applyRequest(program *t.Program, request t.ProgramRequest) {
+	previousMaxes := program.TrainingMaxes
+
+	program.Name = strings.TrimSpace(request.Name)
+	program.Scheme = request.Scheme
+	program.Days = request.Days
+	program.Weeks = request.Weeks
+	program.DeloadEvery = request.DeloadEvery
+	program.DeloadFactor = request.DeloadFactor
+	if program.DeloadFactor <= 0 || program.DeloadFactor >= 1 {
+		program.DeloadFactor = c.DefaultDeloadFactor
+	}
+	program.RoundTo = request.RoundTo
+	if program.RoundTo <= 0 {
+		program.RoundTo = c.DefaultRoundTo
+	}
+	program.TrainingMaxes = request.TrainingMaxes
+	program.Active = request.Active
+	program.UpdatedAt = time.Now()
+
+	loads := map[string]float64{}
+	for name := range liftIncrements(program) {
+		current, ok := program.State.Loads[name]
+		if !ok || previousMaxes[name] != request.TrainingMaxes[name] {
+			current = request.TrainingMaxes[name]
+		}
+		loads[name] = current
+	}
+	program.State.Loads = loads
+}
+
+func validProgram(program t.ProgramRequest) bool {
+	if strings.TrimSpace(program.Name) == "" || !program.Scheme.Valid() || len(program.Days) == 0 {
+		return false
+	}
+	if program.Weeks < 0 || program.DeloadEvery < 0 {
+		return false
+	}
+
+	for _, day := range program.Days {
+		if len(day.Exercises) == 0 {
+			return false
+		}
+		for _, exercise := range day.Exercises {
+			if strings.TrimSpace(exercise.Name) == "" || program.TrainingMaxes[exercise.Name] <= 0 || exercise.Increment < 0 {
+				return false
+			}
+			if program.Scheme == c.ProgressionFiveThreeOne {
+				continue
+			}
+			if exercise.Sets <= 0 || exercise.Sets > c.MaxSets || exercise.Reps <= 0 || exercise.Reps > c.MaxReps {
+				return false
+			}
+			if program.Scheme == c.ProgressionDouble && (exercise.MaxReps < exercise.Reps || exercise.MaxReps > c.MaxReps) {
+				return false
+			}
+		}
+	}
+	return true
+}
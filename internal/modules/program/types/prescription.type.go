@@ -0,0 +1,24 @@
+package types
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+type PrescribedSet struct {
+	Reps    int     `json:"reps"`
+	Weight  float64 `json:"weight"`
+	Percent float64 `json:"percent,omitempty"`
+	AMRAP   bool    `json:"amrap,omitempty"`
+}
+
+type PrescribedExercise struct {
+	Name string          `json:"name"`
+	Sets []PrescribedSet `json:"sets"`
+}
+
+type PrescribedSession struct {
+	ProgramID primitive.ObjectID   `json:"programId"`
+	Week      int                  `json:"week"`
+	Day       int                  `json:"day"`
+	DayName   string               `json:"dayName"`
+	Deload    bool                 `json:"deload"`
+	Exercises []PrescribedExercise `json:"exercises"`
+}
@@ -0,0 +1,69 @@
+package types
+
+import (
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/program/constants"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ProgramExercise is a lift within a program day. Sets and Reps are ignored by
+// 5/3/1, which uses the wave for the week. For double progression Reps is the
+// bottom of the rep range and MaxReps the top.
+type ProgramExercise struct {
+	Name      string  `bson:"name" json:"name"`
+	Sets      int     `bson:"sets" json:"sets"`
+	Reps      int     `bson:"reps" json:"reps"`
+	MaxReps   int     `bson:"maxReps,omitempty" json:"maxReps,omitempty"`
+	Increment float64 `bson:"increment" json:"increment"`
+}
+
+type ProgramDay struct {
+	Name      string            `bson:"name" json:"name"`
+	Exercises []ProgramExercise `bson:"exercises" json:"exercises"`
+}
+
+// ProgramState tracks where the lifter is in the program and the loads that
+// progression has arrived at, it is advanced from logged workouts.
+type ProgramState struct {
+	Week           int                  `bson:"week" json:"week"`
+	Day            int                  `bson:"day" json:"day"`
+	Loads          map[string]float64   `bson:"loads" json:"loads"`
+	RepTargets     map[string]int       `bson:"repTargets,omitempty" json:"repTargets,omitempty"`
+	Failures       map[string]int       `bson:"failures,omitempty" json:"failures,omitempty"`
+	MissedAMRAP    map[string]bool      `bson:"missedAmrap,omitempty" json:"missedAmrap,omitempty"`
+	LastWorkoutAt  time.Time            `bson:"lastWorkoutAt" json:"lastWorkoutAt"`
+	LastWorkoutIDs []primitive.ObjectID `bson:"lastWorkoutIds,omitempty" json:"-"`
+	Completed      bool                 `bson:"completed" json:"completed"`
+}
+
+type Program struct {
+	ID            primitive.ObjectID  `bson:"_id" json:"_id"`
+	UserId        primitive.ObjectID  `bson:"userId" json:"-"`
+	Name          string              `bson:"name" json:"name"`
+	Scheme        c.ProgressionScheme `bson:"scheme" json:"scheme"`
+	Days          []ProgramDay        `bson:"days" json:"days"`
+	Weeks         int                 `bson:"weeks,omitempty" json:"weeks,omitempty"`
+	DeloadEvery   int                 `bson:"deloadEvery,omitempty" json:"deloadEvery,omitempty"`
+	DeloadFactor  float64             `bson:"deloadFactor" json:"deloadFactor"`
+	RoundTo       float64             `bson:"roundTo" json:"roundTo"`
+	TrainingMaxes map[string]float64  `bson:"trainingMaxes" json:"trainingMaxes"`
+	StartDate     time.Time           `bson:"startDate" json:"startDate"`
+	Active        bool                `bson:"active" json:"active"`
+	State         ProgramState        `bson:"state" json:"state"`
+	CreatedAt     time.Time           `bson:"createdAt" json:"createdAt"`
+	UpdatedAt     time.Time           `bson:"updatedAt" json:"updatedAt"`
+}
+
+type ProgramRequest struct {
+	Name          string              `json:"name"`
+	Scheme        c.ProgressionScheme `json:"scheme"`
+	Days          []ProgramDay        `json:"days"`
+	Weeks         int                 `json:"weeks,omitempty"`
+	DeloadEvery   int                 `json:"deloadEvery,omitempty"`
+	DeloadFactor  float64             `json:"deloadFactor,omitempty"`
+	RoundTo       float64             `json:"roundTo,omitempty"`
+	TrainingMaxes map[string]float64  `json:"trainingMaxes"`
+	StartDate     time.Time           `json:"startDate"`
+	Active        bool                `json:"active"`
+}
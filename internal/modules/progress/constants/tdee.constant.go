@@ -0,0 +1,43 @@
+package constants
+
+import wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+
+// Energy stored in a unit of body weight change, roughly the same whether
+// it's gained or lost.
+const (
+	EnergyPerKg = 7700.0
+	EnergyPerLb = 3500.0
+)
+
+const (
+	DefaultTDEEWeeks = 8
+	MaxTDEEWeeks     = 52
+	// TDEEWindowWeeks is how many of the latest complete weeks the estimate averages.
+	TDEEWindowWeeks = 4
+	// MinIntakeDays a week needs logged before it is used for an estimate.
+	MinIntakeDays = 4
+
+	// The confidence band is never narrower than MinBand, and is DefaultBand
+	// either side when only one week could be used.
+	MinBand     = 100.0
+	DefaultBand = 300.0
+
+	// RoundCaloriesTo rounds the recommended calorie target.
+	RoundCaloriesTo = 10.0
+)
+
+// DefaultPhaseRatePercent is the weekly rate of change, as a percent of body
+// weight, recommended for a phase without its own target rate.
+var DefaultPhaseRatePercent = map[wc.CaloriePhase]float64{
+	wc.CaloriePhaseCut:      -0.5,
+	wc.CaloriePhaseBulk:     0.25,
+	wc.CaloriePhaseMaintain: 0,
+}
+
+type Confidence string
+
+const (
+	ConfidenceLow    Confidence = "low"
+	ConfidenceMedium Confidence = "medium"
+	ConfidenceHigh   Confidence = "high"
+)
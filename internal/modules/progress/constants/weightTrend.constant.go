@@ -0,0 +1,27 @@
+package constants
+
+const (
+	// DefaultSmoothing is the share of each day's weigh-in that moves the trend,
+	// the classic 10% from exponentially smoothed weight tracking.
+	DefaultSmoothing = 0.1
+	// WarmupDays of weigh-ins before the range are used to settle the trend.
+	WarmupDays = 60
+	// RateWindowDays is how far back the weekly rate of change is fitted over.
+	RateWindowDays = 28
+
+	// Weekly change, as a percent of body weight, below which weight is treated as stable.
+	StableRatePercent = 0.2
+	// TargetRateTolerance is how far, as a share of the phase's target rate, the
+	// actual rate can be off before it is flagged.
+	TargetRateTolerance = 0.5
+	// MaxProjectionDays stops the goal projection giving dates years away.
+	MaxProjectionDays = 3 * 365
+)
+
+type TrendDirection string
+
+const (
+	TrendLosing  TrendDirection = "losing"
+	TrendGaining TrendDirection = "gaining"
+	TrendStable  TrendDirection = "stable"
+)
@@ -0,0 +1,98 @@
+package progress
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	m "github.com/joshibbotson/gym-tracker-backend/internal/middleware"
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/progress/constants"
+	wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+	util "github.com/joshibbotson/gym-tracker-backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ProgressHandler struct {
+	Service ProgressService
+}
+
+func NewProgressHandler(service ProgressService) *ProgressHandler {
+	return &ProgressHandler{
+		Service: service,
+	}
+}
+
+// Handler serves /progress/weight and /progress/tdee
+func (h *ProgressHandler) Handler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch r.URL.Path {
+	case "/progress/weight":
+		m.PermissionMiddleware(h.handleReadWeightTrend)(w, r)
+	case "/progress/tdee":
+		m.PermissionMiddleware(h.handleReadTDEE)(w, r)
+	default:
+		http.Error(w, "Not found", http.StatusNotFound)
+	}
+}
+
+// handleReadWeightTrend takes from and to (inclusive) dates, defaulting to the
+// last 90 days, and an optional smoothing factor.
+func (h *ProgressHandler) handleReadWeightTrend(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	from, to, ok := util.ParseDateRange(w, r, time.Now().AddDate(0, 0, -90))
+	if !ok {
+		return
+	}
+
+	smoothing := 0.0
+	if param := r.URL.Query().Get("smoothing"); param != "" {
+		parsed, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			http.Error(w, "Invalid smoothing", http.StatusBadRequest)
+			return
+		}
+		smoothing = parsed
+	}
+
+	trend, err := h.Service.GetWeightTrend(userID, wc.AudienceOwner, from, to, smoothing)
+	if errors.Is(err, ErrInvalidSmoothing) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error fetching weight trend", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, trend)
+}
+
+// handleReadTDEE takes an optional weeks query param for how many weeks of
+// history to return.
+func (h *ProgressHandler) handleReadTDEE(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	weeks := c.DefaultTDEEWeeks
+	if param := r.URL.Query().Get("weeks"); param != "" {
+		parsed, err := strconv.Atoi(param)
+		if err != nil || parsed < 1 || parsed > c.MaxTDEEWeeks {
+			http.Error(w, "Invalid weeks", http.StatusBadRequest)
+			return
+		}
+		weeks = parsed
+	}
+
+	estimate, err := h.Service.GetTDEE(userID, weeks)
+	if err != nil {
+		http.Error(w, "Error estimating TDEE", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, estimate)
+}
@@ -0,0 +1,244 @@
+package progress
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/intake"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/phase"
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/progress/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/progress/types"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/user"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/workout"
+	wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+	wt "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ProgressService interface {
+	GetWeightTrend(userID primitive.ObjectID, audience wc.Audience, from time.Time, to time.Time, smoothing float64) (*t.WeightTrend, error)
+	GetTDEE(userID primitive.ObjectID, weeks int) (*t.TDEEEstimate, error)
+}
+
+var ErrInvalidSmoothing = errors.New("smoothing must be greater than 0 and at most 1")
+
+type progressService struct {
+	workoutService workout.WorkoutService
+	userService    user.UserService
+	phaseService   phase.PhaseService
+	intakeService  intake.IntakeService
+}
+
+func NewProgressService(workoutService workout.WorkoutService, userService user.UserService, phaseService phase.PhaseService, intakeService intake.IntakeService) ProgressService {
+	return &progressService{
+		workoutService: workoutService,
+		userService:    userService,
+		phaseService:   phaseService,
+		intakeService:  intakeService,
+	}
+}
+
+// GetWeightTrend smooths the logged body weights from <= date < to, fits the
+// weekly rate of change to the end of the trend and projects when the user's
+// goal weight will be reached at that rate. Only the weights logged on
+// workouts audience can see are used.
+func (s *progressService) GetWeightTrend(userID primitive.ObjectID, audience wc.Audience, from time.Time, to time.Time, smoothing float64) (*t.WeightTrend, error) {
+	if smoothing == 0 {
+		smoothing = c.DefaultSmoothing
+	}
+	// NaN fails every comparison so it has to be checked on its own
+	if math.IsNaN(smoothing) || smoothing < 0 || smoothing > 1 {
+		return nil, ErrInvalidSmoothing
+	}
+
+	entries, err := s.workoutService.GetBodyWeights(userID, audience, from.AddDate(0, 0, -c.WarmupDays), to)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := s.userService.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	trend := &t.WeightTrend{
+		From:       from,
+		To:         to,
+		Smoothing:  smoothing,
+		Points:     []t.TrendPoint{},
+		GoalWeight: settings.GoalWeight,
+		Warnings:   []string{},
+	}
+	for _, point := range smoothWeights(entries, smoothing) {
+		if !point.Date.Before(from) {
+			trend.Points = append(trend.Points, point)
+		}
+	}
+	for _, entry := range entries {
+		if entry.CaloriePhase != nil && !entry.Date.Before(from) {
+			trend.CaloriePhase = entry.CaloriePhase
+		}
+	}
+	if len(trend.Points) == 0 {
+		return trend, nil
+	}
+
+	last := trend.Points[len(trend.Points)-1]
+	current := last.Trend
+	trend.CurrentTrend = &current
+
+	// a phase the user set up takes precedence over the caloriePhase on workouts
+	activePhase, err := s.phaseService.GetPhaseOn(userID, last.Date)
+	if err != nil {
+		return nil, err
+	}
+	if activePhase != nil {
+		trend.CaloriePhase = &activePhase.Type
+		trend.TargetWeeklyRate = activePhase.TargetWeeklyRate
+	}
+
+	rate, ok := weeklyRate(trend.Points)
+	if !ok {
+		trend.Warnings = append(trend.Warnings, fmt.Sprintf("need weigh-ins on at least two days in the last %d days to measure a rate", c.RateWindowDays))
+		return trend, nil
+	}
+	ratePercent := round(rate / current * 100)
+	rate = round(rate)
+	trend.WeeklyRate = &rate
+	trend.WeeklyRatePercent = &ratePercent
+	trend.Direction = direction(ratePercent)
+
+	if trend.GoalWeight != nil {
+		trend.GoalReached, trend.ProjectedGoalDate = projectGoal(last, rate, *trend.GoalWeight)
+		if !trend.GoalReached && trend.ProjectedGoalDate == nil {
+			trend.Warnings = append(trend.Warnings, fmt.Sprintf("trend is %s, it won't reach the goal of %.1f at this rate", trend.Direction, *trend.GoalWeight))
+		}
+	}
+	if trend.CaloriePhase != nil {
+		if warning := phaseWarning(*trend.CaloriePhase, trend.Direction, trend.GoalWeight, current); warning != "" {
+			trend.Warnings = append(trend.Warnings, warning)
+		}
+	}
+	if trend.TargetWeeklyRate != nil && math.Abs(rate-*trend.TargetWeeklyRate) > math.Max(math.Abs(*trend.TargetWeeklyRate)*c.TargetRateTolerance, current*c.StableRatePercent/100) {
+		trend.Warnings = append(trend.Warnings, fmt.Sprintf("changing %.2f a week against the phase's target of %.2f", rate, *trend.TargetWeeklyRate))
+	}
+
+	return trend, nil
+}
+
+// smoothWeights averages weigh-ins per day and runs an exponential moving
+// average over them. Gaps between weigh-ins count as that many days of
+// smoothing so a missed week doesn't move the trend like a single day.
+func smoothWeights(entries []wt.BodyWeightEntry, smoothing float64) []t.TrendPoint {
+	points := []t.TrendPoint{}
+	counts := []int{}
+	for _, entry := range entries {
+		day := time.Date(entry.Date.Year(), entry.Date.Month(), entry.Date.Day(), 0, 0, 0, 0, time.UTC)
+		if n := len(points); n > 0 && points[n-1].Date.Equal(day) {
+			points[n-1].Weight += entry.Weight
+			counts[n-1]++
+			continue
+		}
+		points = append(points, t.TrendPoint{Date: day, Weight: entry.Weight})
+		counts = append(counts, 1)
+	}
+
+	for i := range points {
+		points[i].Weight /= float64(counts[i])
+		if i == 0 {
+			points[i].Trend = points[i].Weight
+			continue
+		}
+		days := points[i].Date.Sub(points[i-1].Date).Hours() / 24
+		alpha := 1 - math.Pow(1-smoothing, days)
+		points[i].Trend = points[i-1].Trend + alpha*(points[i].Weight-points[i-1].Trend)
+	}
+
+	for i := range points {
+		points[i].Weight = round(points[i].Weight)
+		points[i].Trend = round(points[i].Trend)
+	}
+	return points
+}
+
+// weeklyRate fits a least squares line to the trend over the last
+// RateWindowDays and returns its slope per week.
+func weeklyRate(points []t.TrendPoint) (float64, bool) {
+	last := points[len(points)-1].Date
+	windowStart := last.AddDate(0, 0, -c.RateWindowDays)
+
+	var n, sumX, sumY, sumXY, sumXX float64
+	for _, point := range points {
+		if point.Date.Before(windowStart) {
+			continue
+		}
+		x := point.Date.Sub(windowStart).Hours() / 24
+		n++
+		sumX += x
+		sumY += point.Trend
+		sumXY += x * point.Trend
+		sumXX += x * x
+	}
+
+	denominator := n*sumXX - sumX*sumX
+	if n < 2 || denominator == 0 {
+		return 0, false
+	}
+	return (n*sumXY - sumX*sumY) / denominator * 7, true
+}
+
+func direction(ratePercent float64) c.TrendDirection {
+	switch {
+	case ratePercent <= -c.StableRatePercent:
+		return c.TrendLosing
+	case ratePercent >= c.StableRatePercent:
+		return c.TrendGaining
+	default:
+		return c.TrendStable
+	}
+}
+
+// projectGoal returns whether the trend has already reached goal, or else the
+// date it will at rate, nil if it's moving away or would take too long.
+func projectGoal(last t.TrendPoint, rate float64, goal float64) (bool, *time.Time) {
+	remaining := goal - last.Trend
+	if math.Abs(remaining) < 0.05 {
+		return true, nil
+	}
+	if rate == 0 || math.Signbit(rate) != math.Signbit(remaining) {
+		return false, nil
+	}
+
+	days := remaining / rate * 7
+	if days > c.MaxProjectionDays {
+		return false, nil
+	}
+	projected := last.Date.Add(time.Duration(days * 24 * float64(time.Hour))).Truncate(24 * time.Hour)
+	return false, &projected
+}
+
+// phaseWarning checks the trend is heading the way the calorie phase
+// expects, and that the goal weight lies in that direction too.
+func phaseWarning(phase wc.CaloriePhase, trend c.TrendDirection, goal *float64, current float64) string {
+	expected := map[wc.CaloriePhase]c.TrendDirection{
+		wc.CaloriePhaseCut:      c.TrendLosing,
+		wc.CaloriePhaseBulk:     c.TrendGaining,
+		wc.CaloriePhaseMaintain: c.TrendStable,
+	}[phase]
+	if expected == "" {
+		return ""
+	}
+
+	if trend != expected {
+		return fmt.Sprintf("you're in a %s phase but your weight trend is %s", phase, trend)
+	}
+	if goal != nil && ((phase == wc.CaloriePhaseCut && *goal > current) || (phase == wc.CaloriePhaseBulk && *goal < current)) {
+		return fmt.Sprintf("your goal weight of %.1f is the wrong way for a %s phase", *goal, phase)
+	}
+	return ""
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,55 @@
+package progress
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+	wt "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func weighIn(day int, hour int, weight float64) wt.BodyWeightEntry {
+	return wt.BodyWeightEntry{Date: time.Date(2026, 3, day, hour, 0, 0, 0, time.UTC), Weight: weight}
+}
+
+func TestGetWeightTrendRejectsSmoothing(tt *testing.T) {
+	service := &progressService{}
+	for _, smoothing := range []float64{-0.1, 1.1, math.NaN(), math.Inf(1), math.Inf(-1)} {
+		_, err := service.GetWeightTrend(primitive.NewObjectID(), wc.AudienceOwner, time.Now(), time.Now(), smoothing)
+		if !errors.Is(err, ErrInvalidSmoothing) {
+			tt.Fatalf("smoothing %v: got %v, want ErrInvalidSmoothing", smoothing, err)
+		}
+	}
+}
+
+func TestSmoothWeights(tt *testing.T) {
+	tests := []struct {
+		name      string
+		entries   []wt.BodyWeightEntry
+		smoothing float64
+		want      []float64
+	}{
+		{"first weigh-in starts the trend", []wt.BodyWeightEntry{weighIn(1, 7, 80)}, 0.1, []float64{80}},
+		{"each day moves the trend by the smoothing", []wt.BodyWeightEntry{weighIn(1, 7, 80), weighIn(2, 7, 81), weighIn(3, 7, 81)}, 0.1, []float64{80, 80.1, 80.19}},
+		{"weigh-ins on one day are averaged", []wt.BodyWeightEntry{weighIn(1, 7, 80), weighIn(2, 7, 80), weighIn(2, 19, 82)}, 0.1, []float64{80, 80.1}},
+		{"a gap counts as that many days", []wt.BodyWeightEntry{weighIn(1, 7, 80), weighIn(3, 7, 81)}, 0.1, []float64{80, 80.19}},
+		{"full smoothing follows the scale", []wt.BodyWeightEntry{weighIn(1, 7, 80), weighIn(2, 7, 82)}, 1, []float64{80, 82}},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			points := smoothWeights(test.entries, test.smoothing)
+			if len(points) != len(test.want) {
+				tt.Fatalf("got %d points, want %d", len(points), len(test.want))
+			}
+			for i, point := range points {
+				if point.Trend != test.want[i] {
+					tt.Fatalf("point %d trend = %v, want %v", i, point.Trend, test.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package progress
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	it "github.com/joshibbotson/gym-tracker-backend/internal/modules/intake/types"
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/progress/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/progress/types"
+	uc "github.com/joshibbotson/gym-tracker-backend/internal/modules/user/constants"
+	wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+	util "github.com/joshibbotson/gym-tracker-backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetTDEE estimates daily energy expenditure week by week as average intake
+// minus the energy stored or released by the change in trend weight, then
+// recommends a calorie target for the current phase.
+func (s *progressService) GetTDEE(userID primitive.ObjectID, weeks int) (*t.TDEEEstimate, error) {
+	settings, err := s.userService.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	location, err := s.userService.GetLocation(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	today := util.CalendarDay(time.Now(), location)
+	offset := (int(today.Weekday()) - int(settings.WeekStart.Weekday()) + 7) % 7
+	thisWeek := today.AddDate(0, 0, -offset)
+	from := thisWeek.AddDate(0, 0, -7*(weeks-1))
+
+	entries, err := s.workoutService.GetBodyWeights(userID, wc.AudienceOwner, from.AddDate(0, 0, -c.WarmupDays), today.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	intake, err := s.intakeService.GetIntakeInRange(userID, from, today.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	energyPerUnit := c.EnergyPerKg
+	if settings.WeightUnit == uc.WeightUnitLb {
+		energyPerUnit = c.EnergyPerLb
+	}
+
+	points := smoothWeights(entries, c.DefaultSmoothing)
+	estimate := &t.TDEEEstimate{
+		WeightUnit: settings.WeightUnit,
+		Weeks:      weeklyEnergy(points, intake, from, weeks, today, energyPerUnit),
+		Confidence: c.ConfidenceLow,
+		Warnings:   []string{},
+	}
+
+	usable := []float64{}
+	for i := len(estimate.Weeks) - 1; i >= 0 && len(usable) < c.TDEEWindowWeeks; i-- {
+		if estimate.Weeks[i].Estimate != nil {
+			usable = append(usable, *estimate.Weeks[i].Estimate)
+		}
+	}
+	estimate.WeeksUsed = len(usable)
+	if len(usable) == 0 {
+		estimate.Warnings = append(estimate.Warnings, fmt.Sprintf("need a complete week with intake logged on at least %d days and weigh-ins either side of it", c.MinIntakeDays))
+		return estimate, nil
+	}
+
+	mean, band := meanAndBand(usable)
+	low, high := math.Round(mean-band), math.Round(mean+band)
+	mean = math.Round(mean)
+	estimate.Estimate, estimate.Low, estimate.High = &mean, &low, &high
+	switch {
+	case len(usable) >= c.TDEEWindowWeeks && band <= 1.5*c.MinBand:
+		estimate.Confidence = c.ConfidenceHigh
+	case len(usable) >= 2:
+		estimate.Confidence = c.ConfidenceMedium
+	}
+
+	if err := s.recommendCalories(userID, estimate, points, today, energyPerUnit); err != nil {
+		return nil, err
+	}
+	return estimate, nil
+}
+
+// recommendCalories adds the calorie target that would move weight at the
+// current phase's rate, or its default rate when the phase doesn't set one.
+func (s *progressService) recommendCalories(userID primitive.ObjectID, estimate *t.TDEEEstimate, points []t.TrendPoint, today time.Time, energyPerUnit float64) error {
+	activePhase, err := s.phaseService.GetPhaseOn(userID, today)
+	if err != nil {
+		return err
+	}
+	if activePhase == nil {
+		estimate.Warnings = append(estimate.Warnings, "no calorie phase is running, set one up to get a calorie target")
+		return nil
+	}
+	estimate.CaloriePhase = &activePhase.Type
+	estimate.PhaseCalorieTarget = activePhase.CalorieTarget
+
+	rate := activePhase.TargetWeeklyRate
+	if rate == nil && len(points) > 0 {
+		defaultRate := round(points[len(points)-1].Trend * c.DefaultPhaseRatePercent[activePhase.Type] / 100)
+		rate = &defaultRate
+	}
+	if rate == nil {
+		return nil
+	}
+	estimate.TargetWeeklyRate = rate
+
+	recommended := math.Round((*estimate.Estimate+*rate*energyPerUnit/7)/c.RoundCaloriesTo) * c.RoundCaloriesTo
+	estimate.RecommendedCalories = &recommended
+	return nil
+}
+
+// weeklyEnergy builds count weeks starting at from. The week holding today is
+// returned but not estimated until it's over.
+func weeklyEnergy(points []t.TrendPoint, intake []it.IntakeEntry, from time.Time, count int, today time.Time, energyPerUnit float64) []t.WeeklyEnergy {
+	weeks := make([]t.WeeklyEnergy, 0, count)
+	for i := 0; i < count; i++ {
+		start := from.AddDate(0, 0, 7*i)
+		end := start.AddDate(0, 0, 7)
+		week := t.WeeklyEnergy{WeekStart: start, Complete: !end.After(today)}
+
+		total := 0.0
+		for _, entry := range intake {
+			if !entry.Date.Before(start) && entry.Date.Before(end) {
+				total += entry.Calories
+				week.IntakeDays++
+			}
+		}
+		if week.IntakeDays > 0 {
+			average := math.Round(total / float64(week.IntakeDays))
+			week.AverageIntake = &average
+		}
+
+		startTrend, okStart := trendAt(points, start)
+		endTrend, okEnd := trendAt(points, end)
+		if okStart && okEnd {
+			change := round(endTrend - startTrend)
+			week.TrendChange = &change
+		}
+
+		if week.Complete && week.IntakeDays >= c.MinIntakeDays && week.TrendChange != nil {
+			tdee := math.Round(*week.AverageIntake - *week.TrendChange*energyPerUnit/7)
+			week.Estimate = &tdee
+		}
+		weeks = append(weeks, week)
+	}
+	return weeks
+}
+
+// trendAt interpolates the trend weight on date between the weigh-ins either
+// side of it, it isn't known before the first or after the last weigh-in.
+func trendAt(points []t.TrendPoint, date time.Time) (float64, bool) {
+	for i := range points {
+		if points[i].Date.Equal(date) {
+			return points[i].Trend, true
+		}
+		if points[i].Date.After(date) {
+			if i == 0 {
+				return 0, false
+			}
+			before, after := points[i-1], points[i]
+			share := date.Sub(before.Date).Hours() / after.Date.Sub(before.Date).Hours()
+			return before.Trend + share*(after.Trend-before.Trend), true
+		}
+	}
+	return 0, false
+}
+
+// meanAndBand returns the mean of the weekly estimates and the half width of
+// a ~95% confidence interval around it.
+func meanAndBand(values []float64) (float64, float64) {
+	mean := 0.0
+	for _, value := range values {
+		mean += value
+	}
+	mean /= float64(len(values))
+	if len(values) < 2 {
+		return mean, c.DefaultBand
+	}
+
+	variance := 0.0
+	for _, value := range values {
+		variance += (value - mean) * (value - mean)
+	}
+	variance /= float64(len(values) - 1)
+	return mean, math.Max(c.MinBand, 1.96*math.Sqrt(variance/float64(len(values))))
+}
@@ -0,0 +1,109 @@
+package progress
+
+import (
+	"testing"
+	"time"
+
+	it "github.com/joshibbotson/gym-tracker-backend/internal/modules/intake/types"
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/progress/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/progress/types"
+)
+
+func march(day int) time.Time {
+	return time.Date(2026, 3, day, 0, 0, 0, 0, time.UTC)
+}
+
+func ate(calories float64, days ...int) []it.IntakeEntry {
+	entries := []it.IntakeEntry{}
+	for _, day := range days {
+		entries = append(entries, it.IntakeEntry{Date: march(day), Calories: calories})
+	}
+	return entries
+}
+
+func TestWeeklyEnergy(tt *testing.T) {
+	// 0.5kg lost over the week starting Monday the 2nd
+	losing := []t.TrendPoint{{Date: march(2), Trend: 80}, {Date: march(9), Trend: 79.5}}
+
+	tests := []struct {
+		name   string
+		points []t.TrendPoint
+		intake []it.IntakeEntry
+		today  time.Time
+		want   *float64
+	}{
+		{"intake plus the energy released", losing, ate(2500, 2, 3, 4, 5, 6), march(10), ptr(3050)},
+		{"too few days logged", losing, ate(2500, 2, 3, 4), march(10), nil},
+		{"week not over", losing, ate(2500, 2, 3, 4, 5, 6), march(8), nil},
+		{"no weigh-in after the week", losing[:1], ate(2500, 2, 3, 4, 5, 6), march(10), nil},
+		{"intake outside the week is ignored", losing, append(ate(2500, 2, 3, 4, 5), ate(9000, 1, 9)...), march(10), ptr(3050)},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			weeks := weeklyEnergy(test.points, test.intake, march(2), 1, test.today, c.EnergyPerKg)
+			got := weeks[0].Estimate
+			if (got == nil) != (test.want == nil) || (got != nil && *got != *test.want) {
+				tt.Fatalf("estimate = %v, want %v", deref(got), deref(test.want))
+			}
+		})
+	}
+}
+
+func TestTrendAt(tt *testing.T) {
+	points := []t.TrendPoint{{Date: march(2), Trend: 80}, {Date: march(6), Trend: 79}}
+
+	tests := []struct {
+		name  string
+		date  time.Time
+		want  float64
+		known bool
+	}{
+		{"on a weigh-in", march(2), 80, true},
+		{"between weigh-ins", march(4), 79.5, true},
+		{"before the first", march(1), 0, false},
+		{"after the last", march(7), 0, false},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			got, known := trendAt(points, test.date)
+			if got != test.want || known != test.known {
+				tt.Fatalf("got %v (%v), want %v (%v)", got, known, test.want, test.known)
+			}
+		})
+	}
+}
+
+func TestMeanAndBand(tt *testing.T) {
+	tests := []struct {
+		name     string
+		values   []float64
+		wantMean float64
+		wantBand float64
+	}{
+		{"one week uses the default band", []float64{2500}, 2500, c.DefaultBand},
+		{"steady weeks never go below the minimum band", []float64{2500, 2500, 2500, 2500}, 2500, c.MinBand},
+		{"noisy weeks widen it", []float64{2000, 3000, 2000, 3000}, 2500, 1.96 * 577.3502691896257 / 2},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			mean, band := meanAndBand(test.values)
+			if mean != test.wantMean || round(band) != round(test.wantBand) {
+				tt.Fatalf("got %v ± %v, want %v ± %v", mean, band, test.wantMean, test.wantBand)
+			}
+		})
+	}
+}
+
+func ptr(value float64) *float64 {
+	return &value
+}
+
+func deref(value *float64) interface{} {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
@@ -0,0 +1,39 @@
+package types
+
+import (
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/progress/constants"
+	uc "github.com/joshibbotson/gym-tracker-backend/internal/modules/user/constants"
+	wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+)
+
+// WeeklyEnergy balances a week's average intake against the change in trend
+// weight over it. Estimate is only set for complete weeks with enough data.
+type WeeklyEnergy struct {
+	WeekStart     time.Time `json:"weekStart"`
+	Complete      bool      `json:"complete"`
+	IntakeDays    int       `json:"intakeDays"`
+	AverageIntake *float64  `json:"averageIntake,omitempty"`
+	TrendChange   *float64  `json:"trendChange,omitempty"`
+	Estimate      *float64  `json:"estimate,omitempty"`
+}
+
+type TDEEEstimate struct {
+	WeightUnit uc.WeightUnit  `json:"weightUnit"`
+	Weeks      []WeeklyEnergy `json:"weeks"`
+
+	// Estimate averages the latest complete weeks, Low and High bound it at
+	// roughly 95% confidence.
+	Estimate   *float64     `json:"estimate,omitempty"`
+	Low        *float64     `json:"low,omitempty"`
+	High       *float64     `json:"high,omitempty"`
+	WeeksUsed  int          `json:"weeksUsed"`
+	Confidence c.Confidence `json:"confidence"`
+
+	CaloriePhase        *wc.CaloriePhase `json:"caloriePhase,omitempty"`
+	TargetWeeklyRate    *float64         `json:"targetWeeklyRate,omitempty"`
+	RecommendedCalories *float64         `json:"recommendedCalories,omitempty"`
+	PhaseCalorieTarget  *int             `json:"phaseCalorieTarget,omitempty"`
+	Warnings            []string         `json:"warnings"`
+}
@@ -0,0 +1,37 @@
+package types
+
+import (
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/progress/constants"
+	wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+)
+
+// TrendPoint is one day with a weigh-in, Weight is the day's average scale
+// weight and Trend the smoothed weight up to and including it.
+type TrendPoint struct {
+	Date   time.Time `json:"date"`
+	Weight float64   `json:"weight"`
+	Trend  float64   `json:"trend"`
+}
+
+type WeightTrend struct {
+	From      time.Time    `json:"from"`
+	To        time.Time    `json:"to"`
+	Smoothing float64      `json:"smoothing"`
+	Points    []TrendPoint `json:"points"`
+
+	CurrentTrend *float64 `json:"currentTrend,omitempty"`
+	WeeklyRate   *float64 `json:"weeklyRate,omitempty"`
+	// WeeklyRatePercent is WeeklyRate as a percent of the current trend weight.
+	WeeklyRatePercent *float64         `json:"weeklyRatePercent,omitempty"`
+	Direction         c.TrendDirection `json:"direction,omitempty"`
+
+	GoalWeight        *float64   `json:"goalWeight,omitempty"`
+	GoalReached       bool       `json:"goalReached"`
+	ProjectedGoalDate *time.Time `json:"projectedGoalDate,omitempty"`
+
+	CaloriePhase     *wc.CaloriePhase `json:"caloriePhase,omitempty"`
+	TargetWeeklyRate *float64         `json:"targetWeeklyRate,omitempty"`
+	Warnings         []string         `json:"warnings"`
+}
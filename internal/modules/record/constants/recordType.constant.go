@@ -0,0 +1,10 @@
+package constants
+
+type RecordType string
+
+const (
+	RecordHeaviestLoad       RecordType = "heaviest_load"
+	RecordEstimatedOneRepMax RecordType = "e1rm"
+	RecordRepsAtLoad         RecordType = "reps_at_load"
+	RecordBestVolume         RecordType = "best_volume"
+)
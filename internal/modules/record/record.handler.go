@@ -0,0 +1,79 @@
+package record
+
+import (
+	"net/http"
+	"time"
+
+	m "github.com/joshibbotson/gym-tracker-backend/internal/middleware"
+	util "github.com/joshibbotson/gym-tracker-backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type RecordHandler struct {
+	Service RecordService
+}
+
+func NewRecordHandler(service RecordService) *RecordHandler {
+	return &RecordHandler{
+		Service: service,
+	}
+}
+
+// Handler serves /workout/records and /workout/records/{exercise}
+func (h *RecordHandler) Handler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		if r.PathValue("exercise") != "" {
+			m.PermissionMiddleware(h.handleReadExerciseRecords)(w, r)
+			break
+		}
+		m.PermissionMiddleware(h.handleReadRecentRecords)(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleReadRecentRecords lists PRs set between the from and to query params,
+// defaulting to the last 30 days.
+func (h *RecordHandler) handleReadRecentRecords(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	to := time.Now().Add(24 * time.Hour)
+	from := to.AddDate(0, 0, -31)
+	if toParam := r.URL.Query().Get("to"); toParam != "" {
+		parsed, err := util.ParseDate(toParam)
+		if err != nil {
+			http.Error(w, "Error parsing date", http.StatusBadRequest)
+			return
+		}
+		to = parsed.Add(24 * time.Hour)
+	}
+	if fromParam := r.URL.Query().Get("from"); fromParam != "" {
+		parsed, err := util.ParseDate(fromParam)
+		if err != nil {
+			http.Error(w, "Error parsing date", http.StatusBadRequest)
+			return
+		}
+		from = parsed
+	}
+
+	records, err := h.Service.GetRecentRecords(userID, from, to)
+	if err != nil {
+		http.Error(w, "Error fetching personal records", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, records)
+}
+
+func (h *RecordHandler) handleReadExerciseRecords(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	records, err := h.Service.GetExerciseRecords(userID, r.PathValue("exercise"))
+	if err != nil {
+		http.Error(w, "Error fetching personal records", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, records)
+}
@@ -0,0 +1,118 @@
+package record
+
+import (
+	"context"
+	"time"
+
+	db "github.com/joshibbotson/gym-tracker-backend/internal/db"
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/record/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/record/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RecordRepository interface {
+	ReplaceRecords(ctx context.Context, userID primitive.ObjectID, exerciseKey string, records []t.PersonalRecord) error
+	FetchRecentRecords(ctx context.Context, userID primitive.ObjectID, from time.Time, to time.Time) ([]t.PersonalRecord, error)
+	FetchExerciseRecords(ctx context.Context, userID primitive.ObjectID, exerciseKey string) ([]t.PersonalRecord, error)
+}
+
+type recordRepository struct {
+	recordCollection *mongo.Collection
+}
+
+func NewRecordRepository() RecordRepository {
+	return &recordRepository{
+		recordCollection: db.Client.Database(db.DB_NAME).Collection("personalRecord"),
+	}
+}
+
+// recordKey is what makes a record the same record across recomputes.
+type recordKey struct {
+	recordType c.RecordType
+	workoutID  primitive.ObjectID
+	weight     float64
+}
+
+func keyOf(record t.PersonalRecord) recordKey {
+	key := recordKey{recordType: record.Type, workoutID: record.WorkoutID}
+	if record.Weight != nil {
+		key.weight = *record.Weight
+	}
+	return key
+}
+
+// ReplaceRecords swaps an exercise's records for the given ones. Mongo runs
+// standalone so there's no transaction to clear and rewrite them in, instead
+// records that still stand keep their ID and are upserted over, and only then
+// are the ones no longer earned deleted, so the exercise is never left without
+// its records part way through.
+func (r *recordRepository) ReplaceRecords(ctx context.Context, userID primitive.ObjectID, exerciseKey string, records []t.PersonalRecord) error {
+	existing, err := r.FetchExerciseRecords(ctx, userID, exerciseKey)
+	if err != nil {
+		return err
+	}
+	existingIDs := make(map[recordKey]primitive.ObjectID, len(existing))
+	for _, record := range existing {
+		existingIDs[keyOf(record)] = record.ID
+	}
+
+	kept := make([]primitive.ObjectID, len(records))
+	models := make([]mongo.WriteModel, len(records))
+	for i, record := range records {
+		if id, ok := existingIDs[keyOf(record)]; ok {
+			record.ID = id
+		}
+		kept[i] = record.ID
+		models[i] = mongo.NewReplaceOneModel().SetFilter(bson.M{"_id": record.ID}).SetReplacement(record).SetUpsert(true)
+	}
+	if len(models) > 0 {
+		if _, err := r.recordCollection.BulkWrite(ctx, models); err != nil {
+			return err
+		}
+	}
+
+	_, err = r.recordCollection.DeleteMany(ctx, bson.M{
+		"userId":      userID,
+		"exerciseKey": exerciseKey,
+		"_id":         bson.M{"$nin": kept},
+	})
+	return err
+}
+
+func (r *recordRepository) FetchRecentRecords(ctx context.Context, userID primitive.ObjectID, from time.Time, to time.Time) ([]t.PersonalRecord, error) {
+	filter := bson.M{
+		"userId": userID,
+		"date":   bson.M{"$gte": from, "$lt": to},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: -1}, {Key: "exercise", Value: 1}})
+
+	cursor, err := r.recordCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	records := []t.PersonalRecord{}
+	if err = cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func (r *recordRepository) FetchExerciseRecords(ctx context.Context, userID primitive.ObjectID, exerciseKey string) ([]t.PersonalRecord, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}})
+	cursor, err := r.recordCollection.Find(ctx, bson.M{"userId": userID, "exerciseKey": exerciseKey}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	records := []t.PersonalRecord{}
+	if err = cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
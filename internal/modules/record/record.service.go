@@ -0,0 +1,236 @@
+package record
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/record/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/record/types"
+	wt "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+	util "github.com/joshibbotson/gym-tracker-backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type RecordService interface {
+	RecomputeRecords(userID primitive.ObjectID, exercises []string, history ExerciseHistory) error
+	GetRecentRecords(userID primitive.ObjectID, from time.Time, to time.Time) ([]t.PersonalRecord, error)
+	GetExerciseRecords(userID primitive.ObjectID, exercise string) ([]t.PersonalRecord, error)
+}
+
+// ExerciseHistory reads the workouts records are worked out from. The workout
+// service hands itself in when its workouts change, it needs the record service
+// to be built first so it can't be passed to NewRecordService.
+type ExerciseHistory interface {
+	GetExerciseHistory(userID primitive.ObjectID, exercises []string) ([]wt.Workout, error)
+}
+
+type recordService struct {
+	repo RecordRepository
+}
+
+func NewRecordService(repo RecordRepository) RecordService {
+	return &recordService{repo: repo}
+}
+
+// ExerciseKey normalises an exercise name so "Bench Press" and "bench press " share records.
+func ExerciseKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// RecomputeRecords rebuilds the PR history of each exercise from the workouts
+// currently logged, so edits and deletes take back PRs they no longer earn.
+func (s *recordService) RecomputeRecords(userID primitive.ObjectID, exercises []string, history ExerciseHistory) error {
+	keys := uniqueKeys(exercises)
+	if len(keys) == 0 {
+		return nil
+	}
+
+	workouts, err := history.GetExerciseHistory(userID, keys)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		records := detectRecords(userID, key, workouts)
+		if err := s.repo.ReplaceRecords(context.TODO(), userID, key, records); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *recordService) GetRecentRecords(userID primitive.ObjectID, from time.Time, to time.Time) ([]t.PersonalRecord, error) {
+	return s.repo.FetchRecentRecords(context.TODO(), userID, from, to)
+}
+
+func (s *recordService) GetExerciseRecords(userID primitive.ObjectID, exercise string) ([]t.PersonalRecord, error) {
+	return s.repo.FetchExerciseRecords(context.TODO(), userID, ExerciseKey(exercise))
+}
+
+// detectRecords walks an exercise's history oldest first and emits a record
+// every time a workout beats the best that came before it.
+func detectRecords(userID primitive.ObjectID, key string, workouts []wt.Workout) []t.PersonalRecord {
+	records := []t.PersonalRecord{}
+	bests := map[c.RecordType]float64{}
+	repsAtLoad := map[float64]int{}
+
+	for _, workout := range workouts {
+		if workout.Workout == nil {
+			continue
+		}
+
+		var name string
+		var sets []wt.ExerciseSet
+		for _, exercise := range workout.Workout.Exercises {
+			if ExerciseKey(exercise.Name) != key {
+				continue
+			}
+			name = strings.TrimSpace(exercise.Name)
+			for _, set := range exercise.Sets {
+				if !set.Warmup {
+					sets = append(sets, set)
+				}
+			}
+		}
+		if len(sets) == 0 {
+			continue
+		}
+
+		newRecord := func(recordType c.RecordType, value float64, weight float64, reps int) t.PersonalRecord {
+			record := t.PersonalRecord{
+				ID:          primitive.NewObjectID(),
+				UserId:      userID,
+				Exercise:    name,
+				ExerciseKey: key,
+				Type:        recordType,
+				Value:       value,
+				Reps:        reps,
+				WorkoutID:   workout.ID,
+				Date:        workout.Date,
+			}
+			if weight > 0 {
+				record.Weight = &weight
+			}
+			if previous, ok := bests[recordType]; ok {
+				record.Previous = &previous
+			}
+			return record
+		}
+
+		var heaviest, bestEstimate, volume float64
+		var heaviestReps, estimateReps int
+		var estimateWeight float64
+		for _, set := range sets {
+			weight := setWeight(set)
+			if set.Reps <= 0 || weight <= 0 {
+				continue
+			}
+			volume += weight * float64(set.Reps)
+			if weight > heaviest || (weight == heaviest && set.Reps > heaviestReps) {
+				heaviest, heaviestReps = weight, set.Reps
+			}
+			if estimate := util.EstimateOneRepMax(weight, set.Reps); estimate > bestEstimate {
+				bestEstimate, estimateWeight, estimateReps = estimate, weight, set.Reps
+			}
+		}
+
+		if best, ok := bests[c.RecordHeaviestLoad]; heaviest > 0 && (!ok || heaviest > best) {
+			records = append(records, newRecord(c.RecordHeaviestLoad, heaviest, heaviest, heaviestReps))
+			bests[c.RecordHeaviestLoad] = heaviest
+		}
+		if best, ok := bests[c.RecordEstimatedOneRepMax]; bestEstimate > 0 && (!ok || bestEstimate > best) {
+			records = append(records, newRecord(c.RecordEstimatedOneRepMax, bestEstimate, estimateWeight, estimateReps))
+			bests[c.RecordEstimatedOneRepMax] = bestEstimate
+		}
+		if best, ok := bests[c.RecordBestVolume]; volume > 0 && (!ok || volume > best) {
+			records = append(records, newRecord(c.RecordBestVolume, volume, 0, 0))
+			bests[c.RecordBestVolume] = volume
+		}
+
+		for _, set := range repRecords(sets, repsAtLoad) {
+			weight := setWeight(set)
+			record := newRecord(c.RecordRepsAtLoad, float64(set.Reps), weight, set.Reps)
+			record.Previous = nil
+			if previous := mostRepsAtOrAbove(repsAtLoad, weight); previous > 0 {
+				previousReps := float64(previous)
+				record.Previous = &previousReps
+			}
+			records = append(records, record)
+		}
+		for _, set := range sets {
+			weight := setWeight(set)
+			if set.Reps > repsAtLoad[weight] {
+				repsAtLoad[weight] = set.Reps
+			}
+		}
+	}
+
+	return records
+}
+
+// repRecords returns the sets that did more reps than had ever been done at
+// that load or heavier, including heavier sets from the same workout.
+func repRecords(sets []wt.ExerciseSet, history map[float64]int) []wt.ExerciseSet {
+	bestByLoad := map[float64]wt.ExerciseSet{}
+	for _, set := range sets {
+		if set.Reps <= 0 {
+			continue
+		}
+		weight := setWeight(set)
+		if best, ok := bestByLoad[weight]; !ok || set.Reps > best.Reps {
+			bestByLoad[weight] = set
+		}
+	}
+
+	loads := make([]float64, 0, len(bestByLoad))
+	for load := range bestByLoad {
+		loads = append(loads, load)
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(loads)))
+
+	records := []wt.ExerciseSet{}
+	heavierInWorkout := 0
+	for _, load := range loads {
+		set := bestByLoad[load]
+		if set.Reps > mostRepsAtOrAbove(history, load) && set.Reps > heavierInWorkout {
+			records = append(records, set)
+		}
+		if set.Reps > heavierInWorkout {
+			heavierInWorkout = set.Reps
+		}
+	}
+	return records
+}
+
+func mostRepsAtOrAbove(history map[float64]int, weight float64) int {
+	most := 0
+	for load, reps := range history {
+		if load >= weight && reps > most {
+			most = reps
+		}
+	}
+	return most
+}
+
+func setWeight(set wt.ExerciseSet) float64 {
+	if set.Weight == nil {
+		return 0
+	}
+	return *set.Weight
+}
+
+func uniqueKeys(exercises []string) []string {
+	seen := map[string]bool{}
+	keys := []string{}
+	for _, exercise := range exercises {
+		key := ExerciseKey(exercise)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, key)
+	}
+	return keys
+}
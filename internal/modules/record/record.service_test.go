@@ -0,0 +1,175 @@
+package record
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/record/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/record/types"
+	wt "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// squat logs a workout of squat sets on a day in March.
+func squat(day int, sets ...wt.ExerciseSet) wt.Workout {
+	return wt.Workout{
+		ID:      primitive.NewObjectID(),
+		Date:    time.Date(2026, 3, day, 0, 0, 0, 0, time.UTC),
+		Workout: &wt.WorkoutConfig{Exercises: []wt.Exercise{{Name: "Squat ", Sets: sets}}},
+	}
+}
+
+func set(weight float64, reps int) wt.ExerciseSet {
+	return wt.ExerciseSet{Weight: &weight, Reps: reps}
+}
+
+func warmup(weight float64, reps int) wt.ExerciseSet {
+	s := set(weight, reps)
+	s.Warmup = true
+	return s
+}
+
+// summary renders records as "day type value" so tables can compare them.
+func summary(records []t.PersonalRecord) []string {
+	lines := make([]string, len(records))
+	for i, record := range records {
+		lines[i] = fmt.Sprintf("%d %s %.1f", record.Date.Day(), record.Type, record.Value)
+	}
+	return lines
+}
+
+func TestDetectRecords(tt *testing.T) {
+	tests := []struct {
+		name     string
+		workouts []wt.Workout
+		want     []string
+	}{
+		{
+			name:     "first workout sets every record",
+			workouts: []wt.Workout{squat(1, set(100, 5))},
+			want: []string{
+				"1 heaviest_load 100.0",
+				"1 e1rm 116.7",
+				"1 best_volume 500.0",
+				"1 reps_at_load 5.0",
+			},
+		},
+		{
+			name:     "a repeat earns nothing",
+			workouts: []wt.Workout{squat(1, set(100, 5)), squat(2, set(100, 5))},
+			want: []string{
+				"1 heaviest_load 100.0",
+				"1 e1rm 116.7",
+				"1 best_volume 500.0",
+				"1 reps_at_load 5.0",
+			},
+		},
+		{
+			name:     "more reps at a lighter load is only a rep record at that load",
+			workouts: []wt.Workout{squat(1, set(100, 5)), squat(2, set(80, 5))},
+			want: []string{
+				"1 heaviest_load 100.0",
+				"1 e1rm 116.7",
+				"1 best_volume 500.0",
+				"1 reps_at_load 5.0",
+			},
+		},
+		{
+			name:     "warm-ups don't count",
+			workouts: []wt.Workout{squat(1, set(100, 1)), squat(2, warmup(120, 1), set(60, 1))},
+			want: []string{
+				"1 heaviest_load 100.0",
+				"1 e1rm 100.0",
+				"1 best_volume 100.0",
+				"1 reps_at_load 1.0",
+			},
+		},
+		{
+			name:     "heavier sets in the same workout cover lighter ones",
+			workouts: []wt.Workout{squat(1, set(100, 3), set(90, 3))},
+			want: []string{
+				"1 heaviest_load 100.0",
+				"1 e1rm 110.0",
+				"1 best_volume 570.0",
+				"1 reps_at_load 3.0",
+			},
+		},
+		{
+			name:     "a heavier single",
+			workouts: []wt.Workout{squat(1, set(100, 5)), squat(2, set(110, 1))},
+			want: []string{
+				"1 heaviest_load 100.0",
+				"1 e1rm 116.7",
+				"1 best_volume 500.0",
+				"1 reps_at_load 5.0",
+				"2 heaviest_load 110.0",
+				"2 reps_at_load 1.0",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			got := summary(detectRecords(primitive.NewObjectID(), "squat", test.workouts))
+			if fmt.Sprint(got) != fmt.Sprint(test.want) {
+				tt.Fatalf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDetectRecordsPrevious(tt *testing.T) {
+	records := detectRecords(primitive.NewObjectID(), "squat", []wt.Workout{squat(1, set(100, 5)), squat(2, set(100, 6))})
+	for _, record := range records {
+		if record.Date.Day() == 2 && record.Type == c.RecordRepsAtLoad {
+			if record.Previous == nil || *record.Previous != 5 {
+				tt.Fatalf("previous = %v, want 5", record.Previous)
+			}
+			return
+		}
+	}
+	tt.Fatalf("no rep record on day 2 in %v", summary(records))
+}
+
+// fakeRecords keeps the records each exercise was last replaced with.
+type fakeRecords struct {
+	RecordRepository
+	replaced map[string][]t.PersonalRecord
+}
+
+func (r *fakeRecords) ReplaceRecords(ctx context.Context, userID primitive.ObjectID, exerciseKey string, records []t.PersonalRecord) error {
+	r.replaced[exerciseKey] = records
+	return nil
+}
+
+type fakeHistory struct {
+	workouts  []wt.Workout
+	exercises []string
+}
+
+func (h *fakeHistory) GetExerciseHistory(userID primitive.ObjectID, exercises []string) ([]wt.Workout, error) {
+	h.exercises = exercises
+	return h.workouts, nil
+}
+
+func TestRecomputeRecords(tt *testing.T) {
+	repo := &fakeRecords{replaced: map[string][]t.PersonalRecord{}}
+	history := &fakeHistory{workouts: []wt.Workout{squat(1, set(100, 5))}}
+	service := NewRecordService(repo)
+
+	if err := service.RecomputeRecords(primitive.NewObjectID(), []string{"Squat", " squat", "Bench Press", ""}, history); err != nil {
+		tt.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"squat", "bench press"}; !reflect.DeepEqual(history.exercises, want) {
+		tt.Fatalf("read the history of %q, want %q", history.exercises, want)
+	}
+	if len(repo.replaced["squat"]) == 0 {
+		tt.Fatal("expected the squat records to be replaced")
+	}
+	if records, ok := repo.replaced["bench press"]; !ok || len(records) != 0 {
+		tt.Fatalf("bench press records = %+v, want them cleared", records)
+	}
+}
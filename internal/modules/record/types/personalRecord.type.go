@@ -0,0 +1,26 @@
+package types
+
+import (
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/record/constants"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PersonalRecord is written each time a workout beats the previous best for an
+// exercise, so the records for an exercise double as its PR history.
+// Value is the load for heaviest_load, the estimate for e1rm, the reps for
+// reps_at_load and reps x load summed over the session for best_volume.
+type PersonalRecord struct {
+	ID          primitive.ObjectID `bson:"_id" json:"_id"`
+	UserId      primitive.ObjectID `bson:"userId" json:"-"`
+	Exercise    string             `bson:"exercise" json:"exercise"`
+	ExerciseKey string             `bson:"exerciseKey" json:"-"`
+	Type        c.RecordType       `bson:"type" json:"type"`
+	Value       float64            `bson:"value" json:"value"`
+	Previous    *float64           `bson:"previous,omitempty" json:"previous,omitempty"`
+	Weight      *float64           `bson:"weight,omitempty" json:"weight,omitempty"`
+	Reps        int                `bson:"reps,omitempty" json:"reps,omitempty"`
+	WorkoutID   primitive.ObjectID `bson:"workoutId" json:"workoutId"`
+	Date        time.Time          `bson:"date" json:"date"`
+}
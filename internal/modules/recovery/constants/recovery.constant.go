@@ -0,0 +1,61 @@
+package constants
+
+import wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+
+// Hours a muscle group needs to recover from a typical session, larger groups
+// take longer. Groups not listed use DefaultRecoveryHours.
+var RecoveryHours = map[wc.TargetMuscles]float64{
+	wc.Quadriceps: 72,
+	wc.Hamstrings: 72,
+	wc.Glutes:     72,
+	wc.LowerBack:  72,
+	wc.Back:       60,
+	wc.Lats:       60,
+	wc.Chest:      60,
+}
+
+const DefaultRecoveryHours = 48
+
+const (
+	// ReferenceHardSets is the volume a "typical session" recovery time is based on,
+	// sessions above or below it take proportionally longer or shorter.
+	ReferenceHardSets = 10.0
+	// SessionHardSets is assumed for muscles listed in TargetMuscles without logged sets.
+	SessionHardSets = ReferenceHardSets
+	// Recovery time is never scaled below or above these factors.
+	MinVolumeFactor = 0.5
+	MaxVolumeFactor = 1.5
+
+	// LookbackDays is how far back workouts are considered, older ones are fully recovered.
+	LookbackDays = 14
+
+	// ReadyThreshold is the readiness a muscle needs to count as ready to train.
+	ReadyThreshold = 0.9
+	// RestThreshold is the readiness below which no combination is worth training.
+	RestThreshold = 0.5
+)
+
+type RecommendationSource string
+
+const (
+	SourceRoutine   RecommendationSource = "routine"
+	SourceReadiness RecommendationSource = "readiness"
+	SourceRest      RecommendationSource = "rest"
+)
+
+type MuscleCombination struct {
+	Name    string
+	Muscles []wc.TargetMuscles
+}
+
+// MuscleCombinations are the sessions considered when there is no routine to follow,
+// in order of preference when they are equally ready.
+var MuscleCombinations = []MuscleCombination{
+	{Name: "push", Muscles: []wc.TargetMuscles{wc.Push}},
+	{Name: "pull", Muscles: []wc.TargetMuscles{wc.Pull}},
+	{Name: "legs", Muscles: []wc.TargetMuscles{wc.Legs}},
+	{Name: "chest and triceps", Muscles: []wc.TargetMuscles{wc.Chest, wc.Triceps}},
+	{Name: "back and biceps", Muscles: []wc.TargetMuscles{wc.Back, wc.Lats, wc.Biceps}},
+	{Name: "shoulders and arms", Muscles: []wc.TargetMuscles{wc.Shoulders, wc.Biceps, wc.Triceps}},
+	{Name: "core", Muscles: []wc.TargetMuscles{wc.Abs, wc.Obliques, wc.LowerBack}},
+}
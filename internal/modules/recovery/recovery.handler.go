@@ -0,0 +1,67 @@
+package recovery
+
+import (
+	"net/http"
+	"time"
+
+	m "github.com/joshibbotson/gym-tracker-backend/internal/middleware"
+	util "github.com/joshibbotson/gym-tracker-backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type RecoveryHandler struct {
+	Service RecoveryService
+}
+
+func NewRecoveryHandler(service RecoveryService) *RecoveryHandler {
+	return &RecoveryHandler{
+		Service: service,
+	}
+}
+
+// Handler serves /recovery and /recovery/today, both accept an optional tz
+// query param (IANA name) so days line up with the user's calendar.
+func (h *RecoveryHandler) Handler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if r.URL.Path == "/recovery/today" {
+		m.PermissionMiddleware(h.handleReadRecommendation)(w, r)
+		return
+	}
+	m.PermissionMiddleware(h.handleReadReadiness)(w, r)
+}
+
+func (h *RecoveryHandler) handleReadReadiness(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+	location, ok := util.ParseTimezone(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.Service.GetReadiness(userID, time.Now().In(location))
+	if err != nil {
+		http.Error(w, "Error fetching recovery", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, report)
+}
+
+func (h *RecoveryHandler) handleReadRecommendation(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+	location, ok := util.ParseTimezone(w, r)
+	if !ok {
+		return
+	}
+
+	recommendation, err := h.Service.GetRecommendation(userID, time.Now().In(location))
+	if err != nil {
+		http.Error(w, "Error fetching recommendation", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, recommendation)
+}
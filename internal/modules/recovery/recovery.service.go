@@ -0,0 +1,295 @@
+package recovery
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/recovery/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/recovery/types"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/template"
+	tt "github.com/joshibbotson/gym-tracker-backend/internal/modules/template/types"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/workout"
+	wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+	wt "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+	util "github.com/joshibbotson/gym-tracker-backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type RecoveryService interface {
+	GetReadiness(userID primitive.ObjectID, now time.Time) (*t.RecoveryReport, error)
+	GetRecommendation(userID primitive.ObjectID, now time.Time) (*t.Recommendation, error)
+}
+
+type recoveryService struct {
+	workoutService  workout.WorkoutService
+	templateService template.TemplateService
+}
+
+func NewRecoveryService(workoutService workout.WorkoutService, templateService template.TemplateService) RecoveryService {
+	return &recoveryService{workoutService: workoutService, templateService: templateService}
+}
+
+// GetReadiness scores every muscle group from the workouts of the last
+// LookbackDays. now should be in the user's timezone.
+func (s *recoveryService) GetReadiness(userID primitive.ObjectID, now time.Time) (*t.RecoveryReport, error) {
+	workouts, err := s.recentWorkouts(userID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return &t.RecoveryReport{
+		Date:    calendarDay(now),
+		Muscles: readiness(workouts, now),
+	}, nil
+}
+
+// GetRecommendation follows the active routine for today unless it would train
+// a muscle group worked yesterday, otherwise it picks the most recovered
+// combination that doesn't. now should be in the user's timezone.
+func (s *recoveryService) GetRecommendation(userID primitive.ObjectID, now time.Time) (*t.Recommendation, error) {
+	workouts, err := s.recentWorkouts(userID, now)
+	if err != nil {
+		return nil, err
+	}
+	session, err := s.templateService.GetScheduledSession(userID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	muscles := readiness(workouts, now)
+	yesterday := trainedOn(workouts, calendarDay(now).AddDate(0, 0, -1))
+	recommendation := &t.Recommendation{
+		Date:             calendarDay(now),
+		TargetMuscles:    []wc.TargetMuscles{},
+		TrainedYesterday: yesterday,
+		Muscles:          muscles,
+	}
+
+	reason := ""
+	if session.Routine != nil {
+		if session.Rest {
+			recommendation.Source = c.SourceRest
+			recommendation.Reason = fmt.Sprintf("today is a rest day in %s", session.Routine.Name)
+			return recommendation, nil
+		}
+
+		targets := templateMuscles(*session.Template)
+		clashes := overlap(wc.ExpandMuscles(targets), yesterday)
+		if len(clashes) == 0 {
+			recommendation.Source = c.SourceRoutine
+			recommendation.Name = session.Template.Name
+			recommendation.TargetMuscles = targets
+			recommendation.Template = session.Template
+			recommendation.Readiness = averageReadiness(muscles, targets)
+			recommendation.Reason = fmt.Sprintf("%s is scheduled for today in %s", session.Template.Name, session.Routine.Name)
+			return recommendation, nil
+		}
+		reason = fmt.Sprintf("%s would train %s again after yesterday, ", session.Template.Name, joinMuscles(clashes))
+	}
+
+	var best *c.MuscleCombination
+	bestReadiness := 0.0
+	for i, combination := range c.MuscleCombinations {
+		if len(overlap(wc.ExpandMuscles(combination.Muscles), yesterday)) > 0 {
+			continue
+		}
+		if score := averageReadiness(muscles, combination.Muscles); best == nil || score > bestReadiness {
+			best, bestReadiness = &c.MuscleCombinations[i], score
+		}
+	}
+
+	if best == nil || bestReadiness < c.RestThreshold {
+		recommendation.Source = c.SourceRest
+		recommendation.Reason = reason + "no muscle group has recovered enough to train"
+		return recommendation, nil
+	}
+
+	recommendation.Source = c.SourceReadiness
+	recommendation.Name = best.Name
+	recommendation.TargetMuscles = best.Muscles
+	recommendation.Readiness = bestReadiness
+	recommendation.Reason = reason + fmt.Sprintf("%s is the most recovered", best.Name)
+	return recommendation, nil
+}
+
+func (s *recoveryService) recentWorkouts(userID primitive.ObjectID, now time.Time) ([]wt.Workout, error) {
+	today := calendarDay(now)
+	return s.workoutService.GetWorkoutsInRange(userID, today.AddDate(0, 0, -c.LookbackDays), today.AddDate(0, 0, 1))
+}
+
+// readiness adds up the fatigue left by each workout on each muscle group. A
+// workout's fatigue fades linearly over the muscle's recovery time, scaled by
+// how many hard sets it got compared to a typical session.
+func readiness(workouts []wt.Workout, now time.Time) []t.MuscleReadiness {
+	fatigue := map[wc.TargetMuscles]float64{}
+	results := map[wc.TargetMuscles]*t.MuscleReadiness{}
+	for _, muscle := range wc.IndividualMuscles {
+		results[muscle] = &t.MuscleReadiness{Muscle: muscle}
+	}
+
+	for _, workout := range workouts {
+		trained := trainedAt(workout.Date, now.Location())
+		hours := math.Max(0, now.Sub(trained).Hours())
+		for muscle, sets := range workoutStimulus(workout) {
+			result, ok := results[muscle]
+			if !ok {
+				continue
+			}
+			result.RecentSets += sets
+
+			recoveryHours := baseRecoveryHours(muscle) * volumeFactor(sets)
+			fatigue[muscle] += math.Max(0, 1-hours/recoveryHours)
+
+			recoveredAt := trained.Add(time.Duration(recoveryHours * float64(time.Hour)))
+			if recoveredAt.After(now) && (result.RecoveredAt == nil || recoveredAt.After(*result.RecoveredAt)) {
+				result.RecoveredAt = &recoveredAt
+			}
+			if result.LastTrained == nil || workout.Date.After(*result.LastTrained) {
+				date := workout.Date
+				result.LastTrained = &date
+				result.HoursSince = &hours
+			}
+		}
+	}
+
+	muscles := make([]t.MuscleReadiness, 0, len(results))
+	for _, muscle := range wc.IndividualMuscles {
+		result := results[muscle]
+		result.Readiness = math.Round((1-math.Min(1, fatigue[muscle]))*100) / 100
+		result.Ready = result.Readiness >= c.ReadyThreshold
+		muscles = append(muscles, *result)
+	}
+	sort.SliceStable(muscles, func(i, j int) bool { return muscles[i].Readiness > muscles[j].Readiness })
+	return muscles
+}
+
+// trainedAt is when recovery from a workout is measured from, the start of the
+// day it was logged on where the user is. Workout dates are stored as midnight
+// UTC, measuring from that would be off by the user's UTC offset.
+func trainedAt(date time.Time, location *time.Location) time.Time {
+	day := util.CalendarDay(date, location)
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, location)
+}
+
+// workoutStimulus returns the hard sets each muscle group got from a workout.
+// Logged exercises count their working sets, secondary muscles at a reduced
+// weight, and groups only listed in TargetMuscles count as a typical session.
+func workoutStimulus(workout wt.Workout) map[wc.TargetMuscles]float64 {
+	stimulus := map[wc.TargetMuscles]float64{}
+	if workout.Workout == nil {
+		return stimulus
+	}
+
+	for _, exercise := range workout.Workout.Exercises {
+		sets := 0.0
+		for _, set := range exercise.Sets {
+			if !set.Warmup && set.Reps > 0 {
+				sets++
+			}
+		}
+		for _, muscle := range wc.ExpandMuscles(exercise.PrimaryMuscles) {
+			stimulus[muscle] += sets * wc.PrimaryMuscleWeight
+		}
+		for _, muscle := range wc.ExpandMuscles(exercise.SecondaryMuscles) {
+			stimulus[muscle] += sets * wc.SecondaryMuscleWeight
+		}
+	}
+
+	for _, muscle := range wc.ExpandMuscles(workout.Workout.TargetMuscles) {
+		if _, ok := stimulus[muscle]; !ok {
+			stimulus[muscle] = c.SessionHardSets
+		}
+	}
+	return stimulus
+}
+
+func baseRecoveryHours(muscle wc.TargetMuscles) float64 {
+	if hours, ok := c.RecoveryHours[muscle]; ok {
+		return hours
+	}
+	return c.DefaultRecoveryHours
+}
+
+func volumeFactor(sets float64) float64 {
+	return math.Min(c.MaxVolumeFactor, math.Max(c.MinVolumeFactor, sets/c.ReferenceHardSets))
+}
+
+// trainedOn lists the muscle groups worked by workouts dated on day.
+func trainedOn(workouts []wt.Workout, day time.Time) []wc.TargetMuscles {
+	trained := []wc.TargetMuscles{}
+	for _, workout := range workouts {
+		if !calendarDay(workout.Date.UTC()).Equal(day) {
+			continue
+		}
+		for muscle, sets := range workoutStimulus(workout) {
+			if sets > 0 {
+				trained = append(trained, muscle)
+			}
+		}
+	}
+	trained = wc.ExpandMuscles(trained)
+	sort.Slice(trained, func(i, j int) bool { return trained[i] < trained[j] })
+	return trained
+}
+
+// templateMuscles uses the template's TargetMuscles, falling back to the
+// primary muscles of its exercises when none were set.
+func templateMuscles(template tt.Template) []wc.TargetMuscles {
+	if len(template.TargetMuscles) > 0 {
+		return template.TargetMuscles
+	}
+	muscles := []wc.TargetMuscles{}
+	for _, exercise := range template.Exercises {
+		if entry, ok := wc.FindCatalogExercise(exercise.Name); ok {
+			muscles = append(muscles, entry.Primary...)
+		}
+	}
+	return wc.ExpandMuscles(muscles)
+}
+
+func averageReadiness(muscles []t.MuscleReadiness, targets []wc.TargetMuscles) float64 {
+	expanded := wc.ExpandMuscles(targets)
+	if len(expanded) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, target := range expanded {
+		for _, muscle := range muscles {
+			if muscle.Muscle == target {
+				total += muscle.Readiness
+				break
+			}
+		}
+	}
+	return math.Round(total/float64(len(expanded))*100) / 100
+}
+
+func overlap(a []wc.TargetMuscles, b []wc.TargetMuscles) []wc.TargetMuscles {
+	shared := []wc.TargetMuscles{}
+	for _, muscle := range a {
+		for _, other := range b {
+			if muscle == other {
+				shared = append(shared, muscle)
+				break
+			}
+		}
+	}
+	return shared
+}
+
+func joinMuscles(muscles []wc.TargetMuscles) string {
+	names := make([]string, len(muscles))
+	for i, muscle := range muscles {
+		names[i] = strings.ReplaceAll(string(muscle), "_", " ")
+	}
+	return strings.Join(names, ", ")
+}
+
+// calendarDay returns midnight UTC of the date in date's own location, the
+// same form workout dates are stored in.
+func calendarDay(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+}
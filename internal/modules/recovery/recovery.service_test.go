@@ -0,0 +1,194 @@
+package recovery
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/recovery/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/recovery/types"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/template"
+	tt "github.com/joshibbotson/gym-tracker-backend/internal/modules/template/types"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/workout"
+	wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+	wt "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// session logs a workout on a day in March the way it's stored, as midnight UTC.
+func session(day int, muscles ...wc.TargetMuscles) wt.Workout {
+	return wt.Workout{
+		ID:      primitive.NewObjectID(),
+		Date:    time.Date(2026, 3, day, 0, 0, 0, 0, time.UTC),
+		Workout: &wt.WorkoutConfig{TargetMuscles: muscles},
+	}
+}
+
+func sets(count int, warmup bool) []wt.ExerciseSet {
+	logged := make([]wt.ExerciseSet, count)
+	for i := range logged {
+		logged[i] = wt.ExerciseSet{Reps: 8, Warmup: warmup}
+	}
+	return logged
+}
+
+func muscle(muscles []t.MuscleReadiness, target wc.TargetMuscles) t.MuscleReadiness {
+	for _, result := range muscles {
+		if result.Muscle == target {
+			return result
+		}
+	}
+	return t.MuscleReadiness{}
+}
+
+func TestReadinessMeasuresFromTheLocalDay(tt *testing.T) {
+	locations := []*time.Location{time.UTC, time.FixedZone("UTC+10", 10*60*60), time.FixedZone("UTC-5", -5*60*60)}
+
+	for _, location := range locations {
+		tt.Run(location.String(), func(tt *testing.T) {
+			// a typical chest session takes 60 hours, 33 of them have passed
+			now := time.Date(2026, 3, 2, 9, 0, 0, 0, location)
+			chest := muscle(readiness([]wt.Workout{session(1, wc.Chest)}, now), wc.Chest)
+
+			if chest.HoursSince == nil || *chest.HoursSince != 33 {
+				tt.Fatalf("hours since = %v, want 33", chest.HoursSince)
+			}
+			if chest.Readiness != 0.55 || chest.Ready {
+				tt.Fatalf("readiness = %v ready %v, want 0.55 and not ready", chest.Readiness, chest.Ready)
+			}
+			if want := time.Date(2026, 3, 3, 12, 0, 0, 0, location); chest.RecoveredAt == nil || !chest.RecoveredAt.Equal(want) {
+				tt.Fatalf("recovered at %v, want %v", chest.RecoveredAt, want)
+			}
+			if !chest.LastTrained.Equal(session(1).Date) {
+				tt.Fatalf("last trained %v, want the stored day", chest.LastTrained)
+			}
+		})
+	}
+}
+
+func TestReadiness(tt *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	heavy := session(10)
+	heavy.Workout.Exercises = []wt.Exercise{{Name: "Bench", PrimaryMuscles: []wc.TargetMuscles{wc.Chest}, Sets: sets(20, false)}}
+
+	tests := []struct {
+		name     string
+		workouts []wt.Workout
+		want     float64
+	}{
+		{"never trained", nil, 1},
+		{"recovered", []wt.Workout{session(6, wc.Chest)}, 1},
+		{"part way", []wt.Workout{session(9, wc.Push)}, 0.6},
+		{"fatigue adds up", []wt.Workout{session(9, wc.Chest), session(10, wc.Chest)}, 0},
+		{"more sets take longer", []wt.Workout{heavy}, 0.13},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			chest := muscle(readiness(test.workouts, now), wc.Chest)
+			if chest.Readiness != test.want {
+				tt.Fatalf("chest readiness = %v, want %v", chest.Readiness, test.want)
+			}
+			if chest.Ready != (test.want >= c.ReadyThreshold) {
+				tt.Fatalf("ready = %v at %v", chest.Ready, chest.Readiness)
+			}
+		})
+	}
+}
+
+func TestTrainedOn(tt *testing.T) {
+	warmups := session(2)
+	warmups.Workout.Exercises = []wt.Exercise{{Name: "Curl", PrimaryMuscles: []wc.TargetMuscles{wc.Biceps}, Sets: sets(3, true)}}
+	logged := session(2)
+	logged.Workout.Exercises = []wt.Exercise{{Name: "Calf Raise", PrimaryMuscles: []wc.TargetMuscles{wc.Calves}, Sets: sets(3, false)}}
+
+	tests := []struct {
+		name     string
+		workouts []wt.Workout
+		want     string
+	}{
+		{"nothing", nil, ""},
+		{"other days", []wt.Workout{session(1, wc.Chest), session(3, wc.Back)}, ""},
+		{"composites expand, sorted", []wt.Workout{session(2, wc.Push)}, "chest shoulders triceps"},
+		{"two workouts, no duplicates", []wt.Workout{session(2, wc.Chest, wc.Abs), session(2, wc.Chest)}, "abs chest"},
+		{"warmup sets don't count", []wt.Workout{warmups}, ""},
+		{"logged sets count", []wt.Workout{logged}, "calves"},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			trained := trainedOn(test.workouts, time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC))
+			names := make([]string, len(trained))
+			for i, muscle := range trained {
+				names[i] = string(muscle)
+			}
+			if got := strings.Join(names, " "); got != test.want {
+				tt.Fatalf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+type fakeWorkouts struct {
+	workout.WorkoutService
+	workouts []wt.Workout
+}
+
+func (s *fakeWorkouts) GetWorkoutsInRange(userID primitive.ObjectID, from time.Time, to time.Time) ([]wt.Workout, error) {
+	return s.workouts, nil
+}
+
+type fakeTemplates struct {
+	template.TemplateService
+	session tt.ScheduledSession
+}
+
+func (s *fakeTemplates) GetScheduledSession(userID primitive.ObjectID, date time.Time) (*tt.ScheduledSession, error) {
+	return &s.session, nil
+}
+
+// scheduled is a day of a routine training the given muscles, a rest day
+// without any, or no routine at all when there isn't one.
+func scheduled(routine bool, muscles ...wc.TargetMuscles) tt.ScheduledSession {
+	if !routine {
+		return tt.ScheduledSession{}
+	}
+	if len(muscles) == 0 {
+		return tt.ScheduledSession{Routine: &tt.Routine{Name: "PPL"}, Rest: true}
+	}
+	return tt.ScheduledSession{Routine: &tt.Routine{Name: "PPL"}, Template: &tt.Template{Name: "Push Day", TargetMuscles: muscles}}
+}
+
+func TestGetRecommendation(tt *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		yesterday  []wc.TargetMuscles
+		routine    bool
+		scheduled  []wc.TargetMuscles
+		wantSource c.RecommendationSource
+		wantName   string
+		wantReason string
+	}{
+		{"routine followed", []wc.TargetMuscles{wc.Legs}, true, []wc.TargetMuscles{wc.Push}, c.SourceRoutine, "Push Day", "Push Day is scheduled for today in PPL"},
+		{"rest day", []wc.TargetMuscles{wc.Legs}, true, nil, c.SourceRest, "", "today is a rest day in PPL"},
+		{"routine clashes with yesterday", []wc.TargetMuscles{wc.Chest}, true, []wc.TargetMuscles{wc.Push}, c.SourceReadiness, "pull", "Push Day would train chest again after yesterday, pull is the most recovered"},
+		{"no routine", []wc.TargetMuscles{wc.Chest}, false, nil, c.SourceReadiness, "pull", "pull is the most recovered"},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			workouts := &fakeWorkouts{workouts: []wt.Workout{session(1, test.yesterday...)}}
+			service := NewRecoveryService(workouts, &fakeTemplates{session: scheduled(test.routine, test.scheduled...)})
+
+			recommendation, err := service.GetRecommendation(primitive.NewObjectID(), now)
+			if err != nil {
+				tt.Fatalf("unexpected error: %v", err)
+			}
+			if recommendation.Source != test.wantSource || recommendation.Name != test.wantName || recommendation.Reason != test.wantReason {
+				tt.Fatalf("got %s %q %q, want %s %q %q", recommendation.Source, recommendation.Name, recommendation.Reason, test.wantSource, test.wantName, test.wantReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package types
+
+import (
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/recovery/constants"
+	tt "github.com/joshibbotson/gym-tracker-backend/internal/modules/template/types"
+	wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+)
+
+// MuscleReadiness is 1 when a muscle group is fully recovered and 0 straight
+// after a session big enough to need its whole recovery time.
+type MuscleReadiness struct {
+	Muscle      wc.TargetMuscles `json:"muscle"`
+	LastTrained *time.Time       `json:"lastTrained,omitempty"`
+	HoursSince  *float64         `json:"hoursSince,omitempty"`
+	RecentSets  float64          `json:"recentSets"`
+	Readiness   float64          `json:"readiness"`
+	Ready       bool             `json:"ready"`
+	RecoveredAt *time.Time       `json:"recoveredAt,omitempty"`
+}
+
+type RecoveryReport struct {
+	Date    time.Time         `json:"date"`
+	Muscles []MuscleReadiness `json:"muscles"`
+}
+
+type Recommendation struct {
+	Date             time.Time              `json:"date"`
+	Source           c.RecommendationSource `json:"source"`
+	Name             string                 `json:"name,omitempty"`
+	TargetMuscles    []wc.TargetMuscles     `json:"targetMuscles"`
+	Template         *tt.Template           `json:"template,omitempty"`
+	Readiness        float64                `json:"readiness"`
+	TrainedYesterday []wc.TargetMuscles     `json:"trainedYesterday"`
+	Reason           string                 `json:"reason"`
+	Muscles          []MuscleReadiness      `json:"muscles"`
+}
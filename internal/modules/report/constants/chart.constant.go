@@ -0,0 +1,66 @@
+package constants
+
+type ChartType string
+
+const (
+	ChartHeatmap     ChartType = "heatmap"
+	ChartWeight      ChartType = "weight"
+	ChartMeasurement ChartType = "measurement"
+)
+
+func (t ChartType) Valid() bool {
+	return t == ChartHeatmap || t == ChartWeight || t == ChartMeasurement
+}
+
+type ChartFormat string
+
+const (
+	ChartSVG ChartFormat = "svg"
+	ChartPNG ChartFormat = "png"
+)
+
+func (f ChartFormat) Valid() bool {
+	_, ok := ChartContentTypes[f]
+	return ok
+}
+
+var ChartContentTypes = map[ChartFormat]string{
+	ChartSVG: "image/svg+xml",
+	ChartPNG: "image/png",
+}
+
+// Ranges charts cover when none is given, the heatmap shows the last year
+// and the line charts the same 90 days as the weight trend.
+const (
+	DefaultHeatmapDays = 365
+	DefaultChartDays   = 90
+)
+
+// Sizes are in pixels, Cell is the space given to each day of the heatmap.
+const (
+	DefaultChartWidth  = 640
+	DefaultChartHeight = 240
+	MinChartWidth      = 200
+	MaxChartWidth      = 2000
+	MinChartHeight     = 100
+	MaxChartHeight     = 1000
+
+	DefaultCell = 12
+	MinCell     = 4
+	MaxCell     = 40
+
+	ChartPadding = 10
+)
+
+// DefaultSharedCharts are the charts a share link shows when the user
+// doesn't pick, body weight and measurements have to be opted in to.
+var DefaultSharedCharts = []ChartType{ChartHeatmap}
+
+// ShareTokenBytes is how much randomness goes into a chart share token.
+const ShareTokenBytes = 32
+
+const (
+	BackgroundColour = "#ffffff"
+	// SharedChartMaxAge is how long, in seconds, caches can keep a shared chart.
+	SharedChartMaxAge = 900
+)
@@ -0,0 +1,27 @@
+package constants
+
+const (
+	// DefaultReportDays is the range a report covers when none is given,
+	// reports are usually monthly.
+	DefaultReportDays = 30
+	MaxReportDays     = 366
+
+	// MaxReportRecords caps the PRs listed, the rest are counted.
+	MaxReportRecords = 40
+)
+
+// A4 in points, which PDF measures everything in.
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+	PageMargin = 48.0
+)
+
+// Colours used alongside the user's calendar colours.
+const (
+	TextColour   = "#24292f"
+	MutedColour  = "#57606a"
+	GridColour   = "#d0d7de"
+	WeightColour = "#8c959f"
+	TrendColour  = "#0969da"
+)
@@ -0,0 +1,83 @@
+package report
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// canvas is what reports and charts are drawn on. Coordinates are from the
+// top left, in points on a PDF and pixels in an image, and text is
+// positioned by its baseline.
+type canvas interface {
+	Rect(x, y, width, height, radius float64, fill colour)
+	Polyline(points []point, stroke colour, width float64)
+	Text(x, y, size float64, bold bool, fill colour, text string)
+	TextWidth(text string, size float64, bold bool) float64
+}
+
+type point struct {
+	X float64
+	Y float64
+}
+
+type colour struct {
+	R, G, B uint8
+}
+
+// parseColour reads a #rrggbb or #rgb colour, falling back when it can't.
+func parseColour(value string, fallback string) colour {
+	if parsed, ok := hexColour(value); ok {
+		return parsed
+	}
+	parsed, _ := hexColour(fallback)
+	return parsed
+}
+
+func hexColour(value string) (colour, bool) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "#")
+	if len(value) == 3 {
+		value = string([]byte{value[0], value[0], value[1], value[1], value[2], value[2]})
+	}
+	if len(value) != 6 {
+		return colour{}, false
+	}
+	number, err := strconv.ParseUint(value, 16, 32)
+	if err != nil {
+		return colour{}, false
+	}
+	return colour{R: uint8(number >> 16), G: uint8(number >> 8), B: uint8(number)}, true
+}
+
+// helveticaWidths are the advance widths of the printable ASCII characters
+// in Helvetica, in thousandths of the font size.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// textWidth measures text set in Helvetica, bold is close enough to allow
+// for with a flat tenth extra.
+func textWidth(text string, size float64, bold bool) float64 {
+	width := 0
+	for _, r := range text {
+		if r >= 32 && r < 127 {
+			width += helveticaWidths[r-32]
+		} else {
+			width += 556
+		}
+	}
+	result := float64(width) * size / 1000
+	if bold {
+		result *= 1.1
+	}
+	return result
+}
+
+func (c colour) Hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
@@ -0,0 +1,308 @@
+package report
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/report/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/report/types"
+	uc "github.com/joshibbotson/gym-tracker-backend/internal/modules/user/constants"
+	ut "github.com/joshibbotson/gym-tracker-backend/internal/modules/user/types"
+	wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+	wt "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+	util "github.com/joshibbotson/gym-tracker-backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// imageCanvas is a canvas that can be written out as a file.
+type imageCanvas interface {
+	canvas
+	Encode() ([]byte, error)
+}
+
+func newImageCanvas(format c.ChartFormat, width int, height int) imageCanvas {
+	background := parseColour(c.BackgroundColour, c.BackgroundColour)
+	if format == c.ChartPNG {
+		return newPNGCanvas(width, height, background)
+	}
+	return newSVGCanvas(width, height, background)
+}
+
+// RenderChart draws one of the user's charts as an SVG or PNG.
+func (s *reportService) RenderChart(userID primitive.ObjectID, options t.ChartOptions) ([]byte, error) {
+	return s.renderChart(userID, options, false)
+}
+
+// renderChart only draws from public workouts when shared is set, opting in to
+// a chart shares it the way a profile link would.
+func (s *reportService) renderChart(userID primitive.ObjectID, options t.ChartOptions, shared bool) ([]byte, error) {
+	if !options.Chart.Valid() {
+		return nil, ErrUnknownChart
+	}
+	if !options.Format.Valid() {
+		return nil, ErrInvalidChartFormat
+	}
+	if options.To.Sub(options.From) > c.MaxReportDays*24*time.Hour {
+		return nil, ErrRangeTooLarge
+	}
+
+	settings, err := s.userService.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	active, err := chartColour(options.ActiveColour, settings.ActiveDayColour, uc.DefaultActiveDayColour)
+	if err != nil {
+		return nil, err
+	}
+	inactive, err := chartColour(options.InactiveColour, settings.InactiveDayColour, uc.DefaultInactiveDayColour)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Chart == c.ChartHeatmap {
+		return s.renderHeatmap(userID, options, settings, active, inactive, shared)
+	}
+	return s.renderLineChart(userID, options, active, inactive, shared)
+}
+
+// chartColour prefers the colour asked for, then the user's setting.
+func chartColour(requested string, setting string, fallback string) (colour, error) {
+	if requested == "" {
+		return parseColour(setting, fallback), nil
+	}
+	parsed, ok := hexColour(requested)
+	if !ok {
+		return colour{}, ErrInvalidColour
+	}
+	return parsed, nil
+}
+
+func chartSize(requested int, fallback int, low int, high int) (int, error) {
+	if requested == 0 {
+		return fallback, nil
+	}
+	if requested < low || requested > high {
+		return 0, ErrInvalidChartSize
+	}
+	return requested, nil
+}
+
+// renderHeatmap sizes the image to fit the heatmap, the size is set by the
+// space given to each day.
+func (s *reportService) renderHeatmap(userID primitive.ObjectID, options t.ChartOptions, settings *ut.UserSettings, active colour, inactive colour, shared bool) ([]byte, error) {
+	cell, err := chartSize(options.Cell, c.DefaultCell, c.MinCell, c.MaxCell)
+	if err != nil {
+		return nil, err
+	}
+	calendar, err := s.chartCalendar(userID, options.From, options.To, settings.WeekStart, shared)
+	if err != nil {
+		return nil, err
+	}
+
+	chart := heatmap{
+		Calendar:  calendar,
+		From:      options.From,
+		To:        options.To,
+		WeekStart: settings.WeekStart,
+		Cell:      float64(cell),
+		Radius:    float64(settings.DayBorderRadius),
+		Active:    active,
+		Inactive:  inactive,
+		Label:     parseColour(c.MutedColour, c.MutedColour),
+	}
+	width, height := chart.Size()
+	image := newImageCanvas(options.Format, int(math.Ceil(width))+2*c.ChartPadding, int(math.Ceil(height))+2*c.ChartPadding)
+	chart.Draw(image, c.ChartPadding, c.ChartPadding)
+	return image.Encode()
+}
+
+// chartCalendar reads the workouts a chart is drawn from.
+func (s *reportService) chartCalendar(userID primitive.ObjectID, from time.Time, to time.Time, weekStart wc.WeekStart, shared bool) ([]wt.YearlyData, error) {
+	if shared {
+		return s.workoutService.GetSharedCalendar(userID, from, to, weekStart)
+	}
+	return s.workoutService.GetWorkoutsByUserId(userID, from, to, weekStart)
+}
+
+// renderLineChart draws the weight trend over each weigh-in, or a
+// measurement over time, in the active colour on inactive grid lines.
+func (s *reportService) renderLineChart(userID primitive.ObjectID, options t.ChartOptions, active colour, inactive colour, shared bool) ([]byte, error) {
+	width, err := chartSize(options.Width, c.DefaultChartWidth, c.MinChartWidth, c.MaxChartWidth)
+	if err != nil {
+		return nil, err
+	}
+	height, err := chartSize(options.Height, c.DefaultChartHeight, c.MinChartHeight, c.MaxChartHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	chart := lineChart{
+		From:       options.From,
+		To:         options.To,
+		Width:      float64(width - 2*c.ChartPadding),
+		Height:     float64(height - 2*c.ChartPadding),
+		DotColour:  active,
+		LineColour: active,
+		Grid:       inactive,
+		Label:      parseColour(c.MutedColour, c.MutedColour),
+	}
+
+	switch options.Chart {
+	case c.ChartWeight:
+		audience := wc.AudienceOwner
+		if shared {
+			audience = wc.AudiencePublic
+		}
+		trend, err := s.progressService.GetWeightTrend(userID, audience, options.From, options.To, 0)
+		if err != nil {
+			return nil, err
+		}
+		chart.DotColour = parseColour(c.WeightColour, c.WeightColour)
+		for _, point := range trend.Points {
+			chart.Dots = append(chart.Dots, datedValue{Date: point.Date, Value: point.Weight})
+			chart.Line = append(chart.Line, datedValue{Date: point.Date, Value: point.Trend})
+		}
+	case c.ChartMeasurement:
+		values, err := s.measurementValues(userID, options, shared)
+		if err != nil {
+			return nil, err
+		}
+		chart.Dots, chart.Line = values, values
+	}
+
+	image := newImageCanvas(options.Format, width, height)
+	chart.Draw(image, c.ChartPadding, c.ChartPadding)
+	return image.Encode()
+}
+
+// measurementValues reads options.Field from each day it was logged, the
+// last workout of a day wins.
+func (s *reportService) measurementValues(userID primitive.ObjectID, options t.ChartOptions, shared bool) ([]datedValue, error) {
+	index := slices.IndexFunc(sizeFields, func(field reflect.StructField) bool {
+		return strings.Split(field.Tag.Get("json"), ",")[0] == options.Field
+	})
+	if index < 0 {
+		return nil, ErrUnknownField
+	}
+	field := sizeFields[index]
+
+	calendar, err := s.chartCalendar(userID, options.From, options.To, "", shared)
+	if err != nil {
+		return nil, err
+	}
+	values := []datedValue{}
+	forEachDay(calendar, func(day wt.DailyWorkout) {
+		if day.Config == nil {
+			return
+		}
+		value := reflect.ValueOf(*day.Config).FieldByIndex(field.Index)
+		if value.IsNil() {
+			return
+		}
+		if n := len(values); n > 0 && values[n-1].Date.Equal(day.Date) {
+			values[n-1].Value = value.Elem().Float()
+			return
+		}
+		values = append(values, datedValue{Date: day.Date, Value: value.Elem().Float()})
+	})
+	return values, nil
+}
+
+// RenderSharedChart draws a chart for whoever holds a share token, as long as
+// the user chose to share that chart. Only public workouts are drawn.
+func (s *reportService) RenderSharedChart(token string, options t.ChartOptions) ([]byte, error) {
+	share, err := s.repo.FetchChartShareByTokenHash(context.TODO(), util.HashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if share == nil || !slices.Contains(share.Charts, options.Chart) {
+		return nil, ErrChartShareNotFound
+	}
+	return s.renderChart(share.UserId, options, true)
+}
+
+// CreateChartShare gives the user a new share token, replacing the one they
+// had. The token is only returned here, it is stored hashed.
+func (s *reportService) CreateChartShare(userID primitive.ObjectID, request t.ChartShareRequest) (*t.ChartShare, error) {
+	charts, err := sharedCharts(request.Charts)
+	if err != nil {
+		return nil, err
+	}
+	token, err := util.NewToken(c.ShareTokenBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.FetchChartShare(context.TODO(), userID)
+	if err != nil {
+		return nil, err
+	}
+	shareID := primitive.NewObjectID()
+	if existing != nil {
+		shareID = existing.ID
+	}
+
+	share, err := s.repo.ReplaceChartShare(context.TODO(), t.ChartShare{
+		ID:        shareID,
+		UserId:    userID,
+		TokenHash: util.HashToken(token),
+		Charts:    charts,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	share.Token = token
+	return share, nil
+}
+
+// UpdateChartShare changes which charts are shared, keeping the token so
+// charts already embedded keep working.
+func (s *reportService) UpdateChartShare(userID primitive.ObjectID, request t.ChartShareRequest) (*t.ChartShare, error) {
+	charts, err := sharedCharts(request.Charts)
+	if err != nil {
+		return nil, err
+	}
+	share, err := s.GetChartShare(userID)
+	if err != nil {
+		return nil, err
+	}
+	share.Charts = charts
+	return s.repo.ReplaceChartShare(context.TODO(), *share)
+}
+
+func (s *reportService) GetChartShare(userID primitive.ObjectID) (*t.ChartShare, error) {
+	share, err := s.repo.FetchChartShare(context.TODO(), userID)
+	if err != nil {
+		return nil, err
+	}
+	if share == nil {
+		return nil, ErrChartShareNotFound
+	}
+	return share, nil
+}
+
+func (s *reportService) DeleteChartShare(userID primitive.ObjectID) (bool, error) {
+	return s.repo.RemoveChartShare(context.TODO(), userID)
+}
+
+// sharedCharts checks the charts asked for, defaulting to DefaultSharedCharts.
+func sharedCharts(requested []c.ChartType) ([]c.ChartType, error) {
+	if len(requested) == 0 {
+		return c.DefaultSharedCharts, nil
+	}
+	charts := []c.ChartType{}
+	for _, chart := range requested {
+		if !chart.Valid() {
+			return nil, ErrUnknownChart
+		}
+		if !slices.Contains(charts, chart) {
+			charts = append(charts, chart)
+		}
+	}
+	return charts, nil
+}
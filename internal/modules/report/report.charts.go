@@ -0,0 +1,159 @@
+package report
+
+import (
+	"math"
+	"time"
+
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/workout"
+	wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+	wt "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+)
+
+// heatmap lays the days from <= date < to out GitHub style, a column per
+// week and a row per weekday. Cell is the space given to each day, the
+// square drawn in it leaves a gap to its neighbours.
+type heatmap struct {
+	Calendar  []wt.YearlyData
+	From      time.Time
+	To        time.Time
+	WeekStart wc.WeekStart
+	Cell      float64
+	Radius    float64
+	Active    colour
+	Inactive  colour
+	Label     colour
+}
+
+const (
+	heatmapLabelWidth = 24.0
+	heatmapMonthRow   = 12.0
+	heatmapLabelSize  = 7.0
+)
+
+func (h heatmap) weeks() int {
+	start := startOfWeek(h.From, h.WeekStart.Weekday())
+	return int(math.Ceil(h.To.Sub(start).Hours() / 24 / 7))
+}
+
+func (h heatmap) Size() (float64, float64) {
+	return heatmapLabelWidth + float64(h.weeks())*h.Cell, heatmapMonthRow + 7*h.Cell
+}
+
+func (h heatmap) Draw(cv canvas, x float64, y float64) {
+	active := map[time.Time]bool{}
+	forEachDay(h.Calendar, func(day wt.DailyWorkout) {
+		if workout.IsTraining(day.Config) {
+			active[day.Date.UTC()] = true
+		}
+	})
+
+	weekStart := h.WeekStart.Weekday()
+	for row := 1; row < 7; row += 2 {
+		name := time.Weekday((int(weekStart) + row) % 7).String()[:3]
+		cv.Text(x, y+heatmapMonthRow+float64(row+1)*h.Cell-h.Cell*0.3, heatmapLabelSize, false, h.Label, name)
+	}
+
+	square := h.Cell * 0.8
+	labelEnd := 0.0
+	start := startOfWeek(h.From, weekStart)
+	for day, i := start, 0; day.Before(h.To); day, i = day.AddDate(0, 0, 1), i+1 {
+		if day.Before(h.From) {
+			continue
+		}
+		column := x + heatmapLabelWidth + float64(i/7)*h.Cell
+		if (day.Day() == 1 || day.Equal(h.From)) && column >= labelEnd {
+			name := day.Format("Jan")
+			cv.Text(column, y+heatmapMonthRow-3, heatmapLabelSize, false, h.Label, name)
+			labelEnd = column + cv.TextWidth(name, heatmapLabelSize, false) + 4
+		}
+
+		fill := h.Inactive
+		if active[day] {
+			fill = h.Active
+		}
+		cv.Rect(column, y+heatmapMonthRow+float64(i%7)*h.Cell, square, square, h.Radius, fill)
+	}
+}
+
+// datedValue is a value on a line chart, plotted in the middle of its day.
+type datedValue struct {
+	Date  time.Time
+	Value float64
+}
+
+// lineChart plots Dots, such as each day's weigh-in, under Line, such as the
+// smoothed trend of them. Either can be left empty.
+type lineChart struct {
+	Dots       []datedValue
+	Line       []datedValue
+	From       time.Time
+	To         time.Time
+	Width      float64
+	Height     float64
+	DotColour  colour
+	LineColour colour
+	Grid       colour
+	Label      colour
+}
+
+const (
+	chartAxisWidth   = 36.0
+	chartDateRow     = 14.0
+	chartLabelSize   = 7.0
+	chartGridLines   = 4
+	chartMinimumSpan = 1.0
+)
+
+func (chart lineChart) Draw(cv canvas, x float64, y float64) {
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, value := range append(append([]datedValue{}, chart.Dots...), chart.Line...) {
+		low, high = math.Min(low, value.Value), math.Max(high, value.Value)
+	}
+	if math.IsInf(low, 1) {
+		message := "No data in this range"
+		cv.Text(x+(chart.Width-cv.TextWidth(message, chartLabelSize, false))/2, y+chart.Height/2, chartLabelSize, false, chart.Label, message)
+		return
+	}
+	padding := math.Max((high-low)*0.1, (chartMinimumSpan-(high-low))/2)
+	low, high = low-padding, high+padding
+
+	left, plotWidth := x+chartAxisWidth, chart.Width-chartAxisWidth
+	plotHeight := chart.Height - chartDateRow
+	toPoint := func(value datedValue) point {
+		middle := value.Date.Add(12 * time.Hour)
+		return point{
+			X: left + middle.Sub(chart.From).Seconds()/chart.To.Sub(chart.From).Seconds()*plotWidth,
+			Y: y + (high-value.Value)/(high-low)*plotHeight,
+		}
+	}
+
+	for i := 0; i <= chartGridLines; i++ {
+		value := low + (high-low)*float64(i)/chartGridLines
+		lineY := y + (high-value)/(high-low)*plotHeight
+		cv.Polyline([]point{{left, lineY}, {left + plotWidth, lineY}}, chart.Grid, 0.5)
+		label := formatNumber(math.Round(value*10) / 10)
+		cv.Text(left-6-cv.TextWidth(label, chartLabelSize, false), lineY+2.5, chartLabelSize, false, chart.Label, label)
+	}
+
+	for _, value := range chart.Dots {
+		dot := toPoint(value)
+		cv.Rect(dot.X-1.5, dot.Y-1.5, 3, 3, 1.5, chart.DotColour)
+	}
+	line := []point{}
+	for _, value := range chart.Line {
+		line = append(line, toPoint(value))
+	}
+	cv.Polyline(line, chart.LineColour, 1.5)
+
+	first, last := formatDate(chart.From), formatDate(chart.To.AddDate(0, 0, -1))
+	dateY := y + chart.Height - 2
+	cv.Text(left, dateY, chartLabelSize, false, chart.Label, first)
+	cv.Text(left+plotWidth-cv.TextWidth(last, chartLabelSize, false), dateY, chartLabelSize, false, chart.Label, last)
+}
+
+// startOfWeek truncates date to midnight UTC on the most recent weekStart day.
+func startOfWeek(date time.Time, weekStart time.Weekday) time.Time {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	offset := (int(day.Weekday()) - int(weekStart) + 7) % 7
+	return day.AddDate(0, 0, -offset)
+}
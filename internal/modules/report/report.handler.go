@@ -0,0 +1,276 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	m "github.com/joshibbotson/gym-tracker-backend/internal/middleware"
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/report/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/report/types"
+	util "github.com/joshibbotson/gym-tracker-backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ReportHandler struct {
+	Service ReportService
+}
+
+func NewReportHandler(service ReportService) *ReportHandler {
+	return &ReportHandler{
+		Service: service,
+	}
+}
+
+// Handler serves /progress/report
+func (h *ReportHandler) Handler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	m.PermissionMiddleware(h.handleReadReport)(w, r)
+}
+
+// ChartHandler serves /chart/{chart}
+func (h *ReportHandler) ChartHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	m.PermissionMiddleware(h.handleReadChart)(w, r)
+}
+
+// ChartShareHandler serves /chart/share
+func (h *ReportHandler) ChartShareHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		m.PermissionMiddleware(h.handleGetChartShare)(w, r)
+	case http.MethodPost:
+		m.PermissionMiddleware(h.handleCreateChartShare)(w, r)
+	case http.MethodPatch:
+		m.PermissionMiddleware(h.handleUpdateChartShare)(w, r)
+	case http.MethodDelete:
+		m.PermissionMiddleware(h.handleDeleteChartShare)(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// SharedChartHandler serves /chart/share/{token}/{chart}. It's public so
+// charts can be embedded where there's no session, the token decides whose
+// charts are drawn and which of them can be.
+func (h *ReportHandler) SharedChartHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	h.handleReadSharedChart(w, r)
+}
+
+// handleReadReport takes from and to (inclusive) dates, defaulting to the
+// last DefaultReportDays, and responds with the report as a PDF.
+func (h *ReportHandler) handleReadReport(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	from, to, ok := util.ParseDateRange(w, r, time.Now().AddDate(0, 0, -c.DefaultReportDays+1))
+	if !ok {
+		return
+	}
+
+	pdf, err := h.Service.RenderReport(userID, from, to)
+	if err != nil {
+		if errors.Is(err, ErrRangeTooLarge) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Error creating progress report", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="progress-report-%s-%s.pdf"`, from.Format("2006-01-02"), to.AddDate(0, 0, -1).Format("2006-01-02")))
+	w.Write(pdf)
+}
+
+func (h *ReportHandler) handleReadChart(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	options, ok := parseChartOptions(w, r)
+	if !ok {
+		return
+	}
+	chart, err := h.Service.RenderChart(userID, options)
+	if err != nil {
+		writeChartError(w, err)
+		return
+	}
+	writeChart(w, options.Format, "private, no-cache", chart)
+}
+
+func (h *ReportHandler) handleReadSharedChart(w http.ResponseWriter, r *http.Request) {
+	options, ok := parseChartOptions(w, r)
+	if !ok {
+		return
+	}
+	chart, err := h.Service.RenderSharedChart(r.PathValue("token"), options)
+	if err != nil {
+		writeChartError(w, err)
+		return
+	}
+	writeChart(w, options.Format, fmt.Sprintf("public, max-age=%d", c.SharedChartMaxAge), chart)
+}
+
+// parseChartOptions reads the chart and format from a name such as
+// "heatmap.png", svg when there's no extension, and takes
+//   - year, or from and to (inclusive) dates, defaulting to the last
+//     DefaultHeatmapDays for the heatmap and DefaultChartDays otherwise
+//   - active and inactive hex colours, defaulting to the calendar settings
+//   - cell for the size of a heatmap day, width and height for other charts
+//   - field, the *Size measurement a measurement chart plots
+func parseChartOptions(w http.ResponseWriter, r *http.Request) (t.ChartOptions, bool) {
+	query := r.URL.Query()
+	name, format, _ := strings.Cut(r.PathValue("chart"), ".")
+	options := t.ChartOptions{
+		Chart:          c.ChartType(name),
+		Format:         c.ChartFormat(format),
+		Field:          query.Get("field"),
+		ActiveColour:   query.Get("active"),
+		InactiveColour: query.Get("inactive"),
+	}
+	if options.Format == "" {
+		options.Format = c.ChartSVG
+	}
+
+	if year := query.Get("year"); year != "" {
+		parsed, err := strconv.Atoi(year)
+		if err != nil || parsed < 1970 || parsed > 9999 {
+			http.Error(w, "Invalid year", http.StatusBadRequest)
+			return options, false
+		}
+		options.From = time.Date(parsed, time.January, 1, 0, 0, 0, 0, time.UTC)
+		options.To = options.From.AddDate(1, 0, 0)
+	} else {
+		days := c.DefaultChartDays
+		if options.Chart == c.ChartHeatmap {
+			days = c.DefaultHeatmapDays
+		}
+		from, to, ok := util.ParseDateRange(w, r, time.Now().AddDate(0, 0, -days+1))
+		if !ok {
+			return options, false
+		}
+		options.From, options.To = from, to
+	}
+
+	for param, target := range map[string]*int{"width": &options.Width, "height": &options.Height, "cell": &options.Cell} {
+		if value := query.Get(param); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				http.Error(w, "Invalid "+param, http.StatusBadRequest)
+				return options, false
+			}
+			*target = parsed
+		}
+	}
+	return options, true
+}
+
+func writeChartError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrChartShareNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrUnknownChart), errors.Is(err, ErrInvalidChartFormat), errors.Is(err, ErrInvalidColour),
+		errors.Is(err, ErrInvalidChartSize), errors.Is(err, ErrUnknownField), errors.Is(err, ErrRangeTooLarge):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, "Error creating chart", http.StatusInternalServerError)
+	}
+}
+
+func writeChart(w http.ResponseWriter, format c.ChartFormat, cacheControl string, chart []byte) {
+	w.Header().Set("Content-Type", c.ChartContentTypes[format])
+	w.Header().Set("Cache-Control", cacheControl)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Write(chart)
+}
+
+func (h *ReportHandler) handleGetChartShare(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	share, err := h.Service.GetChartShare(userID)
+	if err != nil {
+		if errors.Is(err, ErrChartShareNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Error fetching chart share", http.StatusInternalServerError)
+		return
+	}
+	util.WriteJSON(w, http.StatusOK, share)
+}
+
+// handleCreateChartShare creates the share or regenerates its token, which
+// stops the previous links working. Only the heatmap is shared unless the
+// body lists the charts.
+func (h *ReportHandler) handleCreateChartShare(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	var request t.ChartShareRequest
+	if r.ContentLength != 0 && !util.DecodeBody(w, r, &request) {
+		return
+	}
+
+	share, err := h.Service.CreateChartShare(userID, request)
+	if err != nil {
+		if errors.Is(err, ErrUnknownChart) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Error creating chart share", http.StatusInternalServerError)
+		return
+	}
+	share.URL = util.BaseURL(r) + "/chart/share/" + share.Token
+	util.WriteJSON(w, http.StatusCreated, share)
+}
+
+func (h *ReportHandler) handleUpdateChartShare(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	var request t.ChartShareRequest
+	if !util.DecodeBody(w, r, &request) {
+		return
+	}
+
+	share, err := h.Service.UpdateChartShare(userID, request)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrUnknownChart):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, ErrChartShareNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		default:
+			http.Error(w, "Error updating chart share", http.StatusInternalServerError)
+		}
+		return
+	}
+	util.WriteJSON(w, http.StatusOK, share)
+}
+
+func (h *ReportHandler) handleDeleteChartShare(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	deleted, err := h.Service.DeleteChartShare(userID)
+	if err != nil {
+		http.Error(w, "Error deleting chart share", http.StatusInternalServerError)
+		return
+	}
+	if !deleted {
+		http.Error(w, "Chart share not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"message": "Chart share deleted successfully"}`))
+}
@@ -0,0 +1,356 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	rc "github.com/joshibbotson/gym-tracker-backend/internal/modules/record/constants"
+	rt "github.com/joshibbotson/gym-tracker-backend/internal/modules/record/types"
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/report/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/report/types"
+	uc "github.com/joshibbotson/gym-tracker-backend/internal/modules/user/constants"
+)
+
+// reportLayout flows the sections of a report down the page, starting a new
+// page when the next piece doesn't fit.
+type reportLayout struct {
+	doc   *pdfDocument
+	page  *pdfPage
+	y     float64
+	text  colour
+	muted colour
+	grid  colour
+}
+
+const (
+	contentWidth = c.PageWidth - 2*c.PageMargin
+	bodySize     = 9.0
+	rowHeight    = 15.0
+)
+
+// tableColumn is positioned from the left margin, numbers are right aligned
+// to the end of their column.
+type tableColumn struct {
+	title string
+	x     float64
+	width float64
+	right bool
+}
+
+func renderPDF(report *t.ProgressReport) []byte {
+	l := &reportLayout{
+		doc:   &pdfDocument{title: "Progress report " + formatRange(report.From, report.To)},
+		text:  parseColour(c.TextColour, c.TextColour),
+		muted: parseColour(c.MutedColour, c.MutedColour),
+		grid:  parseColour(c.GridColour, c.GridColour),
+	}
+	l.newPage()
+
+	l.page.Text(c.PageMargin, l.y+18, 20, true, l.text, "Progress report")
+	l.y += 38
+	l.page.Text(c.PageMargin, l.y, 11, false, l.muted, formatRange(report.From, report.To))
+	l.y += 18
+	l.page.Text(c.PageMargin, l.y, bodySize, false, l.text, fmt.Sprintf("%s on %d of %d days, %s.",
+		plural(report.Workouts, "workout"), report.ActiveDays, int(report.To.Sub(report.From).Hours()/24), plural(len(report.Records), "personal record")))
+	l.y += 10
+
+	l.activity(report)
+	l.weightTrend(report)
+	l.measurements(report)
+	l.muscles(report)
+	l.records(report)
+
+	for i, page := range l.doc.pages {
+		footer := fmt.Sprintf("Page %d of %d", i+1, len(l.doc.pages))
+		page.Text(c.PageWidth-c.PageMargin-textWidth(footer, 8, false), c.PageHeight-c.PageMargin/2, 8, false, l.muted, footer)
+	}
+	return l.doc.Bytes()
+}
+
+func (l *reportLayout) newPage() {
+	l.page = l.doc.NewPage()
+	l.y = c.PageMargin
+}
+
+func (l *reportLayout) ensure(height float64) {
+	if l.y+height > c.PageHeight-c.PageMargin {
+		l.newPage()
+	}
+}
+
+// heading starts a section, keeping it on the same page as the first
+// height points of what follows.
+func (l *reportLayout) heading(title string, height float64) {
+	l.y += 22
+	l.ensure(20 + height)
+	l.page.Text(c.PageMargin, l.y+12, 13, true, l.text, title)
+	l.y += 22
+}
+
+func (l *reportLayout) note(text string) {
+	l.page.Text(c.PageMargin, l.y+10, bodySize, false, l.muted, text)
+	l.y += rowHeight
+}
+
+func (l *reportLayout) activity(report *t.ProgressReport) {
+	chart := heatmap{
+		Calendar:  report.Calendar,
+		From:      report.From,
+		To:        report.To,
+		WeekStart: report.WeekStart,
+		Active:    parseColour(report.ActiveColour, uc.DefaultActiveDayColour),
+		Inactive:  parseColour(report.InactiveColour, uc.DefaultInactiveDayColour),
+		Label:     l.muted,
+		Radius:    1,
+	}
+	chart.Cell = math.Min(14, (contentWidth-heatmapLabelWidth)/float64(chart.weeks()))
+	_, height := chart.Size()
+
+	l.heading("Activity", height)
+	chart.Draw(l.page, c.PageMargin, l.y)
+	l.y += height
+}
+
+func (l *reportLayout) weightTrend(report *t.ProgressReport) {
+	trend := report.WeightTrend
+	const height = 170.0
+	if trend == nil || len(trend.Points) == 0 {
+		l.heading("Weight trend", rowHeight)
+		l.note("No weigh-ins logged in this period.")
+		return
+	}
+
+	chart := lineChart{
+		From:       report.From,
+		To:         report.To,
+		Width:      contentWidth,
+		Height:     height,
+		DotColour:  parseColour(c.WeightColour, c.WeightColour),
+		LineColour: parseColour(c.TrendColour, c.TrendColour),
+		Grid:       l.grid,
+		Label:      l.muted,
+	}
+	for _, point := range trend.Points {
+		chart.Dots = append(chart.Dots, datedValue{Date: point.Date, Value: point.Weight})
+		chart.Line = append(chart.Line, datedValue{Date: point.Date, Value: point.Trend})
+	}
+	l.heading("Weight trend", height+rowHeight)
+	chart.Draw(l.page, c.PageMargin, l.y)
+	l.y += height + 6
+
+	first, last := trend.Points[0].Trend, trend.Points[len(trend.Points)-1].Trend
+	summary := fmt.Sprintf("Trend %s to %s %s (%s %s).", formatNumber(first), formatNumber(last), report.WeightUnit, signed(round(last-first)), report.WeightUnit)
+	if trend.WeeklyRate != nil {
+		summary += fmt.Sprintf(" Currently %s, %s %s a week.", trend.Direction, signed(*trend.WeeklyRate), report.WeightUnit)
+	}
+	l.note(summary)
+}
+
+func (l *reportLayout) measurements(report *t.ProgressReport) {
+	if len(report.Measurements) == 0 {
+		l.heading("Measurements", rowHeight)
+		l.note("No measurements logged in this period.")
+		return
+	}
+
+	columns := []tableColumn{
+		{title: "Measurement", x: 0, width: 140},
+		{title: "First", x: 140, width: 60, right: true},
+		{title: "", x: 210, width: 70},
+		{title: "Last", x: 280, width: 60, right: true},
+		{title: "", x: 350, width: 70},
+		{title: "Change", x: 420, width: 60, right: true},
+	}
+	rows := [][]string{}
+	for _, change := range report.Measurements {
+		rows = append(rows, []string{
+			fieldLabel(change.Field),
+			formatNumber(change.First),
+			formatDate(change.FirstDate),
+			formatNumber(change.Last),
+			formatDate(change.LastDate),
+			signed(change.Change),
+		})
+	}
+	l.heading("Measurements", 2*rowHeight)
+	l.table(columns, rows)
+}
+
+func (l *reportLayout) muscles(report *t.ProgressReport) {
+	if len(report.MuscleCounts) == 0 {
+		l.heading("Workouts by muscle", rowHeight)
+		l.note("No target muscles logged in this period.")
+		return
+	}
+
+	const labelWidth, barHeight = 110.0, 9.0
+	most := float64(report.MuscleCounts[0].Workouts)
+	barColour := parseColour(report.ActiveColour, uc.DefaultActiveDayColour)
+
+	l.heading("Workouts by muscle", rowHeight)
+	for _, count := range report.MuscleCounts {
+		l.ensure(rowHeight)
+		l.page.Text(c.PageMargin, l.y+10, bodySize, false, l.text, titleCase(string(count.Muscle)))
+		width := (contentWidth - labelWidth - 30) * float64(count.Workouts) / most
+		l.page.Rect(c.PageMargin+labelWidth, l.y+2, width, barHeight, 1, barColour)
+		l.page.Text(c.PageMargin+labelWidth+width+5, l.y+10, bodySize, false, l.muted, strconv.Itoa(count.Workouts))
+		l.y += rowHeight
+	}
+}
+
+func (l *reportLayout) records(report *t.ProgressReport) {
+	if len(report.Records) == 0 {
+		l.heading("Personal records", rowHeight)
+		l.note("No personal records set in this period.")
+		return
+	}
+
+	columns := []tableColumn{
+		{title: "Date", x: 0, width: 70},
+		{title: "Exercise", x: 70, width: 170},
+		{title: "Record", x: 240, width: 90},
+		{title: "Value", x: 330, width: 80, right: true},
+		{title: "Previous", x: 420, width: 79, right: true},
+	}
+	rows := [][]string{}
+	for i, record := range report.Records {
+		if i == c.MaxReportRecords {
+			break
+		}
+		previous := ""
+		if record.Previous != nil {
+			previous = recordValue(rt.PersonalRecord{Type: record.Type, Value: *record.Previous, Weight: record.Weight}, report.WeightUnit)
+		}
+		rows = append(rows, []string{
+			formatDate(record.Date),
+			record.Exercise,
+			recordLabels[record.Type],
+			recordValue(record, report.WeightUnit),
+			previous,
+		})
+	}
+	l.heading("Personal records", 2*rowHeight)
+	l.table(columns, rows)
+	if extra := len(report.Records) - c.MaxReportRecords; extra > 0 {
+		l.ensure(rowHeight)
+		l.note(fmt.Sprintf("and %s more.", plural(extra, "record")))
+	}
+}
+
+// table writes a header then a row per entry, repeating the header when the
+// table runs onto another page.
+func (l *reportLayout) table(columns []tableColumn, rows [][]string) {
+	header := func() {
+		for _, column := range columns {
+			l.cell(column, column.title, true, l.muted)
+		}
+		l.y += rowHeight
+		l.page.Polyline([]point{{c.PageMargin, l.y - 3}, {c.PageMargin + contentWidth, l.y - 3}}, l.grid, 0.75)
+	}
+
+	header()
+	for _, row := range rows {
+		if l.y+rowHeight > c.PageHeight-c.PageMargin {
+			l.newPage()
+			header()
+		}
+		for i, column := range columns {
+			l.cell(column, row[i], false, l.text)
+		}
+		l.y += rowHeight
+	}
+}
+
+// cell writes text into a column, cutting it short when it doesn't fit.
+func (l *reportLayout) cell(column tableColumn, text string, bold bool, fill colour) {
+	if text == "" {
+		return
+	}
+	size := bodySize
+	if bold {
+		size = 8
+	}
+	for textWidth(text, size, bold) > column.width-4 && len(text) > 1 {
+		text = strings.TrimSpace(string([]rune(text)[:len([]rune(text))-2])) + "…"
+	}
+	x := c.PageMargin + column.x
+	if column.right {
+		x += column.width - textWidth(text, size, bold)
+	}
+	l.page.Text(x, l.y+10, size, bold, fill, text)
+}
+
+var recordLabels = map[rc.RecordType]string{
+	rc.RecordHeaviestLoad:       "Heaviest load",
+	rc.RecordEstimatedOneRepMax: "Estimated 1RM",
+	rc.RecordRepsAtLoad:         "Reps at load",
+	rc.RecordBestVolume:         "Best volume",
+}
+
+func recordValue(record rt.PersonalRecord, unit uc.WeightUnit) string {
+	if record.Type == rc.RecordRepsAtLoad {
+		value := plural(int(record.Value), "rep")
+		if record.Weight != nil {
+			value += fmt.Sprintf(" at %s %s", formatNumber(*record.Weight), unit)
+		}
+		return value
+	}
+	return fmt.Sprintf("%s %s", formatNumber(round(record.Value)), unit)
+}
+
+// fieldLabel turns a field such as "leftCalfSize" into "Left calf".
+func fieldLabel(field string) string {
+	field = strings.TrimSuffix(field, "Size")
+	var label strings.Builder
+	for i, r := range field {
+		if i > 0 && unicode.IsUpper(r) {
+			label.WriteRune(' ')
+		}
+		label.WriteRune(unicode.ToLower(r))
+	}
+	return titleCase(label.String())
+}
+
+// titleCase capitalises the first letter, "lower_back" becomes "Lower back".
+func titleCase(name string) string {
+	name = strings.ReplaceAll(name, "_", " ")
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+func formatDate(date time.Time) string {
+	return date.UTC().Format("2 Jan 2006")
+}
+
+// formatRange writes from <= date < to as the inclusive days it covers.
+func formatRange(from time.Time, to time.Time) string {
+	return formatDate(from) + " – " + formatDate(to.AddDate(0, 0, -1))
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func signed(value float64) string {
+	if value > 0 {
+		return "+" + formatNumber(value)
+	}
+	return formatNumber(value)
+}
+
+func plural(count int, noun string) string {
+	if count == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,178 @@
+package report
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strconv"
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/report/constants"
+)
+
+// pdfDocument writes a PDF without anything outside the standard library.
+// Text uses the standard Helvetica fonts every reader has, so nothing needs
+// embedding, and each page is a compressed content stream.
+type pdfDocument struct {
+	title string
+	pages []*pdfPage
+}
+
+type pdfPage struct {
+	content bytes.Buffer
+}
+
+func (d *pdfDocument) NewPage() *pdfPage {
+	page := &pdfPage{}
+	d.pages = append(d.pages, page)
+	return page
+}
+
+// pdfBezier is how far along a tangent the control points of a quarter
+// circle go, as a share of its radius.
+const pdfBezier = 0.5523
+
+func (p *pdfPage) Rect(x, y, width, height, radius float64, fill colour) {
+	p.fillColour(fill)
+	bottom := c.PageHeight - y - height
+	radius = min(radius, width/2, height/2)
+	if radius <= 0 {
+		fmt.Fprintf(&p.content, "%s %s %s %s re f\n", number(x), number(bottom), number(width), number(height))
+		return
+	}
+
+	right, top := x+width, bottom+height
+	k := radius * pdfBezier
+	fmt.Fprintf(&p.content, "%s %s m\n", number(x+radius), number(bottom))
+	fmt.Fprintf(&p.content, "%s %s l\n", number(right-radius), number(bottom))
+	fmt.Fprintf(&p.content, "%s %s %s %s %s %s c\n", number(right-radius+k), number(bottom), number(right), number(bottom+radius-k), number(right), number(bottom+radius))
+	fmt.Fprintf(&p.content, "%s %s l\n", number(right), number(top-radius))
+	fmt.Fprintf(&p.content, "%s %s %s %s %s %s c\n", number(right), number(top-radius+k), number(right-radius+k), number(top), number(right-radius), number(top))
+	fmt.Fprintf(&p.content, "%s %s l\n", number(x+radius), number(top))
+	fmt.Fprintf(&p.content, "%s %s %s %s %s %s c\n", number(x+radius-k), number(top), number(x), number(top-radius+k), number(x), number(top-radius))
+	fmt.Fprintf(&p.content, "%s %s l\n", number(x), number(bottom+radius))
+	fmt.Fprintf(&p.content, "%s %s %s %s %s %s c\n", number(x), number(bottom+radius-k), number(x+radius-k), number(bottom), number(x+radius), number(bottom))
+	p.content.WriteString("f\n")
+}
+
+func (p *pdfPage) Polyline(points []point, stroke colour, width float64) {
+	if len(points) < 2 {
+		return
+	}
+	fmt.Fprintf(&p.content, "%s %s %s RG %s w 1 J 1 j\n", channel(stroke.R), channel(stroke.G), channel(stroke.B), number(width))
+	for i, pt := range points {
+		operator := "l"
+		if i == 0 {
+			operator = "m"
+		}
+		fmt.Fprintf(&p.content, "%s %s %s\n", number(pt.X), number(c.PageHeight-pt.Y), operator)
+	}
+	p.content.WriteString("S\n")
+}
+
+func (p *pdfPage) Text(x, y, size float64, bold bool, fill colour, text string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	p.fillColour(fill)
+	fmt.Fprintf(&p.content, "BT /%s %s Tf %s %s Td (", font, number(size), number(x), number(c.PageHeight-y))
+	p.content.Write(pdfString(text))
+	p.content.WriteString(") Tj ET\n")
+}
+
+func (p *pdfPage) TextWidth(text string, size float64, bold bool) float64 {
+	return textWidth(text, size, bold)
+}
+
+func (p *pdfPage) fillColour(fill colour) {
+	fmt.Fprintf(&p.content, "%s %s %s rg\n", channel(fill.R), channel(fill.G), channel(fill.B))
+}
+
+func number(value float64) string {
+	return strconv.FormatFloat(value, 'f', 2, 64)
+}
+
+func channel(value uint8) string {
+	return strconv.FormatFloat(float64(value)/255, 'f', 3, 64)
+}
+
+// winAnsi has the characters outside Latin-1 that WinAnsiEncoding, the
+// encoding the fonts are set to, can still show.
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '•': 0x95, '–': 0x96, '—': 0x97,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '™': 0x99,
+}
+
+// pdfString encodes text for a literal string, characters the fonts can't
+// show become "?".
+func pdfString(text string) []byte {
+	encoded := []byte{}
+	for _, r := range text {
+		var b byte
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			encoded = append(encoded, '\\')
+			b = byte(r)
+		case r >= 32 && r < 127, r >= 0xA0 && r <= 0xFF:
+			b = byte(r)
+		default:
+			mapped, ok := winAnsi[r]
+			if !ok {
+				mapped = '?'
+			}
+			b = mapped
+		}
+		encoded = append(encoded, b)
+	}
+	return encoded
+}
+
+// Bytes lays out the file: the catalog, page tree, fonts and info first,
+// then each page and its content, then the cross-reference table.
+func (d *pdfDocument) Bytes() []byte {
+	var out bytes.Buffer
+	offsets := []int{}
+	object := func(body string, stream []byte) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\n", len(offsets), body)
+		if stream != nil {
+			out.WriteString("stream\n")
+			out.Write(stream)
+			out.WriteString("\nendstream\n")
+		}
+		out.WriteString("endobj\n")
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	const firstPage = 6
+	kids := ""
+	for i := range d.pages {
+		kids += fmt.Sprintf("%d 0 R ", firstPage+i*2)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>", nil)
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", kids, len(d.pages)), nil)
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>", nil)
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>", nil)
+	object(fmt.Sprintf("<< /Title (%s) /Producer (gym-tracker) /CreationDate (D:%s) >>", pdfString(d.title), time.Now().UTC().Format("20060102150405Z")), nil)
+
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			number(c.PageWidth), number(c.PageHeight), firstPage+i*2+1), nil)
+
+		var compressed bytes.Buffer
+		writer := zlib.NewWriter(&compressed)
+		writer.Write(page.content.Bytes())
+		writer.Close()
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>", compressed.Len()), compressed.Bytes())
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return out.Bytes()
+}
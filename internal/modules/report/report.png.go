@@ -0,0 +1,158 @@
+package report
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"unicode"
+)
+
+// pngCanvas rasterises onto an image. There's no font renderer in the
+// standard library so text is drawn in a small built in bitmap font.
+type pngCanvas struct {
+	image *image.RGBA
+}
+
+func newPNGCanvas(width int, height int, background colour) *pngCanvas {
+	canvas := &pngCanvas{image: image.NewRGBA(image.Rect(0, 0, width, height))}
+	canvas.Rect(0, 0, float64(width), float64(height), 0, background)
+	return canvas
+}
+
+// Rect fills the pixels whose centres fall inside the rectangle, leaving out
+// those beyond the rounded corners.
+func (p *pngCanvas) Rect(x, y, width, height, radius float64, fill colour) {
+	radius = min(radius, width/2, height/2)
+	paint := color.RGBA{R: fill.R, G: fill.G, B: fill.B, A: 255}
+	for py := int(math.Floor(y)); float64(py) < y+height; py++ {
+		for px := int(math.Floor(x)); float64(px) < x+width; px++ {
+			cx, cy := float64(px)+0.5, float64(py)+0.5
+			if cx < x || cx > x+width || cy < y || cy > y+height {
+				continue
+			}
+			// distance into the corner, past the straight edges
+			dx := math.Max(math.Max(x+radius-cx, cx-(x+width-radius)), 0)
+			dy := math.Max(math.Max(y+radius-cy, cy-(y+height-radius)), 0)
+			if dx*dx+dy*dy > radius*radius {
+				continue
+			}
+			p.image.SetRGBA(px, py, paint)
+		}
+	}
+}
+
+// Polyline stamps a dot of the stroke width every half pixel along each segment.
+func (p *pngCanvas) Polyline(points []point, stroke colour, width float64) {
+	size := math.Max(width, 1)
+	for i := 1; i < len(points); i++ {
+		from, to := points[i-1], points[i]
+		steps := int(math.Ceil(math.Hypot(to.X-from.X, to.Y-from.Y)*2)) + 1
+		for step := 0; step <= steps; step++ {
+			along := float64(step) / float64(steps)
+			x, y := from.X+(to.X-from.X)*along, from.Y+(to.Y-from.Y)*along
+			p.Rect(x-size/2, y-size/2, size, size, size/2, stroke)
+		}
+	}
+}
+
+func (p *pngCanvas) Text(x, y, size float64, bold bool, fill colour, text string) {
+	scale := glyphScale(size)
+	top := y - glyphHeight*scale
+	for _, r := range text {
+		glyph, ok := glyphs[unicode.ToUpper(r)]
+		if !ok {
+			glyph = glyphs['?']
+		}
+		for row, line := range glyph {
+			for column, pixel := range line {
+				if pixel == '#' {
+					p.Rect(x+float64(column)*scale, top+float64(row)*scale, scale, scale, 0, fill)
+				}
+			}
+		}
+		x += glyphAdvance * scale
+	}
+}
+
+func (p *pngCanvas) TextWidth(text string, size float64, bold bool) float64 {
+	scale := glyphScale(size)
+	count := len([]rune(text))
+	if count == 0 {
+		return 0
+	}
+	return (float64(count)*glyphAdvance - 1) * scale
+}
+
+func (p *pngCanvas) Encode() ([]byte, error) {
+	var out bytes.Buffer
+	if err := png.Encode(&out, p.image); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+const (
+	glyphHeight  = 5.0
+	glyphAdvance = 4.0
+)
+
+// glyphScale is how many pixels each dot of a glyph takes, so text about
+// matches the cap height of a font of size.
+func glyphScale(size float64) float64 {
+	return math.Max(1, math.Round(size*0.7/glyphHeight))
+}
+
+// glyphs is a 3x5 font, enough for the labels on charts. Letters are drawn
+// in capitals.
+var glyphs = map[rune][5]string{
+	'A': {".#.", "#.#", "###", "#.#", "#.#"},
+	'B': {"##.", "#.#", "##.", "#.#", "##."},
+	'C': {".##", "#..", "#..", "#..", ".##"},
+	'D': {"##.", "#.#", "#.#", "#.#", "##."},
+	'E': {"###", "#..", "##.", "#..", "###"},
+	'F': {"###", "#..", "##.", "#..", "#.."},
+	'G': {".##", "#..", "#.#", "#.#", ".##"},
+	'H': {"#.#", "#.#", "###", "#.#", "#.#"},
+	'I': {"###", ".#.", ".#.", ".#.", "###"},
+	'J': {"..#", "..#", "..#", "#.#", ".#."},
+	'K': {"#.#", "#.#", "##.", "#.#", "#.#"},
+	'L': {"#..", "#..", "#..", "#..", "###"},
+	'M': {"#.#", "###", "###", "#.#", "#.#"},
+	'N': {"##.", "#.#", "#.#", "#.#", "#.#"},
+	'O': {".#.", "#.#", "#.#", "#.#", ".#."},
+	'P': {"##.", "#.#", "##.", "#..", "#.."},
+	'Q': {".#.", "#.#", "#.#", "##.", ".##"},
+	'R': {"##.", "#.#", "##.", "#.#", "#.#"},
+	'S': {".##", "#..", ".#.", "..#", "##."},
+	'T': {"###", ".#.", ".#.", ".#.", ".#."},
+	'U': {"#.#", "#.#", "#.#", "#.#", "###"},
+	'V': {"#.#", "#.#", "#.#", "#.#", ".#."},
+	'W': {"#.#", "#.#", "###", "###", "#.#"},
+	'X': {"#.#", "#.#", ".#.", "#.#", "#.#"},
+	'Y': {"#.#", "#.#", ".#.", ".#.", ".#."},
+	'Z': {"###", "..#", ".#.", "#..", "###"},
+	'0': {"###", "#.#", "#.#", "#.#", "###"},
+	'1': {".#.", "##.", ".#.", ".#.", "###"},
+	'2': {"##.", "..#", ".#.", "#..", "###"},
+	'3': {"##.", "..#", ".#.", "..#", "##."},
+	'4': {"#.#", "#.#", "###", "..#", "..#"},
+	'5': {"###", "#..", "##.", "..#", "##."},
+	'6': {".##", "#..", "###", "#.#", "###"},
+	'7': {"###", "..#", ".#.", ".#.", ".#."},
+	'8': {"###", "#.#", "###", "#.#", "###"},
+	'9': {"###", "#.#", "###", "..#", "##."},
+	' ': {"...", "...", "...", "...", "..."},
+	'.': {"...", "...", "...", "...", ".#."},
+	',': {"...", "...", "...", ".#.", "#.."},
+	'-': {"...", "...", "###", "...", "..."},
+	'–': {"...", "...", "###", "...", "..."},
+	'+': {"...", ".#.", "###", ".#.", "..."},
+	':': {"...", ".#.", "...", ".#.", "..."},
+	'/': {"..#", "..#", ".#.", "#..", "#.."},
+	'%': {"#.#", "..#", ".#.", "#..", "#.#"},
+	'(': {".#.", "#..", "#..", "#..", ".#."},
+	')': {".#.", "..#", "..#", "..#", ".#."},
+	'?': {"##.", "..#", ".#.", "...", ".#."},
+}
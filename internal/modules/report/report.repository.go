@@ -0,0 +1,67 @@
+package report
+
+import (
+	"context"
+
+	db "github.com/joshibbotson/gym-tracker-backend/internal/db"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/report/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ReportRepository interface {
+	ReplaceChartShare(ctx context.Context, share t.ChartShare) (*t.ChartShare, error)
+	FetchChartShare(ctx context.Context, userID primitive.ObjectID) (*t.ChartShare, error)
+	FetchChartShareByTokenHash(ctx context.Context, tokenHash string) (*t.ChartShare, error)
+	RemoveChartShare(ctx context.Context, userID primitive.ObjectID) (bool, error)
+}
+
+type reportRepository struct {
+	chartShareCollection *mongo.Collection
+}
+
+func NewReportRepository() ReportRepository {
+	return &reportRepository{
+		chartShareCollection: db.Client.Database(db.DB_NAME).Collection("chartShare"),
+	}
+}
+
+// ReplaceChartShare saves the user's share in place of any they had, so the
+// old token stops working.
+func (r *reportRepository) ReplaceChartShare(ctx context.Context, share t.ChartShare) (*t.ChartShare, error) {
+	opts := options.Replace().SetUpsert(true)
+	if _, err := r.chartShareCollection.ReplaceOne(ctx, bson.M{"userId": share.UserId}, share, opts); err != nil {
+		return nil, err
+	}
+	return &share, nil
+}
+
+func (r *reportRepository) FetchChartShare(ctx context.Context, userID primitive.ObjectID) (*t.ChartShare, error) {
+	return r.fetchChartShare(ctx, bson.M{"userId": userID})
+}
+
+func (r *reportRepository) FetchChartShareByTokenHash(ctx context.Context, tokenHash string) (*t.ChartShare, error) {
+	return r.fetchChartShare(ctx, bson.M{"tokenHash": tokenHash})
+}
+
+func (r *reportRepository) fetchChartShare(ctx context.Context, filter bson.M) (*t.ChartShare, error) {
+	var share t.ChartShare
+	err := r.chartShareCollection.FindOne(ctx, filter).Decode(&share)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &share, nil
+}
+
+func (r *reportRepository) RemoveChartShare(ctx context.Context, userID primitive.ObjectID) (bool, error) {
+	result, err := r.chartShareCollection.DeleteOne(ctx, bson.M{"userId": userID})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
@@ -0,0 +1,190 @@
+package report
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/progress"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/record"
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/report/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/report/types"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/user"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/workout"
+	wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+	wt "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ReportService interface {
+	GetReport(userID primitive.ObjectID, from time.Time, to time.Time) (*t.ProgressReport, error)
+	RenderReport(userID primitive.ObjectID, from time.Time, to time.Time) ([]byte, error)
+	RenderChart(userID primitive.ObjectID, options t.ChartOptions) ([]byte, error)
+	RenderSharedChart(token string, options t.ChartOptions) ([]byte, error)
+	CreateChartShare(userID primitive.ObjectID, request t.ChartShareRequest) (*t.ChartShare, error)
+	UpdateChartShare(userID primitive.ObjectID, request t.ChartShareRequest) (*t.ChartShare, error)
+	GetChartShare(userID primitive.ObjectID) (*t.ChartShare, error)
+	DeleteChartShare(userID primitive.ObjectID) (bool, error)
+}
+
+var (
+	ErrRangeTooLarge      = errors.New("date range is too large")
+	ErrUnknownChart       = errors.New("unknown chart, expected heatmap, weight or measurement")
+	ErrInvalidChartFormat = errors.New("invalid format, expected svg or png")
+	ErrInvalidColour      = errors.New("colours must be hex, e.g. 216e39")
+	ErrInvalidChartSize   = errors.New("chart size is out of range")
+	ErrUnknownField       = errors.New("unknown measurement field")
+	ErrChartShareNotFound = errors.New("chart share not found")
+)
+
+type reportService struct {
+	repo            ReportRepository
+	workoutService  workout.WorkoutService
+	progressService progress.ProgressService
+	recordService   record.RecordService
+	userService     user.UserService
+}
+
+func NewReportService(repo ReportRepository, workoutService workout.WorkoutService, progressService progress.ProgressService, recordService record.RecordService, userService user.UserService) ReportService {
+	return &reportService{
+		repo:            repo,
+		workoutService:  workoutService,
+		progressService: progressService,
+		recordService:   recordService,
+		userService:     userService,
+	}
+}
+
+// GetReport gathers the report for from <= date < to.
+func (s *reportService) GetReport(userID primitive.ObjectID, from time.Time, to time.Time) (*t.ProgressReport, error) {
+	if to.Sub(from) > c.MaxReportDays*24*time.Hour {
+		return nil, ErrRangeTooLarge
+	}
+
+	settings, err := s.userService.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	calendar, err := s.workoutService.GetWorkoutsByUserId(userID, from, to, settings.WeekStart)
+	if err != nil {
+		return nil, err
+	}
+	trend, err := s.progressService.GetWeightTrend(userID, wc.AudienceOwner, from, to, 0)
+	if err != nil {
+		return nil, err
+	}
+	records, err := s.recordService.GetRecentRecords(userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &t.ProgressReport{
+		From:           from,
+		To:             to,
+		WeightUnit:     settings.WeightUnit,
+		WeekStart:      settings.WeekStart,
+		ActiveColour:   settings.ActiveDayColour,
+		InactiveColour: settings.InactiveDayColour,
+		Calendar:       calendar,
+		WeightTrend:    trend,
+		Records:        records,
+	}
+
+	workouts := []wt.DailyWorkout{}
+	activeDays := map[time.Time]bool{}
+	trained := 0
+	forEachDay(calendar, func(day wt.DailyWorkout) {
+		if day.Config == nil || day.Date.Before(from) || !day.Date.Before(to) {
+			return
+		}
+		workouts = append(workouts, day)
+		if workout.IsTraining(day.Config) {
+			trained++
+			activeDays[day.Date] = true
+		}
+	})
+	report.Workouts = trained
+	report.ActiveDays = len(activeDays)
+	report.Measurements = measurementChanges(workouts)
+	report.MuscleCounts = muscleCounts(workouts)
+	return report, nil
+}
+
+func (s *reportService) RenderReport(userID primitive.ObjectID, from time.Time, to time.Time) ([]byte, error) {
+	report, err := s.GetReport(userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return renderPDF(report), nil
+}
+
+func forEachDay(calendar []wt.YearlyData, each func(wt.DailyWorkout)) {
+	for _, year := range calendar {
+		for _, month := range year.Months {
+			for _, day := range month.Workouts {
+				each(day)
+			}
+		}
+	}
+}
+
+// sizeFields are the WorkoutConfig fields ending in Size, found by reflection
+// so measurements added later show up in the report.
+var sizeFields = func() []reflect.StructField {
+	fields := []reflect.StructField{}
+	configType := reflect.TypeOf(wt.WorkoutConfig{})
+	for i := 0; i < configType.NumField(); i++ {
+		if field := configType.Field(i); strings.HasSuffix(field.Name, "Size") {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}()
+
+// measurementChanges compares the first and last value of each size
+// measurement, workouts must be in date order.
+func measurementChanges(workouts []wt.DailyWorkout) []t.MeasurementChange {
+	changes := []t.MeasurementChange{}
+	for _, field := range sizeFields {
+		var change *t.MeasurementChange
+		for _, day := range workouts {
+			value := reflect.ValueOf(*day.Config).FieldByIndex(field.Index)
+			if value.IsNil() {
+				continue
+			}
+			if change == nil {
+				change = &t.MeasurementChange{Field: strings.Split(field.Tag.Get("json"), ",")[0], First: value.Elem().Float(), FirstDate: day.Date}
+			}
+			change.Last, change.LastDate = value.Elem().Float(), day.Date
+		}
+		if change != nil {
+			change.Change = round(change.Last - change.First)
+			changes = append(changes, *change)
+		}
+	}
+	return changes
+}
+
+// muscleCounts counts the workouts that targeted each muscle, most trained first.
+func muscleCounts(workouts []wt.DailyWorkout) []t.MuscleCount {
+	counts := map[wc.TargetMuscles]int{}
+	for _, day := range workouts {
+		for _, muscle := range day.Config.TargetMuscles {
+			counts[muscle]++
+		}
+	}
+
+	result := []t.MuscleCount{}
+	for muscle, count := range counts {
+		result = append(result, t.MuscleCount{Muscle: muscle, Workouts: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Workouts != result[j].Workouts {
+			return result[i].Workouts > result[j].Workouts
+		}
+		return result[i].Muscle < result[j].Muscle
+	})
+	return result
+}
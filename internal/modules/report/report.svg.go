@@ -0,0 +1,61 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// svgCanvas draws into an SVG document, sized in pixels.
+type svgCanvas struct {
+	width  int
+	height int
+	body   strings.Builder
+}
+
+func newSVGCanvas(width int, height int, background colour) *svgCanvas {
+	svg := &svgCanvas{width: width, height: height}
+	svg.Rect(0, 0, float64(width), float64(height), 0, background)
+	return svg
+}
+
+func (s *svgCanvas) Rect(x, y, width, height, radius float64, fill colour) {
+	fmt.Fprintf(&s.body, `<rect x="%s" y="%s" width="%s" height="%s"`, number(x), number(y), number(width), number(height))
+	if radius > 0 {
+		fmt.Fprintf(&s.body, ` rx="%s"`, number(radius))
+	}
+	fmt.Fprintf(&s.body, ` fill="%s"/>`, fill.Hex())
+}
+
+func (s *svgCanvas) Polyline(points []point, stroke colour, width float64) {
+	if len(points) < 2 {
+		return
+	}
+	coordinates := make([]string, len(points))
+	for i, p := range points {
+		coordinates[i] = number(p.X) + "," + number(p.Y)
+	}
+	fmt.Fprintf(&s.body, `<polyline points="%s" fill="none" stroke="%s" stroke-width="%s" stroke-linecap="round" stroke-linejoin="round"/>`,
+		strings.Join(coordinates, " "), stroke.Hex(), number(width))
+}
+
+func (s *svgCanvas) Text(x, y, size float64, bold bool, fill colour, text string) {
+	fmt.Fprintf(&s.body, `<text x="%s" y="%s" font-size="%s" fill="%s"`, number(x), number(y), number(size), fill.Hex())
+	if bold {
+		s.body.WriteString(` font-weight="bold"`)
+	}
+	s.body.WriteString(">")
+	xml.EscapeText(&s.body, []byte(text))
+	s.body.WriteString("</text>")
+}
+
+// TextWidth assumes Helvetica, the first choice of font in the document.
+func (s *svgCanvas) TextWidth(text string, size float64, bold bool) float64 {
+	return textWidth(text, size, bold)
+}
+
+func (s *svgCanvas) Encode() ([]byte, error) {
+	document := fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="Helvetica, Arial, sans-serif">%s</svg>`,
+		s.width, s.height, s.width, s.height, s.body.String())
+	return []byte(document), nil
+}
@@ -0,0 +1,40 @@
+package types
+
+import (
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/report/constants"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ChartOptions picks the chart to draw for from <= date < to. Colours left
+// empty come from the user's calendar settings, Field is the *Size
+// measurement a measurement chart plots.
+type ChartOptions struct {
+	Chart          c.ChartType
+	Format         c.ChartFormat
+	From           time.Time
+	To             time.Time
+	Field          string
+	ActiveColour   string
+	InactiveColour string
+	Width          int
+	Height         int
+	Cell           int
+}
+
+// ChartShare lets anyone with its token see the user's Charts. Only a hash of
+// the token is kept, it's handed out once when the share is created.
+type ChartShare struct {
+	ID        primitive.ObjectID `bson:"_id" json:"_id"`
+	UserId    primitive.ObjectID `bson:"userId" json:"-"`
+	TokenHash string             `bson:"tokenHash" json:"-"`
+	Token     string             `bson:"-" json:"token,omitempty"`
+	URL       string             `bson:"-" json:"url,omitempty"`
+	Charts    []c.ChartType      `bson:"charts" json:"charts"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+type ChartShareRequest struct {
+	Charts []c.ChartType `json:"charts"`
+}
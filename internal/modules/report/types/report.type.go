@@ -0,0 +1,46 @@
+package types
+
+import (
+	"time"
+
+	pt "github.com/joshibbotson/gym-tracker-backend/internal/modules/progress/types"
+	rt "github.com/joshibbotson/gym-tracker-backend/internal/modules/record/types"
+	uc "github.com/joshibbotson/gym-tracker-backend/internal/modules/user/constants"
+	wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+	wt "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+)
+
+// MeasurementChange compares the first and last value logged in the range
+// for one of the *Size measurements.
+type MeasurementChange struct {
+	Field     string    `json:"field"`
+	First     float64   `json:"first"`
+	FirstDate time.Time `json:"firstDate"`
+	Last      float64   `json:"last"`
+	LastDate  time.Time `json:"lastDate"`
+	Change    float64   `json:"change"`
+}
+
+type MuscleCount struct {
+	Muscle   wc.TargetMuscles `json:"muscle"`
+	Workouts int              `json:"workouts"`
+}
+
+// ProgressReport is everything a report shows for from <= date < to.
+// Calendar is padded to whole weeks, days outside the range are left out
+// of the heatmap.
+type ProgressReport struct {
+	From           time.Time           `json:"from"`
+	To             time.Time           `json:"to"`
+	WeightUnit     uc.WeightUnit       `json:"weightUnit"`
+	WeekStart      wc.WeekStart        `json:"weekStart"`
+	ActiveColour   string              `json:"activeColour"`
+	InactiveColour string              `json:"inactiveColour"`
+	Calendar       []wt.YearlyData     `json:"calendar"`
+	Workouts       int                 `json:"workouts"`
+	ActiveDays     int                 `json:"activeDays"`
+	WeightTrend    *pt.WeightTrend     `json:"weightTrend"`
+	Measurements   []MeasurementChange `json:"measurements"`
+	MuscleCounts   []MuscleCount       `json:"muscleCounts"`
+	Records        []rt.PersonalRecord `json:"records"`
+}
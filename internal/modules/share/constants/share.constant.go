@@ -0,0 +1,22 @@
+package constants
+
+type ShareKind string
+
+const (
+	ShareWorkout ShareKind = "workout"
+	ShareProfile ShareKind = "profile"
+)
+
+func (k ShareKind) Valid() bool {
+	return k == ShareWorkout || k == ShareProfile
+}
+
+// TokenBytes is how much randomness goes into a share token.
+const TokenBytes = 32
+
+// MaxShares is how many links a user can have at once, expired and revoked
+// links don't count.
+const MaxShares = 100
+
+// ProfileDays is how far back a shared profile's calendar and records go.
+const ProfileDays = 365
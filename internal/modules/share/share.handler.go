@@ -0,0 +1,139 @@
+package share
+
+import (
+	"errors"
+	"net/http"
+
+	m "github.com/joshibbotson/gym-tracker-backend/internal/middleware"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/share/types"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/workout"
+	util "github.com/joshibbotson/gym-tracker-backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ShareHandler struct {
+	Service ShareService
+}
+
+func NewShareHandler(service ShareService) *ShareHandler {
+	return &ShareHandler{
+		Service: service,
+	}
+}
+
+// Handler serves /share and /share/{id}
+func (h *ShareHandler) Handler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		m.PermissionMiddleware(h.handleReadShares)(w, r)
+	case http.MethodPost:
+		m.PermissionMiddleware(h.handleCreateShare)(w, r)
+	case http.MethodDelete:
+		if r.PathValue("id") != "" {
+			m.PermissionMiddleware(h.handleDeleteShare)(w, r)
+			break
+		}
+		m.PermissionMiddleware(h.handleDeleteShares)(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// SharedHandler serves /shared/{token}. Whoever has the link isn't signed in,
+// the token is all that identifies the share.
+func (h *ShareHandler) SharedHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	h.handleReadShared(w, r)
+}
+
+func (h *ShareHandler) handleCreateShare(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	var request t.CreateShareRequest
+	if !util.DecodeBody(w, r, &request) {
+		return
+	}
+
+	share, err := h.Service.CreateShare(userID, request)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidShare), errors.Is(err, ErrInvalidExpiry):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, workout.ErrWorkoutNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, ErrTooManyShares):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, "Error creating share", http.StatusInternalServerError)
+		}
+		return
+	}
+	share.URL = util.BaseURL(r) + "/shared/" + share.Token
+	util.WriteJSON(w, http.StatusCreated, share)
+}
+
+func (h *ShareHandler) handleReadShares(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	shares, err := h.Service.GetShares(userID)
+	if err != nil {
+		http.Error(w, "Error fetching shares", http.StatusInternalServerError)
+		return
+	}
+	util.WriteJSON(w, http.StatusOK, shares)
+}
+
+// handleDeleteShare revokes one link, it stops working straight away.
+func (h *ShareHandler) handleDeleteShare(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	shareID, ok := util.ParseObjectID(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+
+	deleted, err := h.Service.DeleteShare(userID, shareID)
+	if err != nil {
+		http.Error(w, "Error deleting share", http.StatusInternalServerError)
+		return
+	}
+	if !deleted {
+		http.Error(w, "Share not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"message": "Share deleted successfully"}`))
+}
+
+// handleDeleteShares revokes every link the user has made.
+func (h *ShareHandler) handleDeleteShares(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	if err := h.Service.DeleteShares(userID); err != nil {
+		http.Error(w, "Error deleting shares", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"message": "Shares deleted successfully"}`))
+}
+
+func (h *ShareHandler) handleReadShared(w http.ResponseWriter, r *http.Request) {
+	view, err := h.Service.GetSharedView(r.PathValue("token"))
+	if err != nil {
+		if errors.Is(err, ErrShareNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Error fetching share", http.StatusInternalServerError)
+		return
+	}
+	// not cached so a revoked link stops working straight away
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Robots-Tag", "noindex")
+	util.WriteJSON(w, http.StatusOK, view)
+}
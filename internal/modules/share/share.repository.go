@@ -0,0 +1,91 @@
+package share
+
+import (
+	"context"
+	"time"
+
+	db "github.com/joshibbotson/gym-tracker-backend/internal/db"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/share/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ShareRepository interface {
+	InsertShare(ctx context.Context, share t.Share) (*t.Share, error)
+	FetchShares(ctx context.Context, userID primitive.ObjectID) ([]t.Share, error)
+	CountShares(ctx context.Context, userID primitive.ObjectID, now time.Time) (int64, error)
+	FetchShareByTokenHash(ctx context.Context, tokenHash string) (*t.Share, error)
+	RemoveShare(ctx context.Context, userID primitive.ObjectID, shareID primitive.ObjectID) (bool, error)
+	RemoveShares(ctx context.Context, userID primitive.ObjectID) error
+}
+
+type shareRepository struct {
+	shareCollection *mongo.Collection
+}
+
+func NewShareRepository() ShareRepository {
+	return &shareRepository{
+		shareCollection: db.Client.Database(db.DB_NAME).Collection("share"),
+	}
+}
+
+func (r *shareRepository) InsertShare(ctx context.Context, share t.Share) (*t.Share, error) {
+	if _, err := r.shareCollection.InsertOne(ctx, share); err != nil {
+		return nil, err
+	}
+	return &share, nil
+}
+
+// FetchShares returns the user's shares newest first.
+func (r *shareRepository) FetchShares(ctx context.Context, userID primitive.ObjectID) ([]t.Share, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := r.shareCollection.Find(ctx, bson.M{"userId": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	shares := []t.Share{}
+	if err = cursor.All(ctx, &shares); err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+// CountShares counts the user's shares that haven't expired by now.
+func (r *shareRepository) CountShares(ctx context.Context, userID primitive.ObjectID, now time.Time) (int64, error) {
+	return r.shareCollection.CountDocuments(ctx, bson.M{
+		"userId": userID,
+		"$or": bson.A{
+			bson.M{"expiresAt": bson.M{"$exists": false}},
+			bson.M{"expiresAt": bson.M{"$gt": now}},
+		},
+	})
+}
+
+func (r *shareRepository) FetchShareByTokenHash(ctx context.Context, tokenHash string) (*t.Share, error) {
+	var share t.Share
+	err := r.shareCollection.FindOne(ctx, bson.M{"tokenHash": tokenHash}).Decode(&share)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &share, nil
+}
+
+func (r *shareRepository) RemoveShare(ctx context.Context, userID primitive.ObjectID, shareID primitive.ObjectID) (bool, error) {
+	result, err := r.shareCollection.DeleteOne(ctx, bson.M{"_id": shareID, "userId": userID})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+func (r *shareRepository) RemoveShares(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.shareCollection.DeleteMany(ctx, bson.M{"userId": userID})
+	return err
+}
@@ -0,0 +1,234 @@
+package share
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/progress"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/record"
+	rt "github.com/joshibbotson/gym-tracker-backend/internal/modules/record/types"
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/share/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/share/types"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/stats"
+	sc "github.com/joshibbotson/gym-tracker-backend/internal/modules/stats/constants"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/user"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/workout"
+	wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+	util "github.com/joshibbotson/gym-tracker-backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ShareService interface {
+	CreateShare(userID primitive.ObjectID, request t.CreateShareRequest) (*t.Share, error)
+	GetShares(userID primitive.ObjectID) ([]t.Share, error)
+	DeleteShare(userID primitive.ObjectID, shareID primitive.ObjectID) (bool, error)
+	DeleteShares(userID primitive.ObjectID) error
+	GetSharedView(token string) (*t.SharedView, error)
+}
+
+var (
+	ErrShareNotFound = errors.New("share not found")
+	ErrInvalidShare  = errors.New("kind must be workout or profile, a workout share needs a workoutId and a profile share can't have one")
+	ErrInvalidExpiry = errors.New("expiresAt must be in the future")
+	ErrTooManyShares = errors.New("too many share links, revoke some first")
+)
+
+type shareService struct {
+	repo            ShareRepository
+	workoutService  workout.WorkoutService
+	userService     user.UserService
+	statsService    stats.StatsService
+	recordService   record.RecordService
+	progressService progress.ProgressService
+}
+
+func NewShareService(repo ShareRepository, workoutService workout.WorkoutService, userService user.UserService, statsService stats.StatsService, recordService record.RecordService, progressService progress.ProgressService) ShareService {
+	return &shareService{
+		repo:            repo,
+		workoutService:  workoutService,
+		userService:     userService,
+		statsService:    statsService,
+		recordService:   recordService,
+		progressService: progressService,
+	}
+}
+
+// CreateShare makes a new link, the token is only returned here as it is
+// stored hashed.
+func (s *shareService) CreateShare(userID primitive.ObjectID, request t.CreateShareRequest) (*t.Share, error) {
+	if !request.Kind.Valid() || (request.Kind == c.ShareWorkout) != (request.WorkoutId != nil) {
+		return nil, ErrInvalidShare
+	}
+	now := time.Now()
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(now) {
+		return nil, ErrInvalidExpiry
+	}
+	if request.WorkoutId != nil {
+		shared, err := s.workoutService.GetWorkoutById(userID, *request.WorkoutId)
+		if err != nil {
+			return nil, err
+		}
+		if shared == nil {
+			return nil, workout.ErrWorkoutNotFound
+		}
+	}
+
+	count, err := s.repo.CountShares(context.TODO(), userID, now)
+	if err != nil {
+		return nil, err
+	}
+	if count >= c.MaxShares {
+		return nil, ErrTooManyShares
+	}
+
+	token, err := util.NewToken(c.TokenBytes)
+	if err != nil {
+		return nil, err
+	}
+	share, err := s.repo.InsertShare(context.TODO(), t.Share{
+		ID:         primitive.NewObjectID(),
+		UserId:     userID,
+		Kind:       request.Kind,
+		WorkoutId:  request.WorkoutId,
+		TokenHash:  util.HashToken(token),
+		Visibility: request.Visibility,
+		ExpiresAt:  request.ExpiresAt,
+		CreatedAt:  now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	share.Token = token
+	return share, nil
+}
+
+func (s *shareService) GetShares(userID primitive.ObjectID) ([]t.Share, error) {
+	return s.repo.FetchShares(context.TODO(), userID)
+}
+
+func (s *shareService) DeleteShare(userID primitive.ObjectID, shareID primitive.ObjectID) (bool, error) {
+	return s.repo.RemoveShare(context.TODO(), userID, shareID)
+}
+
+func (s *shareService) DeleteShares(userID primitive.ObjectID) error {
+	return s.repo.RemoveShares(context.TODO(), userID)
+}
+
+// GetSharedView loads what a token shows. A workout link is a grant of its
+// own, the holder sees that workout whatever its visibility, while a profile
+// link only shows what's public. Expired links, and links to workouts that
+// have since been deleted, are reported as not found.
+func (s *shareService) GetSharedView(token string) (*t.SharedView, error) {
+	share, err := s.repo.FetchShareByTokenHash(context.TODO(), util.HashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if share == nil || (share.ExpiresAt != nil && !share.ExpiresAt.After(time.Now())) {
+		return nil, ErrShareNotFound
+	}
+
+	profile, err := s.userService.GetProfile(share.UserId)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, ErrShareNotFound
+		}
+		return nil, err
+	}
+	view := &t.SharedView{
+		Kind:       share.Kind,
+		Name:       profile.Name,
+		PictureUrl: profile.PictureUrl,
+		Visibility: share.Visibility,
+		ExpiresAt:  share.ExpiresAt,
+	}
+
+	switch share.Kind {
+	case c.ShareWorkout:
+		shared, err := s.workoutService.GetWorkoutById(share.UserId, *share.WorkoutId)
+		if err != nil {
+			return nil, err
+		}
+		if shared == nil {
+			return nil, ErrShareNotFound
+		}
+		shared.Workout = workout.WithoutBodyData(shared.Workout, share.Visibility.Weight, share.Visibility.Measurements)
+		view.Workout = shared
+	case c.ShareProfile:
+		view.Profile, err = s.sharedProfile(share.UserId, share.Visibility)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return view, nil
+}
+
+// sharedProfile covers the last ProfileDays up to and including today in the
+// user's timezone. The calendar, stats, records and weight trend only come
+// from public workouts.
+func (s *shareService) sharedProfile(userID primitive.ObjectID, visibility t.ShareVisibility) (*t.SharedProfile, error) {
+	settings, err := s.userService.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	location, err := s.userService.GetLocation(userID)
+	if err != nil {
+		return nil, err
+	}
+	to := util.CalendarDay(time.Now(), location).AddDate(0, 0, 1)
+	from := to.AddDate(0, 0, -c.ProfileDays)
+
+	calendar, err := s.workoutService.GetSharedCalendar(userID, from, to, settings.WeekStart)
+	if err != nil {
+		return nil, err
+	}
+	shown := map[primitive.ObjectID]bool{}
+	for _, year := range calendar {
+		for _, month := range year.Months {
+			for i := range month.Workouts {
+				month.Workouts[i].Config = workout.WithoutBodyData(month.Workouts[i].Config, visibility.Weight, visibility.Measurements)
+				shown[month.Workouts[i].ID] = true
+			}
+		}
+	}
+	workoutStats, err := s.statsService.GetStats(userID, wc.AudiencePublic, sc.DefaultWeeks, sc.DefaultMonths)
+	if err != nil {
+		return nil, err
+	}
+	records, err := s.recordService.GetRecentRecords(userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	records = sharedRecords(records, shown)
+
+	profile := &t.SharedProfile{
+		WeightUnit:        settings.WeightUnit,
+		WeekStart:         settings.WeekStart,
+		ActiveDayColour:   settings.ActiveDayColour,
+		InactiveDayColour: settings.InactiveDayColour,
+		DayBorderRadius:   settings.DayBorderRadius,
+		Stats:             workoutStats,
+		Calendar:          calendar,
+		Records:           records,
+	}
+	if visibility.Weight {
+		profile.WeightTrend, err = s.progressService.GetWeightTrend(userID, wc.AudiencePublic, from, to, 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return profile, nil
+}
+
+// sharedRecords keeps the records set in the workouts a share shows. The
+// previous best is left out as it may have come from a workout that isn't.
+func sharedRecords(records []rt.PersonalRecord, shown map[primitive.ObjectID]bool) []rt.PersonalRecord {
+	kept := []rt.PersonalRecord{}
+	for _, record := range records {
+		if shown[record.WorkoutID] {
+			record.Previous = nil
+			kept = append(kept, record)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,228 @@
+package share
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/progress"
+	pt "github.com/joshibbotson/gym-tracker-backend/internal/modules/progress/types"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/record"
+	rt "github.com/joshibbotson/gym-tracker-backend/internal/modules/record/types"
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/share/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/share/types"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/stats"
+	st "github.com/joshibbotson/gym-tracker-backend/internal/modules/stats/types"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/user"
+	ut "github.com/joshibbotson/gym-tracker-backend/internal/modules/user/types"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/workout"
+	wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+	wt "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+	util "github.com/joshibbotson/gym-tracker-backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// the fakes embed the interfaces they stand in for, so anything a test
+// doesn't expect to be called panics
+
+type fakeShares struct {
+	ShareRepository
+	share t.Share
+}
+
+func (r *fakeShares) FetchShareByTokenHash(ctx context.Context, tokenHash string) (*t.Share, error) {
+	if tokenHash != r.share.TokenHash {
+		return nil, nil
+	}
+	return &r.share, nil
+}
+
+type fakeUsers struct {
+	user.UserService
+}
+
+func (s *fakeUsers) GetProfile(userID primitive.ObjectID) (*ut.UserProfile, error) {
+	return &ut.UserProfile{Name: "Sam"}, nil
+}
+
+func (s *fakeUsers) GetSettings(userID primitive.ObjectID) (*ut.UserSettings, error) {
+	return &ut.UserSettings{}, nil
+}
+
+func (s *fakeUsers) GetLocation(userID primitive.ObjectID) (*time.Location, error) {
+	return time.UTC, nil
+}
+
+// fakeWorkouts answers reads with the workouts the audience asked for can see.
+type fakeWorkouts struct {
+	workout.WorkoutService
+	workouts []wt.Workout
+}
+
+func (s *fakeWorkouts) visible(audience wc.Audience) []wt.Workout {
+	visibilities := audience.Visibilities()
+	shown := []wt.Workout{}
+	for _, logged := range s.workouts {
+		for _, visibility := range visibilities {
+			if logged.Visibility == visibility {
+				shown = append(shown, logged)
+			}
+		}
+		if visibilities == nil {
+			shown = append(shown, logged)
+		}
+	}
+	return shown
+}
+
+func (s *fakeWorkouts) calendar(audience wc.Audience) []wt.YearlyData {
+	month := wt.MonthlyData{Month: "3"}
+	for _, logged := range s.visible(audience) {
+		month.Workouts = append(month.Workouts, wt.DailyWorkout{ID: logged.ID, Date: logged.Date, Config: logged.Workout})
+	}
+	return []wt.YearlyData{{Year: 2026, Months: []wt.MonthlyData{month}}}
+}
+
+func (s *fakeWorkouts) GetWorkoutsByUserId(userID primitive.ObjectID, from time.Time, to time.Time, weekStart wc.WeekStart) ([]wt.YearlyData, error) {
+	return s.calendar(wc.AudienceOwner), nil
+}
+
+func (s *fakeWorkouts) GetSharedCalendar(userID primitive.ObjectID, from time.Time, to time.Time, weekStart wc.WeekStart) ([]wt.YearlyData, error) {
+	return s.calendar(wc.AudiencePublic), nil
+}
+
+func (s *fakeWorkouts) GetWorkoutById(userID primitive.ObjectID, workoutID primitive.ObjectID) (*wt.Workout, error) {
+	for _, logged := range s.workouts {
+		if logged.ID == workoutID {
+			return &logged, nil
+		}
+	}
+	return nil, nil
+}
+
+type fakeStats struct {
+	stats.StatsService
+	workouts *fakeWorkouts
+}
+
+func (s *fakeStats) GetStats(userID primitive.ObjectID, audience wc.Audience, weeks int, months int) (*st.WorkoutStats, error) {
+	return &st.WorkoutStats{TotalWorkouts: len(s.workouts.visible(audience))}, nil
+}
+
+type fakeProgress struct {
+	progress.ProgressService
+	workouts *fakeWorkouts
+}
+
+func (s *fakeProgress) GetWeightTrend(userID primitive.ObjectID, audience wc.Audience, from time.Time, to time.Time, smoothing float64) (*pt.WeightTrend, error) {
+	trend := &pt.WeightTrend{Points: []pt.TrendPoint{}}
+	for _, logged := range s.workouts.visible(audience) {
+		if logged.Workout.Weight != nil {
+			trend.Points = append(trend.Points, pt.TrendPoint{Date: logged.Date, Weight: *logged.Workout.Weight})
+		}
+	}
+	return trend, nil
+}
+
+type fakeRecords struct {
+	record.RecordService
+	records []rt.PersonalRecord
+}
+
+func (s *fakeRecords) GetRecentRecords(userID primitive.ObjectID, from time.Time, to time.Time) ([]rt.PersonalRecord, error) {
+	return s.records, nil
+}
+
+func TestSharedViewHidesPrivateWorkouts(tt *testing.T) {
+	weight := 82.0
+	privateWeight := 85.0
+	previous := 150.0
+	date := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	public := wt.Workout{
+		ID:         primitive.NewObjectID(),
+		Date:       date,
+		Visibility: wc.VisibilityPublic,
+		Workout:    &wt.WorkoutConfig{Weight: &weight, Exercises: []wt.Exercise{{Name: "Squat"}}},
+	}
+	private := wt.Workout{
+		ID:         primitive.NewObjectID(),
+		Date:       date.AddDate(0, 0, 1),
+		Visibility: wc.VisibilityPrivate,
+		Workout:    &wt.WorkoutConfig{Weight: &privateWeight, Exercises: []wt.Exercise{{Name: "Deadlift"}}},
+	}
+	workouts := &fakeWorkouts{workouts: []wt.Workout{public, private}}
+	records := &fakeRecords{records: []rt.PersonalRecord{
+		{Exercise: "Squat", Value: 160, Previous: &previous, WorkoutID: public.ID},
+		{Exercise: "Deadlift", Value: 200, Previous: &previous, WorkoutID: private.ID},
+	}}
+
+	view := func(share t.Share) (*t.SharedView, error) {
+		share.TokenHash = util.HashToken("token")
+		service := NewShareService(&fakeShares{share: share}, workouts, &fakeUsers{}, &fakeStats{workouts: workouts}, records, &fakeProgress{workouts: workouts})
+		return service.GetSharedView("token")
+	}
+
+	tt.Run("profile link", func(tt *testing.T) {
+		shared, err := view(t.Share{Kind: c.ShareProfile})
+		if err != nil {
+			tt.Fatalf("unexpected error: %v", err)
+		}
+		days := shared.Profile.Calendar[0].Months[0].Workouts
+		if len(days) != 1 || days[0].ID != public.ID {
+			tt.Fatalf("calendar shows %+v, want only the public workout", days)
+		}
+		if days[0].Config.Weight != nil {
+			tt.Fatal("body weight shown without being opted in to")
+		}
+		if shared.Profile.Stats.TotalWorkouts != 1 {
+			tt.Fatalf("stats count %d workouts, want 1", shared.Profile.Stats.TotalWorkouts)
+		}
+		if len(shared.Profile.Records) != 1 || shared.Profile.Records[0].WorkoutID != public.ID {
+			tt.Fatalf("records = %+v, want only the public workout's", shared.Profile.Records)
+		}
+		if shared.Profile.Records[0].Previous != nil {
+			tt.Fatal("previous best shown, it may come from a private workout")
+		}
+		if shared.Profile.WeightTrend != nil {
+			tt.Fatal("weight trend shown without being opted in to")
+		}
+	})
+
+	tt.Run("profile link with weight", func(tt *testing.T) {
+		shared, err := view(t.Share{Kind: c.ShareProfile, Visibility: t.ShareVisibility{Weight: true}})
+		if err != nil {
+			tt.Fatalf("unexpected error: %v", err)
+		}
+		points := shared.Profile.WeightTrend.Points
+		if len(points) != 1 || points[0].Weight != weight {
+			tt.Fatalf("weight trend = %+v, want only the public weigh-in", points)
+		}
+	})
+
+	tt.Run("workout link", func(tt *testing.T) {
+		// the link itself is the grant, the workout doesn't have to be public
+		granted, err := view(t.Share{Kind: c.ShareWorkout, WorkoutId: &private.ID})
+		if err != nil {
+			tt.Fatalf("unexpected error: %v", err)
+		}
+		if granted.Workout.ID != private.ID || granted.Workout.Workout.Weight != nil {
+			tt.Fatalf("got %+v, want the private workout without its weight", granted.Workout)
+		}
+		gone := primitive.NewObjectID()
+		if _, err := view(t.Share{Kind: c.ShareWorkout, WorkoutId: &gone}); !errors.Is(err, ErrShareNotFound) {
+			tt.Fatalf("deleted workout: got %v, want %v", err, ErrShareNotFound)
+		}
+		expired := time.Now().Add(-time.Hour)
+		if _, err := view(t.Share{Kind: c.ShareWorkout, WorkoutId: &private.ID, ExpiresAt: &expired}); !errors.Is(err, ErrShareNotFound) {
+			tt.Fatalf("expired link: got %v, want %v", err, ErrShareNotFound)
+		}
+		shared, err := view(t.Share{Kind: c.ShareWorkout, WorkoutId: &public.ID, Visibility: t.ShareVisibility{Weight: true}})
+		if err != nil {
+			tt.Fatalf("unexpected error: %v", err)
+		}
+		if shared.Workout.ID != public.ID || shared.Workout.Workout.Weight == nil {
+			tt.Fatalf("got %+v, want the public workout with its weight", shared.Workout)
+		}
+	})
+}
@@ -0,0 +1,71 @@
+package types
+
+import (
+	"time"
+
+	pt "github.com/joshibbotson/gym-tracker-backend/internal/modules/progress/types"
+	rt "github.com/joshibbotson/gym-tracker-backend/internal/modules/record/types"
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/share/constants"
+	st "github.com/joshibbotson/gym-tracker-backend/internal/modules/stats/types"
+	uc "github.com/joshibbotson/gym-tracker-backend/internal/modules/user/constants"
+	wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+	wt "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ShareVisibility opts body data in to a share, everything is hidden unless
+// asked for. Measurements covers the *Size fields and body composition.
+type ShareVisibility struct {
+	Weight       bool `bson:"weight" json:"weight"`
+	Measurements bool `bson:"measurements" json:"measurements"`
+}
+
+// Share is a read-only link to one workout or to the user's profile. Only a
+// hash of the token is kept, it's handed out once when the share is created.
+type Share struct {
+	ID         primitive.ObjectID  `bson:"_id" json:"_id"`
+	UserId     primitive.ObjectID  `bson:"userId" json:"-"`
+	Kind       c.ShareKind         `bson:"kind" json:"kind"`
+	WorkoutId  *primitive.ObjectID `bson:"workoutId,omitempty" json:"workoutId,omitempty"`
+	TokenHash  string              `bson:"tokenHash" json:"-"`
+	Token      string              `bson:"-" json:"token,omitempty"`
+	URL        string              `bson:"-" json:"url,omitempty"`
+	Visibility ShareVisibility     `bson:"visibility" json:"visibility"`
+	ExpiresAt  *time.Time          `bson:"expiresAt,omitempty" json:"expiresAt,omitempty"`
+	CreatedAt  time.Time           `bson:"createdAt" json:"createdAt"`
+}
+
+// CreateShareRequest needs a WorkoutId for a workout share. A share without
+// ExpiresAt lasts until it's revoked.
+type CreateShareRequest struct {
+	Kind       c.ShareKind         `json:"kind"`
+	WorkoutId  *primitive.ObjectID `json:"workoutId,omitempty"`
+	Visibility ShareVisibility     `json:"visibility"`
+	ExpiresAt  *time.Time          `json:"expiresAt,omitempty"`
+}
+
+// SharedProfile is what a profile link shows for the last ProfileDays. The
+// weight trend is only filled in when weight is visible.
+type SharedProfile struct {
+	WeightUnit        uc.WeightUnit       `json:"weightUnit"`
+	WeekStart         wc.WeekStart        `json:"weekStart"`
+	ActiveDayColour   string              `json:"activeDayColour"`
+	InactiveDayColour string              `json:"inactiveDayColour"`
+	DayBorderRadius   int                 `json:"dayBorderRadius"`
+	Stats             *st.WorkoutStats    `json:"stats"`
+	Calendar          []wt.YearlyData     `json:"calendar"`
+	Records           []rt.PersonalRecord `json:"records"`
+	WeightTrend       *pt.WeightTrend     `json:"weightTrend,omitempty"`
+}
+
+// SharedView is the public response for a share token, with Workout or
+// Profile set depending on the Kind.
+type SharedView struct {
+	Kind       c.ShareKind     `json:"kind"`
+	Name       string          `json:"name"`
+	PictureUrl string          `json:"pictureUrl,omitempty"`
+	Visibility ShareVisibility `json:"visibility"`
+	ExpiresAt  *time.Time      `json:"expiresAt,omitempty"`
+	Workout    *wt.Workout     `json:"workout,omitempty"`
+	Profile    *SharedProfile  `json:"profile,omitempty"`
+}
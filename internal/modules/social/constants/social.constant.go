@@ -0,0 +1,23 @@
+package constants
+
+type FollowStatus string
+
+const (
+	FollowPending  FollowStatus = "pending"
+	FollowAccepted FollowStatus = "accepted"
+)
+
+func (s FollowStatus) Valid() bool {
+	return s == FollowPending || s == FollowAccepted
+}
+
+// DefaultFeedLimit and MaxFeedLimit bound how many workouts are on a page of
+// the feed.
+const (
+	DefaultFeedLimit = 20
+	MaxFeedLimit     = 50
+)
+
+// DefaultUserWorkoutDays is how far back another user's workouts go when no
+// range is asked for.
+const DefaultUserWorkoutDays = 30
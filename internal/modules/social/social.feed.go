@@ -0,0 +1,87 @@
+package social
+
+import (
+	"context"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/social/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/social/types"
+	wt "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetFeed returns a page of the friends and public workouts of the users
+// userID follows, newest first. cursor is the previous page's NextCursor, or
+// empty for the first page.
+func (s *socialService) GetFeed(userID primitive.ObjectID, cursor string, limit int) (*t.FeedPage, error) {
+	var after *wt.FeedCursor
+	if cursor != "" {
+		decoded, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		after = decoded
+	}
+
+	follows, err := s.repo.FetchFollowing(context.TODO(), userID, c.FollowAccepted)
+	if err != nil {
+		return nil, err
+	}
+	userIDs := make([]primitive.ObjectID, len(follows))
+	for i, follow := range follows {
+		userIDs[i] = follow.FolloweeId
+	}
+
+	// one extra tells us whether there's another page
+	workouts, err := s.workoutService.GetFeedWorkouts(userIDs, after, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	page := &t.FeedPage{Items: []t.FeedItem{}}
+	if len(workouts) > limit {
+		workouts = workouts[:limit]
+		last := workouts[limit-1]
+		page.NextCursor = encodeCursor(wt.FeedCursor{Date: last.Date, ID: last.ID})
+	}
+
+	profiles, err := s.userService.GetProfiles(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, workout := range workouts {
+		if profile, ok := profiles[workout.UserId]; ok {
+			page.Items = append(page.Items, t.FeedItem{User: profile, Workout: workout})
+		}
+	}
+	return page, nil
+}
+
+// encodeCursor makes an opaque cursor from the workout's date in unix
+// milliseconds and its id.
+func encodeCursor(cursor wt.FeedCursor) string {
+	raw := strconv.FormatInt(cursor.Date.UnixMilli(), 10) + "." + cursor.ID.Hex()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string) (*wt.FeedCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+	millis, hex, ok := strings.Cut(string(raw), ".")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	unixMilli, err := strconv.ParseInt(millis, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	id, err := primitive.ObjectIDFromHex(hex)
+	if err != nil {
+		return nil, err
+	}
+	return &wt.FeedCursor{Date: time.UnixMilli(unixMilli).UTC(), ID: id}, nil
+}
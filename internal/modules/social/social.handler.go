@@ -0,0 +1,366 @@
+package social
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	m "github.com/joshibbotson/gym-tracker-backend/internal/middleware"
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/social/constants"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/user"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/workout"
+	util "github.com/joshibbotson/gym-tracker-backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type SocialHandler struct {
+	Service SocialService
+}
+
+func NewSocialHandler(service SocialService) *SocialHandler {
+	return &SocialHandler{
+		Service: service,
+	}
+}
+
+// FollowHandler serves /social/follow/{userId}
+func (h *SocialHandler) FollowHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		m.PermissionMiddleware(h.handleFollow)(w, r)
+	case http.MethodDelete:
+		m.PermissionMiddleware(h.handleUnfollow)(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// FollowersHandler serves /social/followers and /social/followers/{userId}
+func (h *SocialHandler) FollowersHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		m.PermissionMiddleware(h.handleReadFollowers)(w, r)
+	case http.MethodPatch:
+		m.PermissionMiddleware(h.handleAcceptFollower)(w, r)
+	case http.MethodDelete:
+		m.PermissionMiddleware(h.handleRemoveFollower)(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// FollowingHandler serves /social/following
+func (h *SocialHandler) FollowingHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	m.PermissionMiddleware(h.handleReadFollowing)(w, r)
+}
+
+// BlockHandler serves /social/blocks and /social/blocks/{userId}
+func (h *SocialHandler) BlockHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		m.PermissionMiddleware(h.handleReadBlocks)(w, r)
+	case http.MethodPost:
+		m.PermissionMiddleware(h.handleBlock)(w, r)
+	case http.MethodDelete:
+		m.PermissionMiddleware(h.handleUnblock)(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// FeedHandler serves /social/feed
+func (h *SocialHandler) FeedHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	m.PermissionMiddleware(h.handleReadFeed)(w, r)
+}
+
+// UserHandler serves /social/users/{userId} and /social/users/{userId}/workouts
+func (h *SocialHandler) UserHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if strings.HasSuffix(r.URL.Path, "/workouts") {
+		m.PermissionMiddleware(h.handleReadUserWorkouts)(w, r)
+		return
+	}
+	m.PermissionMiddleware(h.handleReadUser)(w, r)
+}
+
+// handleFollow follows the user, or asks to if their profile is private.
+func (h *SocialHandler) handleFollow(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	targetID, ok := util.ParseObjectID(w, r.PathValue("userId"))
+	if !ok {
+		return
+	}
+
+	follow, err := h.Service.Follow(userID, targetID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrSelf):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, user.ErrUserNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, ErrBlocked):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, "Error following user", http.StatusInternalServerError)
+		}
+		return
+	}
+	util.WriteJSON(w, http.StatusOK, follow)
+}
+
+func (h *SocialHandler) handleUnfollow(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	targetID, ok := util.ParseObjectID(w, r.PathValue("userId"))
+	if !ok {
+		return
+	}
+
+	deleted, err := h.Service.Unfollow(userID, targetID)
+	if err != nil {
+		http.Error(w, "Error unfollowing user", http.StatusInternalServerError)
+		return
+	}
+	if !deleted {
+		http.Error(w, "Follow not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"message": "Follow deleted successfully"}`))
+}
+
+// handleReadFollowers takes an optional status query param, pending to list
+// follow requests waiting to be accepted.
+func (h *SocialHandler) handleReadFollowers(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	status, ok := parseStatus(w, r)
+	if !ok {
+		return
+	}
+
+	followers, err := h.Service.GetFollowers(userID, status)
+	if err != nil {
+		http.Error(w, "Error fetching followers", http.StatusInternalServerError)
+		return
+	}
+	util.WriteJSON(w, http.StatusOK, followers)
+}
+
+func (h *SocialHandler) handleReadFollowing(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	status, ok := parseStatus(w, r)
+	if !ok {
+		return
+	}
+
+	following, err := h.Service.GetFollowing(userID, status)
+	if err != nil {
+		http.Error(w, "Error fetching follows", http.StatusInternalServerError)
+		return
+	}
+	util.WriteJSON(w, http.StatusOK, following)
+}
+
+func parseStatus(w http.ResponseWriter, r *http.Request) (c.FollowStatus, bool) {
+	status := c.FollowStatus(r.URL.Query().Get("status"))
+	if status != "" && !status.Valid() {
+		http.Error(w, "Invalid status", http.StatusBadRequest)
+		return "", false
+	}
+	return status, true
+}
+
+// handleAcceptFollower accepts a pending follow request.
+func (h *SocialHandler) handleAcceptFollower(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	followerID, ok := util.ParseObjectID(w, r.PathValue("userId"))
+	if !ok {
+		return
+	}
+
+	follow, err := h.Service.AcceptFollower(userID, followerID)
+	if err != nil {
+		if errors.Is(err, ErrFollowNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Error accepting follower", http.StatusInternalServerError)
+		return
+	}
+	util.WriteJSON(w, http.StatusOK, follow)
+}
+
+// handleRemoveFollower declines a follow request or removes a follower.
+func (h *SocialHandler) handleRemoveFollower(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	followerID, ok := util.ParseObjectID(w, r.PathValue("userId"))
+	if !ok {
+		return
+	}
+
+	deleted, err := h.Service.RemoveFollower(userID, followerID)
+	if err != nil {
+		http.Error(w, "Error removing follower", http.StatusInternalServerError)
+		return
+	}
+	if !deleted {
+		http.Error(w, "Follower not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"message": "Follower deleted successfully"}`))
+}
+
+func (h *SocialHandler) handleReadBlocks(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	blocked, err := h.Service.GetBlocked(userID)
+	if err != nil {
+		http.Error(w, "Error fetching blocks", http.StatusInternalServerError)
+		return
+	}
+	util.WriteJSON(w, http.StatusOK, blocked)
+}
+
+func (h *SocialHandler) handleBlock(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	targetID, ok := util.ParseObjectID(w, r.PathValue("userId"))
+	if !ok {
+		return
+	}
+
+	block, err := h.Service.Block(userID, targetID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrSelf):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, user.ErrUserNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		default:
+			http.Error(w, "Error blocking user", http.StatusInternalServerError)
+		}
+		return
+	}
+	util.WriteJSON(w, http.StatusOK, block)
+}
+
+func (h *SocialHandler) handleUnblock(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	targetID, ok := util.ParseObjectID(w, r.PathValue("userId"))
+	if !ok {
+		return
+	}
+
+	deleted, err := h.Service.Unblock(userID, targetID)
+	if err != nil {
+		http.Error(w, "Error unblocking user", http.StatusInternalServerError)
+		return
+	}
+	if !deleted {
+		http.Error(w, "Block not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"message": "Block deleted successfully"}`))
+}
+
+// handleReadFeed takes the nextCursor of the previous page as cursor and an
+// optional limit.
+func (h *SocialHandler) handleReadFeed(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	limit := c.DefaultFeedLimit
+	if param := r.URL.Query().Get("limit"); param != "" {
+		parsed, err := strconv.Atoi(param)
+		if err != nil || parsed < 1 || parsed > c.MaxFeedLimit {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	page, err := h.Service.GetFeed(userID, r.URL.Query().Get("cursor"), limit)
+	if err != nil {
+		if errors.Is(err, ErrInvalidCursor) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Error fetching feed", http.StatusInternalServerError)
+		return
+	}
+	util.WriteJSON(w, http.StatusOK, page)
+}
+
+func (h *SocialHandler) handleReadUser(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	targetID, ok := util.ParseObjectID(w, r.PathValue("userId"))
+	if !ok {
+		return
+	}
+
+	summary, err := h.Service.GetUserSummary(userID, targetID)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Error fetching user", http.StatusInternalServerError)
+		return
+	}
+	util.WriteJSON(w, http.StatusOK, summary)
+}
+
+// handleReadUserWorkouts takes from and to (inclusive) dates, defaulting to
+// the last DefaultUserWorkoutDays.
+func (h *SocialHandler) handleReadUserWorkouts(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	targetID, ok := util.ParseObjectID(w, r.PathValue("userId"))
+	if !ok {
+		return
+	}
+	from, to, ok := util.ParseDateRange(w, r, time.Now().AddDate(0, 0, -c.DefaultUserWorkoutDays+1))
+	if !ok {
+		return
+	}
+
+	workouts, err := h.Service.GetUserWorkouts(userID, targetID, from, to)
+	if err != nil {
+		switch {
+		case errors.Is(err, workout.ErrInvalidRange), errors.Is(err, workout.ErrRangeTooLarge):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, user.ErrUserNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, ErrProfilePrivate):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		default:
+			http.Error(w, "Error fetching workouts", http.StatusInternalServerError)
+		}
+		return
+	}
+	util.WriteJSON(w, http.StatusOK, workouts)
+}
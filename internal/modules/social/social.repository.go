@@ -0,0 +1,197 @@
+package social
+
+import (
+	"context"
+	"time"
+
+	db "github.com/joshibbotson/gym-tracker-backend/internal/db"
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/social/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/social/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SocialRepository interface {
+	UpsertFollow(ctx context.Context, follow t.Follow) (*t.Follow, error)
+	FetchFollow(ctx context.Context, followerID primitive.ObjectID, followeeID primitive.ObjectID) (*t.Follow, error)
+	AcceptFollow(ctx context.Context, followerID primitive.ObjectID, followeeID primitive.ObjectID, acceptedAt time.Time) (*t.Follow, error)
+	RemoveFollow(ctx context.Context, followerID primitive.ObjectID, followeeID primitive.ObjectID) (bool, error)
+	RemoveFollowsBetween(ctx context.Context, userID primitive.ObjectID, otherID primitive.ObjectID) error
+	FetchFollowers(ctx context.Context, userID primitive.ObjectID, status c.FollowStatus) ([]t.Follow, error)
+	FetchFollowing(ctx context.Context, userID primitive.ObjectID, status c.FollowStatus) ([]t.Follow, error)
+	CountFollowers(ctx context.Context, userID primitive.ObjectID) (int64, error)
+	CountFollowing(ctx context.Context, userID primitive.ObjectID) (int64, error)
+	UpsertBlock(ctx context.Context, block t.Block) (*t.Block, error)
+	FetchBlocksBetween(ctx context.Context, userID primitive.ObjectID, otherID primitive.ObjectID) ([]t.Block, error)
+	FetchBlocks(ctx context.Context, userID primitive.ObjectID) ([]t.Block, error)
+	RemoveBlock(ctx context.Context, blockerID primitive.ObjectID, blockedID primitive.ObjectID) (bool, error)
+}
+
+type socialRepository struct {
+	followCollection *mongo.Collection
+	blockCollection  *mongo.Collection
+}
+
+func NewSocialRepository() SocialRepository {
+	return &socialRepository{
+		followCollection: db.Client.Database(db.DB_NAME).Collection("follow"),
+		blockCollection:  db.Client.Database(db.DB_NAME).Collection("block"),
+	}
+}
+
+// UpsertFollow saves the follow unless there already is one between the
+// pair, in which case that one is returned unchanged.
+func (r *socialRepository) UpsertFollow(ctx context.Context, follow t.Follow) (*t.Follow, error) {
+	filter := bson.M{"followerId": follow.FollowerId, "followeeId": follow.FolloweeId}
+	update := bson.M{"$setOnInsert": follow}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var saved t.Follow
+	if err := r.followCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&saved); err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+func (r *socialRepository) FetchFollow(ctx context.Context, followerID primitive.ObjectID, followeeID primitive.ObjectID) (*t.Follow, error) {
+	var follow t.Follow
+	err := r.followCollection.FindOne(ctx, bson.M{"followerId": followerID, "followeeId": followeeID}).Decode(&follow)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &follow, nil
+}
+
+// AcceptFollow accepts a pending follow, returning nil if there wasn't one.
+func (r *socialRepository) AcceptFollow(ctx context.Context, followerID primitive.ObjectID, followeeID primitive.ObjectID, acceptedAt time.Time) (*t.Follow, error) {
+	filter := bson.M{"followerId": followerID, "followeeId": followeeID, "status": c.FollowPending}
+	update := bson.M{"$set": bson.M{"status": c.FollowAccepted, "acceptedAt": acceptedAt}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var follow t.Follow
+	err := r.followCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&follow)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &follow, nil
+}
+
+func (r *socialRepository) RemoveFollow(ctx context.Context, followerID primitive.ObjectID, followeeID primitive.ObjectID) (bool, error) {
+	result, err := r.followCollection.DeleteOne(ctx, bson.M{"followerId": followerID, "followeeId": followeeID})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// RemoveFollowsBetween removes the follows either way between the two users.
+func (r *socialRepository) RemoveFollowsBetween(ctx context.Context, userID primitive.ObjectID, otherID primitive.ObjectID) error {
+	_, err := r.followCollection.DeleteMany(ctx, bson.M{"$or": bson.A{
+		bson.M{"followerId": userID, "followeeId": otherID},
+		bson.M{"followerId": otherID, "followeeId": userID},
+	}})
+	return err
+}
+
+// FetchFollowers returns the follows of userID, newest first. An empty status
+// returns them all.
+func (r *socialRepository) FetchFollowers(ctx context.Context, userID primitive.ObjectID, status c.FollowStatus) ([]t.Follow, error) {
+	return r.fetchFollows(ctx, bson.M{"followeeId": userID}, status)
+}
+
+// FetchFollowing returns the follows userID has made, newest first. An empty
+// status returns them all.
+func (r *socialRepository) FetchFollowing(ctx context.Context, userID primitive.ObjectID, status c.FollowStatus) ([]t.Follow, error) {
+	return r.fetchFollows(ctx, bson.M{"followerId": userID}, status)
+}
+
+func (r *socialRepository) fetchFollows(ctx context.Context, filter bson.M, status c.FollowStatus) ([]t.Follow, error) {
+	if status != "" {
+		filter["status"] = status
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := r.followCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	follows := []t.Follow{}
+	if err = cursor.All(ctx, &follows); err != nil {
+		return nil, err
+	}
+	return follows, nil
+}
+
+// CountFollowers only counts accepted follows.
+func (r *socialRepository) CountFollowers(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	return r.followCollection.CountDocuments(ctx, bson.M{"followeeId": userID, "status": c.FollowAccepted})
+}
+
+// CountFollowing only counts accepted follows.
+func (r *socialRepository) CountFollowing(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	return r.followCollection.CountDocuments(ctx, bson.M{"followerId": userID, "status": c.FollowAccepted})
+}
+
+// UpsertBlock saves the block unless it's already there.
+func (r *socialRepository) UpsertBlock(ctx context.Context, block t.Block) (*t.Block, error) {
+	filter := bson.M{"blockerId": block.BlockerId, "blockedId": block.BlockedId}
+	update := bson.M{"$setOnInsert": block}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var saved t.Block
+	if err := r.blockCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&saved); err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+// FetchBlocksBetween returns the blocks either way between the two users.
+func (r *socialRepository) FetchBlocksBetween(ctx context.Context, userID primitive.ObjectID, otherID primitive.ObjectID) ([]t.Block, error) {
+	cursor, err := r.blockCollection.Find(ctx, bson.M{"$or": bson.A{
+		bson.M{"blockerId": userID, "blockedId": otherID},
+		bson.M{"blockerId": otherID, "blockedId": userID},
+	}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	blocks := []t.Block{}
+	if err = cursor.All(ctx, &blocks); err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+// FetchBlocks returns the users userID has blocked, newest first.
+func (r *socialRepository) FetchBlocks(ctx context.Context, userID primitive.ObjectID) ([]t.Block, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := r.blockCollection.Find(ctx, bson.M{"blockerId": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	blocks := []t.Block{}
+	if err = cursor.All(ctx, &blocks); err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+func (r *socialRepository) RemoveBlock(ctx context.Context, blockerID primitive.ObjectID, blockedID primitive.ObjectID) (bool, error) {
+	result, err := r.blockCollection.DeleteOne(ctx, bson.M{"blockerId": blockerID, "blockedId": blockedID})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
@@ -0,0 +1,294 @@
+package social
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/social/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/social/types"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/user"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/workout"
+	wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+	wt "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type SocialService interface {
+	Follow(userID primitive.ObjectID, targetID primitive.ObjectID) (*t.Follow, error)
+	Unfollow(userID primitive.ObjectID, targetID primitive.ObjectID) (bool, error)
+	AcceptFollower(userID primitive.ObjectID, followerID primitive.ObjectID) (*t.Follow, error)
+	RemoveFollower(userID primitive.ObjectID, followerID primitive.ObjectID) (bool, error)
+	GetFollowers(userID primitive.ObjectID, status c.FollowStatus) ([]t.Connection, error)
+	GetFollowing(userID primitive.ObjectID, status c.FollowStatus) ([]t.Connection, error)
+	Block(userID primitive.ObjectID, targetID primitive.ObjectID) (*t.Block, error)
+	Unblock(userID primitive.ObjectID, targetID primitive.ObjectID) (bool, error)
+	GetBlocked(userID primitive.ObjectID) ([]t.Connection, error)
+	GetUserSummary(viewerID primitive.ObjectID, userID primitive.ObjectID) (*t.UserSummary, error)
+	GetUserWorkouts(viewerID primitive.ObjectID, userID primitive.ObjectID, from time.Time, to time.Time) ([]wt.Workout, error)
+	GetAudience(viewerID primitive.ObjectID, ownerID primitive.ObjectID) (wc.Audience, error)
+	GetFeed(userID primitive.ObjectID, cursor string, limit int) (*t.FeedPage, error)
+}
+
+var (
+	ErrSelf           = errors.New("you can't follow or block yourself")
+	ErrBlocked        = errors.New("you have blocked this user, unblock them first")
+	ErrFollowNotFound = errors.New("follow request not found")
+	ErrProfilePrivate = errors.New("this profile is private, follow them to see their workouts")
+	ErrInvalidCursor  = errors.New("invalid cursor")
+)
+
+type socialService struct {
+	repo           SocialRepository
+	userService    user.UserService
+	workoutService workout.WorkoutService
+}
+
+func NewSocialService(repo SocialRepository, userService user.UserService, workoutService workout.WorkoutService) SocialService {
+	return &socialService{repo: repo, userService: userService, workoutService: workoutService}
+}
+
+// Follow follows the target straight away, or asks to if their profile is
+// private. Following someone already followed returns the existing follow.
+func (s *socialService) Follow(userID primitive.ObjectID, targetID primitive.ObjectID) (*t.Follow, error) {
+	if userID == targetID {
+		return nil, ErrSelf
+	}
+	if err := s.checkBlocks(userID, targetID); err != nil {
+		return nil, err
+	}
+	settings, err := s.userService.GetSettings(targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	follow := t.Follow{
+		ID:         primitive.NewObjectID(),
+		FollowerId: userID,
+		FolloweeId: targetID,
+		Status:     c.FollowAccepted,
+		CreatedAt:  now,
+		AcceptedAt: &now,
+	}
+	if settings.PrivateProfile {
+		follow.Status, follow.AcceptedAt = c.FollowPending, nil
+	}
+	return s.repo.UpsertFollow(context.TODO(), follow)
+}
+
+// checkBlocks makes sure the target exists and neither user has blocked the
+// other. Being blocked looks the same as the user not existing.
+func (s *socialService) checkBlocks(userID primitive.ObjectID, targetID primitive.ObjectID) error {
+	if _, err := s.userService.GetProfile(targetID); err != nil {
+		return err
+	}
+	blocks, err := s.repo.FetchBlocksBetween(context.TODO(), userID, targetID)
+	if err != nil {
+		return err
+	}
+	for _, block := range blocks {
+		if block.BlockerId == userID {
+			return ErrBlocked
+		}
+	}
+	if len(blocks) > 0 {
+		return user.ErrUserNotFound
+	}
+	return nil
+}
+
+// Unfollow also withdraws a pending request.
+func (s *socialService) Unfollow(userID primitive.ObjectID, targetID primitive.ObjectID) (bool, error) {
+	return s.repo.RemoveFollow(context.TODO(), userID, targetID)
+}
+
+func (s *socialService) AcceptFollower(userID primitive.ObjectID, followerID primitive.ObjectID) (*t.Follow, error) {
+	follow, err := s.repo.AcceptFollow(context.TODO(), followerID, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if follow == nil {
+		return nil, ErrFollowNotFound
+	}
+	return follow, nil
+}
+
+// RemoveFollower declines a request or stops an accepted follower following.
+func (s *socialService) RemoveFollower(userID primitive.ObjectID, followerID primitive.ObjectID) (bool, error) {
+	return s.repo.RemoveFollow(context.TODO(), followerID, userID)
+}
+
+func (s *socialService) GetFollowers(userID primitive.ObjectID, status c.FollowStatus) ([]t.Connection, error) {
+	follows, err := s.repo.FetchFollowers(context.TODO(), userID, status)
+	if err != nil {
+		return nil, err
+	}
+	return s.connections(follows, func(follow t.Follow) primitive.ObjectID { return follow.FollowerId })
+}
+
+func (s *socialService) GetFollowing(userID primitive.ObjectID, status c.FollowStatus) ([]t.Connection, error) {
+	follows, err := s.repo.FetchFollowing(context.TODO(), userID, status)
+	if err != nil {
+		return nil, err
+	}
+	return s.connections(follows, func(follow t.Follow) primitive.ObjectID { return follow.FolloweeId })
+}
+
+// connections looks up the other user of each follow in one query, leaving
+// out users that no longer exist.
+func (s *socialService) connections(follows []t.Follow, other func(t.Follow) primitive.ObjectID) ([]t.Connection, error) {
+	userIDs := make([]primitive.ObjectID, len(follows))
+	for i, follow := range follows {
+		userIDs[i] = other(follow)
+	}
+	profiles, err := s.userService.GetProfiles(userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	connections := []t.Connection{}
+	for _, follow := range follows {
+		if profile, ok := profiles[other(follow)]; ok {
+			connections = append(connections, t.Connection{User: profile, Status: follow.Status, Since: follow.CreatedAt})
+		}
+	}
+	return connections, nil
+}
+
+// Block stops either user following the other.
+func (s *socialService) Block(userID primitive.ObjectID, targetID primitive.ObjectID) (*t.Block, error) {
+	if userID == targetID {
+		return nil, ErrSelf
+	}
+	if _, err := s.userService.GetProfile(targetID); err != nil {
+		return nil, err
+	}
+
+	block, err := s.repo.UpsertBlock(context.TODO(), t.Block{
+		ID:        primitive.NewObjectID(),
+		BlockerId: userID,
+		BlockedId: targetID,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.RemoveFollowsBetween(context.TODO(), userID, targetID); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+// Unblock doesn't bring back the follows the block removed.
+func (s *socialService) Unblock(userID primitive.ObjectID, targetID primitive.ObjectID) (bool, error) {
+	return s.repo.RemoveBlock(context.TODO(), userID, targetID)
+}
+
+func (s *socialService) GetBlocked(userID primitive.ObjectID) ([]t.Connection, error) {
+	blocks, err := s.repo.FetchBlocks(context.TODO(), userID)
+	if err != nil {
+		return nil, err
+	}
+	userIDs := make([]primitive.ObjectID, len(blocks))
+	for i, block := range blocks {
+		userIDs[i] = block.BlockedId
+	}
+	profiles, err := s.userService.GetProfiles(userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	connections := []t.Connection{}
+	for _, block := range blocks {
+		if profile, ok := profiles[block.BlockedId]; ok {
+			connections = append(connections, t.Connection{User: profile, Since: block.CreatedAt})
+		}
+	}
+	return connections, nil
+}
+
+func (s *socialService) GetUserSummary(viewerID primitive.ObjectID, userID primitive.ObjectID) (*t.UserSummary, error) {
+	profile, err := s.userService.GetProfile(userID)
+	if err != nil {
+		return nil, err
+	}
+	summary := &t.UserSummary{User: *profile}
+
+	if viewerID != userID {
+		blocks, err := s.repo.FetchBlocksBetween(context.TODO(), viewerID, userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, block := range blocks {
+			if block.BlockerId == userID {
+				return nil, user.ErrUserNotFound
+			}
+			summary.Blocked = true
+		}
+		if follow, err := s.repo.FetchFollow(context.TODO(), viewerID, userID); err != nil {
+			return nil, err
+		} else if follow != nil {
+			summary.FollowStatus = &follow.Status
+		}
+		if follow, err := s.repo.FetchFollow(context.TODO(), userID, viewerID); err != nil {
+			return nil, err
+		} else if follow != nil {
+			summary.FollowsYou = &follow.Status
+		}
+	}
+
+	settings, err := s.userService.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	summary.PrivateProfile = settings.PrivateProfile
+	if summary.Followers, err = s.repo.CountFollowers(context.TODO(), userID); err != nil {
+		return nil, err
+	}
+	if summary.Following, err = s.repo.CountFollowing(context.TODO(), userID); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// GetUserWorkouts returns the workouts with from <= date < to the viewer is
+// allowed to see.
+func (s *socialService) GetUserWorkouts(viewerID primitive.ObjectID, userID primitive.ObjectID, from time.Time, to time.Time) ([]wt.Workout, error) {
+	audience, err := s.GetAudience(viewerID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.workoutService.GetVisibleWorkouts(userID, audience, from, to)
+}
+
+// GetAudience works out which of the owner's workouts the viewer can see:
+// all of them if they're the owner, friends and public ones if they follow
+// the owner, and otherwise public ones unless the owner's profile is private.
+func (s *socialService) GetAudience(viewerID primitive.ObjectID, ownerID primitive.ObjectID) (wc.Audience, error) {
+	if viewerID == ownerID {
+		return wc.AudienceOwner, nil
+	}
+	if err := s.checkBlocks(viewerID, ownerID); err != nil {
+		if errors.Is(err, ErrBlocked) {
+			return "", user.ErrUserNotFound
+		}
+		return "", err
+	}
+
+	follow, err := s.repo.FetchFollow(context.TODO(), viewerID, ownerID)
+	if err != nil {
+		return "", err
+	}
+	if follow != nil && follow.Status == c.FollowAccepted {
+		return wc.AudienceFollower, nil
+	}
+	settings, err := s.userService.GetSettings(ownerID)
+	if err != nil {
+		return "", err
+	}
+	if settings.PrivateProfile {
+		return "", ErrProfilePrivate
+	}
+	return wc.AudiencePublic, nil
+}
@@ -0,0 +1,60 @@
+package types
+
+import (
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/social/constants"
+	ut "github.com/joshibbotson/gym-tracker-backend/internal/modules/user/types"
+	wt "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Follow is FollowerId following FolloweeId. Following a private profile
+// starts pending until the followee accepts.
+type Follow struct {
+	ID         primitive.ObjectID `bson:"_id" json:"_id"`
+	FollowerId primitive.ObjectID `bson:"followerId" json:"followerId"`
+	FolloweeId primitive.ObjectID `bson:"followeeId" json:"followeeId"`
+	Status     c.FollowStatus     `bson:"status" json:"status"`
+	CreatedAt  time.Time          `bson:"createdAt" json:"createdAt"`
+	AcceptedAt *time.Time         `bson:"acceptedAt,omitempty" json:"acceptedAt,omitempty"`
+}
+
+// Block stops BlockedId following or seeing anything of BlockerId, and the
+// other way round.
+type Block struct {
+	ID        primitive.ObjectID `bson:"_id" json:"_id"`
+	BlockerId primitive.ObjectID `bson:"blockerId" json:"blockerId"`
+	BlockedId primitive.ObjectID `bson:"blockedId" json:"blockedId"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// Connection is another user in a list of followers, follows or blocks.
+type Connection struct {
+	User   ut.UserProfile `json:"user"`
+	Status c.FollowStatus `json:"status,omitempty"`
+	Since  time.Time      `json:"since"`
+}
+
+// UserSummary is another user as the viewer sees them. FollowStatus is the
+// viewer's follow of them and FollowsYou theirs of the viewer.
+type UserSummary struct {
+	User           ut.UserProfile  `json:"user"`
+	PrivateProfile bool            `json:"privateProfile"`
+	Followers      int64           `json:"followers"`
+	Following      int64           `json:"following"`
+	FollowStatus   *c.FollowStatus `json:"followStatus,omitempty"`
+	FollowsYou     *c.FollowStatus `json:"followsYou,omitempty"`
+	Blocked        bool            `json:"blocked"`
+}
+
+type FeedItem struct {
+	User    ut.UserProfile `json:"user"`
+	Workout wt.Workout     `json:"workout"`
+}
+
+// FeedPage is newest first, NextCursor is empty on the last page.
+type FeedPage struct {
+	Items      []FeedItem `json:"items"`
+	NextCursor string     `json:"nextCursor,omitempty"`
+}
@@ -0,0 +1,11 @@
+package constants
+
+const (
+	DefaultWeeks  = 12
+	DefaultMonths = 12
+	MaxWeeks      = 260
+	MaxMonths     = 120
+
+	// ConsistencyWindowWeeks is how many weeks the rolling consistency looks back over.
+	ConsistencyWindowWeeks = 4
+)
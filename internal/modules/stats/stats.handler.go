@@ -0,0 +1,67 @@
+package stats
+
+import (
+	"net/http"
+	"strconv"
+
+	m "github.com/joshibbotson/gym-tracker-backend/internal/middleware"
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/stats/constants"
+	wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+	util "github.com/joshibbotson/gym-tracker-backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type StatsHandler struct {
+	Service StatsService
+}
+
+func NewStatsHandler(service StatsService) *StatsHandler {
+	return &StatsHandler{
+		Service: service,
+	}
+}
+
+// Handler serves /workout/stats
+func (h *StatsHandler) Handler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	m.PermissionMiddleware(h.handleReadStats)(w, r)
+}
+
+// handleReadStats takes optional weeks and months query params for how much
+// history to return.
+func (h *StatsHandler) handleReadStats(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	weeks, ok := parseCount(w, r, "weeks", c.DefaultWeeks, c.MaxWeeks)
+	if !ok {
+		return
+	}
+	months, ok := parseCount(w, r, "months", c.DefaultMonths, c.MaxMonths)
+	if !ok {
+		return
+	}
+
+	stats, err := h.Service.GetStats(userID, wc.AudienceOwner, weeks, months)
+	if err != nil {
+		http.Error(w, "Error fetching stats", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, stats)
+}
+
+func parseCount(w http.ResponseWriter, r *http.Request, name string, fallback int, limit int) (int, bool) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return fallback, true
+	}
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 1 || count > limit {
+		http.Error(w, "Invalid "+name, http.StatusBadRequest)
+		return 0, false
+	}
+	return count, true
+}
@@ -0,0 +1,174 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/stats/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/stats/types"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/user"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/workout"
+	wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+	util "github.com/joshibbotson/gym-tracker-backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type StatsService interface {
+	GetStats(userID primitive.ObjectID, audience wc.Audience, weeks int, months int) (*t.WorkoutStats, error)
+}
+
+type statsService struct {
+	workoutService workout.WorkoutService
+	userService    user.UserService
+}
+
+func NewStatsService(workoutService workout.WorkoutService, userService user.UserService) StatsService {
+	return &statsService{workoutService: workoutService, userService: userService}
+}
+
+// GetStats works out streaks, weekly goals and monthly totals in the user's
+// timezone using their weekly target, allowed rest days and week start. Only
+// the workouts audience can see are counted.
+func (s *statsService) GetStats(userID primitive.ObjectID, audience wc.Audience, weeks int, months int) (*t.WorkoutStats, error) {
+	settings, err := s.userService.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	location, err := s.userService.GetLocation(userID)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.workoutService.GetWorkoutCountsByDate(userID, audience)
+	if err != nil {
+		return nil, err
+	}
+
+	today := util.CalendarDay(time.Now(), location)
+
+	// a workout can be saved with a timestamp, so several dates may land on one day
+	days := map[time.Time]int{}
+	total := 0
+	for _, count := range counts {
+		days[util.CalendarDay(count.Date, location)] += count.Workouts
+		total += count.Workouts
+	}
+
+	stats := &t.WorkoutStats{
+		Today:           today,
+		Timezone:        location.String(),
+		WeekStart:       settings.WeekStart,
+		TotalWorkouts:   total,
+		AllowedRestDays: settings.AllowedRestDays,
+		WeeklyTarget:    settings.WeeklyWorkoutTarget,
+	}
+	stats.CurrentStreak, stats.LongestStreak = streaks(days, today, settings.AllowedRestDays)
+	stats.Weeks = weeklyGoals(days, today, settings.WeekStart.Weekday(), weeks, settings.WeeklyWorkoutTarget)
+	stats.ThisWeek = stats.Weeks[len(stats.Weeks)-1]
+	stats.Consistency = consistency(weeklyGoals(days, today, settings.WeekStart.Weekday(), c.ConsistencyWindowWeeks+1, settings.WeeklyWorkoutTarget)[:c.ConsistencyWindowWeeks], settings.WeeklyWorkoutTarget)
+	stats.Months = monthlyTotals(days, today, months)
+
+	return stats, nil
+}
+
+// streaks splits the training days up to today into runs and returns the run
+// still going today, if any, and the longest one.
+func streaks(days map[time.Time]int, today time.Time, allowedRestDays int) (t.Streak, t.Streak) {
+	trained := make([]time.Time, 0, len(days))
+	for day := range days {
+		if !day.After(today) {
+			trained = append(trained, day)
+		}
+	}
+	sort.Slice(trained, func(i, j int) bool { return trained[i].Before(trained[j]) })
+
+	var current, longest t.Streak
+	for i, day := range trained {
+		day := day
+		if i == 0 || restDays(trained[i-1], day) > allowedRestDays {
+			current = t.Streak{Start: &day}
+		}
+		current.Length++
+		current.End = &day
+		if current.Length >= longest.Length {
+			longest = current
+		}
+	}
+
+	// today isn't a rest day until it's over
+	if current.End == nil || restDays(*current.End, today) > allowedRestDays {
+		current = t.Streak{}
+	}
+	return current, longest
+}
+
+// restDays counts the days strictly between two training days.
+func restDays(from time.Time, to time.Time) int {
+	return int(math.Round(to.Sub(from).Hours()/24)) - 1
+}
+
+// weeklyGoals returns the last count weeks, oldest first and ending with the
+// week containing today.
+func weeklyGoals(days map[time.Time]int, today time.Time, weekStart time.Weekday, count int, target int) []t.WeeklyGoal {
+	offset := (int(today.Weekday()) - int(weekStart) + 7) % 7
+	thisWeek := today.AddDate(0, 0, -offset)
+
+	goals := make([]t.WeeklyGoal, 0, count)
+	for i := count - 1; i >= 0; i-- {
+		start := thisWeek.AddDate(0, 0, -7*i)
+		goal := t.WeeklyGoal{WeekStart: start, Target: target}
+		for d := 0; d < 7; d++ {
+			if workouts := days[start.AddDate(0, 0, d)]; workouts > 0 {
+				goal.Workouts += workouts
+				goal.TrainingDays++
+			}
+		}
+		goal.Met = goal.Workouts >= target
+		goals = append(goals, goal)
+	}
+
+	for i := range goals {
+		window := goals[max(0, i-c.ConsistencyWindowWeeks+1) : i+1]
+		goals[i].Consistency = consistency(window, target)
+	}
+	return goals
+}
+
+// consistency is the share of the weekly target hit across weeks, each week
+// capped at its target so one big week can't make up for a missed one. There
+// is nothing to measure against without a target.
+func consistency(weeks []t.WeeklyGoal, target int) *float64 {
+	if target <= 0 || len(weeks) == 0 {
+		return nil
+	}
+	hit := 0
+	for _, week := range weeks {
+		hit += min(week.Workouts, target)
+	}
+	percent := math.Round(float64(hit)/float64(target*len(weeks))*1000) / 10
+	return &percent
+}
+
+// monthlyTotals returns the last count months, oldest first and ending with
+// the month containing today.
+func monthlyTotals(days map[time.Time]int, today time.Time, count int) []t.MonthlyTotal {
+	thisMonth := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	index := map[string]int{}
+	totals := make([]t.MonthlyTotal, 0, count)
+	for i := count - 1; i >= 0; i-- {
+		month := thisMonth.AddDate(0, -i, 0)
+		key := fmt.Sprintf("%d-%02d", month.Year(), int(month.Month()))
+		index[key] = len(totals)
+		totals = append(totals, t.MonthlyTotal{Month: key})
+	}
+
+	for day, workouts := range days {
+		key := fmt.Sprintf("%d-%02d", day.Year(), int(day.Month()))
+		if i, ok := index[key]; ok && workouts > 0 {
+			totals[i].Workouts += workouts
+			totals[i].TrainingDays++
+		}
+	}
+	return totals
+}
@@ -0,0 +1,87 @@
+package stats
+
+import (
+	"testing"
+	"time"
+)
+
+func day(d int) time.Time {
+	return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC)
+}
+
+func trainedOn(days ...int) map[time.Time]int {
+	trained := map[time.Time]int{}
+	for _, d := range days {
+		trained[day(d)]++
+	}
+	return trained
+}
+
+func TestStreaks(tt *testing.T) {
+	tests := []struct {
+		name        string
+		days        map[time.Time]int
+		today       int
+		rest        int
+		wantCurrent int
+		wantLongest int
+	}{
+		{"no training", trainedOn(), 10, 0, 0, 0},
+		{"trained today", trainedOn(8, 9, 10), 10, 0, 3, 3},
+		{"today is still open", trainedOn(8, 9), 10, 0, 2, 2},
+		{"missed yesterday", trainedOn(7, 8), 10, 0, 0, 2},
+		{"rest day used up", trainedOn(7, 8), 11, 1, 0, 2},
+		{"rest days bridge gaps", trainedOn(1, 3, 5, 7), 8, 1, 4, 4},
+		{"too long a rest breaks the run", trainedOn(1, 2, 3, 7, 8), 8, 2, 2, 3},
+		{"days after today are ignored", trainedOn(9, 10, 11, 12), 10, 0, 2, 2},
+		{"two workouts in a day count once", map[time.Time]int{day(9): 2, day(10): 1}, 10, 0, 2, 2},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			current, longest := streaks(test.days, day(test.today), test.rest)
+			if current.Length != test.wantCurrent || longest.Length != test.wantLongest {
+				tt.Fatalf("got current %d longest %d, want %d and %d", current.Length, longest.Length, test.wantCurrent, test.wantLongest)
+			}
+		})
+	}
+}
+
+func TestStreaksAcrossDST(tt *testing.T) {
+	// days are midnight UTC so a clock change can't stretch a gap
+	current, _ := streaks(map[time.Time]int{day(28): 1, day(29): 1, day(30): 1}, day(30), 0)
+	if current.Length != 3 || !current.Start.Equal(day(28)) || !current.End.Equal(day(30)) {
+		tt.Fatalf("got %+v, want 3 days from the 28th", current)
+	}
+}
+
+func TestConsistency(tt *testing.T) {
+	tests := []struct {
+		name     string
+		days     map[time.Time]int
+		target   int
+		want     float64
+		disabled bool
+	}{
+		{"every week met", trainedOn(2, 4, 9, 11, 16, 18, 23, 25), 2, 100, false},
+		{"a big week doesn't cover a missed one", trainedOn(2, 3, 4, 5, 16, 18, 23, 25), 2, 75, false},
+		{"no target", trainedOn(2), 0, 0, true},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			// the 29th is a Sunday, so four full Monday weeks end on it
+			weeks := weeklyGoals(test.days, day(29), time.Monday, 4, test.target)
+			got := consistency(weeks, test.target)
+			if test.disabled {
+				if got != nil {
+					tt.Fatalf("got %v, want nil", *got)
+				}
+				return
+			}
+			if got == nil || *got != test.want {
+				tt.Fatalf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package types
+
+import (
+	"time"
+
+	wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+)
+
+// Streak counts the training days in a run where no gap between them was
+// longer than the allowed rest days.
+type Streak struct {
+	Length int        `json:"length"`
+	Start  *time.Time `json:"start,omitempty"`
+	End    *time.Time `json:"end,omitempty"`
+}
+
+type WeeklyGoal struct {
+	WeekStart    time.Time `json:"weekStart"`
+	Workouts     int       `json:"workouts"`
+	TrainingDays int       `json:"trainingDays"`
+	Target       int       `json:"target"`
+	Met          bool      `json:"met"`
+	// Consistency is the rolling percentage of the target hit over the
+	// window of weeks ending with this one.
+	Consistency *float64 `json:"consistency,omitempty"`
+}
+
+type MonthlyTotal struct {
+	Month        string `json:"month"`
+	Workouts     int    `json:"workouts"`
+	TrainingDays int    `json:"trainingDays"`
+}
+
+type WorkoutStats struct {
+	Today           time.Time      `json:"today"`
+	Timezone        string         `json:"timezone"`
+	WeekStart       wc.WeekStart   `json:"weekStart"`
+	TotalWorkouts   int            `json:"totalWorkouts"`
+	AllowedRestDays int            `json:"allowedRestDays"`
+	CurrentStreak   Streak         `json:"currentStreak"`
+	LongestStreak   Streak         `json:"longestStreak"`
+	WeeklyTarget    int            `json:"weeklyTarget"`
+	ThisWeek        WeeklyGoal     `json:"thisWeek"`
+	Consistency     *float64       `json:"consistency,omitempty"`
+	Weeks           []WeeklyGoal   `json:"weeks"`
+	Months          []MonthlyTotal `json:"months"`
+}
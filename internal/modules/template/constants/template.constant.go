@@ -0,0 +1,8 @@
+package constants
+
+// MaxTargetSets and MaxTargetReps bound a template exercise, applying a
+// template makes a set for every target set.
+const (
+	MaxTargetSets = 50
+	MaxTargetReps = 1000
+)
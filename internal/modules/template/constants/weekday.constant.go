@@ -0,0 +1,38 @@
+package constants
+
+import "time"
+
+type Weekday string
+
+const (
+	Monday    Weekday = "monday"
+	Tuesday   Weekday = "tuesday"
+	Wednesday Weekday = "wednesday"
+	Thursday  Weekday = "thursday"
+	Friday    Weekday = "friday"
+	Saturday  Weekday = "saturday"
+	Sunday    Weekday = "sunday"
+)
+
+var weekdays = map[time.Weekday]Weekday{
+	time.Monday:    Monday,
+	time.Tuesday:   Tuesday,
+	time.Wednesday: Wednesday,
+	time.Thursday:  Thursday,
+	time.Friday:    Friday,
+	time.Saturday:  Saturday,
+	time.Sunday:    Sunday,
+}
+
+func WeekdayOf(day time.Weekday) Weekday {
+	return weekdays[day]
+}
+
+func (d Weekday) Valid() bool {
+	for _, weekday := range weekdays {
+		if weekday == d {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,362 @@
+package template
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	m "github.com/joshibbotson/gym-tracker-backend/internal/middleware"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/template/types"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/workout"
+	util "github.com/joshibbotson/gym-tracker-backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TemplateHandler struct {
+	Service TemplateService
+}
+
+func NewTemplateHandler(service TemplateService) *TemplateHandler {
+	return &TemplateHandler{
+		Service: service,
+	}
+}
+
+// Handler serves /template, /template/{id}, /template/apply/{id} and /template/save/{workoutId}
+func (h *TemplateHandler) Handler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		if r.PathValue("workoutId") != "" {
+			m.PermissionMiddleware(h.handleSaveWorkoutAsTemplate)(w, r)
+			break
+		}
+		if r.PathValue("id") != "" {
+			m.PermissionMiddleware(h.handleApplyTemplate)(w, r)
+			break
+		}
+		m.PermissionMiddleware(h.handleCreateTemplate)(w, r)
+	case http.MethodGet:
+		if r.PathValue("id") != "" {
+			m.PermissionMiddleware(h.handleReadTemplate)(w, r)
+			break
+		}
+		m.PermissionMiddleware(h.handleReadTemplates)(w, r)
+	case http.MethodPatch:
+		m.PermissionMiddleware(h.handleUpdateTemplate)(w, r)
+	case http.MethodDelete:
+		m.PermissionMiddleware(h.handleDeleteTemplate)(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// RoutineHandler serves /routine, /routine/{id} and /routine/today
+func (h *TemplateHandler) RoutineHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		m.PermissionMiddleware(h.handleCreateRoutine)(w, r)
+	case http.MethodGet:
+		if r.URL.Path == "/routine/today" {
+			m.PermissionMiddleware(h.handleReadScheduledToday)(w, r)
+			break
+		}
+		if r.PathValue("id") != "" {
+			m.PermissionMiddleware(h.handleReadRoutine)(w, r)
+			break
+		}
+		m.PermissionMiddleware(h.handleReadRoutines)(w, r)
+	case http.MethodPatch:
+		m.PermissionMiddleware(h.handleUpdateRoutine)(w, r)
+	case http.MethodDelete:
+		m.PermissionMiddleware(h.handleDeleteRoutine)(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *TemplateHandler) handleCreateTemplate(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	var request t.TemplateRequest
+	if !util.DecodeBody(w, r, &request) {
+		return
+	}
+
+	template, err := h.Service.CreateTemplate(userID, request)
+	if errors.Is(err, ErrInvalidTemplate) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error creating template", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusCreated, template)
+}
+
+func (h *TemplateHandler) handleReadTemplates(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	templates, err := h.Service.GetTemplates(userID)
+	if err != nil {
+		http.Error(w, "Error fetching templates", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, templates)
+}
+
+func (h *TemplateHandler) handleReadTemplate(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+	templateID, ok := util.ParseObjectID(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+
+	template, err := h.Service.GetTemplateById(userID, templateID)
+	if errors.Is(err, ErrTemplateNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error fetching template", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, template)
+}
+
+func (h *TemplateHandler) handleUpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+	templateID, ok := util.ParseObjectID(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+
+	var request t.TemplateRequest
+	if !util.DecodeBody(w, r, &request) {
+		return
+	}
+
+	template, err := h.Service.UpdateTemplate(userID, templateID, request)
+	if errors.Is(err, ErrInvalidTemplate) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, ErrTemplateNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error updating template", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, template)
+}
+
+func (h *TemplateHandler) handleDeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+	templateID, ok := util.ParseObjectID(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+
+	success, err := h.Service.DeleteTemplate(userID, templateID)
+	if err != nil {
+		http.Error(w, "Error deleting template", http.StatusInternalServerError)
+		return
+	}
+	if !success {
+		http.Error(w, "Template not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"message": "Template deleted successfully"}`))
+}
+
+func (h *TemplateHandler) handleApplyTemplate(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+	templateID, ok := util.ParseObjectID(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+
+	var request t.ApplyTemplateRequest
+	if !util.DecodeBody(w, r, &request) {
+		return
+	}
+	if request.Date.IsZero() {
+		http.Error(w, "Date is required", http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.Service.CreateWorkoutFromTemplate(userID, templateID, request.Date)
+	if errors.Is(err, ErrTemplateNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, ErrInvalidTemplate) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error creating workout", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusCreated, created)
+}
+
+func (h *TemplateHandler) handleSaveWorkoutAsTemplate(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+	workoutID, ok := util.ParseObjectID(w, r.PathValue("workoutId"))
+	if !ok {
+		return
+	}
+
+	var request t.SaveAsTemplateRequest
+	if !util.DecodeBody(w, r, &request) {
+		return
+	}
+
+	template, err := h.Service.SaveWorkoutAsTemplate(userID, workoutID, request.Name)
+	if errors.Is(err, workout.ErrWorkoutNotFound) {
+		http.Error(w, "Workout not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, ErrInvalidTemplate) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error saving template", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusCreated, template)
+}
+
+func (h *TemplateHandler) handleCreateRoutine(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	var request t.RoutineRequest
+	if !util.DecodeBody(w, r, &request) {
+		return
+	}
+
+	routine, err := h.Service.CreateRoutine(userID, request)
+	if errors.Is(err, ErrInvalidRoutine) || errors.Is(err, ErrTemplateNotFound) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error creating routine", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusCreated, routine)
+}
+
+func (h *TemplateHandler) handleReadRoutines(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	routines, err := h.Service.GetRoutines(userID)
+	if err != nil {
+		http.Error(w, "Error fetching routines", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, routines)
+}
+
+func (h *TemplateHandler) handleReadRoutine(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+	routineID, ok := util.ParseObjectID(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+
+	routine, err := h.Service.GetRoutineById(userID, routineID)
+	if errors.Is(err, ErrRoutineNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error fetching routine", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, routine)
+}
+
+func (h *TemplateHandler) handleUpdateRoutine(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+	routineID, ok := util.ParseObjectID(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+
+	var request t.RoutineRequest
+	if !util.DecodeBody(w, r, &request) {
+		return
+	}
+
+	routine, err := h.Service.UpdateRoutine(userID, routineID, request)
+	if errors.Is(err, ErrInvalidRoutine) || errors.Is(err, ErrTemplateNotFound) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, ErrRoutineNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error updating routine", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, routine)
+}
+
+func (h *TemplateHandler) handleDeleteRoutine(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+	routineID, ok := util.ParseObjectID(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+
+	success, err := h.Service.DeleteRoutine(userID, routineID)
+	if err != nil {
+		http.Error(w, "Error deleting routine", http.StatusInternalServerError)
+		return
+	}
+	if !success {
+		http.Error(w, "Routine not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"message": "Routine deleted successfully"}`))
+}
+
+// handleReadScheduledToday accepts an optional tz query param (IANA name) so
+// "today" matches the user's calendar day rather than the server's.
+func (h *TemplateHandler) handleReadScheduledToday(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	location, ok := util.ParseTimezone(w, r)
+	if !ok {
+		return
+	}
+
+	session, err := h.Service.GetScheduledSession(userID, time.Now().In(location))
+	if err != nil {
+		http.Error(w, "Error fetching scheduled session", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, session)
+}
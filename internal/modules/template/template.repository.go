@@ -0,0 +1,162 @@
+package template
+
+import (
+	"context"
+
+	db "github.com/joshibbotson/gym-tracker-backend/internal/db"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/template/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type TemplateRepository interface {
+	InsertTemplate(ctx context.Context, template t.Template) (*t.Template, error)
+	FetchTemplates(ctx context.Context, userID primitive.ObjectID) ([]t.Template, error)
+	FetchTemplateById(ctx context.Context, userID primitive.ObjectID, templateID primitive.ObjectID) (*t.Template, error)
+	UpdateTemplate(ctx context.Context, template t.Template) (*t.Template, error)
+	RemoveTemplate(ctx context.Context, userID primitive.ObjectID, templateID primitive.ObjectID) (bool, error)
+	InsertRoutine(ctx context.Context, routine t.Routine) (*t.Routine, error)
+	FetchRoutines(ctx context.Context, userID primitive.ObjectID) ([]t.Routine, error)
+	FetchRoutineById(ctx context.Context, userID primitive.ObjectID, routineID primitive.ObjectID) (*t.Routine, error)
+	FetchActiveRoutine(ctx context.Context, userID primitive.ObjectID) (*t.Routine, error)
+	UpdateRoutine(ctx context.Context, routine t.Routine) (*t.Routine, error)
+	DeactivateRoutines(ctx context.Context, userID primitive.ObjectID, except primitive.ObjectID) error
+	RemoveRoutine(ctx context.Context, userID primitive.ObjectID, routineID primitive.ObjectID) (bool, error)
+}
+
+type templateRepository struct {
+	templateCollection *mongo.Collection
+	routineCollection  *mongo.Collection
+}
+
+func NewTemplateRepository() TemplateRepository {
+	return &templateRepository{
+		templateCollection: db.Client.Database(db.DB_NAME).Collection("template"),
+		routineCollection:  db.Client.Database(db.DB_NAME).Collection("routine"),
+	}
+}
+
+func (r *templateRepository) InsertTemplate(ctx context.Context, template t.Template) (*t.Template, error) {
+	_, err := r.templateCollection.InsertOne(ctx, template)
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *templateRepository) FetchTemplates(ctx context.Context, userID primitive.ObjectID) ([]t.Template, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := r.templateCollection.Find(ctx, bson.M{"userId": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	templates := []t.Template{}
+	if err = cursor.All(ctx, &templates); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (r *templateRepository) FetchTemplateById(ctx context.Context, userID primitive.ObjectID, templateID primitive.ObjectID) (*t.Template, error) {
+	var template t.Template
+	err := r.templateCollection.FindOne(ctx, bson.M{"_id": templateID, "userId": userID}).Decode(&template)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *templateRepository) UpdateTemplate(ctx context.Context, template t.Template) (*t.Template, error) {
+	_, err := r.templateCollection.UpdateOne(ctx, bson.M{"_id": template.ID, "userId": template.UserId}, bson.M{"$set": template})
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *templateRepository) RemoveTemplate(ctx context.Context, userID primitive.ObjectID, templateID primitive.ObjectID) (bool, error) {
+	res, err := r.templateCollection.DeleteOne(ctx, bson.M{"_id": templateID, "userId": userID})
+	if err != nil {
+		return false, err
+	}
+	return res.DeletedCount > 0, nil
+}
+
+func (r *templateRepository) InsertRoutine(ctx context.Context, routine t.Routine) (*t.Routine, error) {
+	_, err := r.routineCollection.InsertOne(ctx, routine)
+	if err != nil {
+		return nil, err
+	}
+	return &routine, nil
+}
+
+func (r *templateRepository) FetchRoutines(ctx context.Context, userID primitive.ObjectID) ([]t.Routine, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := r.routineCollection.Find(ctx, bson.M{"userId": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	routines := []t.Routine{}
+	if err = cursor.All(ctx, &routines); err != nil {
+		return nil, err
+	}
+	return routines, nil
+}
+
+func (r *templateRepository) FetchRoutineById(ctx context.Context, userID primitive.ObjectID, routineID primitive.ObjectID) (*t.Routine, error) {
+	var routine t.Routine
+	err := r.routineCollection.FindOne(ctx, bson.M{"_id": routineID, "userId": userID}).Decode(&routine)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &routine, nil
+}
+
+func (r *templateRepository) FetchActiveRoutine(ctx context.Context, userID primitive.ObjectID) (*t.Routine, error) {
+	var routine t.Routine
+	err := r.routineCollection.FindOne(ctx, bson.M{"userId": userID, "active": true}).Decode(&routine)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &routine, nil
+}
+
+func (r *templateRepository) UpdateRoutine(ctx context.Context, routine t.Routine) (*t.Routine, error) {
+	_, err := r.routineCollection.UpdateOne(ctx, bson.M{"_id": routine.ID, "userId": routine.UserId}, bson.M{"$set": routine})
+	if err != nil {
+		return nil, err
+	}
+	return &routine, nil
+}
+
+// DeactivateRoutines makes sure only one routine is active at a time.
+func (r *templateRepository) DeactivateRoutines(ctx context.Context, userID primitive.ObjectID, except primitive.ObjectID) error {
+	_, err := r.routineCollection.UpdateMany(ctx,
+		bson.M{"userId": userID, "_id": bson.M{"$ne": except}, "active": true},
+		bson.M{"$set": bson.M{"active": false}},
+	)
+	return err
+}
+
+func (r *templateRepository) RemoveRoutine(ctx context.Context, userID primitive.ObjectID, routineID primitive.ObjectID) (bool, error) {
+	res, err := r.routineCollection.DeleteOne(ctx, bson.M{"_id": routineID, "userId": userID})
+	if err != nil {
+		return false, err
+	}
+	return res.DeletedCount > 0, nil
+}
@@ -0,0 +1,344 @@
+package template
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/template/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/template/types"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/workout"
+	wt "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TemplateService interface {
+	CreateTemplate(userID primitive.ObjectID, template t.TemplateRequest) (*t.Template, error)
+	GetTemplates(userID primitive.ObjectID) ([]t.Template, error)
+	GetTemplateById(userID primitive.ObjectID, templateID primitive.ObjectID) (*t.Template, error)
+	UpdateTemplate(userID primitive.ObjectID, templateID primitive.ObjectID, template t.TemplateRequest) (*t.Template, error)
+	DeleteTemplate(userID primitive.ObjectID, templateID primitive.ObjectID) (bool, error)
+	CreateWorkoutFromTemplate(userID primitive.ObjectID, templateID primitive.ObjectID, date time.Time) (*wt.Workout, error)
+	SaveWorkoutAsTemplate(userID primitive.ObjectID, workoutID primitive.ObjectID, name string) (*t.Template, error)
+	CreateRoutine(userID primitive.ObjectID, routine t.RoutineRequest) (*t.Routine, error)
+	GetRoutines(userID primitive.ObjectID) ([]t.Routine, error)
+	GetRoutineById(userID primitive.ObjectID, routineID primitive.ObjectID) (*t.Routine, error)
+	UpdateRoutine(userID primitive.ObjectID, routineID primitive.ObjectID, routine t.RoutineRequest) (*t.Routine, error)
+	DeleteRoutine(userID primitive.ObjectID, routineID primitive.ObjectID) (bool, error)
+	GetScheduledSession(userID primitive.ObjectID, date time.Time) (*t.ScheduledSession, error)
+	GetSchedule(userID primitive.ObjectID, from time.Time, days int) ([]t.ScheduledSession, error)
+}
+
+var (
+	ErrTemplateNotFound = errors.New("template not found")
+	ErrRoutineNotFound  = errors.New("routine not found")
+	ErrInvalidTemplate  = fmt.Errorf("template needs a name and exercises with a name, 1 to %d sets and 1 to %d reps", c.MaxTargetSets, c.MaxTargetReps)
+	ErrInvalidRoutine   = errors.New("routine needs a name and one template per valid weekday")
+)
+
+type templateService struct {
+	repo           TemplateRepository
+	workoutService workout.WorkoutService
+}
+
+func NewTemplateService(repo TemplateRepository, workoutService workout.WorkoutService) TemplateService {
+	return &templateService{repo: repo, workoutService: workoutService}
+}
+
+func (s *templateService) CreateTemplate(userID primitive.ObjectID, template t.TemplateRequest) (*t.Template, error) {
+	if !validTemplate(template) {
+		return nil, ErrInvalidTemplate
+	}
+
+	newTemplate := t.Template{
+		ID:            primitive.NewObjectID(),
+		UserId:        userID,
+		Name:          strings.TrimSpace(template.Name),
+		TargetMuscles: template.TargetMuscles,
+		Exercises:     template.Exercises,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+
+	return s.repo.InsertTemplate(context.TODO(), newTemplate)
+}
+
+func (s *templateService) GetTemplates(userID primitive.ObjectID) ([]t.Template, error) {
+	return s.repo.FetchTemplates(context.TODO(), userID)
+}
+
+func (s *templateService) GetTemplateById(userID primitive.ObjectID, templateID primitive.ObjectID) (*t.Template, error) {
+	template, err := s.repo.FetchTemplateById(context.TODO(), userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if template == nil {
+		return nil, ErrTemplateNotFound
+	}
+	return template, nil
+}
+
+func (s *templateService) UpdateTemplate(userID primitive.ObjectID, templateID primitive.ObjectID, template t.TemplateRequest) (*t.Template, error) {
+	if !validTemplate(template) {
+		return nil, ErrInvalidTemplate
+	}
+
+	existing, err := s.GetTemplateById(userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing.Name = strings.TrimSpace(template.Name)
+	existing.TargetMuscles = template.TargetMuscles
+	existing.Exercises = template.Exercises
+	existing.UpdatedAt = time.Now()
+
+	return s.repo.UpdateTemplate(context.TODO(), *existing)
+}
+
+func (s *templateService) DeleteTemplate(userID primitive.ObjectID, templateID primitive.ObjectID) (bool, error) {
+	return s.repo.RemoveTemplate(context.TODO(), userID, templateID)
+}
+
+// CreateWorkoutFromTemplate logs a workout on the given date pre-filled with the
+// template's exercises, each expanded into its target number of sets.
+func (s *templateService) CreateWorkoutFromTemplate(userID primitive.ObjectID, templateID primitive.ObjectID, date time.Time) (*wt.Workout, error) {
+	template, err := s.GetTemplateById(userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	exercises := make([]wt.Exercise, 0, len(template.Exercises))
+	for _, exercise := range template.Exercises {
+		sets := make([]wt.ExerciseSet, exercise.TargetSets)
+		for i := range sets {
+			sets[i] = wt.ExerciseSet{Reps: exercise.TargetReps, Weight: exercise.TargetWeight}
+		}
+		exercises = append(exercises, wt.Exercise{Name: exercise.Name, Sets: sets})
+	}
+
+	return s.workoutService.CreateWorkout(userID, wt.CreateWorkoutRequest{
+		Date:          date,
+		TargetMuscles: template.TargetMuscles,
+		Exercises:     exercises,
+	})
+}
+
+// SaveWorkoutAsTemplate turns a logged workout into a template, using the number of
+// sets, the most reps and the heaviest weight logged for each exercise as targets.
+func (s *templateService) SaveWorkoutAsTemplate(userID primitive.ObjectID, workoutID primitive.ObjectID, name string) (*t.Template, error) {
+	loggedWorkout, err := s.workoutService.GetWorkoutById(userID, workoutID)
+	if err != nil {
+		return nil, err
+	}
+	if loggedWorkout == nil || loggedWorkout.Workout == nil {
+		return nil, workout.ErrWorkoutNotFound
+	}
+
+	request := t.TemplateRequest{
+		Name:          name,
+		TargetMuscles: loggedWorkout.Workout.TargetMuscles,
+		Exercises:     []t.TemplateExercise{},
+	}
+	for _, exercise := range loggedWorkout.Workout.Exercises {
+		templateExercise := t.TemplateExercise{Name: exercise.Name, TargetSets: len(exercise.Sets)}
+		for _, set := range exercise.Sets {
+			if set.Reps > templateExercise.TargetReps {
+				templateExercise.TargetReps = set.Reps
+			}
+			if set.Weight != nil && (templateExercise.TargetWeight == nil || *set.Weight > *templateExercise.TargetWeight) {
+				weight := *set.Weight
+				templateExercise.TargetWeight = &weight
+			}
+		}
+		request.Exercises = append(request.Exercises, templateExercise)
+	}
+
+	return s.CreateTemplate(userID, request)
+}
+
+func (s *templateService) CreateRoutine(userID primitive.ObjectID, routine t.RoutineRequest) (*t.Routine, error) {
+	if err := s.validateRoutine(userID, routine); err != nil {
+		return nil, err
+	}
+
+	newRoutine := t.Routine{
+		ID:        primitive.NewObjectID(),
+		UserId:    userID,
+		Name:      strings.TrimSpace(routine.Name),
+		Active:    routine.Active,
+		Days:      routine.Days,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	created, err := s.repo.InsertRoutine(context.TODO(), newRoutine)
+	if err != nil {
+		return nil, err
+	}
+	if created.Active {
+		if err := s.repo.DeactivateRoutines(context.TODO(), userID, created.ID); err != nil {
+			return nil, err
+		}
+	}
+	return created, nil
+}
+
+func (s *templateService) GetRoutines(userID primitive.ObjectID) ([]t.Routine, error) {
+	return s.repo.FetchRoutines(context.TODO(), userID)
+}
+
+func (s *templateService) GetRoutineById(userID primitive.ObjectID, routineID primitive.ObjectID) (*t.Routine, error) {
+	routine, err := s.repo.FetchRoutineById(context.TODO(), userID, routineID)
+	if err != nil {
+		return nil, err
+	}
+	if routine == nil {
+		return nil, ErrRoutineNotFound
+	}
+	return routine, nil
+}
+
+func (s *templateService) UpdateRoutine(userID primitive.ObjectID, routineID primitive.ObjectID, routine t.RoutineRequest) (*t.Routine, error) {
+	if err := s.validateRoutine(userID, routine); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.GetRoutineById(userID, routineID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing.Name = strings.TrimSpace(routine.Name)
+	existing.Active = routine.Active
+	existing.Days = routine.Days
+	existing.UpdatedAt = time.Now()
+
+	updated, err := s.repo.UpdateRoutine(context.TODO(), *existing)
+	if err != nil {
+		return nil, err
+	}
+	if updated.Active {
+		if err := s.repo.DeactivateRoutines(context.TODO(), userID, updated.ID); err != nil {
+			return nil, err
+		}
+	}
+	return updated, nil
+}
+
+func (s *templateService) DeleteRoutine(userID primitive.ObjectID, routineID primitive.ObjectID) (bool, error) {
+	return s.repo.RemoveRoutine(context.TODO(), userID, routineID)
+}
+
+// GetScheduledSession looks up the template the active routine has planned for
+// the weekday of date. Date should already be in the user's timezone.
+func (s *templateService) GetScheduledSession(userID primitive.ObjectID, date time.Time) (*t.ScheduledSession, error) {
+	weekday := c.WeekdayOf(date.Weekday())
+	session := &t.ScheduledSession{
+		Date:    time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC),
+		Weekday: weekday,
+		Rest:    true,
+	}
+
+	routine, err := s.repo.FetchActiveRoutine(context.TODO(), userID)
+	if err != nil {
+		return nil, err
+	}
+	if routine == nil {
+		return session, nil
+	}
+	session.Routine = routine
+
+	for _, day := range routine.Days {
+		if day.Weekday != weekday {
+			continue
+		}
+		template, err := s.repo.FetchTemplateById(context.TODO(), userID, day.TemplateID)
+		if err != nil {
+			return nil, err
+		}
+		// the template may have been deleted since the routine was set up
+		if template != nil {
+			session.Template = template
+			session.Rest = false
+		}
+		break
+	}
+
+	return session, nil
+}
+
+// GetSchedule is GetScheduledSession for each of days days starting at from,
+// looking the routine and its templates up once.
+func (s *templateService) GetSchedule(userID primitive.ObjectID, from time.Time, days int) ([]t.ScheduledSession, error) {
+	routine, err := s.repo.FetchActiveRoutine(context.TODO(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	templates := map[c.Weekday]*t.Template{}
+	if routine != nil {
+		for _, day := range routine.Days {
+			template, err := s.repo.FetchTemplateById(context.TODO(), userID, day.TemplateID)
+			if err != nil {
+				return nil, err
+			}
+			if template != nil {
+				templates[day.Weekday] = template
+			}
+		}
+	}
+
+	sessions := make([]t.ScheduledSession, 0, days)
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	for i := 0; i < days; i++ {
+		date := start.AddDate(0, 0, i)
+		weekday := c.WeekdayOf(date.Weekday())
+		session := t.ScheduledSession{Date: date, Weekday: weekday, Routine: routine, Rest: true}
+		if template, ok := templates[weekday]; ok {
+			session.Template = template
+			session.Rest = false
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+func validTemplate(template t.TemplateRequest) bool {
+	if strings.TrimSpace(template.Name) == "" {
+		return false
+	}
+	for _, exercise := range template.Exercises {
+		if strings.TrimSpace(exercise.Name) == "" ||
+			exercise.TargetSets <= 0 || exercise.TargetSets > c.MaxTargetSets ||
+			exercise.TargetReps <= 0 || exercise.TargetReps > c.MaxTargetReps {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *templateService) validateRoutine(userID primitive.ObjectID, routine t.RoutineRequest) error {
+	if strings.TrimSpace(routine.Name) == "" {
+		return ErrInvalidRoutine
+	}
+
+	seen := make(map[c.Weekday]bool)
+	for _, day := range routine.Days {
+		if !day.Weekday.Valid() || seen[day.Weekday] {
+			return ErrInvalidRoutine
+		}
+		seen[day.Weekday] = true
+
+		template, err := s.repo.FetchTemplateById(context.TODO(), userID, day.TemplateID)
+		if err != nil {
+			return err
+		}
+		if template == nil {
+			return ErrTemplateNotFound
+		}
+	}
+	return nil
+}
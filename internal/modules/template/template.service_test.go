@@ -0,0 +1,33 @@
+package template
+
+import (
+	"testing"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/template/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/template/types"
+)
+
+func TestValidTemplate(tt *testing.T) {
+	tests := []struct {
+		name     string
+		exercise t.TemplateExercise
+		want     bool
+	}{
+		{"valid", t.TemplateExercise{Name: "Squat", TargetSets: 5, TargetReps: 5}, true},
+		{"at the caps", t.TemplateExercise{Name: "Squat", TargetSets: c.MaxTargetSets, TargetReps: c.MaxTargetReps}, true},
+		{"no name", t.TemplateExercise{Name: " ", TargetSets: 3, TargetReps: 10}, false},
+		{"no sets", t.TemplateExercise{Name: "Squat", TargetSets: 0, TargetReps: 10}, false},
+		{"no reps", t.TemplateExercise{Name: "Squat", TargetSets: 3, TargetReps: 0}, false},
+		{"too many sets", t.TemplateExercise{Name: "Squat", TargetSets: 1e9, TargetReps: 10}, false},
+		{"too many reps", t.TemplateExercise{Name: "Squat", TargetSets: 3, TargetReps: c.MaxTargetReps + 1}, false},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			template := t.TemplateRequest{Name: "Legs", Exercises: []t.TemplateExercise{test.exercise}}
+			if got := validTemplate(template); got != test.want {
+				tt.Fatalf("validTemplate = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
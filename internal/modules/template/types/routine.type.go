@@ -0,0 +1,38 @@
+package types
+
+import (
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/template/constants"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RoutineDay maps a weekday onto a template, days without an entry are rest days.
+type RoutineDay struct {
+	Weekday    c.Weekday          `bson:"weekday" json:"weekday"`
+	TemplateID primitive.ObjectID `bson:"templateId" json:"templateId"`
+}
+
+type Routine struct {
+	ID        primitive.ObjectID `bson:"_id" json:"_id"`
+	UserId    primitive.ObjectID `bson:"userId" json:"-"`
+	Name      string             `bson:"name" json:"name"`
+	Active    bool               `bson:"active" json:"active"`
+	Days      []RoutineDay       `bson:"days" json:"days"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+type RoutineRequest struct {
+	Name   string       `json:"name"`
+	Active bool         `json:"active"`
+	Days   []RoutineDay `json:"days"`
+}
+
+type ScheduledSession struct {
+	Date     time.Time `json:"date"`
+	Weekday  c.Weekday `json:"weekday"`
+	Routine  *Routine  `json:"routine,omitempty"`
+	Template *Template `json:"template,omitempty"`
+	Rest     bool      `json:"rest"`
+}
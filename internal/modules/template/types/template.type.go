@@ -0,0 +1,25 @@
+package types
+
+import (
+	"time"
+
+	wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TemplateExercise struct {
+	Name         string   `json:"name" bson:"name"`
+	TargetSets   int      `json:"targetSets" bson:"targetSets"`
+	TargetReps   int      `json:"targetReps" bson:"targetReps"`
+	TargetWeight *float64 `json:"targetWeight,omitempty" bson:"targetWeight,omitempty"`
+}
+
+type Template struct {
+	ID            primitive.ObjectID `bson:"_id" json:"_id"`
+	UserId        primitive.ObjectID `bson:"userId" json:"-"`
+	Name          string             `bson:"name" json:"name"`
+	TargetMuscles []wc.TargetMuscles `bson:"targetMuscles,omitempty" json:"targetMuscles,omitempty"`
+	Exercises     []TemplateExercise `bson:"exercises" json:"exercises"`
+	CreatedAt     time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt     time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
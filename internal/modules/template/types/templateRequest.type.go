@@ -0,0 +1,21 @@
+package types
+
+import (
+	"time"
+
+	wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+)
+
+type TemplateRequest struct {
+	Name          string             `json:"name"`
+	TargetMuscles []wc.TargetMuscles `json:"targetMuscles,omitempty"`
+	Exercises     []TemplateExercise `json:"exercises"`
+}
+
+type ApplyTemplateRequest struct {
+	Date time.Time `json:"date"`
+}
+
+type SaveAsTemplateRequest struct {
+	Name string `json:"name"`
+}
@@ -0,0 +1,36 @@
+package constants
+
+import wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+
+// Defaults for settings a user hasn't saved yet.
+const (
+	DefaultActiveDayColour      = "#216e39"
+	DefaultInactiveDayColour    = "#ebedf0"
+	DefaultTodayHighlightColour = "#f0883e"
+	DefaultShowDays             = true
+	DefaultDayBorderRadius      = 2
+	DefaultTimezone             = "UTC"
+	DefaultWeeklyWorkoutTarget  = 3
+	DefaultAllowedRestDays      = 2
+	DefaultWeekStart            = wc.WeekStartMonday
+	DefaultWeightUnit           = WeightUnitKg
+	DefaultPrivateProfile       = false
+	DefaultVisibility           = wc.VisibilityPrivate
+)
+
+type WeightUnit string
+
+const (
+	WeightUnitKg WeightUnit = "kg"
+	WeightUnitLb WeightUnit = "lb"
+)
+
+func (u WeightUnit) Valid() bool {
+	return u == WeightUnitKg || u == WeightUnitLb
+}
+
+// Limits on the values a user can save.
+const (
+	MaxWeeklyWorkoutTarget = 14
+	MaxAllowedRestDays     = 6
+)
@@ -0,0 +1,10 @@
+package types
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// UserProfile is the part of a user that others can be shown.
+type UserProfile struct {
+	ID         primitive.ObjectID `bson:"_id" json:"_id"`
+	Name       string             `bson:"name" json:"name"`
+	PictureUrl string             `bson:"pictureUrl,omitempty" json:"pictureUrl,omitempty"`
+}
@@ -0,0 +1,49 @@
+package types
+
+import (
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/user/constants"
+	wc "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+)
+
+type UserSettings struct {
+	ActiveDayColour      string `bson:"activeDayColour" json:"activeDayColour"`
+	InactiveDayColour    string `bson:"inactiveDayColour" json:"inactiveDayColour"`
+	TodayHighlightColour string `bson:"todayHighlightColour" json:"todayHighlightColour"`
+	ShowDays             bool   `bson:"showDays" json:"showDays"`
+	DayBorderRadius      int    `bson:"dayBorderRadius" json:"dayBorderRadius"`
+
+	// Timezone is an IANA name, days and weeks are counted in it.
+	Timezone            string       `bson:"timezone" json:"timezone"`
+	WeekStart           wc.WeekStart `bson:"weekStart" json:"weekStart"`
+	WeeklyWorkoutTarget int          `bson:"weeklyWorkoutTarget" json:"weeklyWorkoutTarget"`
+	// AllowedRestDays is how many days in a row can be missed without breaking a streak.
+	AllowedRestDays int `bson:"allowedRestDays" json:"allowedRestDays"`
+
+	// WeightUnit is the unit body weight is logged in.
+	WeightUnit c.WeightUnit `bson:"weightUnit" json:"weightUnit"`
+	// GoalWeight is in WeightUnit.
+	GoalWeight *float64 `bson:"goalWeight,omitempty" json:"goalWeight,omitempty"`
+
+	// PrivateProfile means follow requests need approving.
+	PrivateProfile bool `bson:"privateProfile" json:"privateProfile"`
+	// DefaultVisibility is given to new workouts that don't pick one.
+	DefaultVisibility wc.Visibility `bson:"defaultVisibility" json:"defaultVisibility"`
+}
+
+// UpdateSettingsRequest only changes the fields that are present.
+type UpdateSettingsRequest struct {
+	ActiveDayColour      *string       `json:"activeDayColour,omitempty"`
+	InactiveDayColour    *string       `json:"inactiveDayColour,omitempty"`
+	TodayHighlightColour *string       `json:"todayHighlightColour,omitempty"`
+	ShowDays             *bool         `json:"showDays,omitempty"`
+	DayBorderRadius      *int          `json:"dayBorderRadius,omitempty"`
+	Timezone             *string       `json:"timezone,omitempty"`
+	WeekStart            *wc.WeekStart `json:"weekStart,omitempty"`
+	WeeklyWorkoutTarget  *int          `json:"weeklyWorkoutTarget,omitempty"`
+	AllowedRestDays      *int          `json:"allowedRestDays,omitempty"`
+	WeightUnit           *c.WeightUnit `json:"weightUnit,omitempty"`
+	// GoalWeight of 0 clears the goal.
+	GoalWeight        *float64       `json:"goalWeight,omitempty"`
+	PrivateProfile    *bool          `json:"privateProfile,omitempty"`
+	DefaultVisibility *wc.Visibility `json:"defaultVisibility,omitempty"`
+}
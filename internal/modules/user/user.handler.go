@@ -1,11 +1,66 @@
 package user
 
-// import "net/http"
+import (
+	"errors"
+	"net/http"
 
-// type UserHandler struct {
-// 	Service AuthService
-// }
+	m "github.com/joshibbotson/gym-tracker-backend/internal/middleware"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/user/types"
+	util "github.com/joshibbotson/gym-tracker-backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
-// func (h *AuthHandler) HandleUserDetails(w http.ResponseWriter, r *http.Request) {
+type UserHandler struct {
+	Service UserService
+}
 
-// }
+func NewUserHandler(service UserService) *UserHandler {
+	return &UserHandler{
+		Service: service,
+	}
+}
+
+// SettingsHandler serves /user/settings
+func (h *UserHandler) SettingsHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		m.PermissionMiddleware(h.handleReadSettings)(w, r)
+	case http.MethodPatch:
+		m.PermissionMiddleware(h.handleUpdateSettings)(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *UserHandler) handleReadSettings(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	settings, err := h.Service.GetSettings(userID)
+	if err != nil {
+		http.Error(w, "Error fetching settings", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, settings)
+}
+
+func (h *UserHandler) handleUpdateSettings(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	var request t.UpdateSettingsRequest
+	if !util.DecodeBody(w, r, &request) {
+		return
+	}
+
+	settings, err := h.Service.UpdateSettings(userID, request)
+	if errors.Is(err, ErrInvalidSettings) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error updating settings", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, settings)
+}
@@ -1,11 +1,21 @@
 package user
 
 import (
+	"context"
+
 	"github.com/joshibbotson/gym-tracker-backend/internal/db"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/user/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type UserRepository interface {
+	FetchSettings(ctx context.Context, userID primitive.ObjectID) (*t.UserSettings, error)
+	UpdateSettings(ctx context.Context, userID primitive.ObjectID, settings t.UserSettings) (*t.UserSettings, error)
+	FetchProfile(ctx context.Context, userID primitive.ObjectID) (*t.UserProfile, error)
+	FetchProfiles(ctx context.Context, userIDs []primitive.ObjectID) ([]t.UserProfile, error)
 }
 
 type userRepository struct {
@@ -18,4 +28,54 @@ func NewUserRepository() UserRepository {
 	}
 }
 
-func FetchUserDetails()
+// FetchSettings returns nil when the user has never saved their settings.
+func (r *userRepository) FetchSettings(ctx context.Context, userID primitive.ObjectID) (*t.UserSettings, error) {
+	var user struct {
+		Settings *t.UserSettings `bson:"settings"`
+	}
+	opts := options.FindOne().SetProjection(bson.M{"settings": 1})
+	err := r.userCollection.FindOne(ctx, bson.M{"_id": userID}, opts).Decode(&user)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return user.Settings, nil
+}
+
+func (r *userRepository) UpdateSettings(ctx context.Context, userID primitive.ObjectID, settings t.UserSettings) (*t.UserSettings, error) {
+	_, err := r.userCollection.UpdateByID(ctx, userID, bson.M{"$set": bson.M{"settings": settings}})
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+func (r *userRepository) FetchProfile(ctx context.Context, userID primitive.ObjectID) (*t.UserProfile, error) {
+	var profile t.UserProfile
+	opts := options.FindOne().SetProjection(bson.M{"name": 1, "pictureUrl": 1})
+	err := r.userCollection.FindOne(ctx, bson.M{"_id": userID}, opts).Decode(&profile)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func (r *userRepository) FetchProfiles(ctx context.Context, userIDs []primitive.ObjectID) ([]t.UserProfile, error) {
+	opts := options.Find().SetProjection(bson.M{"name": 1, "pictureUrl": 1})
+	cursor, err := r.userCollection.Find(ctx, bson.M{"_id": bson.M{"$in": userIDs}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	profiles := []t.UserProfile{}
+	if err = cursor.All(ctx, &profiles); err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
@@ -1 +1,194 @@
 package user
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/user/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/user/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type UserService interface {
+	GetSettings(userID primitive.ObjectID) (*t.UserSettings, error)
+	UpdateSettings(userID primitive.ObjectID, request t.UpdateSettingsRequest) (*t.UserSettings, error)
+	GetLocation(userID primitive.ObjectID) (*time.Location, error)
+	GetProfile(userID primitive.ObjectID) (*t.UserProfile, error)
+	GetProfiles(userIDs []primitive.ObjectID) (map[primitive.ObjectID]t.UserProfile, error)
+}
+
+var ErrUserNotFound = errors.New("user not found")
+
+var ErrInvalidSettings = errors.New("invalid settings: timezone must be an IANA name, weekStart monday or sunday, weightUnit kg or lb, defaultVisibility private, friends or public, weeklyWorkoutTarget 0-14, allowedRestDays 0-6 and goalWeight positive")
+
+type userService struct {
+	repo UserRepository
+}
+
+func NewUserService(repo UserRepository) UserService {
+	return &userService{repo: repo}
+}
+
+// GetSettings returns the saved settings with defaults filled in for anything
+// the user hasn't set.
+func (s *userService) GetSettings(userID primitive.ObjectID) (*t.UserSettings, error) {
+	settings, err := s.repo.FetchSettings(context.TODO(), userID)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		settings = &t.UserSettings{
+			ShowDays:            c.DefaultShowDays,
+			DayBorderRadius:     c.DefaultDayBorderRadius,
+			WeeklyWorkoutTarget: c.DefaultWeeklyWorkoutTarget,
+			AllowedRestDays:     c.DefaultAllowedRestDays,
+			PrivateProfile:      c.DefaultPrivateProfile,
+		}
+	}
+	withDefaults(settings)
+	return settings, nil
+}
+
+func (s *userService) UpdateSettings(userID primitive.ObjectID, request t.UpdateSettingsRequest) (*t.UserSettings, error) {
+	settings, err := s.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.ActiveDayColour != nil {
+		settings.ActiveDayColour = strings.TrimSpace(*request.ActiveDayColour)
+	}
+	if request.InactiveDayColour != nil {
+		settings.InactiveDayColour = strings.TrimSpace(*request.InactiveDayColour)
+	}
+	if request.TodayHighlightColour != nil {
+		settings.TodayHighlightColour = strings.TrimSpace(*request.TodayHighlightColour)
+	}
+	if request.ShowDays != nil {
+		settings.ShowDays = *request.ShowDays
+	}
+	if request.DayBorderRadius != nil {
+		settings.DayBorderRadius = *request.DayBorderRadius
+	}
+	if request.Timezone != nil {
+		settings.Timezone = strings.TrimSpace(*request.Timezone)
+	}
+	if request.WeekStart != nil {
+		settings.WeekStart = *request.WeekStart
+	}
+	if request.WeeklyWorkoutTarget != nil {
+		settings.WeeklyWorkoutTarget = *request.WeeklyWorkoutTarget
+	}
+	if request.AllowedRestDays != nil {
+		settings.AllowedRestDays = *request.AllowedRestDays
+	}
+
+	if request.WeightUnit != nil {
+		settings.WeightUnit = *request.WeightUnit
+	}
+	if request.GoalWeight != nil {
+		settings.GoalWeight = request.GoalWeight
+		if *request.GoalWeight == 0 {
+			settings.GoalWeight = nil
+		}
+	}
+	if request.PrivateProfile != nil {
+		settings.PrivateProfile = *request.PrivateProfile
+	}
+	if request.DefaultVisibility != nil {
+		settings.DefaultVisibility = *request.DefaultVisibility
+	}
+
+	if !validSettings(*settings) {
+		return nil, ErrInvalidSettings
+	}
+	withDefaults(settings)
+
+	return s.repo.UpdateSettings(context.TODO(), userID, *settings)
+}
+
+// GetLocation loads the user's timezone, falling back to UTC.
+func (s *userService) GetLocation(userID primitive.ObjectID) (*time.Location, error) {
+	settings, err := s.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	location, err := time.LoadLocation(settings.Timezone)
+	if err != nil {
+		return time.UTC, nil
+	}
+	return location, nil
+}
+
+func (s *userService) GetProfile(userID primitive.ObjectID) (*t.UserProfile, error) {
+	profile, err := s.repo.FetchProfile(context.TODO(), userID)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return nil, ErrUserNotFound
+	}
+	return profile, nil
+}
+
+// GetProfiles looks up several users in one query, keyed by id. Users that
+// no longer exist are left out.
+func (s *userService) GetProfiles(userIDs []primitive.ObjectID) (map[primitive.ObjectID]t.UserProfile, error) {
+	profiles := map[primitive.ObjectID]t.UserProfile{}
+	if len(userIDs) == 0 {
+		return profiles, nil
+	}
+	found, err := s.repo.FetchProfiles(context.TODO(), userIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, profile := range found {
+		profiles[profile.ID] = profile
+	}
+	return profiles, nil
+}
+
+func withDefaults(settings *t.UserSettings) {
+	if settings.ActiveDayColour == "" {
+		settings.ActiveDayColour = c.DefaultActiveDayColour
+	}
+	if settings.InactiveDayColour == "" {
+		settings.InactiveDayColour = c.DefaultInactiveDayColour
+	}
+	if settings.TodayHighlightColour == "" {
+		settings.TodayHighlightColour = c.DefaultTodayHighlightColour
+	}
+	if settings.Timezone == "" {
+		settings.Timezone = c.DefaultTimezone
+	}
+	if settings.WeekStart == "" {
+		settings.WeekStart = c.DefaultWeekStart
+	}
+	if settings.WeightUnit == "" {
+		settings.WeightUnit = c.DefaultWeightUnit
+	}
+	if settings.DefaultVisibility == "" {
+		settings.DefaultVisibility = c.DefaultVisibility
+	}
+}
+
+func validSettings(settings t.UserSettings) bool {
+	if settings.Timezone != "" {
+		if _, err := time.LoadLocation(settings.Timezone); err != nil {
+			return false
+		}
+	}
+	if (settings.WeekStart != "" && !settings.WeekStart.Valid()) || (settings.WeightUnit != "" && !settings.WeightUnit.Valid()) {
+		return false
+	}
+	if settings.DefaultVisibility != "" && !settings.DefaultVisibility.Valid() {
+		return false
+	}
+	if settings.DayBorderRadius < 0 || (settings.GoalWeight != nil && *settings.GoalWeight < 0) {
+		return false
+	}
+	return settings.WeeklyWorkoutTarget >= 0 && settings.WeeklyWorkoutTarget <= c.MaxWeeklyWorkoutTarget &&
+		settings.AllowedRestDays >= 0 && settings.AllowedRestDays <= c.MaxAllowedRestDays
+}
@@ -0,0 +1,4 @@
+package constants
+
+// MaxAnalyticsDays caps the range weekly analytics will bucket in one request.
+const MaxAnalyticsDays = 366 * 5
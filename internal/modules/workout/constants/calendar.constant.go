@@ -0,0 +1,4 @@
+package constants
+
+// MaxCalendarDays caps the range the activity calendar will fill in one request.
+const MaxCalendarDays = 366 * 5
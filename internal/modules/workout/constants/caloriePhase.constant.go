@@ -7,3 +7,7 @@ const (
 	CaloriePhaseBulk     CaloriePhase = "bulk"
 	CaloriePhaseMaintain CaloriePhase = "maintain"
 )
+
+func (p CaloriePhase) Valid() bool {
+	return p == CaloriePhaseCut || p == CaloriePhaseBulk || p == CaloriePhaseMaintain
+}
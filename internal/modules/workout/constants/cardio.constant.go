@@ -0,0 +1,35 @@
+package constants
+
+type CardioModality string
+
+const (
+	CardioRun  CardioModality = "run"
+	CardioRow  CardioModality = "row"
+	CardioBike CardioModality = "bike"
+	CardioSwim CardioModality = "swim"
+	CardioWalk CardioModality = "walk"
+)
+
+var CardioModalities = []CardioModality{CardioRun, CardioRow, CardioBike, CardioSwim, CardioWalk}
+
+func (m CardioModality) Valid() bool {
+	for _, modality := range CardioModalities {
+		if m == modality {
+			return true
+		}
+	}
+	return false
+}
+
+// PaceMetres is the distance pace is quoted over for each modality, e.g. a
+// rowing split is per 500m and a swim per 100m.
+var PaceMetres = map[CardioModality]float64{
+	CardioRun:  1000,
+	CardioRow:  500,
+	CardioBike: 1000,
+	CardioSwim: 100,
+	CardioWalk: 1000,
+}
+
+// MaxHeartRate is the highest heart rate accepted, anything above is a sensor error.
+const MaxHeartRate = 250
@@ -0,0 +1,95 @@
+package constants
+
+import "strings"
+
+type CatalogExercise struct {
+	Primary   []TargetMuscles
+	Secondary []TargetMuscles
+}
+
+// Contribution of a set to the volume of its primary and secondary muscles.
+const (
+	PrimaryMuscleWeight   = 1.0
+	SecondaryMuscleWeight = 0.5
+)
+
+// ExerciseCatalog maps lower case exercise names onto the muscles they train,
+// it is used to fill in muscles for logged exercises that don't specify their own.
+var ExerciseCatalog = map[string]CatalogExercise{
+	"squat":                     {Primary: []TargetMuscles{Quadriceps, Glutes}, Secondary: []TargetMuscles{Hamstrings, Adductors, LowerBack}},
+	"front squat":               {Primary: []TargetMuscles{Quadriceps}, Secondary: []TargetMuscles{Glutes, Abs}},
+	"leg press":                 {Primary: []TargetMuscles{Quadriceps}, Secondary: []TargetMuscles{Glutes}},
+	"lunge":                     {Primary: []TargetMuscles{Quadriceps, Glutes}, Secondary: []TargetMuscles{Hamstrings, Adductors}},
+	"bulgarian split squat":     {Primary: []TargetMuscles{Quadriceps, Glutes}, Secondary: []TargetMuscles{Adductors}},
+	"leg extension":             {Primary: []TargetMuscles{Quadriceps}},
+	"leg curl":                  {Primary: []TargetMuscles{Hamstrings}},
+	"deadlift":                  {Primary: []TargetMuscles{Hamstrings, Glutes, LowerBack}, Secondary: []TargetMuscles{Quadriceps, Traps, Forearms, Lats}},
+	"romanian deadlift":         {Primary: []TargetMuscles{Hamstrings, Glutes}, Secondary: []TargetMuscles{LowerBack}},
+	"hip thrust":                {Primary: []TargetMuscles{Glutes}, Secondary: []TargetMuscles{Hamstrings}},
+	"calf raise":                {Primary: []TargetMuscles{Calves}},
+	"hip adduction":             {Primary: []TargetMuscles{Adductors}},
+	"hip abduction":             {Primary: []TargetMuscles{Abductors}},
+	"bench press":               {Primary: []TargetMuscles{Chest}, Secondary: []TargetMuscles{Triceps, Shoulders}},
+	"incline bench press":       {Primary: []TargetMuscles{Chest, Shoulders}, Secondary: []TargetMuscles{Triceps}},
+	"dumbbell bench press":      {Primary: []TargetMuscles{Chest}, Secondary: []TargetMuscles{Triceps, Shoulders}},
+	"chest fly":                 {Primary: []TargetMuscles{Chest}},
+	"push up":                   {Primary: []TargetMuscles{Chest}, Secondary: []TargetMuscles{Triceps, Shoulders}},
+	"dip":                       {Primary: []TargetMuscles{Chest, Triceps}, Secondary: []TargetMuscles{Shoulders}},
+	"overhead press":            {Primary: []TargetMuscles{Shoulders}, Secondary: []TargetMuscles{Triceps, Traps}},
+	"lateral raise":             {Primary: []TargetMuscles{Shoulders}},
+	"rear delt fly":             {Primary: []TargetMuscles{Shoulders}, Secondary: []TargetMuscles{Traps}},
+	"face pull":                 {Primary: []TargetMuscles{Shoulders}, Secondary: []TargetMuscles{Traps}},
+	"shrug":                     {Primary: []TargetMuscles{Traps}, Secondary: []TargetMuscles{Forearms}},
+	"pull up":                   {Primary: []TargetMuscles{Lats}, Secondary: []TargetMuscles{Biceps, Back, Forearms}},
+	"chin up":                   {Primary: []TargetMuscles{Lats, Biceps}, Secondary: []TargetMuscles{Back, Forearms}},
+	"lat pulldown":              {Primary: []TargetMuscles{Lats}, Secondary: []TargetMuscles{Biceps, Back}},
+	"barbell row":               {Primary: []TargetMuscles{Back, Lats}, Secondary: []TargetMuscles{Biceps, LowerBack, Traps}},
+	"dumbbell row":              {Primary: []TargetMuscles{Back, Lats}, Secondary: []TargetMuscles{Biceps}},
+	"seated cable row":          {Primary: []TargetMuscles{Back, Lats}, Secondary: []TargetMuscles{Biceps, Traps}},
+	"back extension":            {Primary: []TargetMuscles{LowerBack}, Secondary: []TargetMuscles{Glutes, Hamstrings}},
+	"bicep curl":                {Primary: []TargetMuscles{Biceps}, Secondary: []TargetMuscles{Forearms}},
+	"hammer curl":               {Primary: []TargetMuscles{Biceps, Forearms}},
+	"tricep pushdown":           {Primary: []TargetMuscles{Triceps}},
+	"skull crusher":             {Primary: []TargetMuscles{Triceps}},
+	"overhead tricep extension": {Primary: []TargetMuscles{Triceps}},
+	"wrist curl":                {Primary: []TargetMuscles{Forearms}},
+	"crunch":                    {Primary: []TargetMuscles{Abs}},
+	"plank":                     {Primary: []TargetMuscles{Abs}, Secondary: []TargetMuscles{Obliques}},
+	"hanging leg raise":         {Primary: []TargetMuscles{Abs, HipFlexors}, Secondary: []TargetMuscles{Obliques}},
+	"russian twist":             {Primary: []TargetMuscles{Obliques}, Secondary: []TargetMuscles{Abs}},
+	"neck curl":                 {Primary: []TargetMuscles{Neck}},
+}
+
+// ExerciseAliases maps common alternative names onto catalog entries.
+var ExerciseAliases = map[string]string{
+	"back squat":          "squat",
+	"barbell squat":       "squat",
+	"bench":               "bench press",
+	"barbell bench press": "bench press",
+	"ohp":                 "overhead press",
+	"military press":      "overhead press",
+	"shoulder press":      "overhead press",
+	"rdl":                 "romanian deadlift",
+	"bent over row":       "barbell row",
+	"pendlay row":         "barbell row",
+	"pull-up":             "pull up",
+	"chin-up":             "chin up",
+	"push-up":             "push up",
+	"dips":                "dip",
+	"curl":                "bicep curl",
+	"barbell curl":        "bicep curl",
+	"dumbbell curl":       "bicep curl",
+	"triceps pushdown":    "tricep pushdown",
+	"standing calf raise": "calf raise",
+	"seated calf raise":   "calf raise",
+}
+
+// FindCatalogExercise looks an exercise up by name or alias, ignoring case.
+func FindCatalogExercise(name string) (CatalogExercise, bool) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if alias, ok := ExerciseAliases[key]; ok {
+		key = alias
+	}
+	exercise, ok := ExerciseCatalog[key]
+	return exercise, ok
+}
@@ -0,0 +1,14 @@
+package constants
+
+type RevisionAction string
+
+const (
+	RevisionActionCreate  RevisionAction = "create"
+	RevisionActionUpdate  RevisionAction = "update"
+	RevisionActionDelete  RevisionAction = "delete"
+	RevisionActionRestore RevisionAction = "restore"
+)
+
+// MaxRevisionAttempts is how many times saving a revision is tried when a
+// concurrent change takes the revision number first.
+const MaxRevisionAttempts = 5
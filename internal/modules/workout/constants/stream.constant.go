@@ -0,0 +1,5 @@
+package constants
+
+// StreamBatchSize is how many workouts are read from the db at a time when
+// streaming them.
+const StreamBatchSize = 200
@@ -0,0 +1,56 @@
+package constants
+
+type StrengthLevel struct {
+	Name string `json:"name"`
+	// e1RM divided by body weight needed to reach the level
+	Ratio float64 `json:"ratio"`
+}
+
+type StrengthStandard struct {
+	Exercise string          `json:"exercise"`
+	Aliases  []string        `json:"aliases,omitempty"`
+	Levels   []StrengthLevel `json:"levels"`
+}
+
+// Below the first level a lifter is classed as untrained.
+const StrengthLevelUntrained = "untrained"
+
+// DefaultStrengthStandards are used unless STRENGTH_STANDARDS_PATH points at a
+// JSON file of []StrengthStandard, which replaces any exercise it lists.
+var DefaultStrengthStandards = []StrengthStandard{
+	{
+		Exercise: "squat",
+		Aliases:  []string{"back squat", "barbell squat"},
+		Levels:   strengthLevels(0.75, 1.25, 1.5, 2.25, 2.75),
+	},
+	{
+		Exercise: "bench press",
+		Aliases:  []string{"bench", "barbell bench press"},
+		Levels:   strengthLevels(0.5, 0.75, 1.25, 1.5, 2),
+	},
+	{
+		Exercise: "deadlift",
+		Aliases:  []string{"conventional deadlift", "barbell deadlift"},
+		Levels:   strengthLevels(1, 1.5, 2, 2.5, 3),
+	},
+	{
+		Exercise: "overhead press",
+		Aliases:  []string{"ohp", "military press", "shoulder press"},
+		Levels:   strengthLevels(0.4, 0.55, 0.8, 1.05, 1.35),
+	},
+	{
+		Exercise: "barbell row",
+		Aliases:  []string{"bent over row", "pendlay row"},
+		Levels:   strengthLevels(0.5, 0.75, 1, 1.5, 1.75),
+	},
+}
+
+func strengthLevels(beginner, novice, intermediate, advanced, elite float64) []StrengthLevel {
+	return []StrengthLevel{
+		{Name: "beginner", Ratio: beginner},
+		{Name: "novice", Ratio: novice},
+		{Name: "intermediate", Ratio: intermediate},
+		{Name: "advanced", Ratio: advanced},
+		{Name: "elite", Ratio: elite},
+	}
+}
@@ -0,0 +1,7 @@
+package constants
+
+import "time"
+
+// Default time a soft-deleted workout stays in the trash before it is purged.
+// Can be overridden with the WORKOUT_TRASH_RETENTION_DAYS environment variable.
+const TrashRetention = 30 * 24 * time.Hour
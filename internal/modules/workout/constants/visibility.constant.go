@@ -0,0 +1,38 @@
+package constants
+
+// Visibility is who besides the owner can see a workout. Friends are the
+// owner's accepted followers. Workouts saved before visibility existed have
+// none and count as private.
+type Visibility string
+
+const (
+	VisibilityPrivate Visibility = "private"
+	VisibilityFriends Visibility = "friends"
+	VisibilityPublic  Visibility = "public"
+)
+
+func (v Visibility) Valid() bool {
+	return v == VisibilityPrivate || v == VisibilityFriends || v == VisibilityPublic
+}
+
+// Audience is who workouts are being read for, relative to their owner.
+type Audience string
+
+const (
+	AudienceOwner    Audience = "owner"
+	AudienceFollower Audience = "follower"
+	AudiencePublic   Audience = "public"
+)
+
+// Visibilities are the visibilities an audience can see, nil for the owner
+// who sees every workout. Anything unknown only sees public workouts.
+func (a Audience) Visibilities() []Visibility {
+	switch a {
+	case AudienceOwner:
+		return nil
+	case AudienceFollower:
+		return []Visibility{VisibilityFriends, VisibilityPublic}
+	default:
+		return []Visibility{VisibilityPublic}
+	}
+}
@@ -0,0 +1,21 @@
+package constants
+
+import "time"
+
+type WeekStart string
+
+const (
+	WeekStartMonday WeekStart = "monday"
+	WeekStartSunday WeekStart = "sunday"
+)
+
+func (w WeekStart) Valid() bool {
+	return w == WeekStartMonday || w == WeekStartSunday
+}
+
+func (w WeekStart) Weekday() time.Weekday {
+	if w == WeekStartSunday {
+		return time.Sunday
+	}
+	return time.Monday
+}
@@ -26,3 +26,38 @@ const (
 	Adductors  TargetMuscles = "adductors"
 	Abductors  TargetMuscles = "abductors"
 )
+
+// CompositeMuscles are the split names that stand for several muscle groups.
+var CompositeMuscles = map[TargetMuscles][]TargetMuscles{
+	Push: {Chest, Shoulders, Triceps},
+	Pull: {Back, Lats, Traps, Biceps, Forearms},
+	Legs: {Quadriceps, Hamstrings, Glutes, Calves, Adductors, Abductors},
+}
+
+// IndividualMuscles lists every muscle group that isn't a composite.
+var IndividualMuscles = []TargetMuscles{
+	Chest, Back, Shoulders, Biceps, Triceps, Forearms, Abs, Obliques, Quadriceps, Hamstrings,
+	Glutes, Calves, Traps, Lats, LowerBack, Neck, HipFlexors, Adductors, Abductors,
+}
+
+// ExpandMuscles replaces composites with the muscle groups they cover and drops duplicates.
+func ExpandMuscles(muscles []TargetMuscles) []TargetMuscles {
+	seen := map[TargetMuscles]bool{}
+	expanded := []TargetMuscles{}
+	add := func(muscle TargetMuscles) {
+		if !seen[muscle] {
+			seen[muscle] = true
+			expanded = append(expanded, muscle)
+		}
+	}
+	for _, muscle := range muscles {
+		if group, ok := CompositeMuscles[muscle]; ok {
+			for _, member := range group {
+				add(member)
+			}
+			continue
+		}
+		add(muscle)
+	}
+	return expanded
+}
@@ -9,8 +9,14 @@ import (
 type CreateWorkoutRequest struct {
 	Date             time.Time         `bson:"date" json:"date"`
 	Weight           *float64          `json:"weight,omitempty" bson:"weight,omitempty"`
+	BodyFat          *float64          `json:"bodyFat,omitempty" bson:"bodyFat,omitempty"`       // percent
+	MuscleMass       *float64          `json:"muscleMass,omitempty" bson:"muscleMass,omitempty"` // in the weight unit
+	BodyWater        *float64          `json:"bodyWater,omitempty" bson:"bodyWater,omitempty"`   // percent
+	BoneMass         *float64          `json:"boneMass,omitempty" bson:"boneMass,omitempty"`     // in the weight unit
 	TargetMuscles    []c.TargetMuscles `json:"targetMuscles,omitempty" bson:"targetMuscles,omitempty"`
+	Exercises        []Exercise        `json:"exercises,omitempty" bson:"exercises,omitempty"`
 	CaloriePhase     *c.CaloriePhase   `json:"caloriePhase,omitempty" bson:"caloriePhase,omitempty"`
+	Cardio           *CardioSession    `json:"cardio,omitempty" bson:"cardio,omitempty"`
 	NeckSize         *float64          `json:"neckSize,omitempty" bson:"neckSize,omitempty"`
 	ShoulderSize     *float64          `json:"shoulderSize,omitempty" bson:"shoulderSize,omitempty"`
 	LeftCalfSize     *float64          `json:"leftCalfSize,omitempty" bson:"leftCalfSize,omitempty"`
@@ -28,4 +34,5 @@ type CreateWorkoutRequest struct {
 	RightBicepSize   *float64          `json:"rightBicepSize,omitempty" bson:"rightBicepSize,omitempty"`
 	LeftForearmSize  *float64          `json:"leftForearmSize,omitempty" bson:"leftForearmSize,omitempty"`
 	RightForearmSize *float64          `json:"rightForearmSize,omitempty" bson:"rightForearmSize,omitempty"`
+	Visibility       *c.Visibility     `json:"visibility,omitempty" bson:"visibility,omitempty"`
 }
@@ -25,3 +25,9 @@ type YearlyData struct {
 type WorkoutData struct {
 	Data []YearlyData `json:"data"`
 }
+
+// DateCount is the number of workouts logged with one date.
+type DateCount struct {
+	Date     time.Time `bson:"_id" json:"date"`
+	Workouts int       `bson:"workouts" json:"workouts"`
+}
@@ -0,0 +1,61 @@
+package types
+
+import (
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+)
+
+// CardioSession is the conditioning part of a workout. Distance is in km and
+// elevation gain in metres. Pace (seconds per PaceMetres) and Speed (km/h) are
+// worked out on the server from the duration and distance.
+type CardioSession struct {
+	Modality      c.CardioModality `json:"modality" bson:"modality"`
+	Duration      int              `json:"duration" bson:"duration"` // seconds
+	Distance      *float64         `json:"distance,omitempty" bson:"distance,omitempty"`
+	AvgHeartRate  *int             `json:"avgHeartRate,omitempty" bson:"avgHeartRate,omitempty"`
+	MaxHeartRate  *int             `json:"maxHeartRate,omitempty" bson:"maxHeartRate,omitempty"`
+	ElevationGain *float64         `json:"elevationGain,omitempty" bson:"elevationGain,omitempty"`
+	Calories      *float64         `json:"calories,omitempty" bson:"calories,omitempty"`
+	Pace          *float64         `json:"pace,omitempty" bson:"pace,omitempty"`
+	PaceMetres    *float64         `json:"paceMetres,omitempty" bson:"paceMetres,omitempty"`
+	Speed         *float64         `json:"speed,omitempty" bson:"speed,omitempty"`
+}
+
+// CardioBucket is one week and modality as returned by the aggregation.
+type CardioBucket struct {
+	WeekStart     time.Time        `bson:"weekStart"`
+	Modality      c.CardioModality `bson:"modality"`
+	Sessions      int              `bson:"sessions"`
+	Duration      int              `bson:"duration"`
+	Distance      float64          `bson:"distance"`
+	ElevationGain float64          `bson:"elevationGain"`
+	Calories      float64          `bson:"calories"`
+}
+
+// CardioTotal sums sessions of one modality, Pace and Speed are averages over
+// the total distance and time.
+type CardioTotal struct {
+	Modality      c.CardioModality `json:"modality"`
+	Sessions      int              `json:"sessions"`
+	Duration      int              `json:"duration"`
+	Distance      float64          `json:"distance"`
+	ElevationGain float64          `json:"elevationGain"`
+	Calories      float64          `json:"calories"`
+	Pace          *float64         `json:"pace,omitempty"`
+	PaceMetres    *float64         `json:"paceMetres,omitempty"`
+	Speed         *float64         `json:"speed,omitempty"`
+}
+
+type WeeklyCardio struct {
+	WeekStart  time.Time     `json:"weekStart"`
+	Modalities []CardioTotal `json:"modalities"`
+}
+
+type CardioReport struct {
+	From      time.Time      `json:"from"`
+	To        time.Time      `json:"to"`
+	WeekStart c.WeekStart    `json:"weekStart"`
+	Weeks     []WeeklyCardio `json:"weeks"`
+	Totals    []CardioTotal  `json:"totals"`
+}
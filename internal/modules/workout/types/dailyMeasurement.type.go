@@ -0,0 +1,22 @@
+package types
+
+import "time"
+
+// DailyMeasurement is a day's body measurements from an import. Weight,
+// MuscleMass and BoneMass are in the user's weight unit, BodyFat and
+// BodyWater percentages and WaistSize in cm.
+type DailyMeasurement struct {
+	Date       time.Time
+	Weight     *float64
+	BodyFat    *float64
+	MuscleMass *float64
+	BodyWater  *float64
+	BoneMass   *float64
+	WaistSize  *float64
+}
+
+type MeasurementMergeResult struct {
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+}
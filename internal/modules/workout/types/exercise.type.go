@@ -0,0 +1,19 @@
+package types
+
+import (
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+)
+
+type ExerciseSet struct {
+	Reps   int      `json:"reps" bson:"reps"`
+	Weight *float64 `json:"weight,omitempty" bson:"weight,omitempty"`
+	Warmup bool     `json:"warmup,omitempty" bson:"warmup,omitempty"`
+}
+
+// Exercise muscles are filled in from the exercise catalog when not supplied.
+type Exercise struct {
+	Name             string            `json:"name" bson:"name"`
+	PrimaryMuscles   []c.TargetMuscles `json:"primaryMuscles,omitempty" bson:"primaryMuscles,omitempty"`
+	SecondaryMuscles []c.TargetMuscles `json:"secondaryMuscles,omitempty" bson:"secondaryMuscles,omitempty"`
+	Sets             []ExerciseSet     `json:"sets,omitempty" bson:"sets,omitempty"`
+}
@@ -0,0 +1,14 @@
+package types
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FeedCursor is the last workout on a page of the feed, the next page starts
+// after it.
+type FeedCursor struct {
+	Date time.Time
+	ID   primitive.ObjectID
+}
@@ -0,0 +1,7 @@
+package types
+
+// InteractionCounts are the reactions, by kind, and comments on a workout.
+type InteractionCounts struct {
+	Reactions map[string]int64 `json:"reactions"`
+	Comments  int64            `json:"comments"`
+}
@@ -0,0 +1,36 @@
+package types
+
+import (
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+)
+
+// MuscleVolumeBucket is one week and muscle group as returned by the aggregation.
+type MuscleVolumeBucket struct {
+	WeekStart time.Time       `bson:"weekStart"`
+	Muscle    c.TargetMuscles `bson:"muscle"`
+	Sessions  int             `bson:"sessions"`
+	HardSets  float64         `bson:"hardSets"`
+	Tonnage   float64         `bson:"tonnage"`
+}
+
+type MuscleVolume struct {
+	Muscle   c.TargetMuscles `json:"muscle"`
+	Sessions int             `json:"sessions"`
+	HardSets float64         `json:"hardSets"`
+	Tonnage  float64         `json:"tonnage"`
+}
+
+type WeeklyMuscleVolume struct {
+	WeekStart time.Time      `json:"weekStart"`
+	Muscles   []MuscleVolume `json:"muscles"`
+}
+
+type MuscleVolumeReport struct {
+	From      time.Time            `json:"from"`
+	To        time.Time            `json:"to"`
+	WeekStart c.WeekStart          `json:"weekStart"`
+	Weeks     []WeeklyMuscleVolume `json:"weeks"`
+	Totals    []MuscleVolume       `json:"totals"`
+}
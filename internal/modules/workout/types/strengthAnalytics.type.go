@@ -0,0 +1,36 @@
+package types
+
+import (
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type BodyWeightEntry struct {
+	Date         time.Time       `bson:"date" json:"date"`
+	Weight       float64         `bson:"weight" json:"weight"`
+	CaloriePhase *c.CaloriePhase `bson:"caloriePhase,omitempty" json:"caloriePhase,omitempty"`
+}
+
+type OneRepMaxPoint struct {
+	WorkoutID        primitive.ObjectID `json:"workoutId"`
+	Date             time.Time          `json:"date"`
+	EstimatedMax     float64            `json:"estimatedMax"`
+	Weight           float64            `json:"weight"`
+	Reps             int                `json:"reps"`
+	BodyWeight       *float64           `json:"bodyWeight,omitempty"`
+	RelativeStrength *float64           `json:"relativeStrength,omitempty"`
+	Level            string             `json:"level,omitempty"`
+}
+
+type StrengthAnalytics struct {
+	Exercise  string              `json:"exercise"`
+	Formula   string              `json:"formula"`
+	Points    []OneRepMaxPoint    `json:"points"`
+	Best      *OneRepMaxPoint     `json:"best,omitempty"`
+	Latest    *OneRepMaxPoint     `json:"latest,omitempty"`
+	Level     string              `json:"level,omitempty"`
+	NextLevel *c.StrengthLevel    `json:"nextLevel,omitempty"`
+	Standard  *c.StrengthStandard `json:"standard,omitempty"`
+}
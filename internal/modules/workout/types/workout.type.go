@@ -3,14 +3,18 @@ package types
 import (
 	"time"
 
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type Workout struct {
-	ID        primitive.ObjectID `bson:"_id" json:"_id"`
-	Date      time.Time          `bson:"date" json:"date"`
-	UserId    primitive.ObjectID `bson:"userId" json:"-"`
-	Workout   *WorkoutConfig     `bson:"workout,omitempty" json:"workoutConfig"` // Pointer to make it nullable
-	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
-	UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt"`
+	ID           primitive.ObjectID `bson:"_id" json:"_id"`
+	Date         time.Time          `bson:"date" json:"date"`
+	UserId       primitive.ObjectID `bson:"userId" json:"-"`
+	Workout      *WorkoutConfig     `bson:"workout,omitempty" json:"workoutConfig"` // Pointer to make it nullable
+	CreatedAt    time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt    time.Time          `bson:"updatedAt" json:"updatedAt"`
+	DeletedAt    *time.Time         `bson:"deletedAt,omitempty" json:"deletedAt,omitempty"`
+	Visibility   c.Visibility       `bson:"visibility,omitempty" json:"visibility,omitempty"` // empty on older workouts, which are private
+	Interactions *InteractionCounts `bson:"-" json:"interactions,omitempty"`                  // only filled in on reads that show them
 }
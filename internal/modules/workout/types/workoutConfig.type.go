@@ -6,8 +6,14 @@ import (
 
 type WorkoutConfig struct {
 	Weight           *float64          `json:"weight,omitempty" bson:"weight,omitempty"`
+	BodyFat          *float64          `json:"bodyFat,omitempty" bson:"bodyFat,omitempty"`       // percent
+	MuscleMass       *float64          `json:"muscleMass,omitempty" bson:"muscleMass,omitempty"` // in the weight unit
+	BodyWater        *float64          `json:"bodyWater,omitempty" bson:"bodyWater,omitempty"`   // percent
+	BoneMass         *float64          `json:"boneMass,omitempty" bson:"boneMass,omitempty"`     // in the weight unit
 	TargetMuscles    []c.TargetMuscles `json:"targetMuscles,omitempty" bson:"targetMuscles,omitempty"`
+	Exercises        []Exercise        `json:"exercises,omitempty" bson:"exercises,omitempty"`
 	CaloriePhase     *c.CaloriePhase   `json:"caloriePhase,omitempty" bson:"caloriePhase,omitempty"`
+	Cardio           *CardioSession    `json:"cardio,omitempty" bson:"cardio,omitempty"`
 	NeckSize         *float64          `json:"neckSize,omitempty" bson:"neckSize,omitempty"`
 	ShoulderSize     *float64          `json:"shoulderSize,omitempty" bson:"shoulderSize,omitempty"`
 	LeftCalfSize     *float64          `json:"leftCalfSize,omitempty" bson:"leftCalfSize,omitempty"`
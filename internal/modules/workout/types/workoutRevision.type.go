@@ -0,0 +1,33 @@
+package types
+
+import (
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type FieldChange struct {
+	Field  string      `bson:"field" json:"field"`
+	Before interface{} `bson:"before,omitempty" json:"before,omitempty"`
+	After  interface{} `bson:"after,omitempty" json:"after,omitempty"`
+}
+
+// WorkoutRevision is an immutable snapshot of a workout taken on every mutation.
+type WorkoutRevision struct {
+	ID        primitive.ObjectID `bson:"_id" json:"_id"`
+	WorkoutID primitive.ObjectID `bson:"workoutId" json:"workoutId"`
+	UserId    primitive.ObjectID `bson:"userId" json:"-"`
+	Revision  int                `bson:"revision" json:"revision"`
+	Action    c.RevisionAction   `bson:"action" json:"action"`
+	ChangedBy primitive.ObjectID `bson:"changedBy" json:"changedBy"`
+	ChangedAt time.Time          `bson:"changedAt" json:"changedAt"`
+	Date      time.Time          `bson:"date" json:"date"`
+	Workout   *WorkoutConfig     `bson:"workout,omitempty" json:"workoutConfig"`
+	Changes   []FieldChange      `bson:"changes" json:"changes"`
+}
+
+type TrashedWorkout struct {
+	Workout
+	PurgeAt time.Time `json:"purgeAt"`
+}
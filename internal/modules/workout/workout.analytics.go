@@ -0,0 +1,243 @@
+package workout
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+	util "github.com/joshibbotson/gym-tracker-backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetStrengthAnalytics builds an e1RM series for an exercise, one point per
+// workout using its best set, alongside relative strength and strength level.
+func (s *workoutService) GetStrengthAnalytics(userID primitive.ObjectID, exercise string, formulaName string, from time.Time, to time.Time) (*t.StrengthAnalytics, error) {
+	if formulaName == "" {
+		formulaName = "epley"
+	}
+	formula, ok := util.OneRepMaxFormulas[strings.ToLower(formulaName)]
+	if !ok {
+		return nil, ErrUnknownFormula
+	}
+
+	workouts, err := s.repo.FetchWorkoutsWithExercise(context.TODO(), userID, c.AudienceOwner, exercise, from, to)
+	if err != nil {
+		return nil, err
+	}
+	// body weight is looked up across all time so points near the edges of the
+	// range still find their nearest weigh-in
+	bodyWeights, err := s.repo.FetchBodyWeights(context.TODO(), userID, c.AudienceOwner, time.Time{}, time.Now().AddDate(100, 0, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	standard := findStrengthStandard(s.strengthStandards, exercise)
+	analytics := &t.StrengthAnalytics{
+		Exercise: strings.TrimSpace(exercise),
+		Formula:  strings.ToLower(formulaName),
+		Points:   []t.OneRepMaxPoint{},
+		Standard: standard,
+	}
+
+	for _, workout := range workouts {
+		point, ok := bestOneRepMax(workout, exercise, formula)
+		if !ok {
+			continue
+		}
+		if bodyWeight, ok := nearestBodyWeight(bodyWeights, workout.Date); ok {
+			relative := point.EstimatedMax / bodyWeight
+			point.BodyWeight = &bodyWeight
+			point.RelativeStrength = &relative
+			if standard != nil {
+				point.Level, _ = classifyStrength(*standard, relative)
+			}
+		}
+		analytics.Points = append(analytics.Points, point)
+	}
+
+	for i := range analytics.Points {
+		if analytics.Best == nil || analytics.Points[i].EstimatedMax > analytics.Best.EstimatedMax {
+			analytics.Best = &analytics.Points[i]
+		}
+	}
+	if len(analytics.Points) > 0 {
+		analytics.Latest = &analytics.Points[len(analytics.Points)-1]
+	}
+	if analytics.Best != nil && analytics.Best.RelativeStrength != nil && standard != nil {
+		analytics.Level, analytics.NextLevel = classifyStrength(*standard, *analytics.Best.RelativeStrength)
+	}
+
+	return analytics, nil
+}
+
+// bestOneRepMax is the heaviest estimate from the workout's working sets.
+func bestOneRepMax(workout t.Workout, exercise string, formula util.OneRepMaxFormula) (t.OneRepMaxPoint, bool) {
+	point := t.OneRepMaxPoint{WorkoutID: workout.ID, Date: workout.Date}
+	if workout.Workout == nil {
+		return point, false
+	}
+
+	for _, logged := range workout.Workout.Exercises {
+		if !strings.EqualFold(strings.TrimSpace(logged.Name), strings.TrimSpace(exercise)) {
+			continue
+		}
+		for _, set := range logged.Sets {
+			if set.Weight == nil || set.Warmup {
+				continue
+			}
+			if estimate := formula(*set.Weight, set.Reps); estimate > point.EstimatedMax {
+				point.EstimatedMax = estimate
+				point.Weight = *set.Weight
+				point.Reps = set.Reps
+			}
+		}
+	}
+	return point, point.EstimatedMax > 0
+}
+
+// nearestBodyWeight finds the weigh-in closest in time to date, entries must be sorted by date.
+func nearestBodyWeight(entries []t.BodyWeightEntry, date time.Time) (float64, bool) {
+	if len(entries) == 0 {
+		return 0, false
+	}
+
+	i := sort.Search(len(entries), func(i int) bool { return !entries[i].Date.Before(date) })
+	if i == 0 {
+		return entries[0].Weight, true
+	}
+	if i == len(entries) {
+		return entries[len(entries)-1].Weight, true
+	}
+	if date.Sub(entries[i-1].Date) <= entries[i].Date.Sub(date) {
+		return entries[i-1].Weight, true
+	}
+	return entries[i].Weight, true
+}
+
+// classifyStrength returns the highest level reached and the level after it.
+func classifyStrength(standard c.StrengthStandard, relative float64) (string, *c.StrengthLevel) {
+	level := c.StrengthLevelUntrained
+	for i, threshold := range standard.Levels {
+		if relative < threshold.Ratio {
+			return level, &standard.Levels[i]
+		}
+		level = threshold.Name
+	}
+	return level, nil
+}
+
+func findStrengthStandard(standards []c.StrengthStandard, exercise string) *c.StrengthStandard {
+	name := strings.ToLower(strings.TrimSpace(exercise))
+	for i, standard := range standards {
+		if strings.ToLower(standard.Exercise) == name {
+			return &standards[i]
+		}
+		for _, alias := range standard.Aliases {
+			if strings.ToLower(alias) == name {
+				return &standards[i]
+			}
+		}
+	}
+	return nil
+}
+
+// loadStrengthStandards starts from the defaults and replaces any exercise
+// found in the JSON file at STRENGTH_STANDARDS_PATH.
+func loadStrengthStandards() []c.StrengthStandard {
+	standards := append([]c.StrengthStandard{}, c.DefaultStrengthStandards...)
+
+	path := os.Getenv("STRENGTH_STANDARDS_PATH")
+	if path == "" {
+		return standards
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Println("error reading strength standards:", err)
+		return standards
+	}
+	var overrides []c.StrengthStandard
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		fmt.Println("error parsing strength standards:", err)
+		return standards
+	}
+
+	for _, override := range overrides {
+		sort.Slice(override.Levels, func(i, j int) bool { return override.Levels[i].Ratio < override.Levels[j].Ratio })
+		if existing := findStrengthStandard(standards, override.Exercise); existing != nil {
+			*existing = override
+			continue
+		}
+		standards = append(standards, override)
+	}
+	return standards
+}
+
+// GetMuscleVolume reports sessions, hard sets and tonnage per muscle group for
+// every week in the range, weeks without training are included empty.
+func (s *workoutService) GetMuscleVolume(userID primitive.ObjectID, from time.Time, to time.Time, weekStart c.WeekStart) (*t.MuscleVolumeReport, error) {
+	if to.Sub(from) > c.MaxAnalyticsDays*24*time.Hour {
+		return nil, ErrRangeTooLarge
+	}
+
+	buckets, err := s.repo.FetchMuscleVolume(context.TODO(), userID, c.AudienceOwner, from, to, weekStart)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &t.MuscleVolumeReport{
+		From:      from,
+		To:        to,
+		WeekStart: weekStart,
+		Weeks:     []t.WeeklyMuscleVolume{},
+		Totals:    []t.MuscleVolume{},
+	}
+
+	weekIndex := map[time.Time]int{}
+	for week := startOfWeek(from, weekStart.Weekday()); week.Before(to); week = week.AddDate(0, 0, 7) {
+		weekIndex[week] = len(report.Weeks)
+		report.Weeks = append(report.Weeks, t.WeeklyMuscleVolume{WeekStart: week, Muscles: []t.MuscleVolume{}})
+	}
+
+	totals := map[c.TargetMuscles]*t.MuscleVolume{}
+	for _, bucket := range buckets {
+		volume := t.MuscleVolume{
+			Muscle:   bucket.Muscle,
+			Sessions: bucket.Sessions,
+			HardSets: bucket.HardSets,
+			Tonnage:  bucket.Tonnage,
+		}
+		if i, ok := weekIndex[bucket.WeekStart.UTC()]; ok {
+			report.Weeks[i].Muscles = append(report.Weeks[i].Muscles, volume)
+		}
+
+		total, ok := totals[bucket.Muscle]
+		if !ok {
+			total = &t.MuscleVolume{Muscle: bucket.Muscle}
+			totals[bucket.Muscle] = total
+		}
+		total.Sessions += bucket.Sessions
+		total.HardSets += bucket.HardSets
+		total.Tonnage += bucket.Tonnage
+	}
+
+	for _, total := range totals {
+		report.Totals = append(report.Totals, *total)
+	}
+	sort.Slice(report.Totals, func(i, j int) bool { return report.Totals[i].HardSets > report.Totals[j].HardSets })
+
+	return report, nil
+}
+
+// startOfWeek truncates date to midnight UTC on the most recent weekStart day.
+func startOfWeek(date time.Time, weekStart time.Weekday) time.Time {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	offset := (int(day.Weekday()) - int(weekStart) + 7) % 7
+	return day.AddDate(0, 0, -offset)
+}
@@ -0,0 +1,52 @@
+package workout
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+	util "github.com/joshibbotson/gym-tracker-backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestBestOneRepMax(tt *testing.T) {
+	heavy, working := 140.0, 100.0
+
+	tests := []struct {
+		name  string
+		sets  []t.ExerciseSet
+		want  float64
+		found bool
+	}{
+		{"best working set", []t.ExerciseSet{{Reps: 5, Weight: &working}, {Reps: 1, Weight: &heavy}}, 140, true},
+		{"warm-up sets are skipped", []t.ExerciseSet{{Reps: 1, Weight: &heavy, Warmup: true}, {Reps: 1, Weight: &working}}, 100, true},
+		{"only warm-ups", []t.ExerciseSet{{Reps: 1, Weight: &heavy, Warmup: true}}, 0, false},
+		{"unweighted", []t.ExerciseSet{{Reps: 10}}, 0, false},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			workout := t.Workout{Workout: &t.WorkoutConfig{Exercises: []t.Exercise{{Name: "Bench Press", Sets: test.sets}}}}
+			point, found := bestOneRepMax(workout, " bench press", util.Epley)
+			if found != test.found || point.EstimatedMax != test.want {
+				tt.Fatalf("got %v (%v), want %v (%v)", point.EstimatedMax, found, test.want, test.found)
+			}
+		})
+	}
+}
+
+func TestWeeklyAnalyticsCapTheRange(tt *testing.T) {
+	// no repository, a range over the cap has to be turned away before any read
+	service := &workoutService{}
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, c.MaxAnalyticsDays+1)
+
+	if _, err := service.GetMuscleVolume(primitive.NewObjectID(), from, to, c.WeekStartMonday); !errors.Is(err, ErrRangeTooLarge) {
+		tt.Fatalf("muscle volume: got %v, want %v", err, ErrRangeTooLarge)
+	}
+	if _, err := service.GetCardioTotals(primitive.NewObjectID(), from, to, c.WeekStartMonday); !errors.Is(err, ErrRangeTooLarge) {
+		tt.Fatalf("cardio totals: got %v, want %v", err, ErrRangeTooLarge)
+	}
+}
@@ -0,0 +1,132 @@
+package workout
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetCardioTotals reports time, distance, elevation and calories per modality
+// for every week in the range, weeks without cardio are included empty.
+func (s *workoutService) GetCardioTotals(userID primitive.ObjectID, from time.Time, to time.Time, weekStart c.WeekStart) (*t.CardioReport, error) {
+	if to.Sub(from) > c.MaxAnalyticsDays*24*time.Hour {
+		return nil, ErrRangeTooLarge
+	}
+
+	buckets, err := s.repo.FetchCardioTotals(context.TODO(), userID, c.AudienceOwner, from, to, weekStart)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &t.CardioReport{
+		From:      from,
+		To:        to,
+		WeekStart: weekStart,
+		Weeks:     []t.WeeklyCardio{},
+		Totals:    []t.CardioTotal{},
+	}
+
+	weekIndex := map[time.Time]int{}
+	for week := startOfWeek(from, weekStart.Weekday()); week.Before(to); week = week.AddDate(0, 0, 7) {
+		weekIndex[week] = len(report.Weeks)
+		report.Weeks = append(report.Weeks, t.WeeklyCardio{WeekStart: week, Modalities: []t.CardioTotal{}})
+	}
+
+	totals := map[c.CardioModality]*t.CardioTotal{}
+	for _, bucket := range buckets {
+		if i, ok := weekIndex[bucket.WeekStart.UTC()]; ok {
+			total := t.CardioTotal{Modality: bucket.Modality}
+			addCardio(&total, bucket)
+			report.Weeks[i].Modalities = append(report.Weeks[i].Modalities, total)
+		}
+
+		total, ok := totals[bucket.Modality]
+		if !ok {
+			total = &t.CardioTotal{Modality: bucket.Modality}
+			totals[bucket.Modality] = total
+		}
+		addCardio(total, bucket)
+	}
+
+	for _, modality := range c.CardioModalities {
+		if total, ok := totals[modality]; ok {
+			withAveragePace(total)
+			report.Totals = append(report.Totals, *total)
+		}
+	}
+	sort.SliceStable(report.Totals, func(i, j int) bool { return report.Totals[i].Duration > report.Totals[j].Duration })
+
+	return report, nil
+}
+
+func addCardio(total *t.CardioTotal, bucket t.CardioBucket) {
+	total.Sessions += bucket.Sessions
+	total.Duration += bucket.Duration
+	total.Distance = roundTo(total.Distance+bucket.Distance, 100)
+	total.ElevationGain = roundTo(total.ElevationGain+bucket.ElevationGain, 10)
+	total.Calories = roundTo(total.Calories+bucket.Calories, 10)
+	withAveragePace(total)
+}
+
+func withAveragePace(total *t.CardioTotal) {
+	total.Pace, total.PaceMetres, total.Speed = derivePace(total.Modality, total.Duration, total.Distance)
+}
+
+// withDerivedPace copies the session with pace and speed worked out from its
+// duration and distance, any values sent by the client are ignored.
+func withDerivedPace(cardio *t.CardioSession) *t.CardioSession {
+	if cardio == nil {
+		return nil
+	}
+	derived := *cardio
+	distance := 0.0
+	if cardio.Distance != nil {
+		distance = *cardio.Distance
+	}
+	derived.Pace, derived.PaceMetres, derived.Speed = derivePace(cardio.Modality, cardio.Duration, distance)
+	return &derived
+}
+
+// derivePace returns seconds per PaceMetres and km/h, both nil without a
+// distance and duration to work from.
+func derivePace(modality c.CardioModality, seconds int, km float64) (*float64, *float64, *float64) {
+	if seconds <= 0 || km <= 0 {
+		return nil, nil, nil
+	}
+	paceMetres := c.PaceMetres[modality]
+	pace := roundTo(float64(seconds)/(km*1000/paceMetres), 10)
+	speed := roundTo(km/(float64(seconds)/3600), 100)
+	return &pace, &paceMetres, &speed
+}
+
+func validCardio(cardio *t.CardioSession) bool {
+	if cardio == nil {
+		return true
+	}
+	if !cardio.Modality.Valid() || cardio.Duration <= 0 {
+		return false
+	}
+	for _, value := range []*float64{cardio.Distance, cardio.ElevationGain, cardio.Calories} {
+		if value != nil && *value < 0 {
+			return false
+		}
+	}
+	for _, heartRate := range []*int{cardio.AvgHeartRate, cardio.MaxHeartRate} {
+		if heartRate != nil && (*heartRate <= 0 || *heartRate > c.MaxHeartRate) {
+			return false
+		}
+	}
+	if cardio.AvgHeartRate != nil && cardio.MaxHeartRate != nil && *cardio.AvgHeartRate > *cardio.MaxHeartRate {
+		return false
+	}
+	return true
+}
+
+func roundTo(value float64, precision float64) float64 {
+	return math.Round(value*precision) / precision
+}
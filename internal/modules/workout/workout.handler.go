@@ -3,11 +3,14 @@ package workout
 // handle CRUD ops on workout configs
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	m "github.com/joshibbotson/gym-tracker-backend/internal/middleware"
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
 	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
 	util "github.com/joshibbotson/gym-tracker-backend/internal/util"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -65,6 +68,10 @@ func (h *WorkoutHandler) handleCreateWorkout(w http.ResponseWriter, r *http.Requ
 	}
 
 	workout, err := h.Service.CreateWorkout(r.Context().Value("userID").(primitive.ObjectID), unmarshalledBody)
+	if errors.Is(err, ErrInvalidCardio) || errors.Is(err, ErrInvalidVisibility) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Error creating workout", http.StatusInternalServerError)
 		return
@@ -97,21 +104,84 @@ func (h *WorkoutHandler) handleReadByDate(w http.ResponseWriter, r *http.Request
 	}
 }
 
+// handleReadActivites takes optional from, to and weekStart query params, the
+// range defaults to the current year.
 func (h *WorkoutHandler) handleReadActivites(w http.ResponseWriter, r *http.Request) {
 	userID := r.Context().Value("userID").(primitive.ObjectID)
 
-	workout, err := h.Service.GetWorkoutsByUserId(userID)
+	startOfYear := time.Date(time.Now().Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	from, to, ok := util.ParseDateRangeWithDefaults(w, r, startOfYear, startOfYear.AddDate(1, 0, 0))
+	if !ok {
+		return
+	}
+	h.writeCalendar(w, r, userID, from, to)
+}
+
+func (h *WorkoutHandler) writeCalendar(w http.ResponseWriter, r *http.Request, userID primitive.ObjectID, from time.Time, to time.Time) {
+	var weekStart c.WeekStart
+	if r.URL.Query().Get("weekStart") != "" {
+		parsed, ok := parseWeekStart(w, r)
+		if !ok {
+			return
+		}
+		weekStart = parsed
+	}
+
+	workout, err := h.Service.GetWorkoutsByUserId(userID, from, to, weekStart)
+	if errors.Is(err, ErrInvalidRange) || errors.Is(err, ErrRangeTooLarge) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	if err != nil {
-		http.Error(w, "Error creating workout", http.StatusInternalServerError)
+		http.Error(w, "Error fetching workouts", http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
+	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(workout); err != nil {
 		http.Error(w, "Error encoding response", http.StatusInternalServerError)
 	}
+}
 
+// CalendarHandler serves /workout/years, the years holding workouts, and
+// /workout/years/{year}, the activity calendar for one year.
+func (h *WorkoutHandler) CalendarHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if r.PathValue("year") != "" {
+		m.PermissionMiddleware(h.handleReadYear)(w, r)
+		return
+	}
+	m.PermissionMiddleware(h.handleReadYears)(w, r)
+}
+
+func (h *WorkoutHandler) handleReadYears(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	years, err := h.Service.GetWorkoutYears(userID)
+	if err != nil {
+		http.Error(w, "Error fetching years", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, years)
+}
+
+func (h *WorkoutHandler) handleReadYear(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	year, err := strconv.Atoi(r.PathValue("year"))
+	if err != nil || year < 1 || year > 9999 {
+		http.Error(w, "Invalid year", http.StatusBadRequest)
+		return
+	}
+
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	h.writeCalendar(w, r, userID, from, from.AddDate(1, 0, 0))
 }
 
 func (h *WorkoutHandler) handleReadActivitiesCount(w http.ResponseWriter, r *http.Request) {
@@ -147,6 +217,14 @@ func (h *WorkoutHandler) handleUpdateWorkout(w http.ResponseWriter, r *http.Requ
 
 	fmt.Println(unmarshalledBody)
 	workouts, err := h.Service.UpdateWorkout(userID, unmarshalledBody)
+	if errors.Is(err, ErrWorkoutNotFound) {
+		http.Error(w, "Workout not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, ErrInvalidCardio) || errors.Is(err, ErrInvalidVisibility) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		println(err)
 		http.Error(w, "Error updating workout", http.StatusInternalServerError)
@@ -162,6 +240,7 @@ func (h *WorkoutHandler) handleUpdateWorkout(w http.ResponseWriter, r *http.Requ
 
 func (h *WorkoutHandler) handleDeleteWorkout(w http.ResponseWriter, r *http.Request) {
 	println("delete handler hit:", r.PathValue("id"))
+	userID := r.Context().Value("userID").(primitive.ObjectID)
 	idParam := r.PathValue("id")
 	if idParam == "" {
 		http.Error(w, "ID is required", http.StatusBadRequest)
@@ -175,7 +254,7 @@ func (h *WorkoutHandler) handleDeleteWorkout(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	success, err := h.Service.DeleteWorkout(objectID)
+	success, err := h.Service.DeleteWorkout(userID, objectID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -189,3 +268,281 @@ func (h *WorkoutHandler) handleDeleteWorkout(w http.ResponseWriter, r *http.Requ
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte(`{"message": "Workout deleted successfully"}`))
 }
+
+// RevisionsHandler serves /workout/revisions/{id}
+func (h *WorkoutHandler) RevisionsHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		m.PermissionMiddleware(h.handleReadRevisions)(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// RevisionHandler serves /workout/revisions/{id}/{revision}
+func (h *WorkoutHandler) RevisionHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		m.PermissionMiddleware(h.handleReadRevision)(w, r)
+	case http.MethodPost:
+		m.PermissionMiddleware(h.handleRestoreRevision)(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// TrashHandler serves /workout/trash and /workout/trash/{id}
+func (h *WorkoutHandler) TrashHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		m.PermissionMiddleware(h.handleReadTrash)(w, r)
+	case http.MethodPost:
+		m.PermissionMiddleware(h.handleRestoreFromTrash)(w, r)
+	case http.MethodDelete:
+		m.PermissionMiddleware(h.handlePurgeWorkout)(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *WorkoutHandler) handleReadRevisions(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+	workoutID, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID format", http.StatusBadRequest)
+		return
+	}
+
+	revisions, err := h.Service.GetRevisions(userID, workoutID)
+	if err != nil {
+		http.Error(w, "Error fetching revisions", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, revisions)
+}
+
+func (h *WorkoutHandler) handleReadRevision(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+	workoutID, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID format", http.StatusBadRequest)
+		return
+	}
+	revision, err := strconv.Atoi(r.PathValue("revision"))
+	if err != nil {
+		http.Error(w, "Invalid revision", http.StatusBadRequest)
+		return
+	}
+
+	found, err := h.Service.GetRevision(userID, workoutID, revision)
+	if errors.Is(err, ErrRevisionNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error fetching revision", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, found)
+}
+
+func (h *WorkoutHandler) handleRestoreRevision(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+	workoutID, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID format", http.StatusBadRequest)
+		return
+	}
+	revision, err := strconv.Atoi(r.PathValue("revision"))
+	if err != nil {
+		http.Error(w, "Invalid revision", http.StatusBadRequest)
+		return
+	}
+
+	workout, err := h.Service.RestoreRevision(userID, workoutID, revision)
+	if errors.Is(err, ErrWorkoutNotFound) || errors.Is(err, ErrRevisionNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error restoring revision", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, workout)
+}
+
+func (h *WorkoutHandler) handleReadTrash(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	trash, err := h.Service.GetTrash(userID)
+	if err != nil {
+		http.Error(w, "Error fetching trash", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, trash)
+}
+
+func (h *WorkoutHandler) handleRestoreFromTrash(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+	workoutID, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID format", http.StatusBadRequest)
+		return
+	}
+
+	workout, err := h.Service.RestoreFromTrash(userID, workoutID)
+	if errors.Is(err, ErrWorkoutNotFound) {
+		http.Error(w, "Workout not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, ErrRetentionExpired) {
+		http.Error(w, err.Error(), http.StatusGone)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error restoring workout", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, workout)
+}
+
+func (h *WorkoutHandler) handlePurgeWorkout(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+	workoutID, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID format", http.StatusBadRequest)
+		return
+	}
+
+	success, err := h.Service.PurgeWorkout(userID, workoutID)
+	if err != nil {
+		http.Error(w, "Error purging workout", http.StatusInternalServerError)
+		return
+	}
+	if !success {
+		http.Error(w, "Workout not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"message": "Workout permanently deleted"}`))
+}
+
+// AnalyticsHandler serves /workout/analytics/*
+func (h *WorkoutHandler) AnalyticsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch r.URL.Path {
+	case "/workout/analytics/strength":
+		m.PermissionMiddleware(h.handleReadStrengthAnalytics)(w, r)
+	case "/workout/analytics/volume":
+		m.PermissionMiddleware(h.handleReadMuscleVolume)(w, r)
+	case "/workout/analytics/cardio":
+		m.PermissionMiddleware(h.handleReadCardioTotals)(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// handleReadStrengthAnalytics takes exercise, formula, from and to query params.
+// The range defaults to the last year.
+func (h *WorkoutHandler) handleReadStrengthAnalytics(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+	query := r.URL.Query()
+
+	exercise := query.Get("exercise")
+	if exercise == "" {
+		http.Error(w, "Exercise is required", http.StatusBadRequest)
+		return
+	}
+	from, to, ok := util.ParseDateRange(w, r, time.Now().AddDate(-1, 0, 0))
+	if !ok {
+		return
+	}
+
+	analytics, err := h.Service.GetStrengthAnalytics(userID, exercise, query.Get("formula"), from, to)
+	if errors.Is(err, ErrUnknownFormula) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error fetching strength analytics", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, analytics)
+}
+
+// handleReadMuscleVolume takes from, to and weekStart (monday or sunday) query
+// params. The range defaults to the last 12 weeks.
+func (h *WorkoutHandler) handleReadMuscleVolume(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	from, to, ok := util.ParseDateRange(w, r, time.Now().AddDate(0, 0, -7*12))
+	if !ok {
+		return
+	}
+	weekStart, ok := parseWeekStart(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.Service.GetMuscleVolume(userID, from, to, weekStart)
+	if errors.Is(err, ErrRangeTooLarge) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error fetching muscle volume", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, report)
+}
+
+// handleReadCardioTotals takes from, to and weekStart (monday or sunday) query
+// params. The range defaults to the last 12 weeks.
+func (h *WorkoutHandler) handleReadCardioTotals(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(primitive.ObjectID)
+
+	from, to, ok := util.ParseDateRange(w, r, time.Now().AddDate(0, 0, -7*12))
+	if !ok {
+		return
+	}
+	weekStart, ok := parseWeekStart(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.Service.GetCardioTotals(userID, from, to, weekStart)
+	if errors.Is(err, ErrRangeTooLarge) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error fetching cardio totals", http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, report)
+}
+
+func parseWeekStart(w http.ResponseWriter, r *http.Request) (c.WeekStart, bool) {
+	weekStart := c.WeekStart(r.URL.Query().Get("weekStart"))
+	if weekStart == "" {
+		return c.WeekStartMonday, true
+	}
+	if !weekStart.Valid() {
+		http.Error(w, "weekStart must be monday or sunday", http.StatusBadRequest)
+		return weekStart, false
+	}
+	return weekStart, true
+}
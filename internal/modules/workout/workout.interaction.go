@@ -0,0 +1,52 @@
+package workout
+
+import (
+	"context"
+
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// InteractionCounter counts the reactions and comments on a batch of workouts
+// at once. Workouts with none can be left out of the map.
+type InteractionCounter interface {
+	FetchInteractionCounts(ctx context.Context, workoutIDs []primitive.ObjectID) (map[primitive.ObjectID]t.InteractionCounts, error)
+}
+
+// withInteractionCounts fills in the counts on workouts with one lookup
+// however many workouts there are.
+func (s *workoutService) withInteractionCounts(workouts []t.Workout) ([]t.Workout, error) {
+	if len(workouts) == 0 {
+		return workouts, nil
+	}
+	workoutIDs := make([]primitive.ObjectID, len(workouts))
+	for i, workout := range workouts {
+		workoutIDs[i] = workout.ID
+	}
+	counts, err := s.interactions.FetchInteractionCounts(context.TODO(), workoutIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range workouts {
+		count, ok := counts[workouts[i].ID]
+		if !ok {
+			count = t.InteractionCounts{Reactions: map[string]int64{}}
+		}
+		workouts[i].Interactions = &count
+	}
+	return workouts, nil
+}
+
+// GetWorkoutOwner returns who a workout belongs to so the caller can work out
+// what they're allowed to see of it.
+func (s *workoutService) GetWorkoutOwner(workoutID primitive.ObjectID) (primitive.ObjectID, error) {
+	owner, err := s.repo.FetchWorkoutOwner(context.TODO(), workoutID)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	if owner == nil {
+		return primitive.NilObjectID, ErrWorkoutNotFound
+	}
+	return *owner, nil
+}
@@ -0,0 +1,155 @@
+package workout
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// BodyMeasurements returns every *Size field set on a workout config, keyed by
+// its json name.
+func BodyMeasurements(config *t.WorkoutConfig) map[string]float64 {
+	measurements := map[string]float64{}
+	if config == nil {
+		return measurements
+	}
+
+	value := reflect.ValueOf(*config)
+	configType := value.Type()
+	for i := 0; i < configType.NumField(); i++ {
+		name := strings.Split(configType.Field(i).Tag.Get("json"), ",")[0]
+		field := value.Field(i)
+		if !strings.HasSuffix(name, "Size") || field.Kind() != reflect.Ptr || field.IsNil() {
+			continue
+		}
+		if size, ok := field.Elem().Interface().(float64); ok {
+			measurements[name] = size
+		}
+	}
+	return measurements
+}
+
+// IsTraining is false for a config that only holds body data, such as an
+// imported weigh-in, so those days aren't counted as workouts.
+func IsTraining(config *t.WorkoutConfig) bool {
+	return config != nil && (len(config.TargetMuscles) > 0 || len(config.Exercises) > 0 || config.Cardio != nil)
+}
+
+// MeasurementFields lists the json names of every *Size field in WorkoutConfig, in declaration order.
+func MeasurementFields() []string {
+	fields := []string{}
+	configType := reflect.TypeOf(t.WorkoutConfig{})
+	for i := 0; i < configType.NumField(); i++ {
+		name := strings.Split(configType.Field(i).Tag.Get("json"), ",")[0]
+		if strings.HasSuffix(name, "Size") {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+// WithoutBodyData copies config leaving out body weight unless weight is set
+// and the body composition and *Size measurements unless measurements is.
+func WithoutBodyData(config *t.WorkoutConfig, weight bool, measurements bool) *t.WorkoutConfig {
+	if config == nil {
+		return nil
+	}
+	shown := *config
+	if !weight {
+		shown.Weight = nil
+	}
+	if !measurements {
+		shown.BodyFat, shown.MuscleMass, shown.BodyWater, shown.BoneMass = nil, nil, nil, nil
+		value := reflect.ValueOf(&shown).Elem()
+		for i := 0; i < value.NumField(); i++ {
+			if strings.HasSuffix(value.Type().Field(i).Name, "Size") {
+				value.Field(i).SetZero()
+			}
+		}
+	}
+	return &shown
+}
+
+// MergeMeasurements saves imported measurements onto the workout already
+// logged for their day, only filling in fields that are still empty so that
+// importing the same data again changes nothing. A day without a workout gets
+// one holding just the measurements.
+func (s *workoutService) MergeMeasurements(userID primitive.ObjectID, measurements []t.DailyMeasurement) (*t.MeasurementMergeResult, error) {
+	result := &t.MeasurementMergeResult{}
+	for _, measurement := range measurements {
+		existing, err := s.repo.FetchWorkoutByDate(context.TODO(), userID, c.AudienceOwner, measurement.Date)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(existing) == 0 {
+			// there's nothing but body data on these so they're kept to the user
+			newWorkout, err := buildWorkout(userID, t.CreateWorkoutRequest{Date: measurement.Date}, c.VisibilityPrivate)
+			if err != nil {
+				return nil, err
+			}
+			fillMeasurements(newWorkout.Workout, measurement)
+			created, err := s.repo.InsertWorkout(context.TODO(), newWorkout)
+			if err != nil {
+				return nil, err
+			}
+			if err := s.recordRevision(userID, c.RevisionActionCreate, nil, created); err != nil {
+				return nil, err
+			}
+			result.Created++
+			continue
+		}
+
+		before := existing[0]
+		after := before
+		config := t.WorkoutConfig{}
+		if before.Workout != nil {
+			config = *before.Workout
+		}
+		after.Workout = &config
+		if !fillMeasurements(after.Workout, measurement) {
+			result.Unchanged++
+			continue
+		}
+
+		after.UpdatedAt = time.Now()
+		updated, err := s.repo.UpdateWorkout(context.TODO(), after)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.recordRevision(userID, c.RevisionActionUpdate, &before, updated); err != nil {
+			return nil, err
+		}
+		result.Updated++
+	}
+	return result, nil
+}
+
+// fillMeasurements copies each measurement into the config where the config
+// has no value yet, reporting whether anything changed.
+func fillMeasurements(config *t.WorkoutConfig, measurement t.DailyMeasurement) bool {
+	changed := false
+	for _, field := range []struct {
+		target **float64
+		value  *float64
+	}{
+		{&config.Weight, measurement.Weight},
+		{&config.BodyFat, measurement.BodyFat},
+		{&config.MuscleMass, measurement.MuscleMass},
+		{&config.BodyWater, measurement.BodyWater},
+		{&config.BoneMass, measurement.BoneMass},
+		{&config.WaistSize, measurement.WaistSize},
+	} {
+		if *field.target == nil && field.value != nil {
+			value := *field.value
+			*field.target = &value
+			changed = true
+		}
+	}
+	return changed
+}
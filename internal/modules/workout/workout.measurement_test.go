@@ -0,0 +1,33 @@
+package workout
+
+import (
+	"testing"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+)
+
+func TestIsTraining(tt *testing.T) {
+	weight := 80.0
+
+	tests := []struct {
+		name   string
+		config *t.WorkoutConfig
+		want   bool
+	}{
+		{"nothing logged", nil, false},
+		{"weigh-in only", &t.WorkoutConfig{Weight: &weight, WaistSize: &weight}, false},
+		{"empty lists", &t.WorkoutConfig{Weight: &weight, Exercises: []t.Exercise{}, TargetMuscles: []c.TargetMuscles{}}, false},
+		{"target muscles", &t.WorkoutConfig{TargetMuscles: []c.TargetMuscles{c.Legs}}, true},
+		{"exercises", &t.WorkoutConfig{Exercises: []t.Exercise{{Name: "Squat"}}}, true},
+		{"cardio", &t.WorkoutConfig{Weight: &weight, Cardio: &t.CardioSession{}}, true},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			if got := IsTraining(test.config); got != test.want {
+				tt.Fatalf("IsTraining = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -3,33 +3,96 @@ package workout
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	db "github.com/joshibbotson/gym-tracker-backend/internal/db"
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
 
 	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// WorkoutRepository reads take the audience they're for and only return the
+// workouts it can see. Trash and revisions are only ever read by the owner.
 type WorkoutRepository interface {
 	InsertWorkout(ctx context.Context, workout t.Workout) (*t.Workout, error)
-	FetchWorkoutByDate(ctx context.Context, userId primitive.ObjectID, date time.Time) ([]t.Workout, error)
-	FetchWorkoutsByUserId(ctx context.Context, userID primitive.ObjectID) ([]t.YearlyData, error)
-	FetchActivityCountByUserId(ctx context.Context, userID primitive.ObjectID) (int64, error)
+	InsertWorkouts(ctx context.Context, workouts []t.Workout) error
+	FetchWorkoutByDate(ctx context.Context, userId primitive.ObjectID, audience c.Audience, date time.Time) ([]t.Workout, error)
+	FetchWorkoutsByUserId(ctx context.Context, userID primitive.ObjectID, audience c.Audience, from time.Time, to time.Time) ([]t.YearlyData, error)
+	FetchWorkoutYears(ctx context.Context, userID primitive.ObjectID, audience c.Audience) ([]int, error)
+	FetchWorkoutCountsByDate(ctx context.Context, userID primitive.ObjectID, audience c.Audience) ([]t.DateCount, error)
+	FetchActivityCountByUserId(ctx context.Context, userID primitive.ObjectID, audience c.Audience) (int64, error)
+	FetchWorkoutsInRange(ctx context.Context, userID primitive.ObjectID, audience c.Audience, from time.Time, to time.Time) ([]t.Workout, error)
+	FetchWorkoutsWithExercise(ctx context.Context, userID primitive.ObjectID, audience c.Audience, exercise string, from time.Time, to time.Time) ([]t.Workout, error)
+	FetchExerciseHistory(ctx context.Context, userID primitive.ObjectID, exercises []string) ([]t.Workout, error)
+	StreamWorkouts(ctx context.Context, userID primitive.ObjectID, from time.Time, to time.Time, each func(t.Workout) error) error
+	FetchBodyWeights(ctx context.Context, userID primitive.ObjectID, audience c.Audience, from time.Time, to time.Time) ([]t.BodyWeightEntry, error)
+	FetchMuscleVolume(ctx context.Context, userID primitive.ObjectID, audience c.Audience, from time.Time, to time.Time, weekStart c.WeekStart) ([]t.MuscleVolumeBucket, error)
+	FetchCardioTotals(ctx context.Context, userID primitive.ObjectID, audience c.Audience, from time.Time, to time.Time, weekStart c.WeekStart) ([]t.CardioBucket, error)
+	FetchWorkoutById(ctx context.Context, userID primitive.ObjectID, audience c.Audience, workoutID primitive.ObjectID) (*t.Workout, error)
+	FetchFeedWorkouts(ctx context.Context, userIDs []primitive.ObjectID, audience c.Audience, after *t.FeedCursor, limit int) ([]t.Workout, error)
+	FetchWorkoutOwner(ctx context.Context, workoutID primitive.ObjectID) (*primitive.ObjectID, error)
 	UpdateWorkout(ctx context.Context, workout t.Workout) (*t.Workout, error)
-	RemoveWorkout(ctx context.Context, workoutID primitive.ObjectID) (bool, error)
+	ReplaceWorkout(ctx context.Context, workout t.Workout) error
+	DiscardWorkouts(ctx context.Context, workoutIDs []primitive.ObjectID) error
+	SoftDeleteWorkout(ctx context.Context, userID primitive.ObjectID, workoutID primitive.ObjectID, deletedAt time.Time) (bool, error)
+	RestoreWorkout(ctx context.Context, userID primitive.ObjectID, workoutID primitive.ObjectID) (bool, error)
+	FetchDeletedWorkouts(ctx context.Context, userID primitive.ObjectID) ([]t.Workout, error)
+	FetchDeletedWorkoutById(ctx context.Context, userID primitive.ObjectID, workoutID primitive.ObjectID) (*t.Workout, error)
+	RemoveWorkout(ctx context.Context, userID primitive.ObjectID, workoutID primitive.ObjectID) (bool, error)
+	PurgeDeletedWorkouts(ctx context.Context, deletedBefore time.Time) (int64, error)
+	InsertRevision(ctx context.Context, revision t.WorkoutRevision) (*t.WorkoutRevision, error)
+	InsertRevisions(ctx context.Context, revisions []t.WorkoutRevision) error
+	FetchLatestRevisionNumber(ctx context.Context, workoutID primitive.ObjectID) (int, error)
+	FetchRevisions(ctx context.Context, userID primitive.ObjectID, workoutID primitive.ObjectID) ([]t.WorkoutRevision, error)
+	FetchRevision(ctx context.Context, userID primitive.ObjectID, workoutID primitive.ObjectID, revision int) (*t.WorkoutRevision, error)
 }
 
 type workoutRepository struct {
-	workoutCollection *mongo.Collection
+	workoutCollection  *mongo.Collection
+	revisionCollection *mongo.Collection
 }
 
 func NewWorkoutRepository() WorkoutRepository {
+	revisionCollection := db.Client.Database(db.DB_NAME).Collection("workoutRevision")
+	// two changes saved at once can't both take the same revision number
+	db.EnsureIndexes(revisionCollection, mongo.IndexModel{
+		Keys:    bson.D{{Key: "workoutId", Value: 1}, {Key: "revision", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+
 	return &workoutRepository{
-		workoutCollection: db.Client.Database(db.DB_NAME).Collection("workout"),
+		workoutCollection:  db.Client.Database(db.DB_NAME).Collection("workout"),
+		revisionCollection: revisionCollection,
+	}
+}
+
+// notDeleted excludes workouts that are sitting in the trash.
+var notDeleted = bson.M{"$exists": false}
+
+// visibleTo limits a filter to the workouts audience can see, the owner
+// sees them all so nothing is added for them.
+func visibleTo(filter bson.M, audience c.Audience) bson.M {
+	if visibilities := audience.Visibilities(); visibilities != nil {
+		filter["visibility"] = bson.M{"$in": visibilities}
 	}
+	return filter
+}
+
+// training limits a filter to workouts with something trained in them, leaving
+// out days that only hold body data, the query version of IsTraining.
+func training(filter bson.M) bson.M {
+	filter["$or"] = bson.A{
+		bson.M{"workout.targetMuscles.0": bson.M{"$exists": true}},
+		bson.M{"workout.exercises.0": bson.M{"$exists": true}},
+		bson.M{"workout.cardio": bson.M{"$type": "object"}},
+	}
+	return filter
 }
 
 func (r *workoutRepository) InsertWorkout(ctx context.Context, workout t.Workout) (*t.Workout, error) {
@@ -40,19 +103,29 @@ func (r *workoutRepository) InsertWorkout(ctx context.Context, workout t.Workout
 	return &workout, nil
 }
 
-func (r *workoutRepository) FetchWorkoutByDate(ctx context.Context, userId primitive.ObjectID, date time.Time) ([]t.Workout, error) {
+func (r *workoutRepository) InsertWorkouts(ctx context.Context, workouts []t.Workout) error {
+	documents := make([]interface{}, len(workouts))
+	for i, workout := range workouts {
+		documents[i] = workout
+	}
+	_, err := r.workoutCollection.InsertMany(ctx, documents)
+	return err
+}
+
+func (r *workoutRepository) FetchWorkoutByDate(ctx context.Context, userId primitive.ObjectID, audience c.Audience, date time.Time) ([]t.Workout, error) {
 	// Calculate the start and end of the day
 	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
 	endOfDay := startOfDay.Add(24 * time.Hour)
 
 	// Define the query filter
-	filter := bson.M{
+	filter := visibleTo(bson.M{
 		"userId": userId,
 		"date": bson.M{
 			"$gte": startOfDay,
 			"$lt":  endOfDay,
 		},
-	}
+		"deletedAt": notDeleted,
+	}, audience)
 
 	// Query the database
 	var workouts []t.Workout
@@ -75,18 +148,17 @@ func (r *workoutRepository) FetchWorkoutByDate(ctx context.Context, userId primi
 	return workouts, nil
 }
 
-func (r *workoutRepository) FetchWorkoutsByUserId(ctx context.Context, userID primitive.ObjectID) ([]t.YearlyData, error) {
-	startOfYear := time.Date(time.Now().Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
-	endOfYear := time.Date(time.Now().Year(), time.December, 31, 23, 59, 59, 999999999, time.UTC)
-
+// FetchWorkoutsByUserId groups workouts with from <= date < to by year and month.
+func (r *workoutRepository) FetchWorkoutsByUserId(ctx context.Context, userID primitive.ObjectID, audience c.Audience, from time.Time, to time.Time) ([]t.YearlyData, error) {
 	pipeline := mongo.Pipeline{
-		{{Key: "$match", Value: bson.D{
-			{Key: "userId", Value: userID},
-			{Key: "date", Value: bson.D{
-				{Key: "$gte", Value: startOfYear},
-				{Key: "$lte", Value: endOfYear},
-			}},
-		}}},
+		{{Key: "$match", Value: visibleTo(bson.M{
+			"userId": userID,
+			"date": bson.M{
+				"$gte": from,
+				"$lt":  to,
+			},
+			"deletedAt": notDeleted,
+		}, audience)}},
 		{{Key: "$addFields", Value: bson.D{
 			{Key: "ID", Value: "$_id"},
 		}}},
@@ -146,10 +218,71 @@ func (r *workoutRepository) FetchWorkoutsByUserId(ctx context.Context, userID pr
 	return results, nil
 }
 
-func (r *workoutRepository) FetchActivityCountByUserId(ctx context.Context, userId primitive.ObjectID) (int64, error) {
-	filter := bson.M{
-		"userId": userId,
+// FetchWorkoutYears lists the years that have at least one workout, oldest first.
+func (r *workoutRepository) FetchWorkoutYears(ctx context.Context, userID primitive.ObjectID, audience c.Audience) ([]int, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: visibleTo(bson.M{
+			"userId":    userID,
+			"deletedAt": notDeleted,
+		}, audience)}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{{Key: "$year", Value: "$date"}}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+	}
+
+	cursor, err := r.workoutCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("aggregation error: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Year int `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("decoding error: %v", err)
+	}
+
+	years := make([]int, 0, len(results))
+	for _, result := range results {
+		years = append(years, result.Year)
 	}
+	return years, nil
+}
+
+// FetchWorkoutCountsByDate counts workouts per distinct date across all time, oldest first.
+func (r *workoutRepository) FetchWorkoutCountsByDate(ctx context.Context, userID primitive.ObjectID, audience c.Audience) ([]t.DateCount, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: visibleTo(training(bson.M{
+			"userId":    userID,
+			"deletedAt": notDeleted,
+		}), audience)}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$date"},
+			{Key: "workouts", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+	}
+
+	cursor, err := r.workoutCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("aggregation error: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	counts := []t.DateCount{}
+	if err := cursor.All(ctx, &counts); err != nil {
+		return nil, fmt.Errorf("decoding error: %v", err)
+	}
+	return counts, nil
+}
+
+func (r *workoutRepository) FetchActivityCountByUserId(ctx context.Context, userId primitive.ObjectID, audience c.Audience) (int64, error) {
+	filter := visibleTo(training(bson.M{
+		"userId":    userId,
+		"deletedAt": notDeleted,
+	}), audience)
 	count, err := r.workoutCollection.CountDocuments(ctx, filter)
 	if err != nil {
 		return 0, err
@@ -157,6 +290,357 @@ func (r *workoutRepository) FetchActivityCountByUserId(ctx context.Context, user
 	return count, nil
 }
 
+// FetchWorkoutsInRange returns workouts with from <= date < to, oldest first.
+func (r *workoutRepository) FetchWorkoutsInRange(ctx context.Context, userID primitive.ObjectID, audience c.Audience, from time.Time, to time.Time) ([]t.Workout, error) {
+	filter := visibleTo(bson.M{
+		"userId":    userID,
+		"date":      bson.M{"$gte": from, "$lt": to},
+		"deletedAt": notDeleted,
+	}, audience)
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}, {Key: "createdAt", Value: 1}})
+
+	cursor, err := r.workoutCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	workouts := []t.Workout{}
+	if err = cursor.All(ctx, &workouts); err != nil {
+		return nil, err
+	}
+	return workouts, nil
+}
+
+// FetchWorkoutsWithExercise matches the exercise name case-insensitively, oldest first.
+func (r *workoutRepository) FetchWorkoutsWithExercise(ctx context.Context, userID primitive.ObjectID, audience c.Audience, exercise string, from time.Time, to time.Time) ([]t.Workout, error) {
+	filter := visibleTo(bson.M{
+		"userId":                 userID,
+		"date":                   bson.M{"$gte": from, "$lt": to},
+		"workout.exercises.name": primitive.Regex{Pattern: "^\\s*" + regexp.QuoteMeta(strings.TrimSpace(exercise)) + "\\s*$", Options: "i"},
+		"deletedAt":              notDeleted,
+	}, audience)
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}, {Key: "createdAt", Value: 1}})
+
+	cursor, err := r.workoutCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	workouts := []t.Workout{}
+	if err = cursor.All(ctx, &workouts); err != nil {
+		return nil, err
+	}
+	return workouts, nil
+}
+
+// FetchExerciseHistory returns every workout, oldest first, that contains one of
+// the exercises. Names are matched case-insensitively.
+func (r *workoutRepository) FetchExerciseHistory(ctx context.Context, userID primitive.ObjectID, exercises []string) ([]t.Workout, error) {
+	names := make([]interface{}, len(exercises))
+	for i, exercise := range exercises {
+		names[i] = primitive.Regex{Pattern: "^\\s*" + regexp.QuoteMeta(strings.TrimSpace(exercise)) + "\\s*$", Options: "i"}
+	}
+
+	filter := bson.M{
+		"userId":                 userID,
+		"workout.exercises.name": bson.M{"$in": names},
+		"deletedAt":              notDeleted,
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}, {Key: "createdAt", Value: 1}})
+
+	cursor, err := r.workoutCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	workouts := []t.Workout{}
+	if err = cursor.All(ctx, &workouts); err != nil {
+		return nil, err
+	}
+	return workouts, nil
+}
+
+// StreamWorkouts calls each with every workout in the range, oldest first,
+// decoding them one at a time off the cursor. An error from each stops it.
+func (r *workoutRepository) StreamWorkouts(ctx context.Context, userID primitive.ObjectID, from time.Time, to time.Time, each func(t.Workout) error) error {
+	filter := bson.M{
+		"userId":    userID,
+		"date":      bson.M{"$gte": from, "$lt": to},
+		"deletedAt": notDeleted,
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "date", Value: 1}, {Key: "createdAt", Value: 1}}).
+		SetBatchSize(c.StreamBatchSize)
+
+	cursor, err := r.workoutCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var workout t.Workout
+		if err := cursor.Decode(&workout); err != nil {
+			return err
+		}
+		if err := each(workout); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// FetchBodyWeights returns every logged body weight in the range, oldest first.
+func (r *workoutRepository) FetchBodyWeights(ctx context.Context, userID primitive.ObjectID, audience c.Audience, from time.Time, to time.Time) ([]t.BodyWeightEntry, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: visibleTo(bson.M{
+			"userId":         userID,
+			"date":           bson.M{"$gte": from, "$lt": to},
+			"workout.weight": bson.M{"$gt": 0},
+			"deletedAt":      notDeleted,
+		}, audience)}},
+		{{Key: "$sort", Value: bson.D{{Key: "date", Value: 1}}}},
+		{{Key: "$project", Value: bson.D{
+			{Key: "_id", Value: 0},
+			{Key: "date", Value: "$date"},
+			{Key: "weight", Value: "$workout.weight"},
+			{Key: "caloriePhase", Value: "$workout.caloriePhase"},
+		}}},
+	}
+
+	cursor, err := r.workoutCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("aggregation error: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	entries := []t.BodyWeightEntry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, fmt.Errorf("decoding error: %v", err)
+	}
+	return entries, nil
+}
+
+// FetchMuscleVolume buckets workouts into weeks and returns, per week and muscle group,
+// the number of sessions listing it in TargetMuscles and the hard sets and tonnage of
+// exercises training it. Secondary muscles get a reduced share of the sets and tonnage.
+func (r *workoutRepository) FetchMuscleVolume(ctx context.Context, userID primitive.ObjectID, audience c.Audience, from time.Time, to time.Time, weekStart c.WeekStart) ([]t.MuscleVolumeBucket, error) {
+	muscleShares := func(field string, factor float64) bson.D {
+		return bson.D{{Key: "$map", Value: bson.D{
+			{Key: "input", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$workout.exercises." + field, bson.A{}}}}},
+			{Key: "as", Value: "muscle"},
+			{Key: "in", Value: bson.D{{Key: "muscle", Value: "$$muscle"}, {Key: "factor", Value: factor}}},
+		}}}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: visibleTo(bson.M{
+			"userId":    userID,
+			"date":      bson.M{"$gte": from, "$lt": to},
+			"deletedAt": notDeleted,
+		}, audience)}},
+		{{Key: "$addFields", Value: bson.D{
+			{Key: "weekStart", Value: bson.D{{Key: "$dateTrunc", Value: bson.D{
+				{Key: "date", Value: "$date"},
+				{Key: "unit", Value: "week"},
+				{Key: "startOfWeek", Value: string(weekStart)},
+			}}}},
+		}}},
+		{{Key: "$facet", Value: bson.D{
+			{Key: "sessions", Value: bson.A{
+				bson.D{{Key: "$unwind", Value: "$workout.targetMuscles"}},
+				bson.D{{Key: "$group", Value: bson.D{
+					{Key: "_id", Value: bson.D{{Key: "weekStart", Value: "$weekStart"}, {Key: "muscle", Value: "$workout.targetMuscles"}}},
+					{Key: "sessions", Value: bson.D{{Key: "$sum", Value: 1}}},
+				}}},
+			}},
+			{Key: "volume", Value: bson.A{
+				bson.D{{Key: "$unwind", Value: "$workout.exercises"}},
+				bson.D{{Key: "$project", Value: bson.D{
+					{Key: "weekStart", Value: 1},
+					{Key: "sets", Value: bson.D{{Key: "$filter", Value: bson.D{
+						{Key: "input", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$workout.exercises.sets", bson.A{}}}}},
+						{Key: "as", Value: "set"},
+						{Key: "cond", Value: bson.D{{Key: "$and", Value: bson.A{
+							bson.D{{Key: "$ne", Value: bson.A{"$$set.warmup", true}}},
+							bson.D{{Key: "$gt", Value: bson.A{"$$set.reps", 0}}},
+						}}}},
+					}}}},
+					{Key: "muscles", Value: bson.D{{Key: "$concatArrays", Value: bson.A{
+						muscleShares("primaryMuscles", c.PrimaryMuscleWeight),
+						muscleShares("secondaryMuscles", c.SecondaryMuscleWeight),
+					}}}},
+				}}},
+				bson.D{{Key: "$project", Value: bson.D{
+					{Key: "weekStart", Value: 1},
+					{Key: "muscles", Value: 1},
+					{Key: "hardSets", Value: bson.D{{Key: "$size", Value: "$sets"}}},
+					{Key: "tonnage", Value: bson.D{{Key: "$sum", Value: bson.D{{Key: "$map", Value: bson.D{
+						{Key: "input", Value: "$sets"},
+						{Key: "as", Value: "set"},
+						{Key: "in", Value: bson.D{{Key: "$multiply", Value: bson.A{"$$set.reps", bson.D{{Key: "$ifNull", Value: bson.A{"$$set.weight", 0}}}}}}},
+					}}}}}},
+				}}},
+				bson.D{{Key: "$unwind", Value: "$muscles"}},
+				bson.D{{Key: "$group", Value: bson.D{
+					{Key: "_id", Value: bson.D{{Key: "weekStart", Value: "$weekStart"}, {Key: "muscle", Value: "$muscles.muscle"}}},
+					{Key: "hardSets", Value: bson.D{{Key: "$sum", Value: bson.D{{Key: "$multiply", Value: bson.A{"$hardSets", "$muscles.factor"}}}}}},
+					{Key: "tonnage", Value: bson.D{{Key: "$sum", Value: bson.D{{Key: "$multiply", Value: bson.A{"$tonnage", "$muscles.factor"}}}}}},
+				}}},
+			}},
+		}}},
+		{{Key: "$project", Value: bson.D{
+			{Key: "buckets", Value: bson.D{{Key: "$concatArrays", Value: bson.A{"$sessions", "$volume"}}}},
+		}}},
+		{{Key: "$unwind", Value: "$buckets"}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$buckets._id"},
+			{Key: "sessions", Value: bson.D{{Key: "$sum", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$buckets.sessions", 0}}}}}},
+			{Key: "hardSets", Value: bson.D{{Key: "$sum", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$buckets.hardSets", 0}}}}}},
+			{Key: "tonnage", Value: bson.D{{Key: "$sum", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$buckets.tonnage", 0}}}}}},
+		}}},
+		{{Key: "$project", Value: bson.D{
+			{Key: "_id", Value: 0},
+			{Key: "weekStart", Value: "$_id.weekStart"},
+			{Key: "muscle", Value: "$_id.muscle"},
+			{Key: "sessions", Value: 1},
+			{Key: "hardSets", Value: 1},
+			{Key: "tonnage", Value: 1},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "weekStart", Value: 1}, {Key: "muscle", Value: 1}}}},
+	}
+
+	cursor, err := r.workoutCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("aggregation error: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	buckets := []t.MuscleVolumeBucket{}
+	if err := cursor.All(ctx, &buckets); err != nil {
+		return nil, fmt.Errorf("decoding error: %v", err)
+	}
+	return buckets, nil
+}
+
+// FetchCardioTotals sums cardio sessions per week and modality.
+func (r *workoutRepository) FetchCardioTotals(ctx context.Context, userID primitive.ObjectID, audience c.Audience, from time.Time, to time.Time, weekStart c.WeekStart) ([]t.CardioBucket, error) {
+	sumOf := func(field string) bson.D {
+		return bson.D{{Key: "$sum", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$workout.cardio." + field, 0}}}}}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: visibleTo(bson.M{
+			"userId":         userID,
+			"date":           bson.M{"$gte": from, "$lt": to},
+			"deletedAt":      notDeleted,
+			"workout.cardio": bson.M{"$exists": true},
+		}, audience)}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{
+				{Key: "weekStart", Value: bson.D{{Key: "$dateTrunc", Value: bson.D{
+					{Key: "date", Value: "$date"},
+					{Key: "unit", Value: "week"},
+					{Key: "startOfWeek", Value: string(weekStart)},
+				}}}},
+				{Key: "modality", Value: "$workout.cardio.modality"},
+			}},
+			{Key: "sessions", Value: bson.D{{Key: "$sum", Value: 1}}},
+			{Key: "duration", Value: sumOf("duration")},
+			{Key: "distance", Value: sumOf("distance")},
+			{Key: "elevationGain", Value: sumOf("elevationGain")},
+			{Key: "calories", Value: sumOf("calories")},
+		}}},
+		{{Key: "$project", Value: bson.D{
+			{Key: "_id", Value: 0},
+			{Key: "weekStart", Value: "$_id.weekStart"},
+			{Key: "modality", Value: "$_id.modality"},
+			{Key: "sessions", Value: 1},
+			{Key: "duration", Value: 1},
+			{Key: "distance", Value: 1},
+			{Key: "elevationGain", Value: 1},
+			{Key: "calories", Value: 1},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "weekStart", Value: 1}, {Key: "modality", Value: 1}}}},
+	}
+
+	cursor, err := r.workoutCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("aggregation error: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	buckets := []t.CardioBucket{}
+	if err := cursor.All(ctx, &buckets); err != nil {
+		return nil, fmt.Errorf("decoding error: %v", err)
+	}
+	return buckets, nil
+}
+
+func (r *workoutRepository) FetchWorkoutById(ctx context.Context, userID primitive.ObjectID, audience c.Audience, workoutID primitive.ObjectID) (*t.Workout, error) {
+	var workout t.Workout
+	filter := visibleTo(bson.M{"_id": workoutID, "userId": userID, "deletedAt": notDeleted}, audience)
+	err := r.workoutCollection.FindOne(ctx, filter).Decode(&workout)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &workout, nil
+}
+
+// FetchFeedWorkouts returns the newest workouts of any of userIDs, by date
+// then id so a page can carry on after the last workout of the one before.
+func (r *workoutRepository) FetchFeedWorkouts(ctx context.Context, userIDs []primitive.ObjectID, audience c.Audience, after *t.FeedCursor, limit int) ([]t.Workout, error) {
+	filter := visibleTo(bson.M{
+		"userId":    bson.M{"$in": userIDs},
+		"deletedAt": notDeleted,
+	}, audience)
+	if after != nil {
+		filter["$or"] = bson.A{
+			bson.M{"date": bson.M{"$lt": after.Date}},
+			bson.M{"date": after.Date, "_id": bson.M{"$lt": after.ID}},
+		}
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "date", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.workoutCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	workouts := []t.Workout{}
+	if err = cursor.All(ctx, &workouts); err != nil {
+		return nil, err
+	}
+	return workouts, nil
+}
+
+// FetchWorkoutOwner returns who a workout belongs to, nil if it doesn't exist
+// or is in the trash. Nothing else of the workout is read, so the caller can
+// work out the audience before fetching it.
+func (r *workoutRepository) FetchWorkoutOwner(ctx context.Context, workoutID primitive.ObjectID) (*primitive.ObjectID, error) {
+	var workout struct {
+		UserId primitive.ObjectID `bson:"userId"`
+	}
+	opts := options.FindOne().SetProjection(bson.M{"userId": 1})
+	err := r.workoutCollection.FindOne(ctx, bson.M{"_id": workoutID, "deletedAt": notDeleted}, opts).Decode(&workout)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &workout.UserId, nil
+}
+
 func (r *workoutRepository) UpdateWorkout(ctx context.Context, workout t.Workout) (*t.Workout, error) {
 	_, err := r.workoutCollection.UpdateByID(ctx, workout.ID, bson.M{"$set": workout})
 	if err != nil {
@@ -165,10 +649,175 @@ func (r *workoutRepository) UpdateWorkout(ctx context.Context, workout t.Workout
 	return &workout, nil
 }
 
-func (r *workoutRepository) RemoveWorkout(ctx context.Context, workoutID primitive.ObjectID) (bool, error) {
-	res, err := r.workoutCollection.DeleteOne(ctx, bson.M{"_id": workoutID})
+// ReplaceWorkout writes the whole document back, unlike UpdateWorkout fields
+// workout doesn't have are removed.
+func (r *workoutRepository) ReplaceWorkout(ctx context.Context, workout t.Workout) error {
+	_, err := r.workoutCollection.ReplaceOne(ctx, bson.M{"_id": workout.ID}, workout)
+	return err
+}
+
+// DiscardWorkouts removes workouts for good whether or not they're in the
+// trash, revisions included.
+func (r *workoutRepository) DiscardWorkouts(ctx context.Context, workoutIDs []primitive.ObjectID) error {
+	if _, err := r.workoutCollection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": workoutIDs}}); err != nil {
+		return err
+	}
+	_, err := r.revisionCollection.DeleteMany(ctx, bson.M{"workoutId": bson.M{"$in": workoutIDs}})
+	return err
+}
+
+func (r *workoutRepository) SoftDeleteWorkout(ctx context.Context, userID primitive.ObjectID, workoutID primitive.ObjectID, deletedAt time.Time) (bool, error) {
+	res, err := r.workoutCollection.UpdateOne(ctx,
+		bson.M{"_id": workoutID, "userId": userID, "deletedAt": notDeleted},
+		bson.M{"$set": bson.M{"deletedAt": deletedAt}},
+	)
 	if err != nil {
 		return false, err
 	}
-	return res.DeletedCount > 0, nil
+	return res.ModifiedCount > 0, nil
+}
+
+func (r *workoutRepository) RestoreWorkout(ctx context.Context, userID primitive.ObjectID, workoutID primitive.ObjectID) (bool, error) {
+	res, err := r.workoutCollection.UpdateOne(ctx,
+		bson.M{"_id": workoutID, "userId": userID, "deletedAt": bson.M{"$exists": true}},
+		bson.M{"$unset": bson.M{"deletedAt": ""}, "$set": bson.M{"updatedAt": time.Now()}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount > 0, nil
+}
+
+func (r *workoutRepository) FetchDeletedWorkouts(ctx context.Context, userID primitive.ObjectID) ([]t.Workout, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "deletedAt", Value: -1}})
+	cursor, err := r.workoutCollection.Find(ctx, bson.M{"userId": userID, "deletedAt": bson.M{"$exists": true}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	workouts := []t.Workout{}
+	if err = cursor.All(ctx, &workouts); err != nil {
+		return nil, err
+	}
+	return workouts, nil
+}
+
+func (r *workoutRepository) FetchDeletedWorkoutById(ctx context.Context, userID primitive.ObjectID, workoutID primitive.ObjectID) (*t.Workout, error) {
+	var workout t.Workout
+	err := r.workoutCollection.FindOne(ctx, bson.M{"_id": workoutID, "userId": userID, "deletedAt": bson.M{"$exists": true}}).Decode(&workout)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &workout, nil
+}
+
+// RemoveWorkout hard deletes a workout that is already in the trash along with its revisions.
+func (r *workoutRepository) RemoveWorkout(ctx context.Context, userID primitive.ObjectID, workoutID primitive.ObjectID) (bool, error) {
+	res, err := r.workoutCollection.DeleteOne(ctx, bson.M{"_id": workoutID, "userId": userID, "deletedAt": bson.M{"$exists": true}})
+	if err != nil {
+		return false, err
+	}
+	if res.DeletedCount == 0 {
+		return false, nil
+	}
+	if _, err := r.revisionCollection.DeleteMany(ctx, bson.M{"workoutId": workoutID}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// PurgeDeletedWorkouts removes workouts trashed before deletedBefore with their
+// revisions. Each delete checks deletedAt again so a workout restored since it
+// was listed is left alone.
+func (r *workoutRepository) PurgeDeletedWorkouts(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	filter := bson.M{"deletedAt": bson.M{"$lt": deletedBefore}}
+	cursor, err := r.workoutCollection.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var expired []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &expired); err != nil {
+		return 0, err
+	}
+
+	var purged int64
+	for _, workout := range expired {
+		res, err := r.workoutCollection.DeleteOne(ctx, bson.M{"_id": workout.ID, "deletedAt": bson.M{"$lt": deletedBefore}})
+		if err != nil {
+			return purged, err
+		}
+		if res.DeletedCount == 0 {
+			continue
+		}
+		if _, err := r.revisionCollection.DeleteMany(ctx, bson.M{"workoutId": workout.ID}); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+func (r *workoutRepository) InsertRevision(ctx context.Context, revision t.WorkoutRevision) (*t.WorkoutRevision, error) {
+	_, err := r.revisionCollection.InsertOne(ctx, revision)
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+func (r *workoutRepository) InsertRevisions(ctx context.Context, revisions []t.WorkoutRevision) error {
+	documents := make([]interface{}, len(revisions))
+	for i, revision := range revisions {
+		documents[i] = revision
+	}
+	_, err := r.revisionCollection.InsertMany(ctx, documents)
+	return err
+}
+
+func (r *workoutRepository) FetchLatestRevisionNumber(ctx context.Context, workoutID primitive.ObjectID) (int, error) {
+	var latest t.WorkoutRevision
+	opts := options.FindOne().SetSort(bson.D{{Key: "revision", Value: -1}})
+	err := r.revisionCollection.FindOne(ctx, bson.M{"workoutId": workoutID}, opts).Decode(&latest)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return latest.Revision, nil
+}
+
+func (r *workoutRepository) FetchRevisions(ctx context.Context, userID primitive.ObjectID, workoutID primitive.ObjectID) ([]t.WorkoutRevision, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "revision", Value: -1}})
+	cursor, err := r.revisionCollection.Find(ctx, bson.M{"workoutId": workoutID, "userId": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	revisions := []t.WorkoutRevision{}
+	if err = cursor.All(ctx, &revisions); err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+func (r *workoutRepository) FetchRevision(ctx context.Context, userID primitive.ObjectID, workoutID primitive.ObjectID, revision int) (*t.WorkoutRevision, error) {
+	var result t.WorkoutRevision
+	err := r.revisionCollection.FindOne(ctx, bson.M{"workoutId": workoutID, "userId": userID, "revision": revision}).Decode(&result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &result, nil
 }
@@ -0,0 +1,91 @@
+package workout
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+)
+
+// diffWorkouts compares the date, visibility and every WorkoutConfig field of two workouts
+// and returns the fields that changed, keyed by their json name.
+// A nil before or after is treated as an empty workout (create / delete).
+func diffWorkouts(before, after *t.Workout) []t.FieldChange {
+	changes := []t.FieldChange{}
+
+	var beforeDate, afterDate interface{}
+	if before != nil {
+		beforeDate = before.Date
+	}
+	if after != nil {
+		afterDate = after.Date
+	}
+	if !sameValue(beforeDate, afterDate) {
+		changes = append(changes, t.FieldChange{Field: "date", Before: beforeDate, After: afterDate})
+	}
+
+	var beforeVisibility, afterVisibility interface{}
+	if before != nil && before.Visibility != "" {
+		beforeVisibility = before.Visibility
+	}
+	if after != nil && after.Visibility != "" {
+		afterVisibility = after.Visibility
+	}
+	if beforeVisibility != afterVisibility {
+		changes = append(changes, t.FieldChange{Field: "visibility", Before: beforeVisibility, After: afterVisibility})
+	}
+
+	beforeConfig := configValue(before)
+	afterConfig := configValue(after)
+	configType := reflect.TypeOf(t.WorkoutConfig{})
+
+	for i := 0; i < configType.NumField(); i++ {
+		field := configType.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+
+		beforeField := fieldValue(beforeConfig.Field(i))
+		afterField := fieldValue(afterConfig.Field(i))
+		if sameValue(beforeField, afterField) {
+			continue
+		}
+		changes = append(changes, t.FieldChange{Field: name, Before: beforeField, After: afterField})
+	}
+
+	return changes
+}
+
+// sameValue compares times with Equal, as a time read back from Mongo has lost
+// its monotonic reading and location and never DeepEquals the one saved.
+func sameValue(before, after interface{}) bool {
+	beforeTime, beforeIsTime := before.(time.Time)
+	afterTime, afterIsTime := after.(time.Time)
+	if beforeIsTime && afterIsTime {
+		return beforeTime.Equal(afterTime)
+	}
+	return reflect.DeepEqual(before, after)
+}
+
+func configValue(workout *t.Workout) reflect.Value {
+	if workout == nil || workout.Workout == nil {
+		return reflect.ValueOf(t.WorkoutConfig{})
+	}
+	return reflect.ValueOf(*workout.Workout)
+}
+
+// fieldValue dereferences pointers and collapses empty values to nil so that
+// a missing field and an explicitly empty one don't show up as a change.
+func fieldValue(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return v.Elem().Interface()
+	case reflect.Slice, reflect.Map:
+		if v.Len() == 0 {
+			return nil
+		}
+	}
+	return v.Interface()
+}
@@ -0,0 +1,78 @@
+package workout
+
+import (
+	"testing"
+	"time"
+
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+)
+
+func changedFields(changes []t.FieldChange) map[string]bool {
+	fields := map[string]bool{}
+	for _, change := range changes {
+		fields[change.Field] = true
+	}
+	return fields
+}
+
+func TestDiffWorkouts(tt *testing.T) {
+	weight := 80.0
+	heavier := 81.5
+	saved := time.Now()
+	// what the same instant looks like once read back from Mongo
+	roundTripped := time.UnixMilli(saved.UnixMilli()).UTC()
+	saved = time.UnixMilli(saved.UnixMilli()).In(time.FixedZone("UTC-8", -8*60*60))
+
+	tests := []struct {
+		name   string
+		before *t.Workout
+		after  *t.Workout
+		want   []string
+	}{
+		{
+			name:   "round tripped date is unchanged",
+			before: &t.Workout{Date: saved, Workout: &t.WorkoutConfig{Weight: &weight}},
+			after:  &t.Workout{Date: roundTripped, Workout: &t.WorkoutConfig{Weight: &weight}},
+			want:   nil,
+		},
+		{
+			name:   "moved date",
+			before: &t.Workout{Date: roundTripped},
+			after:  &t.Workout{Date: roundTripped.AddDate(0, 0, 1)},
+			want:   []string{"date"},
+		},
+		{
+			name:   "changed weight",
+			before: &t.Workout{Date: saved, Workout: &t.WorkoutConfig{Weight: &weight}},
+			after:  &t.Workout{Date: roundTripped, Workout: &t.WorkoutConfig{Weight: &heavier}},
+			want:   []string{"weight"},
+		},
+		{
+			name:   "empty and missing exercises are the same",
+			before: &t.Workout{Date: saved, Workout: &t.WorkoutConfig{Exercises: []t.Exercise{}}},
+			after:  &t.Workout{Date: saved},
+			want:   nil,
+		},
+		{
+			name:   "delete",
+			before: &t.Workout{Date: saved, Workout: &t.WorkoutConfig{Weight: &weight}},
+			after:  nil,
+			want:   []string{"date", "weight"},
+		},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			changes := diffWorkouts(test.before, test.after)
+			fields := changedFields(changes)
+			if len(fields) != len(test.want) {
+				tt.Fatalf("got changes %v, want %v", changes, test.want)
+			}
+			for _, field := range test.want {
+				if !fields[field] {
+					tt.Fatalf("got changes %v, want %v", changes, test.want)
+				}
+			}
+		})
+	}
+}
@@ -2,36 +2,191 @@ package workout
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"strconv"
 	"time"
 
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/record"
+	"github.com/joshibbotson/gym-tracker-backend/internal/modules/user"
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
 	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type WorkoutService interface {
 	CreateWorkout(userID primitive.ObjectID, workout t.CreateWorkoutRequest) (*t.Workout, error)
-	GetWorkoutsByUserId(userID primitive.ObjectID) ([]t.YearlyData, error)
+	CreateWorkouts(userID primitive.ObjectID, workouts []t.CreateWorkoutRequest) ([]t.Workout, error)
+	MergeMeasurements(userID primitive.ObjectID, measurements []t.DailyMeasurement) (*t.MeasurementMergeResult, error)
+	GetWorkoutsByUserId(userID primitive.ObjectID, from time.Time, to time.Time, weekStart c.WeekStart) ([]t.YearlyData, error)
+	GetWorkoutYears(userID primitive.ObjectID) ([]int, error)
+	GetWorkoutCountsByDate(userID primitive.ObjectID, audience c.Audience) ([]t.DateCount, error)
+	GetBodyWeights(userID primitive.ObjectID, audience c.Audience, from time.Time, to time.Time) ([]t.BodyWeightEntry, error)
 	GetActivityCountByUserId(userID primitive.ObjectID) (int64, error)
 	GetWorkoutsByDate(userID primitive.ObjectID, date time.Time) ([]t.Workout, error)
+	GetWorkoutsInRange(userID primitive.ObjectID, from time.Time, to time.Time) ([]t.Workout, error)
+	GetExerciseHistory(userID primitive.ObjectID, exercises []string) ([]t.Workout, error)
+	StreamWorkouts(userID primitive.ObjectID, from time.Time, to time.Time, each func(t.Workout) error) error
+	GetWorkoutById(userID primitive.ObjectID, workoutID primitive.ObjectID) (*t.Workout, error)
 	UpdateWorkout(userID primitive.ObjectID, workout t.UpdateWorkoutRequest) ([]t.Workout, error)
-	DeleteWorkout(workoutID primitive.ObjectID) (bool, error)
+	DeleteWorkout(userID primitive.ObjectID, workoutID primitive.ObjectID) (bool, error)
+	GetRevisions(userID primitive.ObjectID, workoutID primitive.ObjectID) ([]t.WorkoutRevision, error)
+	GetRevision(userID primitive.ObjectID, workoutID primitive.ObjectID, revision int) (*t.WorkoutRevision, error)
+	RestoreRevision(userID primitive.ObjectID, workoutID primitive.ObjectID, revision int) (*t.Workout, error)
+	GetTrash(userID primitive.ObjectID) ([]t.TrashedWorkout, error)
+	RestoreFromTrash(userID primitive.ObjectID, workoutID primitive.ObjectID) (*t.Workout, error)
+	PurgeWorkout(userID primitive.ObjectID, workoutID primitive.ObjectID) (bool, error)
+	PurgeExpiredTrash() (int64, error)
+	GetStrengthAnalytics(userID primitive.ObjectID, exercise string, formula string, from time.Time, to time.Time) (*t.StrengthAnalytics, error)
+	GetMuscleVolume(userID primitive.ObjectID, from time.Time, to time.Time, weekStart c.WeekStart) (*t.MuscleVolumeReport, error)
+	GetCardioTotals(userID primitive.ObjectID, from time.Time, to time.Time, weekStart c.WeekStart) (*t.CardioReport, error)
+	GetVisibleWorkouts(userID primitive.ObjectID, audience c.Audience, from time.Time, to time.Time) ([]t.Workout, error)
+	GetVisibleWorkout(userID primitive.ObjectID, audience c.Audience, workoutID primitive.ObjectID) (*t.Workout, error)
+	GetSharedCalendar(userID primitive.ObjectID, from time.Time, to time.Time, weekStart c.WeekStart) ([]t.YearlyData, error)
+	GetFeedWorkouts(userIDs []primitive.ObjectID, after *t.FeedCursor, limit int) ([]t.Workout, error)
+	GetWorkoutOwner(workoutID primitive.ObjectID) (primitive.ObjectID, error)
 }
 
+var (
+	ErrWorkoutNotFound   = errors.New("workout not found")
+	ErrRevisionNotFound  = errors.New("revision not found")
+	ErrRetentionExpired  = errors.New("workout is past the trash retention window")
+	ErrInvalidRange      = errors.New("from must be before to")
+	ErrRangeTooLarge     = errors.New("date range is too large")
+	ErrUnknownFormula    = errors.New("unknown formula, expected epley, brzycki or lombardi")
+	ErrInvalidCardio     = errors.New("cardio needs a valid modality and a positive duration, distances and heart rates can't be negative")
+	ErrInvalidVisibility = errors.New("visibility must be private, friends or public")
+)
+
 type workoutService struct {
-	repo WorkoutRepository
+	repo              WorkoutRepository
+	records           record.RecordService
+	users             user.UserService
+	interactions      InteractionCounter
+	trashRetention    time.Duration
+	strengthStandards []c.StrengthStandard
+}
+
+func NewWorkoutService(repo WorkoutRepository, records record.RecordService, users user.UserService, interactions InteractionCounter) WorkoutService {
+	return &workoutService{
+		repo:              repo,
+		records:           records,
+		users:             users,
+		interactions:      interactions,
+		trashRetention:    getTrashRetention(),
+		strengthStandards: loadStrengthStandards(),
+	}
 }
 
-func NewWorkoutService(repo WorkoutRepository) WorkoutService {
-	return &workoutService{repo: repo}
+func getTrashRetention() time.Duration {
+	days, err := strconv.Atoi(os.Getenv("WORKOUT_TRASH_RETENTION_DAYS"))
+	if err != nil || days <= 0 {
+		return c.TrashRetention
+	}
+	return time.Duration(days) * 24 * time.Hour
 }
 
 func (s *workoutService) CreateWorkout(userID primitive.ObjectID, workout t.CreateWorkoutRequest) (*t.Workout, error) {
+	if !validCardio(workout.Cardio) {
+		return nil, ErrInvalidCardio
+	}
+	visibility, err := s.defaultVisibility(userID)
+	if err != nil {
+		return nil, err
+	}
+	newWorkout, err := buildWorkout(userID, workout, visibility)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.repo.InsertWorkout(context.TODO(), newWorkout)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.recordRevision(userID, c.RevisionActionCreate, nil, created); err != nil {
+		return nil, err
+	}
+	s.refreshRecords(userID, created)
+	return created, nil
+}
+
+// CreateWorkouts saves a batch of workouts in one insert and recomputes
+// records once for all of them, for imports of a lot of history at a time.
+func (s *workoutService) CreateWorkouts(userID primitive.ObjectID, workouts []t.CreateWorkoutRequest) ([]t.Workout, error) {
+	visibility, err := s.defaultVisibility(userID)
+	if err != nil {
+		return nil, err
+	}
+	newWorkouts := make([]t.Workout, 0, len(workouts))
+	for _, workout := range workouts {
+		if !validCardio(workout.Cardio) {
+			return nil, ErrInvalidCardio
+		}
+		newWorkout, err := buildWorkout(userID, workout, visibility)
+		if err != nil {
+			return nil, err
+		}
+		newWorkouts = append(newWorkouts, newWorkout)
+	}
+	if len(newWorkouts) == 0 {
+		return newWorkouts, nil
+	}
+
+	if err := s.repo.InsertWorkouts(context.TODO(), newWorkouts); err != nil {
+		return nil, err
+	}
+	// the workouts are new so each starts its history at revision 1
+	created := make([]*t.Workout, len(newWorkouts))
+	revisions := make([]t.WorkoutRevision, len(newWorkouts))
+	for i := range newWorkouts {
+		created[i] = &newWorkouts[i]
+		revisions[i] = newRevision(userID, c.RevisionActionCreate, nil, created[i])
+		revisions[i].Revision = 1
+	}
+	if err := s.repo.InsertRevisions(context.TODO(), revisions); err != nil {
+		// no workout is kept without its revision, see recordRevision
+		ids := make([]primitive.ObjectID, len(newWorkouts))
+		for i := range newWorkouts {
+			ids[i] = newWorkouts[i].ID
+		}
+		if undoErr := s.repo.DiscardWorkouts(context.TODO(), ids); undoErr != nil {
+			fmt.Println("error discarding workouts without revisions:", undoErr)
+		}
+		return nil, err
+	}
+	s.refreshRecords(userID, created...)
+	return newWorkouts, nil
+}
+
+// defaultVisibility is the visibility the user gives new workouts.
+func (s *workoutService) defaultVisibility(userID primitive.ObjectID) (c.Visibility, error) {
+	settings, err := s.users.GetSettings(userID)
+	if err != nil {
+		return "", err
+	}
+	return settings.DefaultVisibility, nil
+}
+
+// buildWorkout uses visibility unless the request picks one.
+func buildWorkout(userID primitive.ObjectID, workout t.CreateWorkoutRequest, visibility c.Visibility) (t.Workout, error) {
+	if workout.Visibility != nil {
+		if !workout.Visibility.Valid() {
+			return t.Workout{}, ErrInvalidVisibility
+		}
+		visibility = *workout.Visibility
+	}
 	config := t.WorkoutConfig{
 		Weight:           workout.Weight,
+		BodyFat:          workout.BodyFat,
+		MuscleMass:       workout.MuscleMass,
+		BodyWater:        workout.BodyWater,
+		BoneMass:         workout.BoneMass,
 		TargetMuscles:    workout.TargetMuscles,
+		Exercises:        withCatalogMuscles(workout.Exercises),
 		CaloriePhase:     workout.CaloriePhase,
+		Cardio:           withDerivedPace(workout.Cardio),
 		NeckSize:         workout.NeckSize,
 		ShoulderSize:     workout.ShoulderSize,
 		LeftCalfSize:     workout.LeftCalfSize,
@@ -51,111 +206,147 @@ func (s *workoutService) CreateWorkout(userID primitive.ObjectID, workout t.Crea
 		RightForearmSize: workout.RightForearmSize,
 	}
 
-	newWorkout := t.Workout{
-		ID:        primitive.NewObjectID(),
-		UserId:    userID,
-		Date:      workout.Date,
-		Workout:   &config,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+	return t.Workout{
+		ID:         primitive.NewObjectID(),
+		UserId:     userID,
+		Date:       workout.Date,
+		Workout:    &config,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+		Visibility: visibility,
+	}, nil
+}
+
+func (s *workoutService) GetWorkoutsByDate(userId primitive.ObjectID, date time.Time) ([]t.Workout, error) {
+	workouts, err := s.repo.FetchWorkoutByDate(context.TODO(), userId, c.AudienceOwner, date)
+	if err != nil {
+		return nil, err
 	}
+	return s.withInteractionCounts(workouts)
+}
 
-	return s.repo.InsertWorkout(context.TODO(), newWorkout)
+func (s *workoutService) GetWorkoutsInRange(userID primitive.ObjectID, from time.Time, to time.Time) ([]t.Workout, error) {
+	return s.repo.FetchWorkoutsInRange(context.TODO(), userID, c.AudienceOwner, from, to)
 }
 
-func (s *workoutService) GetWorkoutsByDate(userId primitive.ObjectID, date time.Time) ([]t.Workout, error) {
-	return s.repo.FetchWorkoutByDate(context.TODO(), userId, date)
+// GetExerciseHistory returns every workout, oldest first, that contains one of
+// the exercises, for rebuilding their personal records.
+func (s *workoutService) GetExerciseHistory(userID primitive.ObjectID, exercises []string) ([]t.Workout, error) {
+	return s.repo.FetchExerciseHistory(context.TODO(), userID, exercises)
+}
+
+// StreamWorkouts calls each with every workout in the range, oldest first,
+// without holding them all in memory, for exports of a whole history.
+func (s *workoutService) StreamWorkouts(userID primitive.ObjectID, from time.Time, to time.Time, each func(t.Workout) error) error {
+	return s.repo.StreamWorkouts(context.TODO(), userID, from, to, each)
+}
+
+func (s *workoutService) GetWorkoutById(userID primitive.ObjectID, workoutID primitive.ObjectID) (*t.Workout, error) {
+	return s.repo.FetchWorkoutById(context.TODO(), userID, c.AudienceOwner, workoutID)
+}
 
+// GetWorkoutsByUserId returns every day with from <= date < to, days without a
+// workout included as placeholders. When weekStart is set the range is widened
+// to whole weeks so the calendar can be laid out in week columns.
+func (s *workoutService) GetWorkoutsByUserId(userID primitive.ObjectID, from time.Time, to time.Time, weekStart c.WeekStart) ([]t.YearlyData, error) {
+	return s.calendar(userID, c.AudienceOwner, from, to, weekStart)
 }
 
-func (s *workoutService) GetWorkoutsByUserId(userID primitive.ObjectID) ([]t.YearlyData, error) {
-	workouts, err := s.repo.FetchWorkoutsByUserId(context.TODO(), userID)
+// calendar is GetWorkoutsByUserId for any audience.
+func (s *workoutService) calendar(userID primitive.ObjectID, audience c.Audience, from time.Time, to time.Time, weekStart c.WeekStart) ([]t.YearlyData, error) {
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	if weekStart != "" {
+		from = startOfWeek(from, weekStart.Weekday())
+		to = startOfWeek(to.AddDate(0, 0, -1), weekStart.Weekday()).AddDate(0, 0, 7)
+	}
+	if !from.Before(to) {
+		return nil, ErrInvalidRange
+	}
+	if to.Sub(from) > c.MaxCalendarDays*24*time.Hour {
+		return nil, ErrRangeTooLarge
+	}
+
+	workouts, err := s.repo.FetchWorkoutsByUserId(context.TODO(), userID, audience, from, to)
 	if err != nil {
 		return nil, err
 	}
 
-	return fillMissingDates(workouts), nil
+	return fillMissingDates(workouts, from, to), nil
+}
+
+func (s *workoutService) GetWorkoutYears(userID primitive.ObjectID) ([]int, error) {
+	return s.repo.FetchWorkoutYears(context.TODO(), userID, c.AudienceOwner)
 }
 
 func (s *workoutService) GetActivityCountByUserId(userID primitive.ObjectID) (int64, error) {
 
-	return s.repo.FetchActivityCountByUserId(context.TODO(), userID)
+	return s.repo.FetchActivityCountByUserId(context.TODO(), userID, c.AudienceOwner)
 }
 
-func fillMissingDates(workoutData []t.YearlyData) []t.YearlyData {
-	location := time.UTC
+func (s *workoutService) GetWorkoutCountsByDate(userID primitive.ObjectID, audience c.Audience) ([]t.DateCount, error) {
+	return s.repo.FetchWorkoutCountsByDate(context.TODO(), userID, audience)
+}
 
-	if len(workoutData) == 0 {
-		year := time.Now().Year()
-		// Initialize a new YearlyData with all months and days for the year
-		workoutData = []t.YearlyData{{Year: year, Months: []t.MonthlyData{}}}
-	}
+func (s *workoutService) GetBodyWeights(userID primitive.ObjectID, audience c.Audience, from time.Time, to time.Time) ([]t.BodyWeightEntry, error) {
+	return s.repo.FetchBodyWeights(context.TODO(), userID, audience, from, to)
+}
 
-	for i, year := range workoutData {
-		yearInt := year.Year
-		// Create a map for existing months for quick lookup
-		existingMonths := make(map[int][]t.DailyWorkout)
+// fillMissingDates lays out every day with from <= date < to by year and month,
+// adding an empty placeholder for each day without a workout.
+func fillMissingDates(workoutData []t.YearlyData, from time.Time, to time.Time) []t.YearlyData {
+	existingDates := make(map[string][]t.DailyWorkout)
+	for _, year := range workoutData {
 		for _, month := range year.Months {
-			monthNumber, err := strconv.Atoi(month.Month)
-			if err != nil {
-				fmt.Printf("Error parsing month: %v\n", err)
-				continue
+			for _, workout := range month.Workouts {
+				dateStr := workout.Date.UTC().Format("2006-01-02")
+				existingDates[dateStr] = append(existingDates[dateStr], workout)
 			}
-			existingMonths[monthNumber] = month.Workouts
 		}
+	}
 
-		// Fill all months for the year
-		var filledMonths []t.MonthlyData
-		for monthNumber := 1; monthNumber <= 12; monthNumber++ {
-			// Get the first and last day of the month
-			firstDay := time.Date(yearInt, time.Month(monthNumber), 1, 0, 0, 0, 0, location)
-			lastDay := firstDay.AddDate(0, 1, -1)
-
-			// Create a map of all existing workout dates for this month
-			existingDates := make(map[string]bool)
-			for _, workout := range existingMonths[monthNumber] {
-				existingDates[workout.Date.Format("2006-01-02")] = true
-			}
-
-			// Create a full list of dates for the month
-			var filledWorkouts []t.DailyWorkout
-			for d := firstDay; !d.After(lastDay); d = d.AddDate(0, 0, 1) {
-				dateStr := d.Format("2006-01-02")
-				if existingDates[dateStr] {
-					// Add all existing workouts for this date
-					for _, workout := range existingMonths[monthNumber] {
-						if workout.Date.Format("2006-01-02") == dateStr {
-							filledWorkouts = append(filledWorkouts, workout)
-						}
-					}
-				} else {
-					// Add placeholder for missing date
-					filledWorkouts = append(filledWorkouts, t.DailyWorkout{
-						Date:   d,
-						Config: nil,
-					})
-				}
-			}
+	filled := []t.YearlyData{}
+	for d := from; d.Before(to); d = d.AddDate(0, 0, 1) {
+		if len(filled) == 0 || filled[len(filled)-1].Year != d.Year() {
+			filled = append(filled, t.YearlyData{Year: d.Year(), Months: []t.MonthlyData{}})
+		}
+		year := &filled[len(filled)-1]
 
-			// Add the filled month to the result
-			filledMonths = append(filledMonths, t.MonthlyData{
-				Month:    fmt.Sprintf("%02d", monthNumber), // Ensure consistent month format
-				Workouts: filledWorkouts,
-			})
+		month := fmt.Sprintf("%02d", int(d.Month())) // Ensure consistent month format
+		if len(year.Months) == 0 || year.Months[len(year.Months)-1].Month != month {
+			year.Months = append(year.Months, t.MonthlyData{Month: month, Workouts: []t.DailyWorkout{}})
 		}
+		monthData := &year.Months[len(year.Months)-1]
 
-		// Update the year with the filled months
-		workoutData[i].Months = filledMonths
+		if workouts, ok := existingDates[d.Format("2006-01-02")]; ok {
+			// Add all existing workouts for this date
+			monthData.Workouts = append(monthData.Workouts, workouts...)
+			continue
+		}
+		// Add placeholder for missing date
+		monthData.Workouts = append(monthData.Workouts, t.DailyWorkout{
+			Date:   d,
+			Config: nil,
+		})
 	}
 
-	return workoutData
+	return filled
 }
 
 func (s *workoutService) UpdateWorkout(userID primitive.ObjectID, workout t.UpdateWorkoutRequest) ([]t.Workout, error) {
+	if !validCardio(workout.Cardio) {
+		return nil, ErrInvalidCardio
+	}
 	config := t.WorkoutConfig{
 		Weight:           workout.Weight,
+		BodyFat:          workout.BodyFat,
+		MuscleMass:       workout.MuscleMass,
+		BodyWater:        workout.BodyWater,
+		BoneMass:         workout.BoneMass,
 		TargetMuscles:    workout.TargetMuscles,
+		Exercises:        withCatalogMuscles(workout.Exercises),
 		CaloriePhase:     workout.CaloriePhase,
+		Cardio:           withDerivedPace(workout.Cardio),
 		NeckSize:         workout.NeckSize,
 		ShoulderSize:     workout.ShoulderSize,
 		LeftCalfSize:     workout.LeftCalfSize,
@@ -175,11 +366,29 @@ func (s *workoutService) UpdateWorkout(userID primitive.ObjectID, workout t.Upda
 		RightForearmSize: workout.RightForearmSize,
 	}
 
+	existing, err := s.repo.FetchWorkoutById(context.TODO(), userID, c.AudienceOwner, workout.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, ErrWorkoutNotFound
+	}
+	visibility := existing.Visibility
+	if workout.Visibility != nil {
+		if !workout.Visibility.Valid() {
+			return nil, ErrInvalidVisibility
+		}
+		visibility = *workout.Visibility
+	}
+
 	updatedWorkout := t.Workout{
-		ID:      workout.ID,
-		Date:    workout.Date,
-		UserId:  userID,
-		Workout: &config,
+		ID:         workout.ID,
+		Date:       workout.Date,
+		UserId:     userID,
+		Workout:    &config,
+		CreatedAt:  existing.CreatedAt,
+		UpdatedAt:  time.Now(),
+		Visibility: visibility,
 	}
 
 	data, err := s.repo.UpdateWorkout(context.TODO(), updatedWorkout)
@@ -188,9 +397,233 @@ func (s *workoutService) UpdateWorkout(userID primitive.ObjectID, workout t.Upda
 		// probably should return the workouts by date here instead of nil? Or maybe not tbf
 		return nil, err
 	}
-	return s.repo.FetchWorkoutByDate(context.TODO(), userID, data.Date)
+	if err := s.recordRevision(userID, c.RevisionActionUpdate, existing, data); err != nil {
+		return nil, err
+	}
+	s.refreshRecords(userID, existing, data)
+	return s.repo.FetchWorkoutByDate(context.TODO(), userID, c.AudienceOwner, data.Date)
 }
 
-func (s *workoutService) DeleteWorkout(workoutID primitive.ObjectID) (bool, error) {
-	return s.repo.RemoveWorkout(context.TODO(), workoutID)
+// DeleteWorkout moves a workout to the trash, it is hard deleted by PurgeExpiredTrash
+// once the retention window has passed.
+func (s *workoutService) DeleteWorkout(userID primitive.ObjectID, workoutID primitive.ObjectID) (bool, error) {
+	existing, err := s.repo.FetchWorkoutById(context.TODO(), userID, c.AudienceOwner, workoutID)
+	if err != nil || existing == nil {
+		return false, err
+	}
+
+	deletedAt := time.Now()
+	success, err := s.repo.SoftDeleteWorkout(context.TODO(), userID, workoutID, deletedAt)
+	if err != nil || !success {
+		return false, err
+	}
+
+	deleted := *existing
+	deleted.DeletedAt = &deletedAt
+	if err := s.recordRevision(userID, c.RevisionActionDelete, existing, &deleted); err != nil {
+		return false, err
+	}
+	s.refreshRecords(userID, existing)
+	return true, nil
+}
+
+func (s *workoutService) GetRevisions(userID primitive.ObjectID, workoutID primitive.ObjectID) ([]t.WorkoutRevision, error) {
+	return s.repo.FetchRevisions(context.TODO(), userID, workoutID)
+}
+
+func (s *workoutService) GetRevision(userID primitive.ObjectID, workoutID primitive.ObjectID, revision int) (*t.WorkoutRevision, error) {
+	found, err := s.repo.FetchRevision(context.TODO(), userID, workoutID, revision)
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, ErrRevisionNotFound
+	}
+	return found, nil
+}
+
+// RestoreRevision puts a workout back to the state captured by the given revision.
+// The restore itself is recorded as a new revision so history is never rewritten.
+func (s *workoutService) RestoreRevision(userID primitive.ObjectID, workoutID primitive.ObjectID, revision int) (*t.Workout, error) {
+	existing, err := s.repo.FetchWorkoutById(context.TODO(), userID, c.AudienceOwner, workoutID)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, ErrWorkoutNotFound
+	}
+
+	target, err := s.repo.FetchRevision(context.TODO(), userID, workoutID, revision)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, ErrRevisionNotFound
+	}
+
+	restored := *existing
+	restored.Date = target.Date
+	restored.Workout = target.Workout
+	restored.UpdatedAt = time.Now()
+
+	data, err := s.repo.UpdateWorkout(context.TODO(), restored)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.recordRevision(userID, c.RevisionActionRestore, existing, data); err != nil {
+		return nil, err
+	}
+	s.refreshRecords(userID, existing, data)
+	return data, nil
+}
+
+func (s *workoutService) GetTrash(userID primitive.ObjectID) ([]t.TrashedWorkout, error) {
+	workouts, err := s.repo.FetchDeletedWorkouts(context.TODO(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	trash := make([]t.TrashedWorkout, 0, len(workouts))
+	for _, workout := range workouts {
+		trash = append(trash, t.TrashedWorkout{
+			Workout: workout,
+			PurgeAt: workout.DeletedAt.Add(s.trashRetention),
+		})
+	}
+	return trash, nil
+}
+
+func (s *workoutService) RestoreFromTrash(userID primitive.ObjectID, workoutID primitive.ObjectID) (*t.Workout, error) {
+	deleted, err := s.repo.FetchDeletedWorkoutById(context.TODO(), userID, workoutID)
+	if err != nil {
+		return nil, err
+	}
+	if deleted == nil {
+		return nil, ErrWorkoutNotFound
+	}
+	if time.Since(*deleted.DeletedAt) > s.trashRetention {
+		return nil, ErrRetentionExpired
+	}
+
+	success, err := s.repo.RestoreWorkout(context.TODO(), userID, workoutID)
+	if err != nil {
+		return nil, err
+	}
+	if !success {
+		return nil, ErrWorkoutNotFound
+	}
+
+	restored, err := s.repo.FetchWorkoutById(context.TODO(), userID, c.AudienceOwner, workoutID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.recordRevision(userID, c.RevisionActionRestore, deleted, restored); err != nil {
+		return nil, err
+	}
+	s.refreshRecords(userID, restored)
+	return restored, nil
+}
+
+// PurgeWorkout hard deletes a workout from the trash without waiting for the retention window.
+func (s *workoutService) PurgeWorkout(userID primitive.ObjectID, workoutID primitive.ObjectID) (bool, error) {
+	return s.repo.RemoveWorkout(context.TODO(), userID, workoutID)
+}
+
+func (s *workoutService) PurgeExpiredTrash() (int64, error) {
+	return s.repo.PurgeDeletedWorkouts(context.TODO(), time.Now().Add(-s.trashRetention))
+}
+
+// withCatalogMuscles fills in muscles from the exercise catalog for any exercise
+// that was logged without them.
+func withCatalogMuscles(exercises []t.Exercise) []t.Exercise {
+	for i, exercise := range exercises {
+		if len(exercise.PrimaryMuscles) > 0 {
+			continue
+		}
+		if catalogExercise, ok := c.FindCatalogExercise(exercise.Name); ok {
+			exercises[i].PrimaryMuscles = catalogExercise.Primary
+			exercises[i].SecondaryMuscles = catalogExercise.Secondary
+		}
+	}
+	return exercises
+}
+
+// refreshRecords recomputes personal records for every exercise in the given workouts.
+// Records can always be rebuilt from the workouts, so a failure here is logged
+// rather than failing the change that triggered it.
+func (s *workoutService) refreshRecords(userID primitive.ObjectID, workouts ...*t.Workout) {
+	exercises := []string{}
+	for _, workout := range workouts {
+		if workout == nil || workout.Workout == nil {
+			continue
+		}
+		for _, exercise := range workout.Workout.Exercises {
+			exercises = append(exercises, exercise.Name)
+		}
+	}
+
+	if err := s.records.RecomputeRecords(userID, exercises, s); err != nil {
+		fmt.Println("error recomputing personal records:", err)
+	}
+}
+
+// recordRevision saves the revision for a change already written to the
+// workout. Mongo runs standalone so the two writes can't share a transaction,
+// instead the change is undone when its revision can't be saved so that no
+// change is ever left without one.
+func (s *workoutService) recordRevision(userID primitive.ObjectID, action c.RevisionAction, before *t.Workout, after *t.Workout) error {
+	err := s.insertRevision(userID, action, before, after)
+	if err == nil {
+		return nil
+	}
+	var undoErr error
+	if before == nil {
+		undoErr = s.repo.DiscardWorkouts(context.TODO(), []primitive.ObjectID{after.ID})
+	} else {
+		undoErr = s.repo.ReplaceWorkout(context.TODO(), *before)
+	}
+	if undoErr != nil {
+		fmt.Println("error undoing a change without a revision:", undoErr)
+	}
+	return err
+}
+
+// insertRevision numbers the revision after the latest one. A concurrent change
+// can take that number between reading it and saving, in which case the unique
+// index rejects the insert and it's tried again with the next number.
+func (s *workoutService) insertRevision(userID primitive.ObjectID, action c.RevisionAction, before *t.Workout, after *t.Workout) error {
+	revision := newRevision(userID, action, before, after)
+	for attempt := 1; ; attempt++ {
+		latest, err := s.repo.FetchLatestRevisionNumber(context.TODO(), after.ID)
+		if err != nil {
+			return err
+		}
+		revision.ID = primitive.NewObjectID()
+		revision.Revision = latest + 1
+
+		_, err = s.repo.InsertRevision(context.TODO(), revision)
+		if err == nil || !mongo.IsDuplicateKeyError(err) || attempt == c.MaxRevisionAttempts {
+			return err
+		}
+	}
+}
+
+// newRevision snapshots after and what changed from before, the caller numbers
+// it.
+func newRevision(userID primitive.ObjectID, action c.RevisionAction, before *t.Workout, after *t.Workout) t.WorkoutRevision {
+	revision := t.WorkoutRevision{
+		ID:        primitive.NewObjectID(),
+		WorkoutID: after.ID,
+		UserId:    after.UserId,
+		Action:    action,
+		ChangedBy: userID,
+		ChangedAt: time.Now(),
+		Date:      after.Date,
+		Workout:   after.Workout,
+		Changes:   diffWorkouts(before, after),
+	}
+	if action == c.RevisionActionDelete {
+		revision.Changes = diffWorkouts(before, nil)
+	}
+	return revision
 }
@@ -0,0 +1,79 @@
+package workout
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// failingRevisions can't save revisions and remembers how the change was undone.
+type failingRevisions struct {
+	WorkoutRepository
+	replaced  *t.Workout
+	discarded []primitive.ObjectID
+}
+
+func (r *failingRevisions) FetchLatestRevisionNumber(ctx context.Context, workoutID primitive.ObjectID) (int, error) {
+	return 1, nil
+}
+
+func (r *failingRevisions) InsertRevision(ctx context.Context, revision t.WorkoutRevision) (*t.WorkoutRevision, error) {
+	return nil, errors.New("connection reset")
+}
+
+func (r *failingRevisions) ReplaceWorkout(ctx context.Context, workout t.Workout) error {
+	r.replaced = &workout
+	return nil
+}
+
+func (r *failingRevisions) DiscardWorkouts(ctx context.Context, workoutIDs []primitive.ObjectID) error {
+	r.discarded = workoutIDs
+	return nil
+}
+
+func TestRecordRevisionUndoesTheChange(tt *testing.T) {
+	weight := 80.0
+	deletedAt := time.Now()
+	before := &t.Workout{ID: primitive.NewObjectID(), Workout: &t.WorkoutConfig{Weight: &weight}}
+	trashed := *before
+	trashed.DeletedAt = &deletedAt
+
+	tests := []struct {
+		name          string
+		action        c.RevisionAction
+		before        *t.Workout
+		wantReplaced  *t.Workout
+		wantDiscarded bool
+	}{
+		{"create is discarded", c.RevisionActionCreate, nil, nil, true},
+		{"update is put back", c.RevisionActionUpdate, before, before, false},
+		{"delete is taken out of the trash", c.RevisionActionDelete, before, before, false},
+		{"restore goes back in the trash", c.RevisionActionRestore, &trashed, &trashed, false},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			repo := &failingRevisions{}
+			service := &workoutService{repo: repo}
+			after := &t.Workout{ID: before.ID}
+
+			if err := service.recordRevision(primitive.NewObjectID(), test.action, test.before, after); err == nil {
+				tt.Fatal("expected the revision error to be returned")
+			}
+			if test.wantDiscarded != (len(repo.discarded) == 1 && repo.discarded[0] == after.ID) {
+				tt.Fatalf("discarded %v, want discarded %v", repo.discarded, test.wantDiscarded)
+			}
+			if (repo.replaced == nil) != (test.wantReplaced == nil) {
+				tt.Fatalf("replaced with %+v, want %+v", repo.replaced, test.wantReplaced)
+			}
+			if repo.replaced != nil && (repo.replaced.DeletedAt != test.wantReplaced.DeletedAt || repo.replaced.Workout.Weight != &weight) {
+				tt.Fatalf("replaced with %+v, want %+v", repo.replaced, test.wantReplaced)
+			}
+		})
+	}
+}
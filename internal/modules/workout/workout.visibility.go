@@ -0,0 +1,73 @@
+package workout
+
+import (
+	"context"
+	"time"
+
+	c "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/constants"
+	t "github.com/joshibbotson/gym-tracker-backend/internal/modules/workout/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetVisibleWorkouts returns the workouts audience can see with from <= date
+// < to, oldest first, with their interaction counts.
+func (s *workoutService) GetVisibleWorkouts(userID primitive.ObjectID, audience c.Audience, from time.Time, to time.Time) ([]t.Workout, error) {
+	if !from.Before(to) {
+		return nil, ErrInvalidRange
+	}
+	if to.Sub(from) > c.MaxCalendarDays*24*time.Hour {
+		return nil, ErrRangeTooLarge
+	}
+	workouts, err := s.repo.FetchWorkoutsInRange(context.TODO(), userID, audience, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return s.withInteractionCounts(forAudience(workouts, audience))
+}
+
+func (s *workoutService) GetVisibleWorkout(userID primitive.ObjectID, audience c.Audience, workoutID primitive.ObjectID) (*t.Workout, error) {
+	workout, err := s.repo.FetchWorkoutById(context.TODO(), userID, audience, workoutID)
+	if err != nil {
+		return nil, err
+	}
+	if workout == nil {
+		return nil, ErrWorkoutNotFound
+	}
+	workouts, err := s.withInteractionCounts(forAudience([]t.Workout{*workout}, audience))
+	if err != nil {
+		return nil, err
+	}
+	return &workouts[0], nil
+}
+
+// GetSharedCalendar is GetWorkoutsByUserId for a profile or chart share link.
+// Only public workouts are on it, body data is left in for the link's own
+// opt-ins to decide.
+func (s *workoutService) GetSharedCalendar(userID primitive.ObjectID, from time.Time, to time.Time, weekStart c.WeekStart) ([]t.YearlyData, error) {
+	return s.calendar(userID, c.AudiencePublic, from, to, weekStart)
+}
+
+// GetFeedWorkouts returns a page of the workouts followers of userIDs can see,
+// newest first, with their interaction counts.
+func (s *workoutService) GetFeedWorkouts(userIDs []primitive.ObjectID, after *t.FeedCursor, limit int) ([]t.Workout, error) {
+	if len(userIDs) == 0 {
+		return []t.Workout{}, nil
+	}
+	workouts, err := s.repo.FetchFeedWorkouts(context.TODO(), userIDs, c.AudienceFollower, after, limit)
+	if err != nil {
+		return nil, err
+	}
+	return s.withInteractionCounts(forAudience(workouts, c.AudienceFollower))
+}
+
+// forAudience leaves body weight and measurements out of workouts read for
+// anyone but their owner, whatever the workout's visibility.
+func forAudience(workouts []t.Workout, audience c.Audience) []t.Workout {
+	if audience == c.AudienceOwner {
+		return workouts
+	}
+	for i := range workouts {
+		workouts[i].Workout = WithoutBodyData(workouts[i].Workout, false, false)
+	}
+	return workouts
+}
@@ -0,0 +1,76 @@
+package util
+
+import (
+	"net/http"
+	"time"
+)
+
+// ParseDate accepts either a full RFC3339 timestamp or a plain YYYY-MM-DD date (taken as UTC midnight).
+func ParseDate(value string) (time.Time, error) {
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		return date, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// ParseTimezone reads the optional tz query param (IANA name), defaulting to UTC.
+// It writes a 400 and returns false when the name is unknown.
+func ParseTimezone(w http.ResponseWriter, r *http.Request) (*time.Location, bool) {
+	tz := r.URL.Query().Get("tz")
+	if tz == "" {
+		return time.UTC, true
+	}
+	location, err := time.LoadLocation(tz)
+	if err != nil {
+		http.Error(w, "Invalid timezone", http.StatusBadRequest)
+		return nil, false
+	}
+	return location, true
+}
+
+// CalendarDay returns the day a stored date falls on for a user in location,
+// as midnight UTC. Dates saved as plain days (midnight UTC) are kept as they
+// are, full timestamps are converted to the user's timezone first.
+func CalendarDay(date time.Time, location *time.Location) time.Time {
+	date = date.UTC()
+	if date.Hour() != 0 || date.Minute() != 0 || date.Second() != 0 || date.Nanosecond() != 0 {
+		date = date.In(location)
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// ParseDateRange reads the from and to query params as dates, to is inclusive
+// and defaults to today, from defaults to defaultFrom.
+func ParseDateRange(w http.ResponseWriter, r *http.Request, defaultFrom time.Time) (time.Time, time.Time, bool) {
+	now := time.Now()
+	return ParseDateRangeWithDefaults(w, r, defaultFrom, time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1))
+}
+
+// ParseDateRangeWithDefaults is ParseDateRange with an exclusive default end.
+func ParseDateRangeWithDefaults(w http.ResponseWriter, r *http.Request, defaultFrom time.Time, defaultTo time.Time) (time.Time, time.Time, bool) {
+	query := r.URL.Query()
+	from := time.Date(defaultFrom.Year(), defaultFrom.Month(), defaultFrom.Day(), 0, 0, 0, 0, time.UTC)
+	to := defaultTo
+
+	if fromParam := query.Get("from"); fromParam != "" {
+		parsed, err := ParseDate(fromParam)
+		if err != nil {
+			http.Error(w, "Error parsing date", http.StatusBadRequest)
+			return from, to, false
+		}
+		from = parsed
+	}
+	if toParam := query.Get("to"); toParam != "" {
+		parsed, err := ParseDate(toParam)
+		if err != nil {
+			http.Error(w, "Error parsing date", http.StatusBadRequest)
+			return from, to, false
+		}
+		to = parsed.AddDate(0, 0, 1)
+	}
+	if !from.Before(to) {
+		http.Error(w, "from must be before to", http.StatusBadRequest)
+		return from, to, false
+	}
+	return from, to, true
+}
@@ -0,0 +1,42 @@
+package util
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DecodeBody reads the request body into target, writing a 400 and returning
+// false if it can't.
+func DecodeBody(w http.ResponseWriter, r *http.Request, target interface{}) bool {
+	body, err := GetBody(r.Body)
+	if err != nil {
+		http.Error(w, "Invalid input", http.StatusBadRequest)
+		return false
+	}
+	if err := json.Unmarshal(body, target); err != nil {
+		http.Error(w, "Invalid input", http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+func WriteJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		http.Error(w, "Error encoding response", http.StatusInternalServerError)
+	}
+}
+
+// ParseObjectID converts a path param to an ObjectID, writing a 400 and
+// returning false if it isn't valid.
+func ParseObjectID(w http.ResponseWriter, idParam string) (primitive.ObjectID, bool) {
+	objectID, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		http.Error(w, "Invalid ID format", http.StatusBadRequest)
+		return primitive.NilObjectID, false
+	}
+	return objectID, true
+}
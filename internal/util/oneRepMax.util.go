@@ -0,0 +1,49 @@
+package util
+
+import "math"
+
+type OneRepMaxFormula func(weight float64, reps int) float64
+
+var OneRepMaxFormulas = map[string]OneRepMaxFormula{
+	"epley":    Epley,
+	"brzycki":  Brzycki,
+	"lombardi": Lombardi,
+}
+
+// EstimateOneRepMax uses the Epley formula, a single rep is taken as the max itself.
+func EstimateOneRepMax(weight float64, reps int) float64 {
+	return Epley(weight, reps)
+}
+
+func Epley(weight float64, reps int) float64 {
+	if reps <= 0 || weight <= 0 {
+		return 0
+	}
+	if reps == 1 {
+		return weight
+	}
+	return weight * (1 + float64(reps)/30)
+}
+
+// BrzyckiMaxReps is where Brzycki stops being useful, it climbs steeply past
+// a dozen reps and divides by zero at 37.
+const BrzyckiMaxReps = 12
+
+// Brzycki tends to be the most accurate under 10 reps, longer sets fall back
+// to Epley.
+func Brzycki(weight float64, reps int) float64 {
+	if reps <= 0 || weight <= 0 {
+		return 0
+	}
+	if reps > BrzyckiMaxReps {
+		return Epley(weight, reps)
+	}
+	return weight * 36 / float64(37-reps)
+}
+
+func Lombardi(weight float64, reps int) float64 {
+	if reps <= 0 || weight <= 0 {
+		return 0
+	}
+	return weight * math.Pow(float64(reps), 0.10)
+}
@@ -0,0 +1,39 @@
+package util
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOneRepMaxFormulas(t *testing.T) {
+	tests := []struct {
+		name    string
+		formula OneRepMaxFormula
+		weight  float64
+		reps    int
+		want    float64
+	}{
+		{"epley single", Epley, 100, 1, 100},
+		{"epley five", Epley, 100, 5, 116.667},
+		{"epley no reps", Epley, 100, 0, 0},
+		{"epley no weight", Epley, 0, 5, 0},
+		{"brzycki single", Brzycki, 100, 1, 100},
+		{"brzycki five", Brzycki, 100, 5, 112.5},
+		{"brzycki at the limit", Brzycki, 100, 12, 144},
+		{"brzycki past the limit uses epley", Brzycki, 100, 20, 166.667},
+		{"brzycki where it would divide by zero", Brzycki, 100, 37, 223.333},
+		{"brzycki far past the limit", Brzycki, 100, 50, 266.667},
+		{"lombardi single", Lombardi, 100, 1, 100},
+		{"lombardi ten", Lombardi, 100, 10, 125.893},
+		{"lombardi negative weight", Lombardi, -100, 10, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.formula(test.weight, test.reps)
+			if math.Abs(got-test.want) > 0.001 {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
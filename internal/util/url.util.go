@@ -0,0 +1,26 @@
+package util
+
+import (
+	"net/http"
+	"os"
+	"strings"
+)
+
+// BaseURL is the scheme and host links handed out should point at. It's read
+// from PUBLIC_BASE_URL so a spoofed Host header can't end up in a shared link,
+// and only falls back to the scheme and host the request came in on, including
+// behind a TLS terminating proxy, when that isn't set.
+func BaseURL(r *http.Request) string {
+	if configured := os.Getenv("PUBLIC_BASE_URL"); configured != "" {
+		return strings.TrimSuffix(configured, "/")
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if forwarded := r.Header.Get("X-Forwarded-Proto"); forwarded != "" {
+		scheme = forwarded
+	}
+	return scheme + "://" + r.Host
+}
@@ -0,0 +1,33 @@
+package util
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBaseURL(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured string
+		forwarded  string
+		want       string
+	}{
+		{"configured wins over the headers", "https://api.example.com/", "http", "https://api.example.com"},
+		{"falls back to the request", "", "", "http://spoofed.example"},
+		{"behind a proxy", "", "https", "https://spoofed.example"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("PUBLIC_BASE_URL", test.configured)
+			r := httptest.NewRequest("GET", "/share/abc", nil)
+			r.Host = "spoofed.example"
+			if test.forwarded != "" {
+				r.Header.Set("X-Forwarded-Proto", test.forwarded)
+			}
+			if got := BaseURL(r); got != test.want {
+				t.Fatalf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}